@@ -0,0 +1,72 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+// Checkpoint persists the progress of a long running task in a JSON file, so an interrupted task
+// resumes where it stopped instead of starting over.
+type Checkpoint struct {
+	path string
+}
+
+type checkpointState struct {
+	// Task identifies the task and its parameters; a checkpoint is never applied to another task.
+	Task string    `json:"task"`
+	Next time.Time `json:"next"`
+}
+
+func NewCheckpoint(path string) (*Checkpoint, error) {
+	if path == "" {
+		return nil, errors.New("path must not be empty")
+	}
+	return &Checkpoint{path}, nil
+}
+
+// Load returns the time the task resumes at, the zero time if the task has not been started.
+func (checkpoint *Checkpoint) Load(task string) (time.Time, error) {
+	content, err := ioutil.ReadFile(checkpoint.path)
+	if os.IsNotExist(err) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, errors.New("unable to read checkpoint: " + err.Error())
+	}
+
+	var state checkpointState
+	if err := json.Unmarshal(content, &state); err != nil {
+		return time.Time{}, errors.New("unable to parse checkpoint `" + checkpoint.path + "`: " +
+			err.Error())
+	}
+	if state.Task != task {
+		return time.Time{}, errors.New("checkpoint `" + checkpoint.path + "` belongs to task `" +
+			state.Task + "`")
+	}
+	return state.Next, nil
+}
+
+// Save records that the task resumes at `next`. The file is replaced atomically, so an
+// interruption never leaves a corrupted checkpoint behind.
+func (checkpoint *Checkpoint) Save(task string, next time.Time) error {
+	content, err := json.Marshal(checkpointState{task, next})
+	if err != nil {
+		return err
+	}
+	tmpPath := checkpoint.path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, content, 0600); err != nil {
+		return errors.New("unable to write checkpoint: " + err.Error())
+	}
+	return os.Rename(tmpPath, checkpoint.path)
+}
+
+// Clear removes the checkpoint of a completed task.
+func (checkpoint *Checkpoint) Clear() error {
+	if err := os.Remove(checkpoint.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
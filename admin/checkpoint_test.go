@@ -0,0 +1,43 @@
+package admin
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCheckpoint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := NewCheckpoint(""); err == nil {
+		t.Error("NewCheckpoint(\"\"): got no error, expected error")
+	}
+	checkpoint, _ := NewCheckpoint(filepath.Join(dir, "checkpoint.json"))
+
+	if next, err := checkpoint.Load("sanitize"); err != nil || !next.IsZero() {
+		t.Errorf("Load(): got (%v, %v), expected zero time of a missing checkpoint", next, err)
+	}
+	next := time.Unix(1537701181, 0)
+	if err := checkpoint.Save("sanitize", next); err != nil {
+		t.Fatalf("Save(): got err (%v), expected nil", err)
+	}
+	if result, err := checkpoint.Load("sanitize"); err != nil || !result.Equal(next) {
+		t.Errorf("Load(): got (%v, %v), expected (%v, nil)", result, err, next)
+	}
+	if _, err := checkpoint.Load("rollups"); err == nil {
+		t.Error("Load(\"rollups\"): got no error, expected error for checkpoint of another task")
+	}
+
+	if err := checkpoint.Clear(); err != nil {
+		t.Errorf("Clear(): got err (%v), expected nil", err)
+	}
+	if result, err := checkpoint.Load("sanitize"); err != nil || !result.IsZero() {
+		t.Errorf("Load(): got (%v, %v), expected zero time after Clear()", result, err)
+	}
+}
@@ -0,0 +1,36 @@
+package admin
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+)
+
+// CreateCredential provisions the credential of a crawler writing the track records of
+// `stations`. Unless a token is passed, e. g. the shared token crawlers used before per-crawler
+// credentials existed, a random token is generated. The token is returned to be handed to the
+// crawler; only its hash is stored.
+func CreateCredential(dao datalayer.CredentialDAO, principalID string, stations []string,
+	token string) (string, error) {
+	if dao == nil {
+		return "", errors.New("dao must not be nil")
+	}
+	if token == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return "", err
+		}
+		token = hex.EncodeToString(secret)
+	}
+
+	credential := model.Credential{model.HashToken(token), principalID, stations}
+	if err := credential.Sanitize(); err != nil {
+		return "", err
+	}
+	if err := dao.Create(credential); err != nil {
+		return "", err
+	}
+	return token, nil
+}
@@ -0,0 +1,52 @@
+package admin
+
+import (
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"reflect"
+	"testing"
+)
+
+func TestCreateCredential(t *testing.T) {
+	dao := datalayer.NewMemoryCredentialDAO()
+
+	var tests = []struct {
+		principalID string
+		stations    []string
+		token       string
+		expectedErr bool
+	}{
+		{"crawler-oe3", []string{"hitradio-oe3", "fm4"}, "", false},
+		{"crawler-legacy", []string{"kronehit"}, "legacy-token", false},
+		// the token is taken already
+		{"crawler-other", []string{"kronehit"}, "legacy-token", true},
+		{"crawler-none", nil, "", true},
+		{"Crawler OE3", []string{"fm4"}, "", true},
+	}
+
+	for _, test := range tests {
+		token, err := CreateCredential(dao, test.principalID, test.stations, test.token)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("CreateCredential(%q, %v, %q): got err (%v), expected err: %v",
+				test.principalID, test.stations, test.token, err, test.expectedErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if (test.token != "" && token != test.token) || (test.token == "" && len(token) != 64) {
+			t.Errorf("CreateCredential(%q, %v, %q): got token %q", test.principalID,
+				test.stations, test.token, token)
+		}
+		credential, err := dao.GetByTokenHash(model.HashToken(token))
+		if err != nil || credential.PrincipalID != test.principalID ||
+			!reflect.DeepEqual(credential.Stations, test.stations) {
+			t.Errorf("CreateCredential(%q, %v, %q): stored (%v, %v)", test.principalID,
+				test.stations, test.token, credential, err)
+		}
+	}
+
+	if _, err := CreateCredential(nil, "crawler-oe3", []string{"fm4"}, ""); err == nil {
+		t.Error("CreateCredential(nil, ...): got no error, expected error")
+	}
+}
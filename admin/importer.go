@@ -0,0 +1,190 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/export"
+	"github.com/RadioCheckerApp/api/model"
+	"io"
+	"sort"
+	"time"
+)
+
+// ImportPrincipalID is recorded on imported track records in place of a crawler's principal ID.
+const ImportPrincipalID = "rcadmin-import"
+
+const importBatchSize = 100
+
+// ImportStats counts the outcomes of an Importer.
+type ImportStats struct {
+	Read int
+	// Imported counts the track records which have been written, or would have been written by a
+	// dry run.
+	Imported int
+	// Duplicates counts the track records which exist already or appear earlier in the input.
+	Duplicates int
+	Rejected   int
+}
+
+// Importer writes the track records of a playlist which pass the sanitization rules and do not
+// exist yet. Every rejected record is reported along with the reason. Since existing track records
+// are skipped, an interrupted import may simply be started again.
+type Importer struct {
+	dao     datalayer.TrackRecordDAO
+	rules   model.TrackRecordRules
+	dryRun  bool
+	report  io.Writer
+	options Options
+	seen    map[string]bool
+	written map[string]map[int64]bool
+	Stats   ImportStats
+}
+
+type importRecord struct {
+	number      int
+	trackRecord model.TrackRecord
+}
+
+func NewImporter(dao datalayer.TrackRecordDAO, rules model.TrackRecordRules, dryRun bool,
+	report io.Writer, options Options) (*Importer, error) {
+	if dao == nil {
+		return nil, errors.New("dao must not be nil")
+	}
+	if report == nil {
+		return nil, errors.New("report must not be nil")
+	}
+	return &Importer{dao, rules, dryRun, report, options, make(map[string]bool),
+		make(map[string]map[int64]bool), ImportStats{}}, nil
+}
+
+// WrittenHours returns the hours track records have been written to per station, identified by
+// their first second in ascending order. Dry runs write nothing.
+func (importer *Importer) WrittenHours() map[string][]time.Time {
+	writtenHours := make(map[string][]time.Time, len(importer.written))
+	for station, hours := range importer.written {
+		for hour := range hours {
+			writtenHours[station] = append(writtenHours[station], time.Unix(hour, 0))
+		}
+		sort.Slice(writtenHours[station], func(i, j int) bool {
+			return writtenHours[station][i].Before(writtenHours[station][j])
+		})
+	}
+	return writtenHours
+}
+
+// Import reads the reader's records until its end. `name` identifies the input in the report.
+func (importer *Importer) Import(name string, reader export.Reader) error {
+	batch := make([]importRecord, 0, importBatchSize)
+	for {
+		trackRecord, number, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if recordErr, ok := err.(export.RecordError); ok {
+			importer.Stats.Read++
+			importer.reject(name, number, recordErr.Err)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("unable to read %s: %v", name, err)
+		}
+
+		importer.Stats.Read++
+		if err := trackRecord.SanitizeWithRules(importer.rules); err != nil {
+			importer.reject(name, number, err)
+			continue
+		}
+		trackRecord.PrincipalID = ImportPrincipalID
+		key := path(trackRecord)
+		if importer.seen[key] {
+			importer.Stats.Duplicates++
+			continue
+		}
+		importer.seen[key] = true
+
+		batch = append(batch, importRecord{number, trackRecord})
+		if len(batch) == importBatchSize {
+			if err := importer.write(name, batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	return importer.write(name, batch)
+}
+
+func (importer *Importer) reject(name string, number int, err error) {
+	importer.Stats.Rejected++
+	fmt.Fprintf(importer.report, "REJECTED %s record %d: %v\n", name, number, err)
+}
+
+// write skips the batch's existing track records and writes the others.
+func (importer *Importer) write(name string, batch []importRecord) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	existing, err := importer.findExisting(batch)
+	if err != nil {
+		return err
+	}
+
+	trackRecords := make([]model.TrackRecord, 0, len(batch))
+	for _, record := range batch {
+		if existing[path(record.trackRecord)] {
+			importer.Stats.Duplicates++
+			continue
+		}
+		trackRecords = append(trackRecords, record.trackRecord)
+	}
+	if !importer.dryRun && len(trackRecords) > 0 {
+		importer.options.Throttle.Wait()
+		if err := importer.dao.PutTrackRecords(trackRecords); err != nil {
+			return fmt.Errorf("unable to write %s records %d to %d: %v", name, batch[0].number,
+				batch[len(batch)-1].number, err)
+		}
+		for _, trackRecord := range trackRecords {
+			station := trackRecord.StationId
+			if importer.written[station] == nil {
+				importer.written[station] = make(map[int64]bool)
+			}
+			importer.written[station][trackRecord.Timestamp-trackRecord.Timestamp%3600] = true
+		}
+	}
+	importer.Stats.Imported += len(trackRecords)
+
+	fmt.Fprintf(importer.options.progress(), "%s record %d: %d imported, %d duplicates, "+
+		"%d rejected\n", name, batch[len(batch)-1].number, importer.Stats.Imported,
+		importer.Stats.Duplicates, importer.Stats.Rejected)
+	return nil
+}
+
+// findExisting returns the paths of the batch's track records which exist already. The existing
+// track records are read per station and hour, which keeps every query within a single page even
+// if the input is not ordered by airtime.
+func (importer *Importer) findExisting(batch []importRecord) (map[string]bool, error) {
+	type stationHour struct {
+		station string
+		hour    int64
+	}
+	hours := make(map[stationHour]bool)
+	for _, record := range batch {
+		hours[stationHour{record.trackRecord.StationId,
+			record.trackRecord.Timestamp - record.trackRecord.Timestamp%3600}] = true
+	}
+
+	existing := make(map[string]bool)
+	for hour := range hours {
+		importer.options.Throttle.Wait()
+		startDate := time.Unix(hour.hour, 0)
+		trackRecords, err := importer.dao.GetRecordsByStation(hour.station, model.RecordTypes,
+			startDate, startDate.Add(time.Hour-time.Second))
+		if err != nil {
+			return nil, err
+		}
+		for _, trackRecord := range trackRecords {
+			existing[path(trackRecord)] = true
+		}
+	}
+	return existing, nil
+}
@@ -0,0 +1,102 @@
+package admin
+
+import (
+	"bytes"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/export"
+	"github.com/RadioCheckerApp/api/model"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestImporter_Import(t *testing.T) {
+	existing := model.TrackRecord{StationId: "fm4", Timestamp: 1425214920, Type: "track",
+		Track: model.Track{"jonas blue, jack & jack", "rise"}, PrincipalID: "crawler"}
+	input := "station,airtime,artist,title\n" +
+		// exists already
+		"FM4,2015-03-01T14:02:00+01:00,Jonas Blue,Rise\n" +
+		"fm4,2015-03-01T14:05:00+01:00,MØ,Final Song\n" +
+		// appears earlier in the input
+		"fm4,1425215100,MØ,Final Song\n" +
+		"oe3,2015-03-01T14:05:00+01:00,RHCP,Californication\n" +
+		"oe3,yesterday,RHCP,Californication\n" +
+		"oe3,2015-03-01T14:10:00+01:00,RHCP,\n" +
+		// aired before the earliest date
+		"oe3,2010-03-01T14:10:00+01:00,RHCP,Californication\n"
+	rules := model.TrackRecordRules{Earliest: time.Date(2014, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	imported := []model.TrackRecord{
+		{StationId: "fm4", Timestamp: 1425215100, Type: "track", Track: model.Track{"mø", "final song"},
+			TrackDisplay: model.TrackDisplay{"MØ", "Final Song"}, PrincipalID: ImportPrincipalID},
+		{StationId: "oe3", Timestamp: 1425215100, Type: "track",
+			Track:        model.Track{"rhcp", "californication"},
+			TrackDisplay: model.TrackDisplay{"RHCP", "Californication"}, PrincipalID: ImportPrincipalID},
+	}
+
+	hour := time.Unix(1425214800, 0)
+
+	var tests = []struct {
+		dryRun        bool
+		expectedStats ImportStats
+		expectedDAO   []model.TrackRecord
+		expectedHours map[string][]time.Time
+	}{
+		{true, ImportStats{7, 2, 2, 3}, []model.TrackRecord{existing}, map[string][]time.Time{}},
+		{false, ImportStats{7, 2, 2, 3}, []model.TrackRecord{existing, imported[0], imported[1]},
+			map[string][]time.Time{"fm4": {hour}, "oe3": {hour}}},
+	}
+
+	for _, test := range tests {
+		dao := datalayer.NewMemoryTrackRecordDAO(existing)
+		report := &bytes.Buffer{}
+		importer, _ := NewImporter(dao, rules, test.dryRun, report, Options{})
+		reader, _ := export.NewReader(export.CSV, strings.NewReader(input))
+		if err := importer.Import("plays.csv", reader); err != nil {
+			t.Errorf("Import(dryRun: %v): got err (%v), expected nil", test.dryRun, err)
+			continue
+		}
+		if importer.Stats != test.expectedStats {
+			t.Errorf("Import(dryRun: %v): got stats %+v, expected %+v", test.dryRun,
+				importer.Stats, test.expectedStats)
+		}
+		expectedReport := "REJECTED plays.csv record 5: airtime `yesterday` is neither unix time " +
+			"nor RFC 3339\n" +
+			"REJECTED plays.csv record 6: title contains invalid data\n" +
+			"REJECTED plays.csv record 7: timestamp is older than RadioChecker\n"
+		if report.String() != expectedReport {
+			t.Errorf("Import(dryRun: %v): got report %q, expected %q", test.dryRun, report.String(),
+				expectedReport)
+		}
+		if records := dumpTrackRecords(dao); !reflect.DeepEqual(records, test.expectedDAO) {
+			t.Errorf("Import(dryRun: %v): got stored %v, expected %v", test.dryRun, records,
+				test.expectedDAO)
+		}
+		if hours := importer.WrittenHours(); !reflect.DeepEqual(hours, test.expectedHours) {
+			t.Errorf("WrittenHours(dryRun: %v): got %v, expected %v", test.dryRun, hours,
+				test.expectedHours)
+		}
+	}
+}
+
+func TestImporter_Import_Batches(t *testing.T) {
+	var input strings.Builder
+	input.WriteString("stationId,airtime,artist,title\n")
+	for i := 0; i < 2*importBatchSize+10; i++ {
+		input.WriteString("fm4," + strconv.Itoa(1425214920+i*60) + ",rhcp,californication\n")
+	}
+
+	dao := datalayer.NewMemoryTrackRecordDAO()
+	importer, _ := NewImporter(dao, model.TrackRecordRules{}, false, &bytes.Buffer{}, Options{})
+	reader, _ := export.NewReader(export.CSV, strings.NewReader(input.String()))
+	if err := importer.Import("plays.csv", reader); err != nil {
+		t.Fatalf("Import(): got err (%v), expected nil", err)
+	}
+	if records := dumpTrackRecords(dao); len(records) != 2*importBatchSize+10 ||
+		importer.Stats.Imported != len(records) {
+		t.Errorf("Import(): stored %d of %d track records, expected %d", len(records),
+			importer.Stats.Imported, 2*importBatchSize+10)
+	}
+}
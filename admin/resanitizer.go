@@ -0,0 +1,140 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// ResanitizeStats counts the outcomes of a Resanitizer.
+type ResanitizeStats struct {
+	Scanned  int
+	Changed  int
+	Rejected int
+	// Rewritten counts the changed track records which have been written back.
+	Rewritten int
+	// Conflicts counts the changed track records which could not be moved to their sanitized
+	// station, since the station already has a track record of the same airtime.
+	Conflicts int
+}
+
+// Resanitizer sanitizes stored track records under the current rules. It reports every track
+// record which would change and every track record the rules reject by now. Unless it performs a
+// dry run, changed track records are written back; rejected track records are never touched.
+type Resanitizer struct {
+	dao      datalayer.TrackRecordDAO
+	rules    model.TrackRecordRules
+	dryRun   bool
+	throttle *Throttle
+	report   io.Writer
+	Stats    ResanitizeStats
+}
+
+func NewResanitizer(dao datalayer.TrackRecordDAO, rules model.TrackRecordRules, dryRun bool,
+	throttle *Throttle, report io.Writer) (*Resanitizer, error) {
+	if dao == nil {
+		return nil, errors.New("dao must not be nil")
+	}
+	if report == nil {
+		return nil, errors.New("report must not be nil")
+	}
+	return &Resanitizer{dao, rules, dryRun, throttle, report, ResanitizeStats{}}, nil
+}
+
+// Process sanitizes the track records; it is meant to be passed to Scanner.Scan.
+func (resanitizer *Resanitizer) Process(trackRecords []model.TrackRecord) error {
+	for _, original := range trackRecords {
+		resanitizer.Stats.Scanned++
+
+		sanitized := original
+		if err := sanitized.SanitizeWithRules(resanitizer.rules); err != nil {
+			resanitizer.Stats.Rejected++
+			fmt.Fprintf(resanitizer.report, "REJECTED %s: %v\n", path(original), err)
+			continue
+		}
+		if reflect.DeepEqual(sanitized, original) {
+			continue
+		}
+
+		resanitizer.Stats.Changed++
+		fmt.Fprintf(resanitizer.report, "CHANGED %s: %s\n", path(original),
+			describeChanges(original, sanitized))
+		if resanitizer.dryRun {
+			continue
+		}
+		if err := resanitizer.rewrite(original, sanitized); err != nil {
+			return fmt.Errorf("unable to rewrite %s: %v", path(original), err)
+		}
+	}
+	return nil
+}
+
+func (resanitizer *Resanitizer) rewrite(original, sanitized model.TrackRecord) error {
+	if sanitized.StationId == original.StationId {
+		resanitizer.throttle.Wait()
+		if err := resanitizer.dao.UpdateTrackRecord(sanitized); err != nil {
+			return err
+		}
+		resanitizer.Stats.Rewritten++
+		return nil
+	}
+
+	// the station is part of the track record's key, hence the track record moves
+	resanitizer.throttle.Wait()
+	err := resanitizer.dao.CreateTrackRecord(sanitized)
+	if datalayer.IsAlreadyExists(err) {
+		resanitizer.Stats.Conflicts++
+		fmt.Fprintf(resanitizer.report, "CONFLICT %s: %s exists already, keeping %s\n",
+			path(original), path(sanitized), path(original))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resanitizer.throttle.Wait()
+	if err := resanitizer.dao.DeleteTrackRecord(original.StationId, original.Timestamp); err != nil {
+		return err
+	}
+	resanitizer.Stats.Rewritten++
+	return nil
+}
+
+// path identifies a track record like the API's paths do, e. g. `fm4/1537701181`.
+func path(trackRecord model.TrackRecord) string {
+	return trackRecord.StationId + "/" + strconv.FormatInt(trackRecord.Timestamp, 10)
+}
+
+func describeChanges(original, sanitized model.TrackRecord) string {
+	fields := []struct {
+		name     string
+		original string
+		changed  string
+	}{
+		{"stationId", original.StationId, sanitized.StationId},
+		{"type", original.Type, sanitized.Type},
+		{"artist", original.Artist, sanitized.Artist},
+		{"title", original.Title, sanitized.Title},
+		{"album", original.Album, sanitized.Album},
+		{"isrc", original.ISRC, sanitized.ISRC},
+		{"label", original.Label, sanitized.Label},
+		{"cover_url", original.CoverURL, sanitized.CoverURL},
+		{"display_artist", original.DisplayArtist, sanitized.DisplayArtist},
+		{"display_title", original.DisplayTitle, sanitized.DisplayTitle},
+	}
+
+	description := ""
+	for _, field := range fields {
+		if field.original == field.changed {
+			continue
+		}
+		if description != "" {
+			description += ", "
+		}
+		description += fmt.Sprintf("%s %q -> %q", field.name, field.original, field.changed)
+	}
+	return description
+}
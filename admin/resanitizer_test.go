@@ -0,0 +1,92 @@
+package admin
+
+import (
+	"bytes"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResanitizer_Process(t *testing.T) {
+	stored := []model.TrackRecord{
+		// clean
+		{StationId: "fm4", Timestamp: 1537700000, Type: "track",
+			Track: model.Track{"rhcp", "californication"}},
+		// artist changes
+		{StationId: "fm4", Timestamp: 1537701000, Type: "track",
+			Track: model.Track{"Cardi  B", "i like it"}},
+		// station changes, the sanitized station has no record of the airtime
+		{StationId: "OE3", Timestamp: 1537702000, Type: "track",
+			Track: model.Track{"mø", "final song"}},
+		// station changes, the sanitized station has a record of the airtime
+		{StationId: "Fm4", Timestamp: 1537700000, Type: "track",
+			Track: model.Track{"rhcp", "californication"}},
+		// rejected by the current rules
+		{StationId: "fm4", Timestamp: 1451606000, Type: "track",
+			Track: model.Track{"rhcp", "scar tissue"}},
+	}
+	rules := model.TrackRecordRules{Earliest: time.Unix(1451606400, 0)}
+
+	var tests = []struct {
+		dryRun        bool
+		expectedStats ResanitizeStats
+		expectedDAO   []model.TrackRecord
+	}{
+		{true, ResanitizeStats{5, 3, 1, 0, 0}, []model.TrackRecord{stored[4], stored[3], stored[0],
+			stored[1], stored[2]}},
+		{false, ResanitizeStats{5, 3, 1, 2, 1}, []model.TrackRecord{
+			stored[4],
+			stored[3],
+			stored[0],
+			{StationId: "fm4", Timestamp: 1537701000, Type: "track",
+				Track: model.Track{"cardi b", "i like it"}, TrackDisplay: model.TrackDisplay{
+					DisplayArtist: "Cardi B"}},
+			{StationId: "oe3", Timestamp: 1537702000, Type: "track",
+				Track: model.Track{"mø", "final song"}},
+		}},
+	}
+
+	for _, test := range tests {
+		dao := datalayer.NewMemoryTrackRecordDAO(stored...)
+		report := &bytes.Buffer{}
+		resanitizer, _ := NewResanitizer(dao, rules, test.dryRun, nil, report)
+		if err := resanitizer.Process(stored); err != nil {
+			t.Errorf("Process(dryRun: %v): got err (%v), expected nil", test.dryRun, err)
+			continue
+		}
+		if resanitizer.Stats != test.expectedStats {
+			t.Errorf("Process(dryRun: %v): got stats %+v, expected %+v", test.dryRun,
+				resanitizer.Stats, test.expectedStats)
+		}
+		changed := "CHANGED fm4/1537701000: artist \"Cardi  B\" -> \"cardi b\", " +
+			"display_artist \"\" -> \"Cardi B\"\n"
+		if !strings.Contains(report.String(), changed) {
+			t.Errorf("Process(dryRun: %v): got report %q, expected change of artist", test.dryRun,
+				report.String())
+		}
+		records := dumpTrackRecords(dao)
+		if !reflect.DeepEqual(records, test.expectedDAO) {
+			t.Errorf("Process(dryRun: %v): got stored %v, expected %v", test.dryRun, records,
+				test.expectedDAO)
+		}
+	}
+}
+
+func TestNewResanitizer(t *testing.T) {
+	dao := datalayer.NewMemoryTrackRecordDAO()
+	if _, err := NewResanitizer(nil, model.DefaultTrackRecordRules, true, nil,
+		&bytes.Buffer{}); err == nil {
+		t.Error("NewResanitizer(nil dao): got no error, expected error")
+	}
+	if _, err := NewResanitizer(dao, model.DefaultTrackRecordRules, true, nil, nil); err == nil {
+		t.Error("NewResanitizer(nil report): got no error, expected error")
+	}
+}
+
+func dumpTrackRecords(dao *datalayer.MemoryTrackRecordDAO) []model.TrackRecord {
+	trackRecords, _ := dao.GetTrackRecords(time.Unix(0, 0), time.Unix(1600000000, 0))
+	return trackRecords
+}
@@ -0,0 +1,93 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"github.com/RadioCheckerApp/api/request"
+	"time"
+)
+
+// RebuildRollups recalculates the rollups of the stations' days between `startDate` and `endDate`
+// from their track records, e. g. after enabling rollups or after re-sanitizing track records.
+// If a checkpoint of the task exists, the rebuild resumes at the day it stopped at.
+func RebuildRollups(rollups *request.Rollups, dao datalayer.TrackRecordDAO, stations []string,
+	startDate, endDate time.Time, task string, options Options) error {
+	if rollups == nil || dao == nil {
+		return errors.New("rollups and dao must not be nil")
+	}
+	if startDate.After(endDate) {
+		return errors.New("startDate must be before endDate")
+	}
+
+	progress := options.progress()
+	date := startDate
+	if options.Checkpoint != nil {
+		next, err := options.Checkpoint.Load(task)
+		if err != nil {
+			return err
+		}
+		if !next.IsZero() {
+			date = next.In(startDate.Location())
+			fmt.Fprintf(progress, "resuming at %s\n", date.Format("2006-01-02"))
+		}
+	}
+
+	for ; !date.After(endDate); date = date.AddDate(0, 0, 1) {
+		for _, station := range stations {
+			options.Throttle.Wait()
+			dayRollups, err := rollups.Rebuild(dao, station, date)
+			if err != nil {
+				return fmt.Errorf("unable to rebuild %s of %s: %v", date.Format("2006-01-02"),
+					station, err)
+			}
+			plays := 0
+			for _, rollup := range dayRollups {
+				plays += rollup.Plays
+			}
+			fmt.Fprintf(progress, "%s %s: %d plays of %d tracks\n", date.Format("2006-01-02"),
+				station, plays, len(dayRollups))
+		}
+		if options.Checkpoint != nil {
+			if err := options.Checkpoint.Save(task, date.AddDate(0, 0, 1)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if options.Checkpoint != nil {
+		return options.Checkpoint.Clear()
+	}
+	return nil
+}
+
+// RebuildWrittenRollups recalculates the rollups of the days containing the stations' hours, e. g.
+// the hours an Importer has written track records to. Days are calculated in `location`, each
+// station's day is rebuilt once.
+func RebuildWrittenRollups(rollups *request.Rollups, dao datalayer.TrackRecordDAO,
+	writtenHours map[string][]time.Time, location *time.Location, options Options) error {
+	if rollups == nil || dao == nil {
+		return errors.New("rollups and dao must not be nil")
+	}
+
+	progress := options.progress()
+	for station, hours := range writtenHours {
+		rebuilt := make(map[string]bool)
+		for _, hour := range hours {
+			day := model.FormatRollupDay(hour.In(location))
+			if rebuilt[day] {
+				continue
+			}
+			rebuilt[day] = true
+
+			options.Throttle.Wait()
+			dayRollups, err := rollups.Rebuild(dao, station, hour)
+			if err != nil {
+				return fmt.Errorf("unable to rebuild %s of %s: %v", day, station, err)
+			}
+			fmt.Fprintf(progress, "%s %s: rebuilt %d rollups\n", day, station, len(dayRollups))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package admin
+
+import (
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"github.com/RadioCheckerApp/api/request"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// MockRollupDAO records the station days whose rollups are replaced.
+type MockRollupDAO struct {
+	replaced *[]string
+}
+
+func (dao MockRollupDAO) GetRollups(day string) ([]model.TrackRollup, error) {
+	return []model.TrackRollup{}, nil
+}
+
+func (dao MockRollupDAO) GetRollupsByStation(station, day string) ([]model.TrackRollup, error) {
+	return []model.TrackRollup{}, nil
+}
+
+func (dao MockRollupDAO) AddTrackRecord(day string, trackRecord model.TrackRecord) error {
+	return nil
+}
+
+func (dao MockRollupDAO) ReplaceRollups(station, day string, rollups []model.TrackRollup) error {
+	*dao.replaced = append(*dao.replaced, station+" "+day)
+	return nil
+}
+
+func TestRebuildWrittenRollups(t *testing.T) {
+	location, _ := time.LoadLocation("Europe/Vienna")
+	replaced := make([]string, 0)
+	rollups, _ := request.NewRollups(MockRollupDAO{&replaced}, location)
+	dao := datalayer.NewMemoryTrackRecordDAO()
+
+	// 23:00 UTC is the next day in Vienna, both fm4 hours of 2018-09-23 are rebuilt once
+	writtenHours := map[string][]time.Time{
+		"fm4": {time.Date(2018, 9, 23, 10, 0, 0, 0, time.UTC),
+			time.Date(2018, 9, 23, 12, 0, 0, 0, time.UTC),
+			time.Date(2018, 9, 23, 23, 0, 0, 0, time.UTC)},
+		"oe3": {time.Date(2018, 9, 23, 10, 0, 0, 0, time.UTC)},
+	}
+	if err := RebuildWrittenRollups(rollups, dao, writtenHours, location, Options{}); err != nil {
+		t.Fatalf("RebuildWrittenRollups(): got err (%v), expected nil", err)
+	}
+
+	sort.Strings(replaced)
+	expected := []string{"fm4 2018-09-23", "fm4 2018-09-24", "oe3 2018-09-23"}
+	if !reflect.DeepEqual(replaced, expected) {
+		t.Errorf("RebuildWrittenRollups(): got rebuilt days %v, expected %v", replaced, expected)
+	}
+
+	if err := RebuildWrittenRollups(nil, dao, writtenHours, location, Options{}); err == nil {
+		t.Error("RebuildWrittenRollups(nil): got no error, expected error")
+	}
+}
@@ -0,0 +1,116 @@
+// Package admin implements the long running maintenance tasks of the rcadmin command, e. g.
+// sanitizing stored track records again after the rules changed.
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"io"
+	"io/ioutil"
+	"time"
+)
+
+const progressTimeFormat = "2006-01-02 15:04"
+
+// Options control how long running tasks are executed.
+type Options struct {
+	// Throttle bounds the rate of operations, nil disables throttling.
+	Throttle *Throttle
+	// Checkpoint persists the progress of the task, nil disables resuming.
+	Checkpoint *Checkpoint
+	// Progress receives a line per processed chunk, nil discards the progress.
+	Progress io.Writer
+}
+
+func (options Options) progress() io.Writer {
+	if options.Progress == nil {
+		return ioutil.Discard
+	}
+	return options.Progress
+}
+
+// Scanner reads the track records of a period chunk by chunk, regardless of their type. Each chunk
+// is read by a single query, hence chunks have to be small enough for their track records to fit
+// into a single page.
+type Scanner struct {
+	dao     datalayer.TrackRecordDAO
+	station string
+	chunk   time.Duration
+	options Options
+}
+
+// NewScanner creates a scanner reading the track records of `station`, or of all stations if the
+// station is empty.
+func NewScanner(dao datalayer.TrackRecordDAO, station string, chunk time.Duration,
+	options Options) (*Scanner, error) {
+	if dao == nil {
+		return nil, errors.New("dao must not be nil")
+	}
+	if chunk < time.Second {
+		return nil, errors.New("chunk must be at least one second")
+	}
+	return &Scanner{dao, station, chunk, options}, nil
+}
+
+// Scan passes the track records aired between `startDate` and `endDate` to `process`, one chunk
+// at a time. If a checkpoint of the task exists, the scan resumes after the last chunk processed.
+func (scanner *Scanner) Scan(task string, startDate, endDate time.Time,
+	process func([]model.TrackRecord) error) error {
+	if startDate.After(endDate) {
+		return errors.New("startDate must be before endDate")
+	}
+
+	checkpoint := scanner.options.Checkpoint
+	progress := scanner.options.progress()
+	chunkStart := startDate
+	if checkpoint != nil {
+		next, err := checkpoint.Load(task)
+		if err != nil {
+			return err
+		}
+		if !next.IsZero() {
+			chunkStart = next
+			fmt.Fprintf(progress, "resuming at %s\n", next.Format(progressTimeFormat))
+		}
+	}
+
+	for !chunkStart.After(endDate) {
+		chunkEnd := chunkStart.Add(scanner.chunk - time.Second)
+		if chunkEnd.After(endDate) {
+			chunkEnd = endDate
+		}
+
+		scanner.options.Throttle.Wait()
+		trackRecords, err := scanner.read(chunkStart, chunkEnd)
+		if err != nil {
+			return fmt.Errorf("unable to read track records of %s: %v",
+				chunkStart.Format(progressTimeFormat), err)
+		}
+		if err := process(trackRecords); err != nil {
+			return err
+		}
+		fmt.Fprintf(progress, "%s - %s: %d track records\n", chunkStart.Format(progressTimeFormat),
+			chunkEnd.Format(progressTimeFormat), len(trackRecords))
+
+		chunkStart = chunkEnd.Add(time.Second)
+		if checkpoint != nil {
+			if err := checkpoint.Save(task, chunkStart); err != nil {
+				return err
+			}
+		}
+	}
+
+	if checkpoint != nil {
+		return checkpoint.Clear()
+	}
+	return nil
+}
+
+func (scanner *Scanner) read(startDate, endDate time.Time) ([]model.TrackRecord, error) {
+	if scanner.station == "" {
+		return scanner.dao.GetRecords(model.RecordTypes, startDate, endDate)
+	}
+	return scanner.dao.GetRecordsByStation(scanner.station, model.RecordTypes, startDate, endDate)
+}
@@ -0,0 +1,100 @@
+package admin
+
+import (
+	"bytes"
+	"errors"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var scanTrackRecords = []model.TrackRecord{
+	{StationId: "fm4", Timestamp: 1537700000, Type: "track",
+		Track: model.Track{"rhcp", "californication"}},
+	{StationId: "oe3", Timestamp: 1537703000, Type: "track", Track: model.Track{"mø", "final song"}},
+	{StationId: "fm4", Timestamp: 1537708000, Type: "track",
+		Track: model.Track{"cardi b", "i like it"}},
+}
+
+func TestNewScanner(t *testing.T) {
+	dao := datalayer.NewMemoryTrackRecordDAO()
+	if _, err := NewScanner(nil, "", time.Hour, Options{}); err == nil {
+		t.Error("NewScanner(nil): got no error, expected error")
+	}
+	if _, err := NewScanner(dao, "", 0, Options{}); err == nil {
+		t.Error("NewScanner(chunk 0): got no error, expected error")
+	}
+}
+
+func TestScanner_Scan(t *testing.T) {
+	dao := datalayer.NewMemoryTrackRecordDAO(scanTrackRecords...)
+	startDate, endDate := time.Unix(1537696800, 0), time.Unix(1537711199, 0)
+
+	var tests = []struct {
+		station        string
+		expectedChunks [][]model.TrackRecord
+	}{
+		{"", [][]model.TrackRecord{scanTrackRecords[:1], scanTrackRecords[1:2], {},
+			scanTrackRecords[2:]}},
+		{"fm4", [][]model.TrackRecord{scanTrackRecords[:1], {}, {}, scanTrackRecords[2:]}},
+	}
+
+	for _, test := range tests {
+		scanner, _ := NewScanner(dao, test.station, time.Hour, Options{})
+		chunks := [][]model.TrackRecord{}
+		err := scanner.Scan("test", startDate, endDate, func(trackRecords []model.TrackRecord) error {
+			chunks = append(chunks, trackRecords)
+			return nil
+		})
+		if err != nil || !reflect.DeepEqual(chunks, test.expectedChunks) {
+			t.Errorf("Scan(%q): got (%v, %v), expected (%v, nil)", test.station, chunks, err,
+				test.expectedChunks)
+		}
+	}
+}
+
+func TestScanner_Scan_Resume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scanner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	checkpoint, _ := NewCheckpoint(filepath.Join(dir, "checkpoint.json"))
+	progress := &bytes.Buffer{}
+	dao := datalayer.NewMemoryTrackRecordDAO(scanTrackRecords...)
+	scanner, _ := NewScanner(dao, "", time.Hour, Options{nil, checkpoint, progress})
+	startDate, endDate := time.Unix(1537696800, 0), time.Unix(1537711199, 0)
+
+	// the second chunk fails, the first one must not be processed again
+	var processed []model.TrackRecord
+	fail := true
+	process := func(trackRecords []model.TrackRecord) error {
+		if fail && len(processed) == 1 {
+			return errors.New("processing error")
+		}
+		processed = append(processed, trackRecords...)
+		return nil
+	}
+	if err := scanner.Scan("test", startDate, endDate, process); err == nil {
+		t.Fatal("Scan(): got no error, expected processing error")
+	}
+	fail = false
+	if err := scanner.Scan("test", startDate, endDate, process); err != nil {
+		t.Fatalf("Scan(): got err (%v), expected nil", err)
+	}
+	if !reflect.DeepEqual(processed, scanTrackRecords) {
+		t.Errorf("Scan(): processed %v, expected %v", processed, scanTrackRecords)
+	}
+	if !bytes.Contains(progress.Bytes(), []byte("resuming at")) {
+		t.Errorf("Scan(): got progress %q, expected scan to resume", progress.String())
+	}
+	if next, _ := checkpoint.Load("test"); !next.IsZero() {
+		t.Errorf("Scan(): got checkpoint %v after completion, expected none", next)
+	}
+}
@@ -0,0 +1,40 @@
+package admin
+
+import (
+	"errors"
+	"time"
+)
+
+// Throttle spaces out operations evenly, so long running tasks stay within the provisioned
+// capacity of the DynamoDB tables they read and write.
+type Throttle struct {
+	interval time.Duration
+	next     time.Time
+	now      func() time.Time
+	sleep    func(time.Duration)
+}
+
+// NewThrottle allows `perSecond` operations per second; 0 disables throttling.
+func NewThrottle(perSecond float64) (*Throttle, error) {
+	if perSecond < 0 {
+		return nil, errors.New("rate must not be negative")
+	}
+	var interval time.Duration
+	if perSecond > 0 {
+		interval = time.Duration(float64(time.Second) / perSecond)
+	}
+	return &Throttle{interval, time.Time{}, time.Now, time.Sleep}, nil
+}
+
+// Wait blocks until the next operation may be executed. A nil throttle never blocks.
+func (throttle *Throttle) Wait() {
+	if throttle == nil || throttle.interval == 0 {
+		return
+	}
+	now := throttle.now()
+	if throttle.next.After(now) {
+		throttle.sleep(throttle.next.Sub(now))
+		now = throttle.next
+	}
+	throttle.next = now.Add(throttle.interval)
+}
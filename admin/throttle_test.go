@@ -0,0 +1,39 @@
+package admin
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewThrottle(t *testing.T) {
+	if _, err := NewThrottle(-1); err == nil {
+		t.Error("NewThrottle(-1): got no error, expected error")
+	}
+}
+
+func TestThrottle_Wait(t *testing.T) {
+	now := time.Unix(1537701181, 0)
+	var sleeps []time.Duration
+	throttle, _ := NewThrottle(4)
+	throttle.now = func() time.Time { return now }
+	throttle.sleep = func(d time.Duration) {
+		sleeps = append(sleeps, d)
+		now = now.Add(d)
+	}
+
+	throttle.Wait()
+	throttle.Wait()
+	now = now.Add(100 * time.Millisecond)
+	throttle.Wait()
+	now = now.Add(time.Second)
+	throttle.Wait()
+
+	expected := []time.Duration{250 * time.Millisecond, 150 * time.Millisecond}
+	if !reflect.DeepEqual(sleeps, expected) {
+		t.Errorf("Wait(): got sleeps %v, expected %v", sleeps, expected)
+	}
+
+	var disabled *Throttle
+	disabled.Wait()
+}
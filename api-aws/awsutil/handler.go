@@ -0,0 +1,83 @@
+package awsutil
+
+import (
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"github.com/RadioCheckerApp/api/request"
+	"github.com/aws/aws-lambda-go/events"
+	"time"
+)
+
+// HandlerFunc serves a single API Gateway proxy request.
+type HandlerFunc func(apiRequest events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse,
+	error)
+
+// cacheControlled is implemented by workers whose results may be cached by clients, see
+// request.CachedWorker.
+type cacheControlled interface {
+	MaxAge() (maxAge time.Duration, immutable bool)
+}
+
+// WorkerFactory creates the worker serving an API Gateway proxy request, usually by passing the
+// request's parameters to one of the request.Create*Worker functions.
+type WorkerFactory func(apiRequest events.APIGatewayProxyRequest) (request.Worker, error)
+
+// NewHandler wraps the worker created by the factory into the default middleware (see
+// DefaultMiddleware) followed by the given endpoint specific middleware. The result can be passed
+// to lambda.Start directly.
+func NewHandler(factory WorkerFactory, middleware ...Middleware) HandlerFunc {
+	chain := append(DefaultMiddleware(), middleware...)
+	return Chain(dispatch(factory), chain...)
+}
+
+// dispatch serves the request by the factory's worker. Invalid requests and errors of known types
+// are answered with the matching status code (see errorStatusCode), other errors of the worker are
+// reported to the client by an unsuccessful APIResponseMessage, just like before. Raw data is
+// served as is. Successful results carry a `Last-Modified` header if they are derived from track
+// records and a `Cache-Control` header if the worker is cache controlled.
+func dispatch(factory WorkerFactory) HandlerFunc {
+	return func(apiRequest events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		worker, err := factory(apiRequest)
+		if err != nil {
+			return events.APIGatewayProxyResponse{}, NewStatusError(400, err)
+		}
+
+		data, err := worker.HandleRequest()
+		if statusCode := errorStatusCode(err); statusCode != 0 {
+			return events.APIGatewayProxyResponse{}, NewStatusError(statusCode, err)
+		}
+		var response events.APIGatewayProxyResponse
+		if raw, ok := data.(model.RawData); ok && err == nil {
+			response = CreateRawResponse(200, raw)
+		} else {
+			response = CreateResponse(200, model.NewAPIResponseMessage(data, err))
+		}
+		if err == nil {
+			setLastModifiedHeader(response.Headers, model.LastModified(data))
+		}
+		if cached, ok := worker.(cacheControlled); ok && err == nil {
+			maxAge, immutable := cached.MaxAge()
+			setCacheControlHeader(response.Headers, maxAge, immutable)
+		}
+		return response, nil
+	}
+}
+
+// errorStatusCode returns the status code answering the worker's error, 0 if the error is not
+// of a known type.
+func errorStatusCode(err error) int {
+	if _, ok := err.(request.DuplicateError); ok {
+		return 409
+	}
+	switch {
+	case err == nil:
+		return 0
+	case request.IsValidation(err):
+		return 400
+	case datalayer.IsNotFound(err), err == request.ErrUnknownStation:
+		return 404
+	case datalayer.IsAlreadyExists(err), err == request.ErrInactiveStation:
+		return 409
+	}
+	return 0
+}
@@ -0,0 +1,216 @@
+package awsutil
+
+import (
+	"errors"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"github.com/RadioCheckerApp/api/request"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"testing"
+	"time"
+)
+
+type mockWorker struct {
+	data interface{}
+	err  error
+}
+
+func (worker mockWorker) HandleRequest() (interface{}, error) {
+	return worker.data, worker.err
+}
+
+type cacheControlledWorker struct {
+	mockWorker
+	maxAge    time.Duration
+	immutable bool
+}
+
+func (worker cacheControlledWorker) MaxAge() (time.Duration, bool) {
+	return worker.maxAge, worker.immutable
+}
+
+type panickingWorker struct{}
+
+func (worker panickingWorker) HandleRequest() (interface{}, error) {
+	panic("nil map")
+}
+
+// conflictingDynamoDB fails every conditional write, as DynamoDB does for an existing item.
+type conflictingDynamoDB struct {
+	datalayer.DynamoDB
+}
+
+func (db conflictingDynamoDB) PutItem(*dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+	return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException,
+		"The conditional request failed", nil)
+}
+
+func TestNewHandler(t *testing.T) {
+	var tests = []struct {
+		factory        WorkerFactory
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			func(events.APIGatewayProxyRequest) (request.Worker, error) {
+				return mockWorker{test{"hello world"}, nil}, nil
+			},
+			200,
+			"{\"success\":true,\"data\":{\"payload\":\"hello world\"}}",
+		},
+		{
+			func(events.APIGatewayProxyRequest) (request.Worker, error) {
+				return mockWorker{nil, errors.New("worker error")}, nil
+			},
+			200,
+			"{\"success\":false,\"message\":\"worker error\"}",
+		},
+		{
+			func(events.APIGatewayProxyRequest) (request.Worker, error) {
+				return nil, errors.New("invalid request")
+			},
+			400,
+			"{\"success\":false,\"message\":\"invalid request\"}",
+		},
+		{
+			func(events.APIGatewayProxyRequest) (request.Worker, error) {
+				return mockWorker{nil, request.NewValidationError(errors.New("invalid"))}, nil
+			},
+			400,
+			"{\"success\":false,\"message\":\"invalid\"}",
+		},
+		{
+			func(events.APIGatewayProxyRequest) (request.Worker, error) {
+				return mockWorker{nil, datalayer.NewNotFoundError("not found")}, nil
+			},
+			404,
+			"{\"success\":false,\"message\":\"not found\"}",
+		},
+		{
+			func(events.APIGatewayProxyRequest) (request.Worker, error) {
+				return mockWorker{nil, request.ErrUnknownStation}, nil
+			},
+			404,
+			"{\"success\":false,\"message\":\"invalid stationId provided\"}",
+		},
+		{
+			func(events.APIGatewayProxyRequest) (request.Worker, error) {
+				return mockWorker{nil, datalayer.NewAlreadyExistsError("exists")}, nil
+			},
+			409,
+			"{\"success\":false,\"message\":\"exists\"}",
+		},
+		{
+			func(events.APIGatewayProxyRequest) (request.Worker, error) {
+				dao := datalayer.NewDDBStationDAO(conflictingDynamoDB{}, "stations")
+				return request.NewCreateStationWorker(dao, model.Station{ID: "fm4", Name: "FM4"})
+			},
+			409,
+			"{\"success\":false,\"message\":\"station fm4 already exists\"}",
+		},
+		{
+			func(events.APIGatewayProxyRequest) (request.Worker, error) {
+				return mockWorker{nil, request.DuplicateError{model.TrackRecord{
+					StationId: "fm4", Timestamp: 1537701181}}}, nil
+			},
+			409,
+			"{\"success\":false,\"message\":\"duplicate: play has been recorded already as " +
+				"/stations/fm4/tracks/1537701181\"}",
+		},
+		{
+			func(events.APIGatewayProxyRequest) (request.Worker, error) {
+				return panickingWorker{}, nil
+			},
+			500,
+			"{\"success\":false,\"message\":\"internal server error\"}",
+		},
+	}
+
+	for i, test := range tests {
+		apiRequest := events.APIGatewayProxyRequest{HTTPMethod: "GET", Path: "/meta"}
+		response, err := NewHandler(test.factory)(apiRequest)
+		if err != nil || response.StatusCode != test.expectedStatus ||
+			response.Body != test.expectedBody {
+			t.Errorf("#%d Handler(): got (%d, %s, %v), expected (%d, %s, nil)", i,
+				response.StatusCode, response.Body, err, test.expectedStatus, test.expectedBody)
+		}
+		if response.Headers["Access-Control-Allow-Origin"] != "*" ||
+			response.Headers["X-Request-Id"] == "" {
+			t.Errorf("#%d Handler(): got headers %v, expected CORS and request ID headers", i,
+				response.Headers)
+		}
+	}
+}
+
+func TestNewHandler_CacheControl(t *testing.T) {
+	var tests = []struct {
+		worker               request.Worker
+		expectedCacheControl string
+	}{
+		{mockWorker{test{"hello world"}, nil}, ""},
+		{cacheControlledWorker{mockWorker{test{"hello world"}, nil}, time.Minute, false},
+			"private, max-age=60"},
+		{cacheControlledWorker{mockWorker{test{"hello world"}, nil}, 0, true},
+			"private, max-age=31536000, immutable"},
+		{cacheControlledWorker{mockWorker{nil, errors.New("worker error")}, 0, true}, ""},
+	}
+
+	for i, test := range tests {
+		worker := test.worker
+		handler := NewHandler(func(events.APIGatewayProxyRequest) (request.Worker, error) {
+			return worker, nil
+		})
+		response, _ := handler(events.APIGatewayProxyRequest{HTTPMethod: "GET"})
+		if cacheControl := response.Headers["Cache-Control"]; cacheControl !=
+			test.expectedCacheControl {
+			t.Errorf("#%d Handler(): got Cache-Control %q, expected %q", i, cacheControl,
+				test.expectedCacheControl)
+		}
+	}
+}
+
+func TestNewHandler_LastModified(t *testing.T) {
+	lastModified := time.Date(2018, 9, 23, 10, 0, 0, 0, time.UTC)
+
+	var tests = []struct {
+		data                 interface{}
+		expectedLastModified string
+	}{
+		{test{"hello world"}, ""},
+		{model.CountedTracks{LastModified: lastModified}, "Sun, 23 Sep 2018 10:00:00 GMT"},
+		{model.CountedTracks{}, ""},
+		{model.TrackRecord{Timestamp: lastModified.Unix()}, "Sun, 23 Sep 2018 10:00:00 GMT"},
+	}
+
+	for i, test := range tests {
+		worker := mockWorker{test.data, nil}
+		handler := NewHandler(func(events.APIGatewayProxyRequest) (request.Worker, error) {
+			return worker, nil
+		})
+		response, _ := handler(events.APIGatewayProxyRequest{HTTPMethod: "GET"})
+		if response.Headers["Last-Modified"] != test.expectedLastModified {
+			t.Errorf("#%d Handler(): got Last-Modified %q, expected %q", i,
+				response.Headers["Last-Modified"], test.expectedLastModified)
+		}
+	}
+}
+
+func TestNewHandler_RawData(t *testing.T) {
+	body := []byte("stationId,airtime,type,artist,title\n")
+	handler := NewHandler(func(events.APIGatewayProxyRequest) (request.Worker, error) {
+		return mockWorker{model.RawData{"text/csv; charset=utf-8", body}, nil}, nil
+	})
+
+	response, err := handler(events.APIGatewayProxyRequest{HTTPMethod: "GET"})
+	if err != nil || response.StatusCode != 200 || response.Body != string(body) {
+		t.Errorf("Handler(): got (%d, %q, %v), expected (200, %q, nil)", response.StatusCode,
+			response.Body, err, body)
+	}
+	if response.Headers["Content-Type"] != "text/csv; charset=utf-8" ||
+		response.Headers["ETag"] == "" {
+		t.Errorf("Handler(): got headers %v, expected Content-Type of the data and an ETag",
+			response.Headers)
+	}
+}
@@ -0,0 +1,281 @@
+package awsutil
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/RadioCheckerApp/api/auth"
+	"github.com/RadioCheckerApp/api/model"
+	"github.com/RadioCheckerApp/api/request"
+	"github.com/aws/aws-lambda-go/events"
+	"log"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Middleware decorates a handler with functionality shared by several endpoints.
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// StatusError is an error which is answered with a specific HTTP status code rather than the
+// default `500 Internal Server Error`, see MapErrors.
+type StatusError struct {
+	StatusCode int
+	Err        error
+}
+
+func NewStatusError(statusCode int, err error) StatusError {
+	return StatusError{statusCode, err}
+}
+
+func (err StatusError) Error() string {
+	return err.Err.Error()
+}
+
+var errInternal = errors.New("internal server error")
+
+// now is replaced by tests.
+var now = time.Now
+
+// Chain wraps the handler into the middleware. The first middleware is the outermost one, i. e.
+// it sees the request first and the response last.
+func Chain(handler HandlerFunc, middleware ...Middleware) HandlerFunc {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
+
+// DefaultMiddleware returns the middleware every endpoint is wrapped into, outermost first.
+func DefaultMiddleware() []Middleware {
+	return []Middleware{CORS, RequestID, AccessLog, ConditionalGET, MapErrors, Recover}
+}
+
+// CORS makes sure every response, including error responses, may be read by browsers.
+func CORS(next HandlerFunc) HandlerFunc {
+	return func(apiRequest events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		response, err := next(apiRequest)
+		if response.Headers == nil {
+			response.Headers = make(map[string]string)
+		}
+		if _, ok := response.Headers["Access-Control-Allow-Origin"]; !ok {
+			response.Headers["Access-Control-Allow-Origin"] = "*"
+		}
+		return response, err
+	}
+}
+
+// RequestID makes sure every request carries an ID, which is reported to the client in the
+// `X-Request-Id` header. API Gateway's request ID is used if available.
+func RequestID(next HandlerFunc) HandlerFunc {
+	return func(apiRequest events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		if apiRequest.RequestContext.RequestID == "" {
+			apiRequest.RequestContext.RequestID = newRequestID()
+		}
+		response, err := next(apiRequest)
+		if response.Headers == nil {
+			response.Headers = make(map[string]string)
+		}
+		response.Headers["X-Request-Id"] = apiRequest.RequestContext.RequestID
+		return response, err
+	}
+}
+
+func newRequestID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return strconv.FormatInt(now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(id)
+}
+
+type accessLogEntry struct {
+	RequestID   string  `json:"requestId"`
+	Method      string  `json:"method"`
+	Path        string  `json:"path"`
+	Status      int     `json:"status"`
+	LatencyMs   float64 `json:"latencyMs"`
+	PrincipalID string  `json:"principalId,omitempty"`
+	SourceIP    string  `json:"sourceIp,omitempty"`
+	Function    string  `json:"function"`
+	// _aws turns the entry into a CloudWatch embedded metric, which records the latency per
+	// function without any further API calls.
+	AWS embeddedMetric `json:"_aws"`
+}
+
+type embeddedMetric struct {
+	Timestamp         int64             `json:"Timestamp"`
+	CloudWatchMetrics []metricDirective `json:"CloudWatchMetrics"`
+}
+
+type metricDirective struct {
+	Namespace  string              `json:"Namespace"`
+	Dimensions [][]string          `json:"Dimensions"`
+	Metrics    []map[string]string `json:"Metrics"`
+}
+
+// AccessLog writes one JSON line per request, which doubles as latency metric.
+func AccessLog(next HandlerFunc) HandlerFunc {
+	return func(apiRequest events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		start := now()
+		response, err := next(apiRequest)
+		latency := now().Sub(start)
+
+		entry := accessLogEntry{
+			RequestID:   apiRequest.RequestContext.RequestID,
+			Method:      apiRequest.HTTPMethod,
+			Path:        apiRequest.Path,
+			Status:      response.StatusCode,
+			LatencyMs:   float64(latency) / float64(time.Millisecond),
+			PrincipalID: GetPrincipalID(apiRequest),
+			SourceIP:    apiRequest.RequestContext.Identity.SourceIP,
+			Function:    os.Getenv("AWS_LAMBDA_FUNCTION_NAME"),
+			AWS: embeddedMetric{
+				Timestamp: start.UnixNano() / int64(time.Millisecond),
+				CloudWatchMetrics: []metricDirective{{
+					Namespace:  "RadioCheckerAPI",
+					Dimensions: [][]string{{"function"}},
+					Metrics:    []map[string]string{{"Name": "latencyMs", "Unit": "Milliseconds"}},
+				}},
+			},
+		}
+		encodedEntry, _ := json.Marshal(entry)
+		log.Println(string(encodedEntry))
+
+		return response, err
+	}
+}
+
+// ConditionalGET answers conditional GET requests with `304 Not Modified` and no body if the
+// client's copy of the response is still valid. As required by RFC 7232, `If-Modified-Since` is
+// ignored if the request contains `If-None-Match`.
+func ConditionalGET(next HandlerFunc) HandlerFunc {
+	return func(apiRequest events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		response, err := next(apiRequest)
+		if err != nil || apiRequest.HTTPMethod != "GET" || response.StatusCode != 200 ||
+			!notModified(apiRequest, response.Headers) {
+			return response, err
+		}
+
+		delete(response.Headers, "Content-Type")
+		return events.APIGatewayProxyResponse{Headers: response.Headers, StatusCode: 304}, nil
+	}
+}
+
+func notModified(apiRequest events.APIGatewayProxyRequest, headers map[string]string) bool {
+	if ifNoneMatch := GetHeader(apiRequest, "If-None-Match"); ifNoneMatch != "" {
+		return matchesETag(ifNoneMatch, headers["ETag"])
+	}
+
+	ifModifiedSince := GetHeader(apiRequest, "If-Modified-Since")
+	if ifModifiedSince == "" || headers["Last-Modified"] == "" {
+		return false
+	}
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+	lastModified, err := http.ParseTime(headers["Last-Modified"])
+	return err == nil && !lastModified.After(since)
+}
+
+// matchesETag reports whether the `If-None-Match` header lists the ETag. The comparison is weak,
+// i. e. the `W/` prefix is ignored.
+func matchesETag(ifNoneMatch, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// MapErrors turns errors returned by the handler into responses. A Lambda function returning an
+// error makes API Gateway respond with `502 Bad Gateway` and an opaque message instead.
+func MapErrors(next HandlerFunc) HandlerFunc {
+	return func(apiRequest events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		response, err := next(apiRequest)
+		if err == nil {
+			return response, nil
+		}
+
+		var errorResponse events.APIGatewayProxyResponse
+		if statusErr, ok := err.(StatusError); ok {
+			responseMessage := model.NewAPIResponseMessage(nil, statusErr.Err)
+			errorResponse = CreateResponse(statusErr.StatusCode, responseMessage)
+		} else {
+			log.Printf("ERROR: request `%s` failed: %v", apiRequest.RequestContext.RequestID, err)
+			responseMessage := model.NewAPIResponseMessage(nil, errInternal)
+			errorResponse = CreateResponse(500, responseMessage)
+		}
+
+		// keep the headers set by inner middleware, e. g. RateLimit
+		for name, value := range response.Headers {
+			if _, ok := errorResponse.Headers[name]; !ok {
+				errorResponse.Headers[name] = value
+			}
+		}
+		return errorResponse, nil
+	}
+}
+
+// Recover turns a panicking handler into an error.
+func Recover(next HandlerFunc) HandlerFunc {
+	return func(apiRequest events.APIGatewayProxyRequest) (response events.APIGatewayProxyResponse,
+		err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("PANIC: %v\n%s", r, debug.Stack())
+				response = events.APIGatewayProxyResponse{}
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+		return next(apiRequest)
+	}
+}
+
+// RateLimit returns middleware counting the request against its client's rate limit (see
+// CheckRateLimit) and reporting the limit's state in the `X-RateLimit-*` headers. The limiter is
+// meant to be shared by all requests served by the Lambda container, see container.RateLimiter.
+func RateLimit(limiter *request.RateLimiter) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(apiRequest events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse,
+			error) {
+			rateLimit, err := CheckRateLimit(limiter, apiRequest)
+			if err != nil {
+				responseMessage := model.NewAPIResponseMessage(nil, err)
+				return CreateResponse(429, responseMessage, rateLimit), nil
+			}
+
+			response, err := next(apiRequest)
+			if response.Headers == nil {
+				response.Headers = make(map[string]string)
+			}
+			setRateLimitHeaders(response.Headers, rateLimit)
+			return response, err
+		}
+	}
+}
+
+// ContentDigest verifies the body of HMAC signed requests. The authorizer does not see the
+// request body, hence the content digest can only be verified here. A body which does not match
+// the signature is answered with `401 Unauthorized`, just like a request the authorizer denies.
+func ContentDigest(next HandlerFunc) HandlerFunc {
+	return func(apiRequest events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		authorizationHeader := GetHeader(apiRequest, "Authorization")
+		err := auth.VerifyContentDigest(authorizationHeader, []byte(apiRequest.Body))
+		if err != nil {
+			return events.APIGatewayProxyResponse{}, NewStatusError(401, err)
+		}
+		return next(apiRequest)
+	}
+}
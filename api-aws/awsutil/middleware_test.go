@@ -0,0 +1,215 @@
+package awsutil
+
+import (
+	"errors"
+	"github.com/RadioCheckerApp/api/model"
+	"github.com/RadioCheckerApp/api/request"
+	"github.com/aws/aws-lambda-go/events"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func respondWith(statusCode int, err error) HandlerFunc {
+	return func(apiRequest events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		if err != nil {
+			return events.APIGatewayProxyResponse{}, err
+		}
+		return events.APIGatewayProxyResponse{StatusCode: statusCode}, nil
+	}
+}
+
+func TestChain(t *testing.T) {
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(apiRequest events.APIGatewayProxyRequest) (events.
+				APIGatewayProxyResponse, error) {
+				order = append(order, name)
+				return next(apiRequest)
+			}
+		}
+	}
+
+	Chain(respondWith(200, nil), trace("a"), trace("b"), trace("c"))(
+		events.APIGatewayProxyRequest{})
+
+	if expected := []string{"a", "b", "c"}; !reflect.DeepEqual(order, expected) {
+		t.Errorf("Chain(): got order %v, expected %v", order, expected)
+	}
+}
+
+func TestMapErrors(t *testing.T) {
+	var tests = []struct {
+		handler        HandlerFunc
+		expectedStatus int
+		expectedBody   string
+	}{
+		{respondWith(200, nil), 200, ""},
+		{respondWith(0, NewStatusError(404, errors.New("station does not exist"))), 404,
+			"{\"success\":false,\"message\":\"station does not exist\"}"},
+		{respondWith(0, errors.New("connection reset")), 500,
+			"{\"success\":false,\"message\":\"internal server error\"}"},
+	}
+
+	for i, test := range tests {
+		response, err := MapErrors(test.handler)(events.APIGatewayProxyRequest{})
+		if err != nil || response.StatusCode != test.expectedStatus ||
+			response.Body != test.expectedBody {
+			t.Errorf("#%d MapErrors(): got (%d, %s, %v), expected (%d, %s, nil)", i,
+				response.StatusCode, response.Body, err, test.expectedStatus, test.expectedBody)
+		}
+	}
+}
+
+func TestMapErrors_KeepHeaders(t *testing.T) {
+	handler := MapErrors(func(events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse,
+		error) {
+		return events.APIGatewayProxyResponse{Headers: map[string]string{
+			"X-RateLimit-Limit": "60",
+			"Content-Type":      "text/csv",
+		}}, NewStatusError(400, errors.New("invalid request"))
+	})
+
+	response, _ := handler(events.APIGatewayProxyRequest{})
+	if response.Headers["X-RateLimit-Limit"] != "60" ||
+		response.Headers["Content-Type"] != "application/json" {
+		t.Errorf("MapErrors(): got headers %v, expected rate limit and JSON content type",
+			response.Headers)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	handler := Recover(func(events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse,
+		error) {
+		var station *model.Station
+		return events.APIGatewayProxyResponse{Body: station.Name}, nil
+	})
+
+	if _, err := handler(events.APIGatewayProxyRequest{}); err == nil {
+		t.Error("Recover(): got no error, expected error")
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	var tests = []struct {
+		requestID string
+		expected  string
+	}{
+		{"c6af9ac6-7b61-11e6-9a41-93e8deadbeef", "c6af9ac6-7b61-11e6-9a41-93e8deadbeef"},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		apiRequest := events.APIGatewayProxyRequest{
+			RequestContext: events.APIGatewayProxyRequestContext{RequestID: test.requestID},
+		}
+		response, _ := RequestID(respondWith(200, nil))(apiRequest)
+		requestID := response.Headers["X-Request-Id"]
+		if requestID == "" || (test.expected != "" && requestID != test.expected) {
+			t.Errorf("RequestID(%q): got `%s`, expected `%s`", test.requestID, requestID,
+				test.expected)
+		}
+	}
+}
+
+func TestCORS(t *testing.T) {
+	response, _ := CORS(respondWith(500, nil))(events.APIGatewayProxyRequest{})
+	if response.Headers["Access-Control-Allow-Origin"] != "*" {
+		t.Errorf("CORS(): got headers %v, expected `Access-Control-Allow-Origin: *`",
+			response.Headers)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	apiRequest := events.APIGatewayProxyRequest{RequestContext: events.
+		APIGatewayProxyRequestContext{Authorizer: map[string]interface{}{
+		"principalId": "rate-limit-test", "tier": "free",
+	}}}
+
+	limiter := request.NewRateLimiter(map[model.Tier]request.RateLimitPolicy{
+		model.TierFree: {60, time.Second},
+	})
+	response, err := RateLimit(limiter)(respondWith(200, nil))(apiRequest)
+	if err != nil || response.StatusCode != 200 || response.Headers["X-RateLimit-Limit"] != "60" ||
+		response.Headers["X-RateLimit-Remaining"] != "59" {
+		t.Errorf("RateLimit(): got (%d, %v, %v), expected (200, limit 60, remaining 59)",
+			response.StatusCode, response.Headers, err)
+	}
+}
+
+func TestContentDigest(t *testing.T) {
+	var tests = []struct {
+		authorization  string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"Bearer oe3-token", 200, ""},
+		{"RC-HMAC-SHA256 KeyId=oe3,Timestamp=1537701181,ContentSHA256=abc,Signature=def", 401,
+			"{\"success\":false,\"message\":\"request body does not match the signed content digest\"}"},
+	}
+
+	for _, test := range tests {
+		apiRequest := events.APIGatewayProxyRequest{
+			Headers: map[string]string{"Authorization": test.authorization},
+			Body:    "{\"artist\":\"RHCP\",\"title\":\"Californication\"}",
+		}
+		response, err := MapErrors(ContentDigest(respondWith(200, nil)))(apiRequest)
+		if err != nil || response.StatusCode != test.expectedStatus ||
+			response.Body != test.expectedBody {
+			t.Errorf("ContentDigest(%q): got (%d, %s, %v), expected (%d, %s, nil)",
+				test.authorization, response.StatusCode, response.Body, err, test.expectedStatus,
+				test.expectedBody)
+		}
+	}
+}
+
+func TestConditionalGET(t *testing.T) {
+	respondWithChart := func(apiRequest events.APIGatewayProxyRequest) (events.
+		APIGatewayProxyResponse, error) {
+		response := CreateResponse(200, model.APIResponseMessage{true, test{"chart"}, ""})
+		response.Headers["Last-Modified"] = "Sun, 23 Sep 2018 10:00:00 GMT"
+		return response, nil
+	}
+	etag := CreateResponse(200, model.APIResponseMessage{true, test{"chart"}, ""}).Headers["ETag"]
+
+	var tests = []struct {
+		method         string
+		headers        map[string]string
+		handler        HandlerFunc
+		expectedStatus int
+	}{
+		{"GET", nil, respondWithChart, 200},
+		{"GET", map[string]string{"If-None-Match": etag}, respondWithChart, 304},
+		{"GET", map[string]string{"if-none-match": `"other", W/` + etag}, respondWithChart, 304},
+		{"GET", map[string]string{"If-None-Match": "*"}, respondWithChart, 304},
+		{"GET", map[string]string{"If-None-Match": `"other"`}, respondWithChart, 200},
+		{"POST", map[string]string{"If-None-Match": etag}, respondWithChart, 200},
+		{"GET", map[string]string{"If-Modified-Since": "Sun, 23 Sep 2018 10:00:00 GMT"},
+			respondWithChart, 304},
+		{"GET", map[string]string{"If-Modified-Since": "Sun, 23 Sep 2018 11:00:00 GMT"},
+			respondWithChart, 304},
+		{"GET", map[string]string{"If-Modified-Since": "Sun, 23 Sep 2018 09:59:59 GMT"},
+			respondWithChart, 200},
+		{"GET", map[string]string{"If-Modified-Since": "yesterday"}, respondWithChart, 200},
+		// If-None-Match takes precedence
+		{"GET", map[string]string{"If-None-Match": `"other"`,
+			"If-Modified-Since": "Sun, 23 Sep 2018 11:00:00 GMT"}, respondWithChart, 200},
+		// unsuccessful responses carry no ETag
+		{"GET", map[string]string{"If-None-Match": "*"}, respondWith(500, nil), 500},
+	}
+
+	for i, test := range tests {
+		apiRequest := events.APIGatewayProxyRequest{HTTPMethod: test.method, Headers: test.headers}
+		response, err := ConditionalGET(test.handler)(apiRequest)
+		if err != nil || response.StatusCode != test.expectedStatus {
+			t.Errorf("#%d ConditionalGET(%s, %v): got (%d, %v), expected (%d, nil)", i,
+				test.method, test.headers, response.StatusCode, err, test.expectedStatus)
+			continue
+		}
+		if response.StatusCode == 304 && (response.Body != "" || response.Headers["ETag"] != etag) {
+			t.Errorf("#%d ConditionalGET(%s, %v): got (%s, %v), expected no body and ETag %s", i,
+				test.method, test.headers, response.Body, response.Headers, etag)
+		}
+	}
+}
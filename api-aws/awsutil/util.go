@@ -1,22 +1,124 @@
 package awsutil
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"github.com/RadioCheckerApp/api/model"
+	"github.com/RadioCheckerApp/api/request"
 	"github.com/aws/aws-lambda-go/events"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
-func CreateResponse(statusCode int, message model.APIResponseMessage) events.
-	APIGatewayProxyResponse {
+// GetPrincipalID returns the principal ID the request's custom authorizer has determined, or an
+// empty string if the endpoint is not protected by a custom authorizer.
+func GetPrincipalID(apiRequest events.APIGatewayProxyRequest) string {
+	principalID, _ := apiRequest.RequestContext.Authorizer["principalId"].(string)
+	return principalID
+}
+
+// GetHeader returns the value of the request header `name`. API Gateway passes header names on as
+// sent by the client, hence the lookup is case-insensitive.
+func GetHeader(apiRequest events.APIGatewayProxyRequest, name string) string {
+	for key, value := range apiRequest.Headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
+
+// GetTier returns the client tier the read authorizer has determined. Requests without a tier are
+// treated as free requests.
+func GetTier(apiRequest events.APIGatewayProxyRequest) model.Tier {
+	tier, _ := apiRequest.RequestContext.Authorizer["tier"].(string)
+	if tier == "" {
+		return model.TierFree
+	}
+	return model.Tier(tier)
+}
+
+// CheckRateLimit counts the request against its client's rate limit. Requests are attributed to
+// the authorized principal or, lacking one, to the caller's source IP.
+func CheckRateLimit(limiter *request.RateLimiter,
+	apiRequest events.APIGatewayProxyRequest) (model.RateLimit, error) {
+	clientID := GetPrincipalID(apiRequest)
+	if clientID == "" {
+		clientID = apiRequest.RequestContext.Identity.SourceIP
+	}
+	return limiter.Allow(clientID, GetTier(apiRequest))
+}
+
+// CreateResponse wraps the message into an API Gateway response. Successful messages carry an
+// `ETag` derived from the encoded message. If a rate limit is passed, its state is reported in the
+// `X-RateLimit-*` headers.
+func CreateResponse(statusCode int, message model.APIResponseMessage,
+	rateLimit ...model.RateLimit) events.APIGatewayProxyResponse {
 	encodedMessage, _ := json.Marshal(message)
+	headers := map[string]string{
+		"Content-Type": "application/json",
+		// required for CORS support,
+		// see https://github.com/serverless/serverless/issues/1955#issuecomment-266235353
+		"Access-Control-Allow-Origin": "*",
+	}
+	if message.Success {
+		headers["ETag"] = createETag(encodedMessage)
+	}
+	if len(rateLimit) > 0 {
+		setRateLimitHeaders(headers, rateLimit[0])
+	}
+	return events.APIGatewayProxyResponse{
+		Headers:    headers,
+		Body:       string(encodedMessage),
+		StatusCode: statusCode,
+	}
+}
+
+// CreateRawResponse serves the data as is, e. g. an export of track records. Like successful
+// messages, it carries an `ETag` derived from the body.
+func CreateRawResponse(statusCode int, data model.RawData) events.APIGatewayProxyResponse {
 	return events.APIGatewayProxyResponse{
 		Headers: map[string]string{
-			"Content-Type": "application/json",
-			// required for CORS support,
-			// see https://github.com/serverless/serverless/issues/1955#issuecomment-266235353
+			"Content-Type":                data.ContentType,
 			"Access-Control-Allow-Origin": "*",
+			"ETag":                        createETag(data.Body),
 		},
-		Body:       string(encodedMessage),
+		Body:       string(data.Body),
 		StatusCode: statusCode,
 	}
 }
+
+func setRateLimitHeaders(headers map[string]string, rateLimit model.RateLimit) {
+	headers["X-RateLimit-Limit"] = strconv.Itoa(rateLimit.Limit)
+	headers["X-RateLimit-Remaining"] = strconv.Itoa(rateLimit.Remaining)
+	headers["X-RateLimit-Reset"] = strconv.FormatInt(rateLimit.Reset, 10)
+}
+
+func createETag(encodedMessage []byte) string {
+	hash := sha256.Sum256(encodedMessage)
+	return `"` + hex.EncodeToString(hash[:16]) + `"`
+}
+
+func setLastModifiedHeader(headers map[string]string, lastModified time.Time) {
+	if !lastModified.IsZero() {
+		headers["Last-Modified"] = lastModified.UTC().Format(http.TimeFormat)
+	}
+}
+
+// immutableMaxAge is the maximum age of immutable responses, one year as recommended by RFC 7234.
+const immutableMaxAge = 365 * 24 * time.Hour
+
+// setCacheControlHeader allows clients to cache the response. Responses require an API key, hence
+// shared caches must not store them.
+func setCacheControlHeader(headers map[string]string, maxAge time.Duration, immutable bool) {
+	if immutable {
+		headers["Cache-Control"] = fmt.Sprintf("private, max-age=%d, immutable",
+			int64(immutableMaxAge/time.Second))
+		return
+	}
+	headers["Cache-Control"] = fmt.Sprintf("private, max-age=%d", int64(maxAge/time.Second))
+}
@@ -24,6 +24,7 @@ func TestCreateResponse(t *testing.T) {
 				Headers: map[string]string{
 					"Content-Type":                "application/json",
 					"Access-Control-Allow-Origin": "*",
+					"ETag":                        `"9f43173a4aab77e6f2c27fb920d6aa46"`,
 				},
 				Body:       "{\"success\":true,\"data\":{\"payload\":\"hello world\"}}",
 				StatusCode: 200,
@@ -50,3 +51,84 @@ func TestCreateResponse(t *testing.T) {
 		}
 	}
 }
+
+func TestCreateResponse_RateLimit(t *testing.T) {
+	response := CreateResponse(429, model.APIResponseMessage{false, nil, "rate limit exceeded"},
+		model.RateLimit{60, 0, 1537701241})
+
+	expectedHeaders := map[string]string{
+		"Content-Type":                "application/json",
+		"Access-Control-Allow-Origin": "*",
+		"X-RateLimit-Limit":           "60",
+		"X-RateLimit-Remaining":       "0",
+		"X-RateLimit-Reset":           "1537701241",
+	}
+	if response.StatusCode != 429 || !reflect.DeepEqual(response.Headers, expectedHeaders) {
+		t.Errorf("CreateResponse(): got (%d, %v), expected (429, %v)", response.StatusCode,
+			response.Headers, expectedHeaders)
+	}
+}
+
+func TestGetTier(t *testing.T) {
+	var tests = []struct {
+		apiRequest   events.APIGatewayProxyRequest
+		expectedTier model.Tier
+	}{
+		{
+			events.APIGatewayProxyRequest{RequestContext: events.APIGatewayProxyRequestContext{
+				Authorizer: map[string]interface{}{"principalId": "radio-app", "tier": "partner"},
+			}},
+			model.TierPartner,
+		},
+		{events.APIGatewayProxyRequest{}, model.TierFree},
+	}
+
+	for _, test := range tests {
+		if tier := GetTier(test.apiRequest); tier != test.expectedTier {
+			t.Errorf("GetTier(%v): got `%s`, expected `%s`", test.apiRequest, tier,
+				test.expectedTier)
+		}
+	}
+}
+
+func TestGetPrincipalID(t *testing.T) {
+	var tests = []struct {
+		apiRequest  events.APIGatewayProxyRequest
+		expectedStr string
+	}{
+		{
+			events.APIGatewayProxyRequest{RequestContext: events.APIGatewayProxyRequestContext{
+				Authorizer: map[string]interface{}{"principalId": "oe3-crawler"},
+			}},
+			"oe3-crawler",
+		},
+		{events.APIGatewayProxyRequest{}, ""},
+	}
+
+	for _, test := range tests {
+		if principalID := GetPrincipalID(test.apiRequest); principalID != test.expectedStr {
+			t.Errorf("GetPrincipalID(%v): got `%s`, expected `%s`", test.apiRequest, principalID,
+				test.expectedStr)
+		}
+	}
+}
+
+func TestGetHeader(t *testing.T) {
+	var tests = []struct {
+		headers     map[string]string
+		expectedStr string
+	}{
+		{map[string]string{"Authorization": "Bearer abc"}, "Bearer abc"},
+		{map[string]string{"authorization": "Bearer abc"}, "Bearer abc"},
+		{map[string]string{"Content-Type": "application/json"}, ""},
+		{nil, ""},
+	}
+
+	for _, test := range tests {
+		apiRequest := events.APIGatewayProxyRequest{Headers: test.headers}
+		if value := GetHeader(apiRequest, "Authorization"); value != test.expectedStr {
+			t.Errorf("GetHeader(%v, \"Authorization\"): got `%s`, expected `%s`", test.headers,
+				value, test.expectedStr)
+		}
+	}
+}
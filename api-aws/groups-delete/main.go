@@ -0,0 +1,20 @@
+package main
+
+import (
+	"github.com/RadioCheckerApp/api/api-aws/awsutil"
+	"github.com/RadioCheckerApp/api/config"
+	"github.com/RadioCheckerApp/api/container"
+	"github.com/RadioCheckerApp/api/request"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+var deps = container.MustNew(config.MustLoad())
+
+func newWorker(apiRequest events.APIGatewayProxyRequest) (request.Worker, error) {
+	return request.CreateDeleteStationGroupWorker(deps.StationGroupDAO(), apiRequest.PathParameters)
+}
+
+func main() {
+	lambda.Start(awsutil.NewHandler(newWorker))
+}
@@ -2,19 +2,19 @@ package main
 
 import (
 	"github.com/RadioCheckerApp/api/api-aws/awsutil"
-	"github.com/RadioCheckerApp/api/model"
+	"github.com/RadioCheckerApp/api/config"
+	"github.com/RadioCheckerApp/api/container"
 	"github.com/RadioCheckerApp/api/request"
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 )
 
-func Handler() (events.APIGatewayProxyResponse, error) {
-	worker := request.CreateMetaWorker()
-	message, err := worker.HandleRequest()
-	responseMessage := model.NewAPIResponseMessage(message, err)
-	return awsutil.CreateResponse(200, responseMessage), nil
+var deps = container.MustNew(config.MustLoad())
+
+func newWorker(apiRequest events.APIGatewayProxyRequest) (request.Worker, error) {
+	return request.CreateMetaWorker(), nil
 }
 
 func main() {
-	lambda.Start(Handler)
+	lambda.Start(awsutil.NewHandler(newWorker, awsutil.RateLimit(deps.RateLimiter())))
 }
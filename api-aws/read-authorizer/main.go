@@ -0,0 +1,23 @@
+package main
+
+import (
+	"github.com/RadioCheckerApp/api/auth"
+	"github.com/RadioCheckerApp/api/config"
+	"github.com/RadioCheckerApp/api/container"
+	"github.com/aws/aws-lambda-go/lambda"
+	"log"
+)
+
+func main() {
+	deps := container.MustNew(config.MustLoad())
+	clientDAO, err := deps.ClientDAO()
+	if err != nil {
+		log.Fatalf("ERROR: %v", err)
+	}
+
+	authorizer, err := auth.NewReadAuthorizer(clientDAO)
+	if err != nil {
+		log.Fatalf("ERROR: %v", err)
+	}
+	lambda.Start(authorizer.Authorize)
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"github.com/RadioCheckerApp/api/api-aws/awsutil"
+	"github.com/RadioCheckerApp/api/config"
+	"github.com/RadioCheckerApp/api/container"
+	"github.com/RadioCheckerApp/api/request"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+var deps = container.MustNew(config.MustLoad())
+
+func newWorker(apiRequest events.APIGatewayProxyRequest) (request.Worker, error) {
+	worker, err := request.CreateAirtimeWorker(deps.TrackRecordDAO(), apiRequest.PathParameters,
+		apiRequest.QueryStringParameters, deps.Settings())
+	if err != nil {
+		return nil, err
+	}
+	return deps.ResponseCache().Wrap(worker), nil
+}
+
+func main() {
+	lambda.Start(awsutil.NewHandler(newWorker, awsutil.RateLimit(deps.RateLimiter())))
+}
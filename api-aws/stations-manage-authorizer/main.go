@@ -0,0 +1,16 @@
+package main
+
+import (
+	"github.com/RadioCheckerApp/api/auth"
+	"github.com/RadioCheckerApp/api/config"
+	"github.com/aws/aws-lambda-go/lambda"
+	"log"
+)
+
+func main() {
+	authorizer, err := auth.NewManageAuthorizer(config.MustLoad().Auth.StationsManageToken)
+	if err != nil {
+		log.Fatalf("ERROR: %v", err)
+	}
+	lambda.Start(authorizer.Authorize)
+}
@@ -2,48 +2,27 @@ package main
 
 import (
 	"github.com/RadioCheckerApp/api/api-aws/awsutil"
-	"github.com/RadioCheckerApp/api/datalayer"
-	"github.com/RadioCheckerApp/api/model"
+	"github.com/RadioCheckerApp/api/config"
+	"github.com/RadioCheckerApp/api/container"
 	"github.com/RadioCheckerApp/api/request"
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/dynamodb"
-	"os"
 )
 
-func Handler(apiRequest events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	// AWS config implicitly defined by serverless.yml
-	dbSession, _ := session.NewSession(&aws.Config{})
+var deps = container.MustNew(config.MustLoad())
 
-	db := dynamodb.New(dbSession)
-	trackRecordsDAO := datalayer.NewDDBTrackRecordDAO(
-		db,
-		os.Getenv("TRACKRECORDS_TABLE"),
-		os.Getenv("TRACKRECORDS_TABLE_GSI_TYPE_AIRTIME"),
-	)
-	stationDAO := datalayer.NewDDBStationDAO(
-		db,
-		os.Getenv("STATIONS_TABLE"),
-	)
-
-	worker, err := request.CreateCreateTrackWorker(
-		trackRecordsDAO,
-		stationDAO,
+func newWorker(apiRequest events.APIGatewayProxyRequest) (request.Worker, error) {
+	return request.CreateCreateTrackWorker(
+		deps.TrackRecordDAO(),
+		deps.StationCache(),
+		deps.Rollups(),
 		apiRequest.PathParameters,
 		[]byte(apiRequest.Body),
+		awsutil.GetPrincipalID(apiRequest),
+		deps.Settings(),
 	)
-	if err != nil {
-		responseMessage := model.NewAPIResponseMessage(nil, err)
-		return awsutil.CreateResponse(200, responseMessage), nil
-	}
-
-	tracks, err := worker.HandleRequest()
-	responseMessage := model.NewAPIResponseMessage(tracks, err)
-	return awsutil.CreateResponse(200, responseMessage), nil
 }
 
 func main() {
-	lambda.Start(Handler)
+	lambda.Start(awsutil.NewHandler(newWorker, awsutil.ContentDigest))
 }
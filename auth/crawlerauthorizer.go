@@ -0,0 +1,173 @@
+package auth
+
+import (
+	"errors"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"github.com/aws/aws-lambda-go/events"
+	"log"
+	"strings"
+	"time"
+)
+
+// TracksWriteScope is the JWT scope required to create track records.
+const TracksWriteScope = "tracks:write"
+
+// CrawlerAuthorizer grants crawlers write access to the track records of the stations they are
+// bound to. Crawlers authenticate by one of
+//   - a bearer token looked up in the credential store,
+//   - a bearer JWT signed with one of their keys,
+//   - an HMAC signed request (see HMACScheme), which is accepted once.
+type CrawlerAuthorizer struct {
+	dao        datalayer.CredentialDAO
+	signatures datalayer.SignatureDAO
+	keyRing    KeyRing
+	audience   string
+	now        func() time.Time
+}
+
+// crawlerIdentity is the outcome of a successful authentication.
+type crawlerIdentity struct {
+	principalID string
+	stations    []string
+}
+
+func NewCrawlerAuthorizer(dao datalayer.CredentialDAO, signatures datalayer.SignatureDAO,
+	keyRing KeyRing, audience string) (CrawlerAuthorizer, error) {
+	if dao == nil {
+		return CrawlerAuthorizer{}, errors.New("dao must not be nil")
+	}
+	if signatures == nil {
+		return CrawlerAuthorizer{}, errors.New("signatures must not be nil")
+	}
+	if audience == "" {
+		return CrawlerAuthorizer{}, errors.New("audience must not be empty")
+	}
+	return CrawlerAuthorizer{dao, signatures, keyRing, audience, time.Now}, nil
+}
+
+func (authorizer CrawlerAuthorizer) Authorize(authRequest events.
+	APIGatewayCustomAuthorizerRequest) (events.APIGatewayCustomAuthorizerResponse, error) {
+	identity, err := authorizer.authenticate(authRequest)
+	if err != nil {
+		return unauthorized(authRequest, err.Error())
+	}
+
+	if len(identity.stations) == 0 {
+		return unauthorized(authRequest, "no stations granted to `"+identity.principalID+"`")
+	}
+
+	resourceArns, err := BuildStationResourceArns(authRequest.MethodArn, identity.stations)
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		return unauthorized(authRequest, "invalid method ARN")
+	}
+
+	log.Printf("AUTHORIZE REQUEST: Type: `%s`, Token: `%s`, Principal: `%s`, ARNs: `%v`",
+		authRequest.Type, RedactToken(authRequest.AuthorizationToken), identity.principalID,
+		resourceArns)
+
+	return events.APIGatewayCustomAuthorizerResponse{
+		PrincipalID:    identity.principalID,
+		PolicyDocument: generatePolicy("Allow", resourceArns),
+	}, nil
+}
+
+func (authorizer CrawlerAuthorizer) authenticate(authRequest events.
+	APIGatewayCustomAuthorizerRequest) (crawlerIdentity, error) {
+	if IsHMACAuthorization(authRequest.AuthorizationToken) {
+		return authorizer.authenticateHMAC(authRequest)
+	}
+
+	token, err := ExtractBearerToken(authRequest.AuthorizationToken)
+	if err != nil {
+		return crawlerIdentity{}, errors.New("missing bearer token")
+	}
+	if IsJWT(token) {
+		return authorizer.authenticateJWT(token)
+	}
+	return authorizer.authenticateToken(token)
+}
+
+func (authorizer CrawlerAuthorizer) authenticateHMAC(authRequest events.
+	APIGatewayCustomAuthorizerRequest) (crawlerIdentity, error) {
+	authorization, err := ParseHMACAuthorization(authRequest.AuthorizationToken)
+	if err != nil {
+		return crawlerIdentity{}, err
+	}
+	method, path, err := splitMethodArn(authRequest.MethodArn)
+	if err != nil {
+		return crawlerIdentity{}, err
+	}
+	now := authorizer.now()
+	key, err := authorization.Verify(authorizer.keyRing, method, path, now)
+	if err != nil {
+		return crawlerIdentity{}, err
+	}
+
+	// a signature stays valid until its request leaves the replay window
+	expires := time.Unix(authorization.Timestamp, 0).Add(ReplayWindow)
+	if err := authorizer.signatures.Record(authorization.Signature, now, expires); err != nil {
+		if datalayer.IsAlreadyExists(err) {
+			return crawlerIdentity{}, errors.New("request signature has been used before")
+		}
+		log.Printf("ERROR: unable to record request signature: %v", err)
+		return crawlerIdentity{}, errors.New("unable to record request signature")
+	}
+	return crawlerIdentity{key.PrincipalID, key.Stations}, nil
+}
+
+func (authorizer CrawlerAuthorizer) authenticateJWT(token string) (crawlerIdentity, error) {
+	claims, key, err := VerifyJWT(token, authorizer.keyRing, authorizer.audience,
+		TracksWriteScope, authorizer.now())
+	if err != nil {
+		return crawlerIdentity{}, err
+	}
+	if len(claims.Stations) == 0 {
+		return crawlerIdentity{key.PrincipalID, key.Stations}, nil
+	}
+	return crawlerIdentity{key.PrincipalID, intersect(key.Stations, claims.Stations)}, nil
+}
+
+func (authorizer CrawlerAuthorizer) authenticateToken(token string) (crawlerIdentity, error) {
+	credential, err := authorizer.dao.GetByTokenHash(model.HashToken(token))
+	if err != nil {
+		if !datalayer.IsNotFound(err) {
+			log.Printf("ERROR: unable to look up credential: %v", err)
+		}
+		return crawlerIdentity{}, errors.New("unknown token")
+	}
+	return crawlerIdentity{credential.PrincipalID, credential.Stations}, nil
+}
+
+// splitMethodArn extracts the HTTP method and the path from the ARN of the invoked method, e.g.
+// `PUT` and `/stations/hitradio-oe3/tracks/1537701181` from
+// arn:aws:execute-api:eu-central-1:001975686909:pul5mro035/dev/PUT/stations/hitradio-oe3/tracks/1537701181
+func splitMethodArn(methodArn string) (string, string, error) {
+	split := strings.SplitN(methodArn, "/", 4)
+	if len(split) != 4 {
+		return "", "", errors.New("unable to split ARN `" + methodArn + "`")
+	}
+	return split[2], "/" + split[3], nil
+}
+
+func intersect(a, b []string) []string {
+	result := make([]string, 0)
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				result = append(result, x)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func unauthorized(authRequest events.APIGatewayCustomAuthorizerRequest,
+	reason string) (events.APIGatewayCustomAuthorizerResponse, error) {
+	log.Printf("UNAUTHORIZE REQUEST: Type: `%s`, Token: `%s`, ARN: `%s`, Reason: %s",
+		authRequest.Type, RedactToken(authRequest.AuthorizationToken), authRequest.MethodArn,
+		reason)
+	return events.APIGatewayCustomAuthorizerResponse{}, ErrUnauthorized
+}
@@ -0,0 +1,187 @@
+package auth
+
+import (
+	"errors"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"github.com/aws/aws-lambda-go/events"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type MockCredentialDAOFail struct{}
+
+func (dao MockCredentialDAOFail) GetByTokenHash(tokenHash string) (model.Credential, error) {
+	return model.Credential{}, errors.New("error")
+}
+
+func (dao MockCredentialDAOFail) Create(credential model.Credential) error {
+	return errors.New("error")
+}
+
+const testArnPrefix = "arn:aws:execute-api:eu-central-1:001975686909:pul5mro035/dev/PUT/stations/"
+
+var (
+	testNow = time.Unix(1537701181, 0)
+
+	oe3Key = Key{"oe3-2018-09", []byte("0123456789abcdef0123456789abcdef"), "oe3-crawler",
+		[]string{"hitradio-oe3", "fm4"}}
+	oe3RotatedKey = Key{"oe3-2018-10", []byte("fedcba9876543210fedcba9876543210"), "oe3-crawler",
+		[]string{"hitradio-oe3", "fm4"}}
+)
+
+func newTestCrawlerAuthorizer(dao datalayer.CredentialDAO) CrawlerAuthorizer {
+	authorizer, _ := NewCrawlerAuthorizer(dao, datalayer.NewMemorySignatureDAO(),
+		NewKeyRing(oe3Key, oe3RotatedKey), "radiochecker-api")
+	authorizer.now = func() time.Time { return testNow }
+	return authorizer
+}
+
+func signedJWT(key Key, modify func(claims *Claims)) string {
+	claims := Claims{
+		Subject:   "oe3-crawler",
+		Audience:  Audience{"radiochecker-api"},
+		ExpiresAt: testNow.Add(time.Hour).Unix(),
+		Scope:     "tracks:read tracks:write",
+	}
+	if modify != nil {
+		modify(&claims)
+	}
+	token, _ := SignJWT(claims, key)
+	return "Bearer " + token
+}
+
+func signedHMAC(key Key, path string, timestamp time.Time) string {
+	contentSHA256 := ContentSHA256([]byte("{\"artist\":\"RHCP\",\"title\":\"Californication\"}"))
+	return HMACScheme + " KeyId=" + key.ID +
+		",Timestamp=" + strconv.FormatInt(timestamp.Unix(), 10) +
+		",ContentSHA256=" + contentSHA256 +
+		",Signature=" + SignRequest(key.Secret, "PUT", path, timestamp.Unix(), contentSHA256)
+}
+
+func TestCrawlerAuthorizer_Authorize(t *testing.T) {
+	methodArn := testArnPrefix + "hitradio-oe3/tracks/1537701181"
+	path := "/stations/hitradio-oe3/tracks/1537701181"
+	allowedArns := []string{testArnPrefix + "hitradio-oe3/tracks/*", testArnPrefix + "fm4/tracks/*"}
+
+	credentialDAO := datalayer.NewMemoryCredentialDAO(model.Credential{
+		model.HashToken("oe3-token"), "oe3-crawler", []string{"hitradio-oe3", "fm4"},
+	})
+
+	var tests = []struct {
+		dao          datalayer.CredentialDAO
+		token        string
+		methodArn    string
+		expectedArns []string
+	}{
+		// bearer token
+		{credentialDAO, "Bearer oe3-token", methodArn, allowedArns},
+		{credentialDAO, "Bearer OE3-TOKEN", methodArn, nil}, // tokens are case-sensitive
+		{credentialDAO, "Bearer unknown", methodArn, nil},
+		{credentialDAO, "oe3-token", methodArn, nil},
+		{credentialDAO, "Bearer oe3-token", "invalid", nil},
+		{MockCredentialDAOFail{}, "Bearer oe3-token", methodArn, nil},
+		// JWT
+		{credentialDAO, signedJWT(oe3Key, nil), methodArn, allowedArns},
+		{credentialDAO, signedJWT(oe3RotatedKey, nil), methodArn, allowedArns},
+		{
+			credentialDAO,
+			signedJWT(oe3Key, func(c *Claims) { c.Stations = []string{"fm4", "kronehit"} }),
+			methodArn,
+			[]string{testArnPrefix + "fm4/tracks/*"},
+		},
+		{credentialDAO, signedJWT(oe3Key, func(c *Claims) { c.Stations = []string{"kronehit"} }),
+			methodArn, nil},
+		{credentialDAO, signedJWT(Key{"unknown", oe3Key.Secret, "oe3-crawler", nil}, nil),
+			methodArn, nil},
+		{credentialDAO, signedJWT(Key{oe3Key.ID, []byte("forged"), "oe3-crawler", nil}, nil),
+			methodArn, nil},
+		{credentialDAO, signedJWT(oe3Key, func(c *Claims) { c.Scope = "tracks:read" }),
+			methodArn, nil},
+		// HMAC
+		{credentialDAO, signedHMAC(oe3Key, path, testNow), methodArn, allowedArns},
+		{credentialDAO, signedHMAC(oe3RotatedKey, path, testNow.Add(-4*time.Minute)), methodArn,
+			allowedArns},
+		{credentialDAO, signedHMAC(oe3Key, path, testNow.Add(-6*time.Minute)), methodArn, nil},
+		{credentialDAO, signedHMAC(oe3Key, "/stations/fm4/tracks/1537701181", testNow),
+			methodArn, nil},
+	}
+
+	for i, test := range tests {
+		authorizer := newTestCrawlerAuthorizer(test.dao)
+		authRequest := events.APIGatewayCustomAuthorizerRequest{
+			Type:               "TOKEN",
+			AuthorizationToken: test.token,
+			MethodArn:          test.methodArn,
+		}
+
+		response, err := authorizer.Authorize(authRequest)
+		if test.expectedArns == nil {
+			if err != ErrUnauthorized {
+				t.Errorf("#%d Authorize(%v): got err (%v), expected ErrUnauthorized", i,
+					authRequest, err)
+			}
+			continue
+		}
+
+		expectedResponse := events.APIGatewayCustomAuthorizerResponse{
+			PrincipalID:    "oe3-crawler",
+			PolicyDocument: generatePolicy("Allow", test.expectedArns),
+		}
+		if err != nil || !reflect.DeepEqual(response, expectedResponse) {
+			t.Errorf("#%d Authorize(%v): got (%v, %v), expected (%v, nil)", i, authRequest,
+				response, err, expectedResponse)
+		}
+	}
+}
+
+func TestCrawlerAuthorizer_Authorize_Replay(t *testing.T) {
+	authorizer := newTestCrawlerAuthorizer(datalayer.NewMemoryCredentialDAO())
+	authRequest := events.APIGatewayCustomAuthorizerRequest{
+		Type:               "TOKEN",
+		AuthorizationToken: signedHMAC(oe3Key, "/stations/hitradio-oe3/tracks/1537701181", testNow),
+		MethodArn:          testArnPrefix + "hitradio-oe3/tracks/1537701181",
+	}
+
+	if _, err := authorizer.Authorize(authRequest); err != nil {
+		t.Fatalf("Authorize(): got err (%v), expected nil", err)
+	}
+	if _, err := authorizer.Authorize(authRequest); err != ErrUnauthorized {
+		t.Errorf("Authorize() replayed: got err (%v), expected ErrUnauthorized", err)
+	}
+
+	// the signature is accepted again once the request has left the replay window, where it is
+	// rejected as outdated anyways
+	authorizer.now = func() time.Time { return testNow.Add(ReplayWindow + time.Second) }
+	if _, err := authorizer.Authorize(authRequest); err != ErrUnauthorized {
+		t.Errorf("Authorize() outdated: got err (%v), expected ErrUnauthorized", err)
+	}
+}
+
+func TestNewCrawlerAuthorizer(t *testing.T) {
+	signatureDAO := datalayer.NewMemorySignatureDAO()
+	if _, err := NewCrawlerAuthorizer(nil, signatureDAO, NewKeyRing(),
+		"radiochecker-api"); err == nil {
+		t.Error("NewCrawlerAuthorizer(nil, ...): got no error, expected error")
+	}
+	if _, err := NewCrawlerAuthorizer(datalayer.NewMemoryCredentialDAO(), nil, NewKeyRing(),
+		"radiochecker-api"); err == nil {
+		t.Error("NewCrawlerAuthorizer(..., nil, ...): got no error, expected error")
+	}
+	if _, err := NewCrawlerAuthorizer(datalayer.NewMemoryCredentialDAO(), signatureDAO,
+		NewKeyRing(), ""); err == nil {
+		t.Error("NewCrawlerAuthorizer(..., \"\"): got no error, expected error")
+	}
+}
+
+func TestSplitMethodArn(t *testing.T) {
+	method, path, err := splitMethodArn(testArnPrefix + "hitradio-oe3/tracks/1537701181")
+	if err != nil || method != "PUT" || path != "/stations/hitradio-oe3/tracks/1537701181" {
+		t.Errorf("splitMethodArn(): got (%s, %s, %v)", method, path, err)
+	}
+	if _, _, err := splitMethodArn("invalid"); err == nil {
+		t.Error("splitMethodArn(\"invalid\"): got no error, expected error")
+	}
+}
@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HMACScheme is the authorization scheme of HMAC signed requests:
+// `RC-HMAC-SHA256 KeyId=<kid>,Timestamp=<unix>,ContentSHA256=<hex>,Signature=<hex>`
+const HMACScheme = "RC-HMAC-SHA256"
+
+// ReplayWindow is the maximum age of a signed request. Requests signed too far in the future are
+// rejected as well. Each signature is accepted once, see CrawlerAuthorizer.
+const ReplayWindow = 5 * time.Minute
+
+type HMACAuthorization struct {
+	KeyID         string
+	Timestamp     int64
+	ContentSHA256 string
+	Signature     string
+}
+
+// IsHMACAuthorization reports whether the header value uses the HMAC scheme.
+func IsHMACAuthorization(authorizationToken string) bool {
+	return strings.HasPrefix(strings.TrimSpace(authorizationToken), HMACScheme+" ")
+}
+
+func ParseHMACAuthorization(authorizationToken string) (HMACAuthorization, error) {
+	if !IsHMACAuthorization(authorizationToken) {
+		return HMACAuthorization{}, errors.New("authorization scheme must be " + HMACScheme)
+	}
+
+	params := strings.TrimPrefix(strings.TrimSpace(authorizationToken), HMACScheme+" ")
+	var authorization HMACAuthorization
+	for _, param := range strings.Split(params, ",") {
+		keyValue := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(keyValue) != 2 {
+			return HMACAuthorization{}, errors.New("malformed HMAC authorization")
+		}
+		switch keyValue[0] {
+		case "KeyId":
+			authorization.KeyID = keyValue[1]
+		case "Timestamp":
+			timestamp, err := strconv.ParseInt(keyValue[1], 10, 64)
+			if err != nil {
+				return HMACAuthorization{}, errors.New("malformed HMAC timestamp")
+			}
+			authorization.Timestamp = timestamp
+		case "ContentSHA256":
+			authorization.ContentSHA256 = strings.ToLower(keyValue[1])
+		case "Signature":
+			authorization.Signature = strings.ToLower(keyValue[1])
+		}
+	}
+
+	if authorization.KeyID == "" || authorization.Timestamp == 0 ||
+		authorization.ContentSHA256 == "" || authorization.Signature == "" {
+		return HMACAuthorization{}, errors.New("incomplete HMAC authorization")
+	}
+	return authorization, nil
+}
+
+// StringToSign assembles the message covered by the signature of a request.
+func StringToSign(method, path string, timestamp int64, contentSHA256 string) string {
+	return strings.Join([]string{
+		HMACScheme,
+		strings.ToUpper(method),
+		path,
+		strconv.FormatInt(timestamp, 10),
+		strings.ToLower(contentSHA256),
+	}, "\n")
+}
+
+// SignRequest computes the hex encoded signature of a request.
+func SignRequest(secret []byte, method, path string, timestamp int64, contentSHA256 string) string {
+	return hex.EncodeToString(signHMAC(secret, StringToSign(method, path, timestamp,
+		contentSHA256)))
+}
+
+// ContentSHA256 returns the hex encoded SHA-256 digest of a request body.
+func ContentSHA256(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// Verify checks the signature against the key referenced by KeyId and enforces the replay
+// window. The body digest cannot be checked here since authorizers do not receive the body, see
+// VerifyContentDigest.
+func (authorization HMACAuthorization) Verify(keyRing KeyRing, method, path string,
+	now time.Time) (Key, error) {
+	signedAt := time.Unix(authorization.Timestamp, 0)
+	if signedAt.Before(now.Add(-ReplayWindow)) || signedAt.After(now.Add(ReplayWindow)) {
+		return Key{}, errors.New("request signature lies outside of the replay window")
+	}
+
+	key, ok := keyRing.Get(authorization.KeyID)
+	if !ok {
+		return Key{}, errors.New("unknown key `" + authorization.KeyID + "`")
+	}
+
+	signature, err := hex.DecodeString(authorization.Signature)
+	if err != nil {
+		return Key{}, errors.New("malformed HMAC signature")
+	}
+	expected := signHMAC(key.Secret, StringToSign(method, path, authorization.Timestamp,
+		authorization.ContentSHA256))
+	if !hmac.Equal(signature, expected) {
+		return Key{}, errors.New("invalid HMAC signature")
+	}
+	return key, nil
+}
+
+// VerifyContentDigest ensures the body of an HMAC signed request matches the signed digest.
+// Requests using any other authorization scheme pass unchecked.
+func VerifyContentDigest(authorizationToken string, body []byte) error {
+	if !IsHMACAuthorization(authorizationToken) {
+		return nil
+	}
+	authorization, err := ParseHMACAuthorization(authorizationToken)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(authorization.ContentSHA256), []byte(ContentSHA256(body))) {
+		return errors.New("request body does not match the signed content digest")
+	}
+	return nil
+}
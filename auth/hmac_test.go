@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"testing"
+)
+
+func TestParseHMACAuthorization(t *testing.T) {
+	var tests = []struct {
+		input       string
+		expected    HMACAuthorization
+		expectedErr bool
+	}{
+		{
+			"RC-HMAC-SHA256 KeyId=oe3-2018-09, Timestamp=1537701181,ContentSHA256=ABC,Signature=DEF",
+			HMACAuthorization{"oe3-2018-09", 1537701181, "abc", "def"},
+			false,
+		},
+		{"RC-HMAC-SHA256 KeyId=oe3-2018-09,Timestamp=1537701181,Signature=def",
+			HMACAuthorization{}, true},
+		{"RC-HMAC-SHA256 KeyId=oe3-2018-09,Timestamp=now,ContentSHA256=abc,Signature=def",
+			HMACAuthorization{}, true},
+		{"RC-HMAC-SHA256 KeyId", HMACAuthorization{}, true},
+		{"Bearer abc", HMACAuthorization{}, true},
+	}
+
+	for _, test := range tests {
+		result, err := ParseHMACAuthorization(test.input)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("ParseHMACAuthorization(%q): got err (%v), expected err: %v", test.input,
+				err, test.expectedErr)
+			continue
+		}
+		if result != test.expected {
+			t.Errorf("ParseHMACAuthorization(%q): got (%v), expected (%v)", test.input, result,
+				test.expected)
+		}
+	}
+}
+
+func TestVerifyContentDigest(t *testing.T) {
+	path := "/stations/hitradio-oe3/tracks/1537701181"
+	body := []byte("{\"artist\":\"RHCP\",\"title\":\"Californication\"}")
+
+	var tests = []struct {
+		authorizationToken string
+		body               []byte
+		expectedErr        bool
+	}{
+		{signedHMAC(oe3Key, path, testNow), body, false},
+		{signedHMAC(oe3Key, path, testNow), []byte("{\"artist\":\"RHCP\"}"), true},
+		{"RC-HMAC-SHA256 KeyId=oe3-2018-09", body, true},
+		{"Bearer oe3-token", body, false},
+	}
+
+	for _, test := range tests {
+		err := VerifyContentDigest(test.authorizationToken, test.body)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("VerifyContentDigest(%q, %s): got err (%v), expected err: %v",
+				test.authorizationToken, test.body, err, test.expectedErr)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// jwtLeeway tolerates clock skew between the crawlers and the API.
+const jwtLeeway = 30 * time.Second
+
+// Claims are the JWT claims the API evaluates. Stations optionally narrows the stations granted
+// by the signing key.
+type Claims struct {
+	Subject   string   `json:"sub"`
+	Audience  Audience `json:"aud"`
+	ExpiresAt int64    `json:"exp"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	Scope     string   `json:"scope"`
+	Stations  []string `json:"stations,omitempty"`
+}
+
+// Audience holds the `aud` claim, which is either a single string or a list of strings.
+type Audience []string
+
+func (audience *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*audience = Audience{single}
+		return nil
+	}
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return errors.New("aud claim must be a string or a list of strings")
+	}
+	*audience = Audience(multiple)
+	return nil
+}
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ,omitempty"`
+	KeyID     string `json:"kid"`
+}
+
+// IsJWT reports whether the token has the compact JWS layout `header.payload.signature`.
+func IsJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// SignJWT creates an HS256 signed JWT carrying the claims.
+func SignJWT(claims Claims, key Key) (string, error) {
+	header, err := json.Marshal(jwtHeader{"HS256", "JWT", key.ID})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := encodeSegment(header) + "." + encodeSegment(payload)
+	return signingInput + "." + encodeSegment(signHMAC(key.Secret, signingInput)), nil
+}
+
+// VerifyJWT checks the token's signature against the key referenced by its `kid` header and
+// validates its time based claims, audience and scope. It returns the claims along with the key.
+func VerifyJWT(token string, keyRing KeyRing, audience, scope string, now time.Time) (Claims,
+	Key, error) {
+	segments := strings.Split(token, ".")
+	if len(segments) != 3 {
+		return Claims{}, Key{}, errors.New("malformed JWT")
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(segments[0], &header); err != nil {
+		return Claims{}, Key{}, err
+	}
+	// only HS256 is accepted, which rules out `none` as well as algorithm confusion attacks
+	if header.Algorithm != "HS256" {
+		return Claims{}, Key{}, errors.New("unsupported JWT algorithm `" + header.Algorithm + "`")
+	}
+
+	key, ok := keyRing.Get(header.KeyID)
+	if !ok {
+		return Claims{}, Key{}, errors.New("unknown key `" + header.KeyID + "`")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(segments[2])
+	if err != nil {
+		return Claims{}, Key{}, errors.New("malformed JWT signature")
+	}
+	if !hmac.Equal(signature, signHMAC(key.Secret, segments[0]+"."+segments[1])) {
+		return Claims{}, Key{}, errors.New("invalid JWT signature")
+	}
+
+	var claims Claims
+	if err := decodeSegment(segments[1], &claims); err != nil {
+		return Claims{}, Key{}, err
+	}
+	if err := claims.validate(key, audience, scope, now); err != nil {
+		return Claims{}, Key{}, err
+	}
+	return claims, key, nil
+}
+
+func (claims Claims) validate(key Key, audience, scope string, now time.Time) error {
+	if claims.ExpiresAt == 0 {
+		return errors.New("JWT lacks an expiry")
+	}
+	if now.Add(-jwtLeeway).After(time.Unix(claims.ExpiresAt, 0)) {
+		return errors.New("JWT expired")
+	}
+	if claims.NotBefore != 0 && now.Add(jwtLeeway).Before(time.Unix(claims.NotBefore, 0)) {
+		return errors.New("JWT not valid yet")
+	}
+	if claims.Subject != key.PrincipalID {
+		return errors.New("JWT subject does not match the principal of its key")
+	}
+	if !claims.HasAudience(audience) {
+		return errors.New("JWT audience mismatch")
+	}
+	if !claims.HasScope(scope) {
+		return errors.New("JWT lacks scope `" + scope + "`")
+	}
+	return nil
+}
+
+func (claims Claims) HasAudience(audience string) bool {
+	for _, aud := range claims.Audience {
+		if aud == audience {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope reports whether the space separated `scope` claim contains the scope.
+func (claims Claims) HasScope(scope string) bool {
+	for _, s := range strings.Fields(claims.Scope) {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func signHMAC(secret []byte, message string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(message))
+	return mac.Sum(nil)
+}
+
+func encodeSegment(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return errors.New("malformed JWT segment")
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return errors.New("malformed JWT segment")
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifyJWT(t *testing.T) {
+	keyRing := NewKeyRing(oe3Key)
+
+	var tests = []struct {
+		token       string
+		expectedErr bool
+	}{
+		{signedJWT(oe3Key, nil), false},
+		{signedJWT(oe3Key, func(c *Claims) { c.NotBefore = testNow.Add(-time.Minute).Unix() }),
+			false},
+		// expired, leeway included
+		{signedJWT(oe3Key, func(c *Claims) { c.ExpiresAt = testNow.Add(-10 * time.Second).Unix() }),
+			false},
+		{signedJWT(oe3Key, func(c *Claims) { c.ExpiresAt = testNow.Add(-time.Minute).Unix() }),
+			true},
+		{signedJWT(oe3Key, func(c *Claims) { c.ExpiresAt = 0 }), true},
+		{signedJWT(oe3Key, func(c *Claims) { c.NotBefore = testNow.Add(time.Minute).Unix() }),
+			true},
+		{signedJWT(oe3Key, func(c *Claims) { c.Audience = Audience{"other-api"} }), true},
+		{signedJWT(oe3Key, func(c *Claims) { c.Subject = "fm4-crawler" }), true},
+		{signedJWT(oe3Key, func(c *Claims) { c.Scope = "" }), true},
+		{signedJWT(oe3RotatedKey, nil), true}, // key not part of the ring
+		{"Bearer a.b.c", true},
+		{"Bearer " + encodeSegment([]byte(`{"alg":"none","kid":"oe3-2018-09"}`)) + "." +
+			strings.Split(signedJWT(oe3Key, nil), ".")[1] + ".", true},
+	}
+
+	for i, test := range tests {
+		token := strings.TrimPrefix(test.token, "Bearer ")
+		_, key, err := VerifyJWT(token, keyRing, "radiochecker-api", TracksWriteScope, testNow)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("#%d VerifyJWT(%s): got err (%v), expected err: %v", i, token, err,
+				test.expectedErr)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(key, oe3Key) {
+			t.Errorf("#%d VerifyJWT(%s): got key (%v), expected (%v)", i, token, key, oe3Key)
+		}
+	}
+}
+
+func TestAudience_UnmarshalJSON(t *testing.T) {
+	var tests = []struct {
+		input       string
+		expected    Audience
+		expectedErr bool
+	}{
+		{`"radiochecker-api"`, Audience{"radiochecker-api"}, false},
+		{`["radiochecker-api","other"]`, Audience{"radiochecker-api", "other"}, false},
+		{`42`, nil, true},
+	}
+
+	for _, test := range tests {
+		var audience Audience
+		err := json.Unmarshal([]byte(test.input), &audience)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("Unmarshal(%s): got err (%v), expected err: %v", test.input, err,
+				test.expectedErr)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(audience, test.expected) {
+			t.Errorf("Unmarshal(%s): got (%v), expected (%v)", test.input, audience, test.expected)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// Key is a secret shared with a single crawler. It verifies both HMAC request signatures and the
+// signatures of JWTs minted by the crawler. A crawler may own several active keys at once, which
+// allows rotating keys without downtime.
+type Key struct {
+	ID          string
+	Secret      []byte
+	PrincipalID string
+	Stations    []string
+}
+
+// KeyRing holds all active keys indexed by their ID.
+type KeyRing struct {
+	keys map[string]Key
+}
+
+func NewKeyRing(keys ...Key) KeyRing {
+	keyRing := KeyRing{make(map[string]Key)}
+	for _, key := range keys {
+		keyRing.keys[key.ID] = key
+	}
+	return keyRing
+}
+
+// ParseKeyRing parses a JSON encoded list of keys, e.g.
+// `[{"kid":"oe3-2018-09","secret":"<base64>","principalId":"oe3-crawler","stations":["hitradio-oe3"]}]`.
+// An empty string results in an empty key ring.
+func ParseKeyRing(str string) (KeyRing, error) {
+	if strings.TrimSpace(str) == "" {
+		return NewKeyRing(), nil
+	}
+
+	var encodedKeys []struct {
+		ID          string   `json:"kid"`
+		Secret      string   `json:"secret"`
+		PrincipalID string   `json:"principalId"`
+		Stations    []string `json:"stations"`
+	}
+	if err := json.Unmarshal([]byte(str), &encodedKeys); err != nil {
+		return KeyRing{}, errors.New("key ring contains invalid JSON")
+	}
+
+	keys := make([]Key, len(encodedKeys))
+	for i, encodedKey := range encodedKeys {
+		secret, err := base64.StdEncoding.DecodeString(encodedKey.Secret)
+		if err != nil || len(secret) < 32 {
+			return KeyRing{}, errors.New("secret of key `" + encodedKey.ID +
+				"` must be a base64 encoded value of at least 32 bytes")
+		}
+		if encodedKey.ID == "" || encodedKey.PrincipalID == "" {
+			return KeyRing{}, errors.New("keys require a kid and a principalId")
+		}
+		keys[i] = Key{encodedKey.ID, secret, encodedKey.PrincipalID, encodedKey.Stations}
+	}
+	return NewKeyRing(keys...), nil
+}
+
+func (keyRing KeyRing) Get(keyID string) (Key, bool) {
+	key, ok := keyRing.keys[keyID]
+	return key, ok
+}
@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseKeyRing(t *testing.T) {
+	// base64 of `0123456789abcdef0123456789abcdef`
+	const secret = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+
+	var tests = []struct {
+		input       string
+		expected    KeyRing
+		expectedErr bool
+	}{
+		{"", NewKeyRing(), false},
+		{
+			`[{"kid":"oe3-2018-09","secret":"` + secret + `","principalId":"oe3-crawler",` +
+				`"stations":["hitradio-oe3","fm4"]}]`,
+			NewKeyRing(oe3Key),
+			false,
+		},
+		{`[{"kid":"oe3-2018-09","secret":"c2hvcnQ=","principalId":"oe3-crawler"}]`,
+			KeyRing{}, true},
+		{`[{"kid":"","secret":"` + secret + `","principalId":"oe3-crawler"}]`, KeyRing{}, true},
+		{`{"kid":"oe3-2018-09"}`, KeyRing{}, true},
+	}
+
+	for _, test := range tests {
+		result, err := ParseKeyRing(test.input)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("ParseKeyRing(%q): got err (%v), expected err: %v", test.input, err,
+				test.expectedErr)
+			continue
+		}
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("ParseKeyRing(%q): got (%v), expected (%v)", test.input, result,
+				test.expected)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"log"
+	"strings"
+)
+
+const managePrincipalID = "station-admin"
+
+// ManageAuthorizer grants the holder of the station management token access to the endpoints
+// managing stations and station groups.
+type ManageAuthorizer struct {
+	token string
+}
+
+func NewManageAuthorizer(token string) (ManageAuthorizer, error) {
+	if token == "" {
+		return ManageAuthorizer{}, errors.New("token must not be empty")
+	}
+	return ManageAuthorizer{token}, nil
+}
+
+func (authorizer ManageAuthorizer) Authorize(authRequest events.
+	APIGatewayCustomAuthorizerRequest) (events.APIGatewayCustomAuthorizerResponse, error) {
+	token, err := ExtractBearerToken(authRequest.AuthorizationToken)
+	if err != nil {
+		return unauthorized(authRequest, "missing bearer token")
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(authorizer.token)) != 1 {
+		return unauthorized(authRequest, "invalid token")
+	}
+
+	resourceArns, err := BuildManageResourceArns(authRequest.MethodArn)
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		return unauthorized(authRequest, "invalid method ARN")
+	}
+
+	log.Printf("AUTHORIZE REQUEST: Type: `%s`, Token: `%s`, ARNs: `%v`",
+		authRequest.Type, RedactToken(authRequest.AuthorizationToken), resourceArns)
+
+	return events.APIGatewayCustomAuthorizerResponse{
+		PrincipalID:    managePrincipalID,
+		PolicyDocument: generatePolicy("Allow", resourceArns),
+	}, nil
+}
+
+// manageResources are the resources managed by the holder of the station management token.
+var manageResources = []string{"stations", "groups"}
+
+// BuildManageResourceArns derives the resource ARNs covering all methods and IDs of the managed
+// resources from the ARN of the invoked method. The policy is cached by API Gateway, hence it has
+// to cover POST, PUT and DELETE of stations and groups alike.
+func BuildManageResourceArns(methodArn string) ([]string, error) {
+	// resource ARN example layout:
+	// arn:aws:execute-api:eu-central-1:001975686909:pul5mro035/dev/POST/stations/hitradio-oe3
+	split := strings.Split(methodArn, "/")
+	if len(split) != 5 {
+		return nil, errors.New("unable to split ARN `" + methodArn + "`")
+	}
+
+	resourceArns := make([]string, len(manageResources))
+	for i, resource := range manageResources {
+		resourceArns[i] = strings.Join([]string{split[0], split[1], "*", resource, "*"}, "/")
+	}
+	return resourceArns, nil
+}
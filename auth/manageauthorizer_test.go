@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"github.com/aws/aws-lambda-go/events"
+	"reflect"
+	"testing"
+)
+
+func TestNewManageAuthorizer(t *testing.T) {
+	if _, err := NewManageAuthorizer(""); err == nil {
+		t.Error("NewManageAuthorizer(\"\"): got no error, expected error")
+	}
+	if _, err := NewManageAuthorizer("s3cr3t-Token"); err != nil {
+		t.Errorf("NewManageAuthorizer(\"s3cr3t-Token\"): got err (%v), expected none", err)
+	}
+}
+
+func TestManageAuthorizer_Authorize(t *testing.T) {
+	const arnPrefix = "arn:aws:execute-api:eu-central-1:001975686909:pul5mro035/dev/"
+	authorizer, _ := NewManageAuthorizer("s3cr3t-Token")
+
+	var tests = []struct {
+		token            string
+		methodArn        string
+		expectedResponse events.APIGatewayCustomAuthorizerResponse
+		expectedErr      error
+	}{
+		{
+			"Bearer s3cr3t-Token",
+			arnPrefix + "POST/stations/hitradio-oe3",
+			events.APIGatewayCustomAuthorizerResponse{
+				PrincipalID: "station-admin",
+				PolicyDocument: generatePolicy("Allow", []string{arnPrefix + "*/stations/*",
+					arnPrefix + "*/groups/*"}),
+			},
+			nil,
+		},
+		// the cached policy covers stations regardless of the resource invoked first
+		{
+			"Bearer s3cr3t-Token",
+			arnPrefix + "DELETE/groups/oe3-regional",
+			events.APIGatewayCustomAuthorizerResponse{
+				PrincipalID: "station-admin",
+				PolicyDocument: generatePolicy("Allow", []string{arnPrefix + "*/stations/*",
+					arnPrefix + "*/groups/*"}),
+			},
+			nil,
+		},
+		// tokens are compared case-sensitively
+		{"Bearer s3cr3t-token", arnPrefix + "POST/stations/hitradio-oe3",
+			events.APIGatewayCustomAuthorizerResponse{}, ErrUnauthorized},
+		{"s3cr3t-Token", arnPrefix + "POST/stations/hitradio-oe3",
+			events.APIGatewayCustomAuthorizerResponse{}, ErrUnauthorized},
+		{"", arnPrefix + "POST/stations/hitradio-oe3",
+			events.APIGatewayCustomAuthorizerResponse{}, ErrUnauthorized},
+		{"Bearer s3cr3t-Token", "invalid", events.APIGatewayCustomAuthorizerResponse{},
+			ErrUnauthorized},
+	}
+
+	for _, test := range tests {
+		authRequest := events.APIGatewayCustomAuthorizerRequest{
+			Type:               "TOKEN",
+			AuthorizationToken: test.token,
+			MethodArn:          test.methodArn,
+		}
+		response, err := authorizer.Authorize(authRequest)
+		if err != test.expectedErr || !reflect.DeepEqual(response, test.expectedResponse) {
+			t.Errorf("Authorize(%q, %q): got (%v, %v), expected (%v, %v)", test.token,
+				test.methodArn, response, err, test.expectedResponse, test.expectedErr)
+		}
+	}
+}
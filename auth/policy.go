@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"strings"
+)
+
+// ErrUnauthorized makes API Gateway respond with `401 Unauthorized`. The message must not be
+// altered, API Gateway matches it literally.
+var ErrUnauthorized = errors.New("Unauthorized")
+
+// ExtractBearerToken returns the token of an `Authorization: Bearer <token>` header value.
+func ExtractBearerToken(authorizationToken string) (string, error) {
+	split := strings.Split(authorizationToken, "Bearer")
+	if len(split) != 2 {
+		return "", ErrUnauthorized
+	}
+
+	token := strings.TrimSpace(split[1])
+	if token == "" {
+		return "", ErrUnauthorized
+	}
+	return token, nil
+}
+
+// BuildStationResourceArns derives one resource ARN per station from the ARN of the invoked
+// method, each allowing any timestamp of that station's track records.
+func BuildStationResourceArns(methodArn string, stations []string) ([]string, error) {
+	// resource ARN example layout:
+	// arn:aws:execute-api:eu-central-1:001975686909:pul5mro035/dev/PUT/stations/hitradio-oe3/tracks/1537701181
+	split := strings.Split(methodArn, "/")
+	if len(split) != 7 {
+		return nil, errors.New("unable to split ARN `" + methodArn + "`")
+	}
+
+	resourceArns := make([]string, len(stations))
+	for i, station := range stations {
+		split[4] = station
+		split[6] = "*"
+		resourceArns[i] = strings.Join(split, "/")
+	}
+	return resourceArns, nil
+}
+
+func generatePolicy(effect string, resourceArns []string) events.APIGatewayCustomAuthorizerPolicy {
+	statement := events.IAMPolicyStatement{
+		Action:   []string{"execute-api:Invoke"}, // default action
+		Effect:   effect,
+		Resource: resourceArns,
+	}
+
+	return events.APIGatewayCustomAuthorizerPolicy{
+		Version:   "2012-10-17", // default version
+		Statement: []events.IAMPolicyStatement{statement},
+	}
+}
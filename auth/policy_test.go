@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractBearerToken(t *testing.T) {
+	var tests = []struct {
+		authorizationToken string
+		expectedToken      string
+		expectedErr        bool
+	}{
+		{"Bearer abc", "abc", false},
+		{"Bearer   AbC  ", "AbC", false},
+		{"Bearer ", "", true},
+		{"abc", "", true},
+		{"", "", true},
+		{"Bearer abc Bearer def", "", true},
+	}
+
+	for _, test := range tests {
+		token, err := ExtractBearerToken(test.authorizationToken)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("ExtractBearerToken(%q): got err (%v), expected err: %v",
+				test.authorizationToken, err, test.expectedErr)
+			continue
+		}
+		if token != test.expectedToken {
+			t.Errorf("ExtractBearerToken(%q): got `%s`, expected `%s`",
+				test.authorizationToken, token, test.expectedToken)
+		}
+	}
+}
+
+func TestBuildStationResourceArns(t *testing.T) {
+	const arnPrefix = "arn:aws:execute-api:eu-central-1:001975686909:pul5mro035/dev/PUT/stations/"
+
+	var tests = []struct {
+		methodArn      string
+		stations       []string
+		expectedResult []string
+		expectedErr    bool
+	}{
+		{
+			arnPrefix + "hitradio-oe3/tracks/1537701181",
+			[]string{"hitradio-oe3", "fm4"},
+			[]string{arnPrefix + "hitradio-oe3/tracks/*", arnPrefix + "fm4/tracks/*"},
+			false,
+		},
+		{
+			arnPrefix + "kronehit/tracks/1537701181",
+			[]string{"hitradio-oe3"},
+			[]string{arnPrefix + "hitradio-oe3/tracks/*"},
+			false,
+		},
+		{arnPrefix + "kronehit/tracks/1537701181", []string{}, []string{}, false},
+		{"arn:aws:execute-api:eu-central-1:001975686909:pul5mro035/dev/GET/meta",
+			[]string{"kronehit"}, nil, true},
+	}
+
+	for _, test := range tests {
+		result, err := BuildStationResourceArns(test.methodArn, test.stations)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("BuildStationResourceArns(%q, %v): got err (%v), expected err: %v",
+				test.methodArn, test.stations, err, test.expectedErr)
+			continue
+		}
+		if !reflect.DeepEqual(result, test.expectedResult) {
+			t.Errorf("BuildStationResourceArns(%q, %v): got %v, expected %v",
+				test.methodArn, test.stations, result, test.expectedResult)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"errors"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"github.com/aws/aws-lambda-go/events"
+	"log"
+	"strings"
+)
+
+// ReadAuthorizer resolves the API key of a read request to the client it has been issued to. The
+// client's tier is passed on to the endpoints via the authorizer context, see awsutil.GetTier.
+// The API key is returned as usage identifier, so API Gateway applies the usage plan the key is
+// registered with.
+type ReadAuthorizer struct {
+	dao datalayer.ClientDAO
+}
+
+func NewReadAuthorizer(dao datalayer.ClientDAO) (ReadAuthorizer, error) {
+	if dao == nil {
+		return ReadAuthorizer{}, errors.New("dao must not be nil")
+	}
+	return ReadAuthorizer{dao}, nil
+}
+
+func (authorizer ReadAuthorizer) Authorize(authRequest events.
+	APIGatewayCustomAuthorizerRequest) (events.APIGatewayCustomAuthorizerResponse, error) {
+	apiKey := strings.TrimSpace(authRequest.AuthorizationToken)
+	if apiKey == "" {
+		return unauthorized(authRequest, "missing API key")
+	}
+
+	client, err := authorizer.dao.GetByKeyHash(model.HashToken(apiKey))
+	if err != nil {
+		if !datalayer.IsNotFound(err) {
+			log.Printf("ERROR: unable to look up client: %v", err)
+		}
+		return unauthorized(authRequest, "unknown API key")
+	}
+
+	resourceArn, err := BuildReadResourceArn(authRequest.MethodArn)
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		return unauthorized(authRequest, "invalid method ARN")
+	}
+
+	log.Printf("AUTHORIZE REQUEST: Type: `%s`, Token: `%s`, Client: `%s`, Tier: `%s`, ARN: `%s`",
+		authRequest.Type, RedactToken(authRequest.AuthorizationToken), client.ClientID,
+		client.Tier, resourceArn)
+
+	return events.APIGatewayCustomAuthorizerResponse{
+		PrincipalID:        client.ClientID,
+		PolicyDocument:     generatePolicy("Allow", []string{resourceArn}),
+		Context:            map[string]interface{}{"tier": string(client.Tier)},
+		UsageIdentifierKey: apiKey,
+	}, nil
+}
+
+// BuildReadResourceArn derives a resource ARN covering all GET endpoints of the stage from the ARN
+// of the invoked method. The policy is cached by API Gateway, hence it must not be limited to the
+// invoked endpoint.
+func BuildReadResourceArn(methodArn string) (string, error) {
+	// resource ARN example layout:
+	// arn:aws:execute-api:eu-central-1:001975686909:pul5mro035/dev/GET/stations/kronehit/tracks
+	split := strings.SplitN(methodArn, "/", 3)
+	if len(split) != 3 {
+		return "", errors.New("unable to split ARN `" + methodArn + "`")
+	}
+	return split[0] + "/" + split[1] + "/GET/*", nil
+}
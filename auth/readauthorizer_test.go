@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"errors"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"github.com/aws/aws-lambda-go/events"
+	"reflect"
+	"testing"
+)
+
+type MockClientDAO struct{}
+
+func (dao MockClientDAO) GetByKeyHash(keyHash string) (model.Client, error) {
+	switch keyHash {
+	case model.HashToken("radio-app-key"):
+		return model.Client{keyHash, "radio-app", model.TierPartner}, nil
+	case model.HashToken("error"):
+		return model.Client{}, errors.New("database error")
+	}
+	return model.Client{}, datalayer.NewNotFoundError("client does not exist")
+}
+
+func TestReadAuthorizer_Authorize(t *testing.T) {
+	methodArn := "arn:aws:execute-api:eu-central-1:001975686909:pul5mro035/dev/GET/stations/" +
+		"kronehit/tracks"
+	authorizer, _ := NewReadAuthorizer(MockClientDAO{})
+
+	var tests = []struct {
+		token            string
+		methodArn        string
+		expectedResponse events.APIGatewayCustomAuthorizerResponse
+		expectedErr      error
+	}{
+		{
+			"radio-app-key",
+			methodArn,
+			events.APIGatewayCustomAuthorizerResponse{
+				PrincipalID: "radio-app",
+				PolicyDocument: generatePolicy("Allow", []string{
+					"arn:aws:execute-api:eu-central-1:001975686909:pul5mro035/dev/GET/*",
+				}),
+				Context:            map[string]interface{}{"tier": "partner"},
+				UsageIdentifierKey: "radio-app-key",
+			},
+			nil,
+		},
+		{"unknown-key", methodArn, events.APIGatewayCustomAuthorizerResponse{}, ErrUnauthorized},
+		{"error", methodArn, events.APIGatewayCustomAuthorizerResponse{}, ErrUnauthorized},
+		{"", methodArn, events.APIGatewayCustomAuthorizerResponse{}, ErrUnauthorized},
+		{"radio-app-key", "invalid", events.APIGatewayCustomAuthorizerResponse{}, ErrUnauthorized},
+	}
+
+	for _, test := range tests {
+		authRequest := events.APIGatewayCustomAuthorizerRequest{
+			Type:               "TOKEN",
+			AuthorizationToken: test.token,
+			MethodArn:          test.methodArn,
+		}
+		response, err := authorizer.Authorize(authRequest)
+		if err != test.expectedErr || !reflect.DeepEqual(response, test.expectedResponse) {
+			t.Errorf("Authorize(%v): got (%v, %v), expected (%v, %v)", authRequest, response, err,
+				test.expectedResponse, test.expectedErr)
+		}
+	}
+}
+
+func TestNewReadAuthorizer(t *testing.T) {
+	if _, err := NewReadAuthorizer(nil); err == nil {
+		t.Error("NewReadAuthorizer(nil): got no error, expected error")
+	}
+}
@@ -0,0 +1,23 @@
+package auth
+
+import "strings"
+
+const redactedVisibleChars = 4
+
+// RedactToken masks the credentials of an `Authorization` header value so it can be logged. The
+// scheme and the first characters of the credentials remain visible to ease debugging.
+func RedactToken(authorizationToken string) string {
+	authorizationToken = strings.TrimSpace(authorizationToken)
+	if authorizationToken == "" {
+		return ""
+	}
+
+	scheme, credentials := "", authorizationToken
+	if idx := strings.Index(authorizationToken, " "); idx > 0 {
+		scheme, credentials = authorizationToken[:idx+1], strings.TrimSpace(authorizationToken[idx:])
+	}
+	if len(credentials) <= redactedVisibleChars {
+		return scheme + "[REDACTED]"
+	}
+	return scheme + credentials[:redactedVisibleChars] + "...[REDACTED]"
+}
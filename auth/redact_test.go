@@ -0,0 +1,23 @@
+package auth
+
+import "testing"
+
+func TestRedactToken(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"Bearer 0123456789abcdef", "Bearer 0123...[REDACTED]"},
+		{"Bearer abc", "Bearer [REDACTED]"},
+		{"0123456789abcdef", "0123...[REDACTED]"},
+		{"RC-HMAC-SHA256 KeyId=oe3,Signature=abc", "RC-HMAC-SHA256 KeyI...[REDACTED]"},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		if result := RedactToken(test.input); result != test.expected {
+			t.Errorf("RedactToken(%q): got `%s`, expected `%s`", test.input, result,
+				test.expected)
+		}
+	}
+}
@@ -0,0 +1,409 @@
+// Command rcadmin runs maintenance tasks against the stored track records:
+//
+//	rcadmin sanitize -from 2018-01-01 [-to 2018-09-23] [-station fm4] [-dry-run=false]
+//	rcadmin rollups -from 2018-01-01 [-to 2018-09-23] [-station fm4]
+//	rcadmin export -from 2018-01-01 [-to 2018-09-23] [-station fm4] [-format jsonl] [-out file]
+//	               [-types track,ad,news]
+//	rcadmin import [-dry-run=false] [-ignore-earliest] [-report file] plays.csv [plays.jsonl ...]
+//	rcadmin credentials create -principal crawler-oe3 -stations hitradio-oe3,fm4 [-token token]
+//
+// `sanitize` runs the current sanitization rules against the track records aired in the period and
+// reports every track record which would change or is rejected by now. Unless `-dry-run=false` is
+// passed, nothing is written. Rejected track records are only reported, never deleted. `rollups`
+// rebuilds the daily rollups from the track records, e. g. after enabling rollups or after
+// sanitizing track records. `export` writes the track records of the period in the order of their
+// airtime as CSV or JSON Lines to stdout or the `-out` file; `-types` selects other record types
+// than tracks. `import` reads playlists in the same
+// formats, sanitizes them and writes the track records which do not exist yet; every rejected
+// record is reported. Like `sanitize`, it only writes if `-dry-run=false` is passed; if rollups are
+// enabled, it rebuilds the rollups of the days it has written track records to. `sanitize`,
+// `rollups` and `import` delete the cached responses of the days and weeks they rewrite.
+// `credentials create` stores the credential of a crawler and prints its token, which is not
+// stored and cannot be shown again; `-token` reuses an existing token instead of generating one.
+//
+// `sanitize`, `rollups` and `import` accept `-rate` to bound the DynamoDB operations per second.
+// `sanitize` and `rollups` accept `-checkpoint` to name a file recording their progress; an
+// interrupted task started again with the same arguments resumes where it stopped. The
+// configuration is read like the API's (CONFIG_FILE and environment), days are calculated in the
+// configured timezone.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/RadioCheckerApp/api/admin"
+	"github.com/RadioCheckerApp/api/config"
+	"github.com/RadioCheckerApp/api/container"
+	"github.com/RadioCheckerApp/api/export"
+	"github.com/RadioCheckerApp/api/model"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const usage = "usage: rcadmin sanitize|rollups|export|import|credentials [flags]"
+
+// taskFlags are shared by all tasks; only resumable tasks accept `-rate` and `-checkpoint`.
+type taskFlags struct {
+	from       *string
+	to         *string
+	station    *string
+	rate       *float64
+	checkpoint *string
+}
+
+func registerTaskFlags(flags *flag.FlagSet, resumable bool) taskFlags {
+	task := taskFlags{
+		from: flags.String("from", "", "first day of the period (`2006-01-02`)"),
+		to: flags.String("to", "", "last day of the period (`2006-01-02`), "+
+			"defaults to yesterday"),
+		station:    flags.String("station", "", "station to process, defaults to all stations"),
+		rate:       new(float64),
+		checkpoint: new(string),
+	}
+	if resumable {
+		task.rate = flags.Float64("rate", 10, "maximum DynamoDB operations per second, "+
+			"0 disables the limit")
+		task.checkpoint = flags.String("checkpoint", "", "file recording the progress to resume from")
+	}
+	return task
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "sanitize":
+		err = sanitize(os.Args[2:])
+	case "rollups":
+		err = rollups(os.Args[2:])
+	case "export":
+		err = exportTrackRecords(os.Args[2:])
+	case "import":
+		err = importTrackRecords(os.Args[2:])
+	case "credentials":
+		err = credentials(os.Args[2:])
+	default:
+		log.Fatal(usage)
+	}
+	if err != nil {
+		log.Println("ERROR: " + err.Error())
+		os.Exit(1)
+	}
+}
+
+func sanitize(args []string) error {
+	flags := flag.NewFlagSet("sanitize", flag.ExitOnError)
+	task := registerTaskFlags(flags, true)
+	dryRun := flags.Bool("dry-run", true, "only report the changes, pass false to rewrite")
+	chunk := flags.Duration("chunk", time.Hour, "period of track records read at once")
+	flags.Parse(args)
+
+	deps, startDate, endDate, options, err := prepare(task)
+	if err != nil {
+		return err
+	}
+	scanner, err := admin.NewScanner(deps.TrackRecordDAO(), *task.station, *chunk, options)
+	if err != nil {
+		return err
+	}
+	resanitizer, err := admin.NewResanitizer(deps.TrackRecordDAO(),
+		deps.Settings().TrackRecordRules, *dryRun, options.Throttle, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	taskID := fmt.Sprintf("sanitize %s %s %s %v", *task.station, *task.from, *task.to, *dryRun)
+	err = scanner.Scan(taskID, startDate, endDate, resanitizer.Process)
+	stats := resanitizer.Stats
+	fmt.Printf("scanned %d, changed %d, rejected %d, rewritten %d, conflicts %d\n", stats.Scanned,
+		stats.Changed, stats.Rejected, stats.Rewritten, stats.Conflicts)
+	if stats.Rewritten > 0 {
+		fmt.Println("track records have been rewritten, run `rcadmin rollups` for the period if " +
+			"rollups are enabled")
+		if purgeErr := purgeResponses(deps, *task.station, startDate, endDate); err == nil {
+			err = purgeErr
+		}
+	}
+	return err
+}
+
+func rollups(args []string) error {
+	flags := flag.NewFlagSet("rollups", flag.ExitOnError)
+	task := registerTaskFlags(flags, true)
+	flags.Parse(args)
+
+	deps, startDate, endDate, options, err := prepare(task)
+	if err != nil {
+		return err
+	}
+	if deps.Rollups() == nil {
+		return errors.New("configuration incomplete: ROLLUPS_TABLE is not set")
+	}
+
+	stations := []string{*task.station}
+	if *task.station == "" {
+		all, err := deps.StationDAO().GetAll()
+		if err != nil {
+			return err
+		}
+		stations = make([]string, len(all))
+		for i, station := range all {
+			stations[i] = station.ID
+		}
+	}
+
+	taskID := fmt.Sprintf("rollups %s %s %s", strings.Join(stations, ","), *task.from, *task.to)
+	if err := admin.RebuildRollups(deps.Rollups(), deps.TrackRecordDAO(), stations, startDate,
+		endDate, taskID, options); err != nil {
+		return err
+	}
+	return purgeResponses(deps, *task.station, startDate, endDate)
+}
+
+// purgeResponses deletes the cached responses of the station, or of all stations if it is empty,
+// which overlap with the period, since their track records or rollups have been rewritten.
+func purgeResponses(deps *container.Container, station string, startDate,
+	endDate time.Time) error {
+	purged, err := deps.ResponseCache().Purge(func(cachedStation string, cachedStartDate,
+		cachedEndDate time.Time) bool {
+		return (station == "" || cachedStation == "" || cachedStation == station) &&
+			!cachedStartDate.After(endDate) && !cachedEndDate.Before(startDate)
+	})
+	fmt.Fprintf(os.Stderr, "purged %d cached responses\n", purged)
+	return err
+}
+
+// purgeWrittenHours deletes the cached responses overlapping with the hours track records have
+// been written to, see admin.Importer.
+func purgeWrittenHours(deps *container.Container, writtenHours map[string][]time.Time) error {
+	if len(writtenHours) == 0 {
+		return nil
+	}
+	purged, err := deps.ResponseCache().Purge(func(cachedStation string, cachedStartDate,
+		cachedEndDate time.Time) bool {
+		for station, hours := range writtenHours {
+			if cachedStation != "" && cachedStation != station {
+				continue
+			}
+			for _, hour := range hours {
+				if !cachedStartDate.After(hour.Add(time.Hour-time.Second)) &&
+					!cachedEndDate.Before(hour) {
+					return true
+				}
+			}
+		}
+		return false
+	})
+	fmt.Fprintf(os.Stderr, "purged %d cached responses\n", purged)
+	return err
+}
+
+// exportTrackRecords cannot be resumed, since a partial export would have to be truncated to the
+// last chunk written.
+func exportTrackRecords(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	task := registerTaskFlags(flags, false)
+	formatStr := flags.String("format", "csv", "export format, `csv` or `jsonl`")
+	out := flags.String("out", "", "file to write the export to, defaults to stdout")
+	chunk := flags.Duration("chunk", export.DefaultChunk, "period of track records read at once")
+	typesStr := flags.String("types", model.TypeTrack, "comma-separated record types, e. g. "+
+		"`track,ad,news`")
+	flags.Parse(args)
+
+	format, err := export.ParseFormat(*formatStr)
+	if err != nil {
+		return err
+	}
+	types, err := model.ParseRecordTypes(*typesStr)
+	if err != nil {
+		return err
+	}
+	deps, startDate, endDate, _, err := prepare(task)
+	if err != nil {
+		return err
+	}
+
+	output := os.Stdout
+	if *out != "" {
+		if output, err = os.Create(*out); err != nil {
+			return err
+		}
+		defer output.Close()
+	}
+	writer, err := export.NewWriter(format, output)
+	if err != nil {
+		return err
+	}
+	count, err := export.Export(deps.TrackRecordDAO(), *task.station, types, startDate, endDate,
+		*chunk, writer)
+	fmt.Fprintf(os.Stderr, "exported %d track records\n", count)
+	return err
+}
+
+func importTrackRecords(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	formatStr := flags.String("format", "", "input format, `csv` or `jsonl`, defaults to the "+
+		"files' extension")
+	dryRun := flags.Bool("dry-run", true, "only report the rejections, pass false to import")
+	ignoreEarliest := flags.Bool("ignore-earliest", false, "accept track records aired before "+
+		"TRACK_EARLIEST_DATE")
+	rate := flags.Float64("rate", 10, "maximum DynamoDB operations per second, 0 disables the limit")
+	reportPath := flags.String("report", "", "file to write the rejections to, defaults to stdout")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		return errors.New("no files to import")
+	}
+
+	deps, err := loadContainer()
+	if err != nil {
+		return err
+	}
+	rules := deps.Settings().TrackRecordRules
+	if *ignoreEarliest {
+		rules.Earliest = time.Time{}
+	}
+	options := admin.Options{Progress: os.Stderr}
+	if options.Throttle, err = admin.NewThrottle(*rate); err != nil {
+		return err
+	}
+	report := os.Stdout
+	if *reportPath != "" {
+		if report, err = os.Create(*reportPath); err != nil {
+			return err
+		}
+		defer report.Close()
+	}
+	importer, err := admin.NewImporter(deps.TrackRecordDAO(), rules, *dryRun, report, options)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range flags.Args() {
+		if err = importFile(importer, name, *formatStr); err != nil {
+			break
+		}
+	}
+	stats := importer.Stats
+	fmt.Fprintf(os.Stderr, "read %d, imported %d, duplicates %d, rejected %d\n", stats.Read,
+		stats.Imported, stats.Duplicates, stats.Rejected)
+	writtenHours := importer.WrittenHours()
+	if deps.Rollups() != nil && len(writtenHours) > 0 {
+		if rollupsErr := admin.RebuildWrittenRollups(deps.Rollups(), deps.TrackRecordDAO(),
+			writtenHours, deps.Settings().Location, options); rollupsErr != nil {
+			fmt.Fprintf(os.Stderr, "unable to rebuild the rollups, run `rcadmin rollups` for the "+
+				"imported days: %v\n", rollupsErr)
+			if err == nil {
+				err = rollupsErr
+			}
+		}
+	}
+	if purgeErr := purgeWrittenHours(deps, writtenHours); err == nil {
+		err = purgeErr
+	}
+	return err
+}
+
+func importFile(importer *admin.Importer, name, formatStr string) error {
+	if formatStr == "" {
+		formatStr = strings.TrimPrefix(strings.ToLower(filepath.Ext(name)), ".")
+	}
+	format, err := export.ParseFormat(formatStr)
+	if err != nil {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	reader, err := export.NewReader(format, file)
+	if err != nil {
+		return err
+	}
+	return importer.Import(name, reader)
+}
+
+func credentials(args []string) error {
+	if len(args) == 0 || args[0] != "create" {
+		return errors.New("usage: rcadmin credentials create -principal <id> -stations <ids>")
+	}
+	flags := flag.NewFlagSet("credentials create", flag.ExitOnError)
+	principalID := flags.String("principal", "", "principal ID recorded on the track records")
+	stationsStr := flags.String("stations", "", "comma-separated stations the crawler may write to")
+	token := flags.String("token", "", "token to reuse, e. g. the former TRACKS_CREATE_AUTH_TOKEN, "+
+		"defaults to a random token")
+	flags.Parse(args[1:])
+
+	deps, err := loadContainer()
+	if err != nil {
+		return err
+	}
+	credentialDAO, err := deps.CredentialDAO()
+	if err != nil {
+		return err
+	}
+	var stations []string
+	if *stationsStr != "" {
+		stations = strings.Split(*stationsStr, ",")
+	}
+	createdToken, err := admin.CreateCredential(credentialDAO, *principalID, stations, *token)
+	if err != nil {
+		return err
+	}
+	fmt.Println(createdToken)
+	return nil
+}
+
+// prepare loads the configuration and parses the flags shared by all tasks. The period ends at the
+// end of the `-to` day.
+func prepare(task taskFlags) (*container.Container, time.Time, time.Time, admin.Options, error) {
+	fail := func(err error) (*container.Container, time.Time, time.Time, admin.Options, error) {
+		return nil, time.Time{}, time.Time{}, admin.Options{}, err
+	}
+
+	deps, err := loadContainer()
+	if err != nil {
+		return fail(err)
+	}
+
+	location := deps.Settings().Location
+	startDate, err := time.ParseInLocation("2006-01-02", *task.from, location)
+	if err != nil {
+		return fail(errors.New("-from must be formatted as `2006-01-02`"))
+	}
+	now := time.Now().In(location)
+	endDate := time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, location)
+	if *task.to != "" {
+		if endDate, err = time.ParseInLocation("2006-01-02", *task.to, location); err != nil {
+			return fail(errors.New("-to must be formatted as `2006-01-02`"))
+		}
+	}
+	endDate = endDate.AddDate(0, 0, 1).Add(-time.Second)
+
+	options := admin.Options{Progress: os.Stderr}
+	if options.Throttle, err = admin.NewThrottle(*task.rate); err != nil {
+		return fail(err)
+	}
+	if *task.checkpoint != "" {
+		if options.Checkpoint, err = admin.NewCheckpoint(*task.checkpoint); err != nil {
+			return fail(err)
+		}
+	}
+	return deps, startDate, endDate, options, nil
+}
+
+func loadContainer() (*container.Container, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	return container.New(cfg)
+}
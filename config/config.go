@@ -0,0 +1,331 @@
+// Package config loads the API's configuration. Defaults are overridden by an optional YAML or
+// JSON file (see CONFIG_FILE) which in turn is overridden by environment variables, so
+// serverless.yml keeps precedence over any file shipped with a function.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/RadioCheckerApp/api/model"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"time"
+)
+
+type Config struct {
+	Storage    Storage    `yaml:"storage"`
+	Time       Time       `yaml:"time"`
+	Validation Validation `yaml:"validation"`
+	Ranking    Ranking    `yaml:"ranking"`
+	Cache      Cache      `yaml:"cache"`
+	RateLimit  RateLimit  `yaml:"rateLimit"`
+	Auth       Auth       `yaml:"auth"`
+}
+
+type Storage struct {
+	StationsTable              string `yaml:"stationsTable"`
+	StationGroupsTable         string `yaml:"stationGroupsTable"`
+	TrackRecordsTable          string `yaml:"trackRecordsTable"`
+	TrackRecordsGSITypeAirtime string `yaml:"trackRecordsGSITypeAirtime"`
+	// CredentialsTable, SignaturesTable and ClientsTable are only required by the authorizers.
+	CredentialsTable string `yaml:"credentialsTable"`
+	SignaturesTable  string `yaml:"signaturesTable"`
+	ClientsTable     string `yaml:"clientsTable"`
+	// ResponseCacheTable optionally shares cached responses between Lambda containers.
+	ResponseCacheTable string `yaml:"responseCacheTable"`
+	// RollupsTable optionally holds the daily play counts, which are read instead of the track
+	// records of complete days.
+	RollupsTable string `yaml:"rollupsTable"`
+	// DynamoDBEndpoint overrides the default endpoint, e. g. to use DynamoDB Local.
+	DynamoDBEndpoint string `yaml:"dynamoDBEndpoint"`
+}
+
+type Time struct {
+	// Timezone is the IANA name of the timezone days and weeks are calculated in.
+	Timezone string `yaml:"timezone"`
+}
+
+type Validation struct {
+	// FutureTolerance is how far track records may lie in the future; some station APIs report
+	// upcoming tracks.
+	FutureTolerance time.Duration `yaml:"futureTolerance"`
+	// EarliestDate (`2006-01-02`) rejects track records aired before RadioChecker existed.
+	EarliestDate string `yaml:"earliestDate"`
+	// DuplicateWindow is the maximum distance between the airtimes of two reports of the same play,
+	// 0 disables the detection of duplicates.
+	DuplicateWindow time.Duration `yaml:"duplicateWindow"`
+	// DuplicatePolicy is either `reject` or `merge`, see request.DuplicatePolicy.
+	DuplicatePolicy string `yaml:"duplicatePolicy"`
+	// Normalization holds the rules merging variants of tracks. TRACK_NORMALIZATION_RULES replaces
+	// them by its JSON encoded rules.
+	Normalization model.NormalizationRules `yaml:"normalization"`
+}
+
+type Ranking struct {
+	// TopRanks is the number of distinct play counts served by the `top` filter.
+	TopRanks int `yaml:"topRanks"`
+	// MinPlays serves the complete chart if no track has been played more often.
+	MinPlays int `yaml:"minPlays"`
+}
+
+type Cache struct {
+	StationTTL time.Duration `yaml:"stationTTL"`
+	// ResponseTTL is how long responses covering the present are cached; responses of past days
+	// and weeks are cached until they are purged, see Generation.
+	ResponseTTL time.Duration `yaml:"responseTTL"`
+	// ResponseCacheSize is the number of responses cached in memory, 0 disables the memory cache.
+	ResponseCacheSize int `yaml:"responseCacheSize"`
+	// Generation is part of the version of cached responses together with the normalization
+	// rules; bumping it drops all cached responses, including those held in memory by Lambdas.
+	Generation int `yaml:"generation"`
+}
+
+// RateLimit configures the token bucket of each client tier, see request.RateLimiter.
+type RateLimit struct {
+	Free     RateLimitTier `yaml:"free"`
+	Partner  RateLimitTier `yaml:"partner"`
+	Internal RateLimitTier `yaml:"internal"`
+}
+
+// RateLimitTier lets a client burst up to Capacity requests, one request is refilled per
+// RefillInterval.
+type RateLimitTier struct {
+	Capacity       int           `yaml:"capacity"`
+	RefillInterval time.Duration `yaml:"refillInterval"`
+}
+
+type Auth struct {
+	// Keys is the JSON encoded key ring of the crawlers, see auth.ParseKeyRing.
+	Keys                string `yaml:"keys"`
+	JWTAudience         string `yaml:"jwtAudience"`
+	StationsManageToken string `yaml:"stationsManageToken"`
+}
+
+func Default() Config {
+	return Config{
+		Time: Time{Timezone: "Europe/Berlin"},
+		Validation: Validation{
+			FutureTolerance: 30 * time.Minute,
+			EarliestDate:    "2016-01-01",
+			DuplicateWindow: 5 * time.Minute,
+			DuplicatePolicy: "reject",
+		},
+		Ranking: Ranking{TopRanks: 3, MinPlays: 3},
+		Cache: Cache{
+			StationTTL:        5 * time.Minute,
+			ResponseTTL:       time.Minute,
+			ResponseCacheSize: 1000,
+		},
+		RateLimit: RateLimit{
+			Free:     RateLimitTier{60, time.Second},
+			Partner:  RateLimitTier{600, 100 * time.Millisecond},
+			Internal: RateLimitTier{6000, 10 * time.Millisecond},
+		},
+	}
+}
+
+// Load reads the configuration from CONFIG_FILE (if set) and the environment and validates it.
+func Load() (Config, error) {
+	config := Default()
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := config.loadFile(path); err != nil {
+			return Config{}, err
+		}
+	}
+	if err := config.loadEnv(); err != nil {
+		return Config{}, err
+	}
+	if err := config.Validate(); err != nil {
+		return Config{}, err
+	}
+	return config, nil
+}
+
+// MustLoad is like Load but panics if the configuration is invalid. It is meant to be called
+// during initialization, where a misconfigured function should fail immediately.
+func MustLoad() Config {
+	config, err := Load()
+	if err != nil {
+		panic(err)
+	}
+	return config
+}
+
+// loadFile overrides the configuration by the file's settings. YAML is a superset of JSON, hence
+// both formats are accepted.
+func (config *Config) loadFile(path string) error {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.New("unable to read config file: " + err.Error())
+	}
+	if err := yaml.Unmarshal(content, config); err != nil {
+		return errors.New("unable to parse config file `" + path + "`: " + err.Error())
+	}
+	return nil
+}
+
+func (config *Config) loadEnv() error {
+	stringSettings := []struct {
+		name  string
+		value *string
+	}{
+		{"STATIONS_TABLE", &config.Storage.StationsTable},
+		{"STATIONGROUPS_TABLE", &config.Storage.StationGroupsTable},
+		{"TRACKRECORDS_TABLE", &config.Storage.TrackRecordsTable},
+		{"TRACKRECORDS_TABLE_GSI_TYPE_AIRTIME", &config.Storage.TrackRecordsGSITypeAirtime},
+		{"CREDENTIALS_TABLE", &config.Storage.CredentialsTable},
+		{"SIGNATURES_TABLE", &config.Storage.SignaturesTable},
+		{"CLIENTS_TABLE", &config.Storage.ClientsTable},
+		{"RESPONSE_CACHE_TABLE", &config.Storage.ResponseCacheTable},
+		{"ROLLUPS_TABLE", &config.Storage.RollupsTable},
+		{"DYNAMODB_ENDPOINT", &config.Storage.DynamoDBEndpoint},
+		{"TIMEZONE", &config.Time.Timezone},
+		{"TRACK_EARLIEST_DATE", &config.Validation.EarliestDate},
+		{"TRACK_DUPLICATE_POLICY", &config.Validation.DuplicatePolicy},
+		{"AUTH_KEYS", &config.Auth.Keys},
+		{"AUTH_JWT_AUDIENCE", &config.Auth.JWTAudience},
+		{"STATIONS_MANAGE_AUTH_TOKEN", &config.Auth.StationsManageToken},
+	}
+	for _, setting := range stringSettings {
+		if value, ok := os.LookupEnv(setting.name); ok {
+			*setting.value = value
+		}
+	}
+
+	durationSettings := []struct {
+		name  string
+		value *time.Duration
+	}{
+		{"TRACK_FUTURE_TOLERANCE", &config.Validation.FutureTolerance},
+		{"TRACK_DUPLICATE_WINDOW", &config.Validation.DuplicateWindow},
+		{"CACHE_STATION_TTL", &config.Cache.StationTTL},
+		{"CACHE_RESPONSE_TTL", &config.Cache.ResponseTTL},
+		{"RATE_LIMIT_FREE_REFILL_INTERVAL", &config.RateLimit.Free.RefillInterval},
+		{"RATE_LIMIT_PARTNER_REFILL_INTERVAL", &config.RateLimit.Partner.RefillInterval},
+		{"RATE_LIMIT_INTERNAL_REFILL_INTERVAL", &config.RateLimit.Internal.RefillInterval},
+	}
+	for _, setting := range durationSettings {
+		if value, ok := os.LookupEnv(setting.name); ok {
+			duration, err := time.ParseDuration(value)
+			if err != nil {
+				return errors.New(setting.name + " must be a duration, e. g. `30m`")
+			}
+			*setting.value = duration
+		}
+	}
+
+	if value, ok := os.LookupEnv("TRACK_NORMALIZATION_RULES"); ok {
+		config.Validation.Normalization = model.NormalizationRules{}
+		if err := json.Unmarshal([]byte(value), &config.Validation.Normalization); err != nil {
+			return errors.New("TRACK_NORMALIZATION_RULES must be JSON encoded rules: " +
+				err.Error())
+		}
+	}
+
+	intSettings := []struct {
+		name  string
+		value *int
+	}{
+		{"RANKING_TOP_RANKS", &config.Ranking.TopRanks},
+		{"RANKING_MIN_PLAYS", &config.Ranking.MinPlays},
+		{"CACHE_RESPONSE_SIZE", &config.Cache.ResponseCacheSize},
+		{"CACHE_GENERATION", &config.Cache.Generation},
+		{"RATE_LIMIT_FREE_CAPACITY", &config.RateLimit.Free.Capacity},
+		{"RATE_LIMIT_PARTNER_CAPACITY", &config.RateLimit.Partner.Capacity},
+		{"RATE_LIMIT_INTERNAL_CAPACITY", &config.RateLimit.Internal.Capacity},
+	}
+	for _, setting := range intSettings {
+		if value, ok := os.LookupEnv(setting.name); ok {
+			number, err := strconv.Atoi(value)
+			if err != nil {
+				return errors.New(setting.name + " must be an integer")
+			}
+			*setting.value = number
+		}
+	}
+	return nil
+}
+
+func (config Config) Validate() error {
+	required := []struct {
+		value string
+		name  string
+	}{
+		{config.Storage.StationsTable, "STATIONS_TABLE"},
+		{config.Storage.StationGroupsTable, "STATIONGROUPS_TABLE"},
+		{config.Storage.TrackRecordsTable, "TRACKRECORDS_TABLE"},
+		{config.Storage.TrackRecordsGSITypeAirtime, "TRACKRECORDS_TABLE_GSI_TYPE_AIRTIME"},
+	}
+	for _, setting := range required {
+		if setting.value == "" {
+			return errors.New("configuration incomplete: " + setting.name + " is not set")
+		}
+	}
+
+	if _, err := config.Time.Location(); err != nil {
+		return err
+	}
+	if config.Validation.FutureTolerance < 0 {
+		return errors.New("futureTolerance must not be negative")
+	}
+	if _, err := config.Validation.Earliest(); err != nil {
+		return err
+	}
+	if config.Validation.DuplicateWindow < 0 {
+		return errors.New("duplicateWindow must not be negative")
+	}
+	if policy := config.Validation.DuplicatePolicy; policy != "reject" && policy != "merge" {
+		return errors.New("duplicatePolicy must be `reject` or `merge`")
+	}
+	if _, err := config.Validation.Normalizer(); err != nil {
+		return err
+	}
+	if config.Ranking.TopRanks < 1 {
+		return errors.New("topRanks must be positive")
+	}
+	if config.Ranking.MinPlays < 0 {
+		return errors.New("minPlays must not be negative")
+	}
+	if config.Cache.StationTTL < 0 || config.Cache.ResponseTTL < 0 {
+		return errors.New("cache TTLs must not be negative")
+	}
+	if config.Cache.ResponseCacheSize < 0 {
+		return errors.New("responseCacheSize must not be negative")
+	}
+	if config.Cache.Generation < 0 {
+		return errors.New("cache generation must not be negative")
+	}
+	for _, tier := range []RateLimitTier{config.RateLimit.Free, config.RateLimit.Partner,
+		config.RateLimit.Internal} {
+		if tier.Capacity < 1 || tier.RefillInterval <= 0 {
+			return errors.New("rate limit capacities and refill intervals must be positive")
+		}
+	}
+	return nil
+}
+
+func (t Time) Location() (*time.Location, error) {
+	location, err := time.LoadLocation(t.Timezone)
+	if err != nil || t.Timezone == "" {
+		return nil, errors.New("unknown timezone `" + t.Timezone + "`")
+	}
+	return location, nil
+}
+
+func (validation Validation) Earliest() (time.Time, error) {
+	earliest, err := time.Parse("2006-01-02", validation.EarliestDate)
+	if err != nil {
+		return time.Time{}, errors.New("earliestDate must be formatted as `2006-01-02`")
+	}
+	return earliest, nil
+}
+
+func (validation Validation) Normalizer() (*model.Normalizer, error) {
+	normalizer, err := model.NewNormalizer(validation.Normalization)
+	if err != nil {
+		return nil, errors.New("invalid normalization rules: " + err.Error())
+	}
+	return normalizer, nil
+}
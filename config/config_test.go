@@ -0,0 +1,178 @@
+package config
+
+import (
+	"github.com/RadioCheckerApp/api/model"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func validConfig() Config {
+	config := Default()
+	config.Storage = Storage{
+		StationsTable:              "stations",
+		StationGroupsTable:         "stationgroups",
+		TrackRecordsTable:          "trackrecords",
+		TrackRecordsGSITypeAirtime: "trackrecords-gsi-type-airtime",
+	}
+	return config
+}
+
+func TestConfig_Validate(t *testing.T) {
+	var tests = []struct {
+		modify      func(config *Config)
+		expectedErr string
+	}{
+		{func(config *Config) {}, ""},
+		{func(config *Config) { config.Storage.TrackRecordsTable = "" },
+			"configuration incomplete: TRACKRECORDS_TABLE is not set"},
+		{func(config *Config) { config.Storage.TrackRecordsGSITypeAirtime = "" },
+			"configuration incomplete: TRACKRECORDS_TABLE_GSI_TYPE_AIRTIME is not set"},
+		{func(config *Config) { config.Time.Timezone = "Europe/Atlantis" },
+			"unknown timezone `Europe/Atlantis`"},
+		{func(config *Config) { config.Time.Timezone = "" }, "unknown timezone ``"},
+		{func(config *Config) { config.Validation.FutureTolerance = -time.Minute },
+			"futureTolerance must not be negative"},
+		{func(config *Config) { config.Validation.EarliestDate = "01.01.2016" },
+			"earliestDate must be formatted as `2006-01-02`"},
+		{func(config *Config) { config.Validation.DuplicatePolicy = "drop" },
+			"duplicatePolicy must be `reject` or `merge`"},
+		{func(config *Config) {
+			config.Validation.Normalization.Common = []model.NormalizationRule{{Type: "alias"}}
+		}, "invalid normalization rules: rule 1: alias rules require a field"},
+		{func(config *Config) { config.Ranking.TopRanks = 0 }, "topRanks must be positive"},
+		{func(config *Config) { config.Cache.StationTTL = -time.Second },
+			"cache TTLs must not be negative"},
+		{func(config *Config) { config.Cache.Generation = -1 },
+			"cache generation must not be negative"},
+		{func(config *Config) { config.RateLimit.Partner.Capacity = 0 },
+			"rate limit capacities and refill intervals must be positive"},
+		{func(config *Config) { config.RateLimit.Free.RefillInterval = 0 },
+			"rate limit capacities and refill intervals must be positive"},
+	}
+
+	for i, test := range tests {
+		config := validConfig()
+		test.modify(&config)
+		err := config.Validate()
+		if (err == nil && test.expectedErr != "") ||
+			(err != nil && err.Error() != test.expectedErr) {
+			t.Errorf("#%d Validate(): got err (%v), expected err (%s)", i, err, test.expectedErr)
+		}
+	}
+}
+
+var envNames = []string{"CONFIG_FILE", "STATIONS_TABLE", "STATIONGROUPS_TABLE",
+	"TRACKRECORDS_TABLE", "TRACKRECORDS_TABLE_GSI_TYPE_AIRTIME", "CACHE_STATION_TTL",
+	"RANKING_TOP_RANKS", "TRACK_NORMALIZATION_RULES", "RATE_LIMIT_FREE_CAPACITY",
+	"RATE_LIMIT_FREE_REFILL_INTERVAL", "CACHE_GENERATION"}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	yamlFile := filepath.Join(dir, "config.yml")
+	ioutil.WriteFile(yamlFile, []byte(`
+storage:
+  stationsTable: file-stations
+  stationGroupsTable: file-stationgroups
+  trackRecordsTable: file-trackrecords
+  trackRecordsGSITypeAirtime: file-gsi
+time:
+  timezone: Europe/Vienna
+validation:
+  futureTolerance: 1h
+ranking:
+  topRanks: 10
+rateLimit:
+  partner:
+    capacity: 1200
+    refillInterval: 50ms
+`), 0600)
+	jsonFile := filepath.Join(dir, "config.json")
+	ioutil.WriteFile(jsonFile, []byte(`{"storage": {"stationsTable": "file-stations",
+		"stationGroupsTable": "file-stationgroups", "trackRecordsTable": "file-trackrecords",
+		"trackRecordsGSITypeAirtime": "file-gsi"}, "cache": {"responseCacheSize": 50}}`), 0600)
+
+	fromFile := Default()
+	fromFile.Storage = Storage{
+		StationsTable:              "file-stations",
+		StationGroupsTable:         "file-stationgroups",
+		TrackRecordsTable:          "file-trackrecords",
+		TrackRecordsGSITypeAirtime: "file-gsi",
+	}
+
+	yamlConfig := fromFile
+	yamlConfig.Time.Timezone = "Europe/Vienna"
+	yamlConfig.Validation.FutureTolerance = time.Hour
+	yamlConfig.Ranking.TopRanks = 10
+	yamlConfig.RateLimit.Partner = RateLimitTier{1200, 50 * time.Millisecond}
+
+	jsonConfig := fromFile
+	jsonConfig.Cache.ResponseCacheSize = 50
+
+	envConfig := yamlConfig
+	envConfig.Storage.TrackRecordsTable = "env-trackrecords"
+	envConfig.Cache.StationTTL = 10 * time.Second
+	envConfig.Cache.Generation = 2
+
+	rateLimitConfig := yamlConfig
+	rateLimitConfig.RateLimit.Free = RateLimitTier{30, 2 * time.Second}
+
+	normalizationConfig := yamlConfig
+	normalizationConfig.Validation.Normalization = model.NormalizationRules{
+		Stations: map[string][]model.NormalizationRule{
+			"fm4": {{Type: "suffix", Field: "title", Suffixes: []string{"(fm4 session)"}}},
+		},
+	}
+
+	var tests = []struct {
+		env         map[string]string
+		expected    Config
+		expectedErr bool
+	}{
+		{map[string]string{"CONFIG_FILE": yamlFile}, yamlConfig, false},
+		{map[string]string{"CONFIG_FILE": jsonFile}, jsonConfig, false},
+		// environment variables take precedence over the file
+		{map[string]string{"CONFIG_FILE": yamlFile, "TRACKRECORDS_TABLE": "env-trackrecords",
+			"CACHE_STATION_TTL": "10s", "CACHE_GENERATION": "2"}, envConfig, false},
+		{map[string]string{"CONFIG_FILE": yamlFile, "RATE_LIMIT_FREE_CAPACITY": "30",
+			"RATE_LIMIT_FREE_REFILL_INTERVAL": "2s"}, rateLimitConfig, false},
+		{map[string]string{"CONFIG_FILE": yamlFile, "CACHE_STATION_TTL": "10"}, Config{}, true},
+		{map[string]string{"CONFIG_FILE": yamlFile, "RANKING_TOP_RANKS": "ten"}, Config{}, true},
+		{map[string]string{"CONFIG_FILE": yamlFile, "TRACK_NORMALIZATION_RULES": `{"stations":
+			{"fm4": [{"type": "suffix", "field": "title", "suffixes": ["(fm4 session)"]}]}}`},
+			normalizationConfig, false},
+		{map[string]string{"CONFIG_FILE": yamlFile, "TRACK_NORMALIZATION_RULES": "suffix"},
+			Config{}, true},
+		{map[string]string{"CONFIG_FILE": yamlFile,
+			"TRACK_NORMALIZATION_RULES": `{"common": [{"type": "regex"}]}`}, Config{}, true},
+		{map[string]string{"CONFIG_FILE": filepath.Join(dir, "missing.yml")}, Config{}, true},
+		// storage settings are required
+		{map[string]string{}, Config{}, true},
+	}
+
+	for i, test := range tests {
+		for _, name := range envNames {
+			os.Unsetenv(name)
+		}
+		for name, value := range test.env {
+			os.Setenv(name, value)
+		}
+
+		config, err := Load()
+		if (err != nil) != test.expectedErr {
+			t.Errorf("#%d Load(): got err (%v), expected err: %v", i, err, test.expectedErr)
+			continue
+		}
+		if !reflect.DeepEqual(config, test.expected) {
+			t.Errorf("#%d Load(): got\n%v, expected\n%v", i, config, test.expected)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+// Package container wires the DAOs the workers depend on. A container is built once per process,
+// i. e. once per Lambda container or once per HTTP server, and shared by all requests.
+package container
+
+import (
+	"errors"
+	"github.com/RadioCheckerApp/api/config"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"github.com/RadioCheckerApp/api/request"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+type Container struct {
+	config          config.Config
+	settings        request.Settings
+	stationDAO      datalayer.StationDAO
+	stationGroupDAO datalayer.StationGroupDAO
+	trackRecordDAO  datalayer.TrackRecordDAO
+	credentialDAO   datalayer.CredentialDAO
+	signatureDAO    datalayer.SignatureDAO
+	clientDAO       datalayer.ClientDAO
+	stationCache    *request.StationCache
+	responseCache   *request.ResponseCache
+	rollups         *request.Rollups
+	rateLimiter     *request.RateLimiter
+}
+
+// New validates the configuration and connects to DynamoDB.
+func New(cfg config.Config) (*Container, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	// credentials and region implicitly defined by serverless.yml
+	awsConfig := &aws.Config{}
+	if cfg.Storage.DynamoDBEndpoint != "" {
+		awsConfig.Endpoint = aws.String(cfg.Storage.DynamoDBEndpoint)
+	}
+	dbSession, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, errors.New("unable to create AWS session: " + err.Error())
+	}
+
+	return NewWithDynamoDB(cfg, dynamodb.New(dbSession))
+}
+
+// NewWithDynamoDB validates the configuration and uses the given DynamoDB client.
+func NewWithDynamoDB(cfg config.Config, db datalayer.DynamoDB) (*Container, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if db == nil {
+		return nil, errors.New("db must not be nil")
+	}
+
+	// Validate guarantees location, earliest date and normalization rules to be valid
+	location, _ := cfg.Time.Location()
+	earliest, _ := cfg.Validation.Earliest()
+	normalizer, _ := cfg.Validation.Normalizer()
+
+	storage := cfg.Storage
+	container := &Container{
+		config: cfg,
+		settings: request.Settings{
+			Location: location,
+			Ranking: request.Ranking{
+				TopRanks: cfg.Ranking.TopRanks,
+				MinPlays: cfg.Ranking.MinPlays,
+			},
+			TrackRecordRules: model.TrackRecordRules{
+				FutureTolerance: cfg.Validation.FutureTolerance,
+				Earliest:        earliest,
+				Normalizer:      normalizer,
+			},
+			Duplicates: request.DuplicatePolicy{
+				Window: cfg.Validation.DuplicateWindow,
+				Merge:  cfg.Validation.DuplicatePolicy == "merge",
+			},
+		},
+		stationDAO:      datalayer.NewDDBStationDAO(db, storage.StationsTable),
+		stationGroupDAO: datalayer.NewDDBStationGroupDAO(db, storage.StationGroupsTable),
+		trackRecordDAO: datalayer.NewDDBTrackRecordDAO(db, storage.TrackRecordsTable,
+			storage.TrackRecordsGSITypeAirtime),
+	}
+
+	// Validate guarantees TTLs and cache size not to be negative, hence the caches cannot fail
+	container.stationCache, _ = request.NewStationCache(container.stationDAO, cfg.Cache.StationTTL)
+	var cacheBackends []datalayer.ResponseCacheDAO
+	if cfg.Cache.ResponseCacheSize > 0 {
+		memoryCache, _ := datalayer.NewMemoryResponseCacheDAO(cfg.Cache.ResponseCacheSize)
+		cacheBackends = append(cacheBackends, memoryCache)
+	}
+	if storage.ResponseCacheTable != "" {
+		cacheBackends = append(cacheBackends,
+			datalayer.NewDDBResponseCacheDAO(db, storage.ResponseCacheTable))
+	}
+	container.responseCache, _ = request.NewResponseCache(cfg.Cache.ResponseTTL,
+		request.CacheVersion(cfg.Cache.Generation, cfg.Validation.Normalization), cacheBackends...)
+
+	if storage.RollupsTable != "" {
+		container.rollups, _ = request.NewRollups(
+			datalayer.NewDDBRollupDAO(db, storage.RollupsTable), location)
+	}
+
+	container.rateLimiter = request.NewRateLimiter(map[model.Tier]request.RateLimitPolicy{
+		model.TierFree:     rateLimitPolicy(cfg.RateLimit.Free),
+		model.TierPartner:  rateLimitPolicy(cfg.RateLimit.Partner),
+		model.TierInternal: rateLimitPolicy(cfg.RateLimit.Internal),
+	})
+
+	if storage.CredentialsTable != "" {
+		container.credentialDAO = datalayer.NewDDBCredentialDAO(db, storage.CredentialsTable)
+	}
+	if storage.SignaturesTable != "" {
+		container.signatureDAO = datalayer.NewDDBSignatureDAO(db, storage.SignaturesTable)
+	}
+	if storage.ClientsTable != "" {
+		container.clientDAO = datalayer.NewDDBClientDAO(db, storage.ClientsTable)
+	}
+	return container, nil
+}
+
+func rateLimitPolicy(tier config.RateLimitTier) request.RateLimitPolicy {
+	return request.RateLimitPolicy{Capacity: tier.Capacity, RefillInterval: tier.RefillInterval}
+}
+
+// MustNew is like New but panics if the container cannot be built. It is meant to be called during
+// initialization, where a misconfigured function should fail immediately.
+func MustNew(cfg config.Config) *Container {
+	container, err := New(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return container
+}
+
+func (container *Container) Config() config.Config {
+	return container.config
+}
+
+// Settings returns the worker settings derived from the configuration.
+func (container *Container) Settings() request.Settings {
+	return container.settings
+}
+
+func (container *Container) StationDAO() datalayer.StationDAO {
+	return container.stationDAO
+}
+
+func (container *Container) StationGroupDAO() datalayer.StationGroupDAO {
+	return container.stationGroupDAO
+}
+
+// StationCache returns the cache of active stations shared by all requests of the container.
+func (container *Container) StationCache() *request.StationCache {
+	return container.stationCache
+}
+
+// ResponseCache returns the cache of worker results shared by all requests of the container. It
+// caches nothing if neither a memory cache size nor a response cache table is configured.
+func (container *Container) ResponseCache() *request.ResponseCache {
+	return container.responseCache
+}
+
+// Rollups returns the daily play counts, nil if ROLLUPS_TABLE is not set.
+func (container *Container) Rollups() *request.Rollups {
+	return container.rollups
+}
+
+// RateLimiter returns the rate limiter shared by all requests of the container.
+func (container *Container) RateLimiter() *request.RateLimiter {
+	return container.rateLimiter
+}
+
+func (container *Container) TrackRecordDAO() datalayer.TrackRecordDAO {
+	return container.trackRecordDAO
+}
+
+func (container *Container) CredentialDAO() (datalayer.CredentialDAO, error) {
+	if container.credentialDAO == nil {
+		return nil, errors.New("configuration incomplete: CREDENTIALS_TABLE is not set")
+	}
+	return container.credentialDAO, nil
+}
+
+func (container *Container) SignatureDAO() (datalayer.SignatureDAO, error) {
+	if container.signatureDAO == nil {
+		return nil, errors.New("configuration incomplete: SIGNATURES_TABLE is not set")
+	}
+	return container.signatureDAO, nil
+}
+
+func (container *Container) ClientDAO() (datalayer.ClientDAO, error) {
+	if container.clientDAO == nil {
+		return nil, errors.New("configuration incomplete: CLIENTS_TABLE is not set")
+	}
+	return container.clientDAO, nil
+}
@@ -0,0 +1,92 @@
+package container
+
+import (
+	"github.com/RadioCheckerApp/api/config"
+	"github.com/RadioCheckerApp/api/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"testing"
+	"time"
+)
+
+func validConfig() config.Config {
+	cfg := config.Default()
+	cfg.Storage = config.Storage{
+		StationsTable:              "stations",
+		StationGroupsTable:         "stationgroups",
+		TrackRecordsTable:          "trackrecords",
+		TrackRecordsGSITypeAirtime: "trackrecords-gsi-type-airtime",
+	}
+	return cfg
+}
+
+func TestNewWithDynamoDB(t *testing.T) {
+	container, err := NewWithDynamoDB(validConfig(), &dynamodb.DynamoDB{})
+	if err != nil {
+		t.Fatalf("NewWithDynamoDB(): got err (%v), expected nil", err)
+	}
+	if container.StationDAO() == nil || container.StationGroupDAO() == nil ||
+		container.TrackRecordDAO() == nil || container.StationCache() == nil ||
+		container.ResponseCache() == nil || container.RateLimiter() == nil {
+		t.Error("NewWithDynamoDB(): expected DAOs, caches and the rate limiter to be wired")
+	}
+	if _, err := container.CredentialDAO(); err == nil {
+		t.Error("CredentialDAO(): got no error, expected error as CREDENTIALS_TABLE is not set")
+	}
+	if _, err := container.SignatureDAO(); err == nil {
+		t.Error("SignatureDAO(): got no error, expected error as SIGNATURES_TABLE is not set")
+	}
+	if _, err := container.ClientDAO(); err == nil {
+		t.Error("ClientDAO(): got no error, expected error as CLIENTS_TABLE is not set")
+	}
+	if rollups := container.Rollups(); rollups != nil {
+		t.Errorf("Rollups(): got %v, expected nil as ROLLUPS_TABLE is not set", rollups)
+	}
+
+	authorizerConfig := validConfig()
+	authorizerConfig.Storage.CredentialsTable = "credentials"
+	authorizerConfig.Storage.SignaturesTable = "signatures"
+	container, _ = NewWithDynamoDB(authorizerConfig, &dynamodb.DynamoDB{})
+	if dao, err := container.CredentialDAO(); dao == nil || err != nil {
+		t.Errorf("CredentialDAO(): got (%v, %v), expected DAO", dao, err)
+	}
+	if dao, err := container.SignatureDAO(); dao == nil || err != nil {
+		t.Errorf("SignatureDAO(): got (%v, %v), expected DAO", dao, err)
+	}
+
+	rollupsConfig := validConfig()
+	rollupsConfig.Storage.RollupsTable = "rollups"
+	container, _ = NewWithDynamoDB(rollupsConfig, &dynamodb.DynamoDB{})
+	if container.Rollups() == nil {
+		t.Error("Rollups(): got nil, expected rollups as ROLLUPS_TABLE is set")
+	}
+
+	if _, err := NewWithDynamoDB(config.Default(), &dynamodb.DynamoDB{}); err == nil {
+		t.Error("NewWithDynamoDB(config.Default()): got no error, expected error")
+	}
+	if _, err := NewWithDynamoDB(validConfig(), nil); err == nil {
+		t.Error("NewWithDynamoDB(nil): got no error, expected error")
+	}
+}
+
+func TestContainer_Settings(t *testing.T) {
+	cfg := validConfig()
+	cfg.Time.Timezone = "Europe/Vienna"
+	cfg.Validation.FutureTolerance = time.Hour
+	cfg.Validation.EarliestDate = "2018-01-01"
+	cfg.Validation.DuplicatePolicy = "merge"
+	cfg.Ranking.TopRanks = 10
+
+	container, err := NewWithDynamoDB(cfg, &dynamodb.DynamoDB{})
+	if err != nil {
+		t.Fatalf("NewWithDynamoDB(): got err (%v), expected nil", err)
+	}
+
+	settings := container.Settings()
+	if settings.Location.String() != "Europe/Vienna" || settings.Ranking.TopRanks != 10 ||
+		settings.Ranking.MinPlays != 3 || settings.TrackRecordRules.FutureTolerance != time.Hour ||
+		!settings.TrackRecordRules.Earliest.Equal(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)) ||
+		settings.Duplicates != (request.DuplicatePolicy{Window: 5 * time.Minute, Merge: true}) ||
+		settings.TrackRecordRules.Normalizer == nil {
+		t.Errorf("Settings(): got %v, expected settings derived from %v", settings, cfg)
+	}
+}
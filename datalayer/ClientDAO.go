@@ -0,0 +1,7 @@
+package datalayer
+
+import "github.com/RadioCheckerApp/api/model"
+
+type ClientDAO interface {
+	GetByKeyHash(keyHash string) (model.Client, error)
+}
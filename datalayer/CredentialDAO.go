@@ -0,0 +1,8 @@
+package datalayer
+
+import "github.com/RadioCheckerApp/api/model"
+
+type CredentialDAO interface {
+	GetByTokenHash(tokenHash string) (model.Credential, error)
+	Create(credential model.Credential) error
+}
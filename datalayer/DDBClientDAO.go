@@ -0,0 +1,40 @@
+package datalayer
+
+import (
+	"github.com/RadioCheckerApp/api/model"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+type DDBClientDAO struct {
+	dynamoDB  DynamoDB
+	tableName string
+}
+
+func NewDDBClientDAO(dynamodb DynamoDB, tableName string) *DDBClientDAO {
+	return &DDBClientDAO{dynamodb, tableName}
+}
+
+func (dao *DDBClientDAO) GetByKeyHash(keyHash string) (model.Client, error) {
+	getInput := &dynamodb.GetItemInput{
+		TableName: aws.String(dao.tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"keyHash": {S: aws.String(keyHash)},
+		},
+	}
+
+	output, err := dao.dynamoDB.GetItem(getInput)
+	if err != nil {
+		return model.Client{}, err
+	}
+	if len(output.Item) == 0 {
+		return model.Client{}, NewNotFoundError("client does not exist")
+	}
+
+	var client model.Client
+	if err := dynamodbattribute.UnmarshalMap(output.Item, &client); err != nil {
+		return model.Client{}, err
+	}
+	return client, nil
+}
@@ -0,0 +1,92 @@
+package datalayer
+
+import (
+	"errors"
+	"github.com/RadioCheckerApp/api/model"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"testing"
+)
+
+var radioAppClient = model.Client{model.HashToken("radio-app-key"), "radio-app", model.TierPartner}
+
+// MockClientsDynamoDB simulates a clients table containing the `radio-app` partner client.
+type MockClientsDynamoDB struct{}
+
+func (ddb MockClientsDynamoDB) ScanPages(input *dynamodb.ScanInput,
+	fn func(*dynamodb.ScanOutput, bool) bool) error {
+	return errors.New("not supported")
+}
+
+func (ddb MockClientsDynamoDB) Query(input *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+	return nil, errors.New("not supported")
+}
+
+func (ddb MockClientsDynamoDB) GetItem(input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput,
+	error) {
+	if input.TableName == nil {
+		return nil, errors.New("TableName must not be nil")
+	}
+	key, ok := input.Key["keyHash"]
+	if !ok || key.S == nil {
+		return nil, errors.New("Key must contain `keyHash`")
+	}
+	if *key.S == "error" {
+		return nil, errors.New("database error")
+	}
+	if *key.S != radioAppClient.KeyHash {
+		return &dynamodb.GetItemOutput{}, nil
+	}
+	return &dynamodb.GetItemOutput{Item: map[string]*dynamodb.AttributeValue{
+		"keyHash":  {S: aws.String(radioAppClient.KeyHash)},
+		"clientId": {S: aws.String("radio-app")},
+		"tier":     {S: aws.String("partner")},
+	}}, nil
+}
+
+func (ddb MockClientsDynamoDB) PutItem(input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput,
+	error) {
+	return nil, errors.New("not supported")
+}
+
+func (ddb MockClientsDynamoDB) UpdateItem(input *dynamodb.UpdateItemInput) (*dynamodb.
+	UpdateItemOutput, error) {
+	return nil, errors.New("not supported")
+}
+
+func (ddb MockClientsDynamoDB) DeleteItem(input *dynamodb.DeleteItemInput) (*dynamodb.
+	DeleteItemOutput, error) {
+	return nil, errors.New("not supported")
+}
+
+func (ddb MockClientsDynamoDB) BatchWriteItem(input *dynamodb.BatchWriteItemInput) (*dynamodb.
+	BatchWriteItemOutput, error) {
+	return nil, errors.New("not supported")
+}
+
+func TestDDBClientDAO_GetByKeyHash(t *testing.T) {
+	clientDAO := NewDDBClientDAO(MockClientsDynamoDB{}, "testTable")
+
+	var tests = []struct {
+		keyHash        string
+		expectedResult model.Client
+		expectedErr    bool
+	}{
+		{radioAppClient.KeyHash, radioAppClient, false},
+		{model.HashToken("unknown"), model.Client{}, true},
+		{"error", model.Client{}, true},
+	}
+
+	for _, test := range tests {
+		result, err := clientDAO.GetByKeyHash(test.keyHash)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("GetByKeyHash(%q): got err (%v), expected err: %v", test.keyHash, err,
+				test.expectedErr)
+			continue
+		}
+		if result != test.expectedResult {
+			t.Errorf("GetByKeyHash(%q): got (%v), expected (%v)", test.keyHash, result,
+				test.expectedResult)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package datalayer
+
+import (
+	"github.com/RadioCheckerApp/api/model"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+type DDBCredentialDAO struct {
+	dynamoDB  DynamoDB
+	tableName string
+}
+
+func NewDDBCredentialDAO(dynamodb DynamoDB, tableName string) *DDBCredentialDAO {
+	return &DDBCredentialDAO{dynamodb, tableName}
+}
+
+func (dao *DDBCredentialDAO) GetByTokenHash(tokenHash string) (model.Credential, error) {
+	getInput := &dynamodb.GetItemInput{
+		TableName: aws.String(dao.tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"tokenHash": {S: aws.String(tokenHash)},
+		},
+	}
+
+	output, err := dao.dynamoDB.GetItem(getInput)
+	if err != nil {
+		return model.Credential{}, err
+	}
+	if len(output.Item) == 0 {
+		return model.Credential{}, NewNotFoundError("credential does not exist")
+	}
+
+	var credential model.Credential
+	if err := dynamodbattribute.UnmarshalMap(output.Item, &credential); err != nil {
+		return model.Credential{}, err
+	}
+	return credential, nil
+}
+
+func (dao *DDBCredentialDAO) Create(credential model.Credential) error {
+	attributeMap, err := dynamodbattribute.MarshalMap(credential)
+	if err != nil {
+		return err
+	}
+
+	putInput := &dynamodb.PutItemInput{
+		TableName:           aws.String(dao.tableName),
+		Item:                attributeMap,
+		ConditionExpression: aws.String("attribute_not_exists(tokenHash)"),
+	}
+
+	_, err = dao.dynamoDB.PutItem(putInput)
+	if isConditionalCheckFailed(err) {
+		return NewAlreadyExistsError("credential for principal " + credential.PrincipalID +
+			" already exists")
+	}
+	return err
+}
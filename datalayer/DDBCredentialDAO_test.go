@@ -0,0 +1,127 @@
+package datalayer
+
+import (
+	"errors"
+	"github.com/RadioCheckerApp/api/model"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"reflect"
+	"testing"
+)
+
+var oe3Credential = model.Credential{model.HashToken("oe3-token"), "oe3-crawler",
+	[]string{"hitradio-oe3"}}
+
+// MockCredentialsDynamoDB simulates a credentials table containing the credential of the
+// `oe3-crawler`.
+type MockCredentialsDynamoDB struct{}
+
+func (ddb MockCredentialsDynamoDB) ScanPages(input *dynamodb.ScanInput,
+	fn func(*dynamodb.ScanOutput, bool) bool) error {
+	return errors.New("not supported")
+}
+
+func (ddb MockCredentialsDynamoDB) Query(input *dynamodb.QueryInput) (*dynamodb.QueryOutput,
+	error) {
+	return nil, errors.New("not supported")
+}
+
+func (ddb MockCredentialsDynamoDB) GetItem(input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput,
+	error) {
+	if input.TableName == nil {
+		return nil, errors.New("TableName must not be nil")
+	}
+	key, ok := input.Key["tokenHash"]
+	if !ok || key.S == nil {
+		return nil, errors.New("Key must contain `tokenHash`")
+	}
+	if *key.S == "error" {
+		return nil, errors.New("database error")
+	}
+	if *key.S != oe3Credential.TokenHash {
+		return &dynamodb.GetItemOutput{}, nil
+	}
+	return &dynamodb.GetItemOutput{Item: map[string]*dynamodb.AttributeValue{
+		"tokenHash":   {S: aws.String(oe3Credential.TokenHash)},
+		"principalId": {S: aws.String("oe3-crawler")},
+		"stations":    {L: []*dynamodb.AttributeValue{{S: aws.String("hitradio-oe3")}}},
+	}}, nil
+}
+
+func (ddb MockCredentialsDynamoDB) PutItem(input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput,
+	error) {
+	if input.TableName == nil {
+		return nil, errors.New("TableName must not be nil")
+	}
+	if input.ConditionExpression == nil ||
+		*input.ConditionExpression != "attribute_not_exists(tokenHash)" {
+		return nil, errors.New("ConditionExpression must be `attribute_not_exists(tokenHash)`")
+	}
+	if *input.Item["tokenHash"].S == oe3Credential.TokenHash {
+		return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "", nil)
+	}
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (ddb MockCredentialsDynamoDB) UpdateItem(input *dynamodb.UpdateItemInput) (*dynamodb.
+	UpdateItemOutput, error) {
+	return nil, errors.New("not supported")
+}
+
+func (ddb MockCredentialsDynamoDB) DeleteItem(input *dynamodb.DeleteItemInput) (*dynamodb.
+	DeleteItemOutput, error) {
+	return nil, errors.New("not supported")
+}
+
+func (ddb MockCredentialsDynamoDB) BatchWriteItem(input *dynamodb.BatchWriteItemInput) (*dynamodb.
+	BatchWriteItemOutput, error) {
+	return nil, errors.New("not supported")
+}
+
+func TestDDBCredentialDAO_GetByTokenHash(t *testing.T) {
+	credentialDAO := NewDDBCredentialDAO(MockCredentialsDynamoDB{}, "testTable")
+
+	var tests = []struct {
+		tokenHash      string
+		expectedResult model.Credential
+		expectedErr    bool
+	}{
+		{oe3Credential.TokenHash, oe3Credential, false},
+		{model.HashToken("unknown"), model.Credential{}, true},
+		{"error", model.Credential{}, true},
+	}
+
+	for _, test := range tests {
+		result, err := credentialDAO.GetByTokenHash(test.tokenHash)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("GetByTokenHash(%q): got err (%v), expected err: %v", test.tokenHash, err,
+				test.expectedErr)
+			continue
+		}
+		if !reflect.DeepEqual(result, test.expectedResult) {
+			t.Errorf("GetByTokenHash(%q): got (%v), expected (%v)", test.tokenHash, result,
+				test.expectedResult)
+		}
+	}
+}
+
+func TestDDBCredentialDAO_Create(t *testing.T) {
+	credentialDAO := NewDDBCredentialDAO(MockCredentialsDynamoDB{}, "testTable")
+
+	var tests = []struct {
+		credential  model.Credential
+		expectedErr bool
+	}{
+		{model.Credential{model.HashToken("fm4-token"), "fm4-crawler", []string{"fm4"}}, false},
+		{oe3Credential, true},
+	}
+
+	for _, test := range tests {
+		err := credentialDAO.Create(test.credential)
+		if (err != nil) != test.expectedErr || IsAlreadyExists(err) != test.expectedErr {
+			t.Errorf("Create(%v): got err (%v), expected conflict: %v", test.credential, err,
+				test.expectedErr)
+		}
+	}
+}
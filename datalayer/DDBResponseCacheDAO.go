@@ -0,0 +1,84 @@
+package datalayer
+
+import (
+	"github.com/RadioCheckerApp/api/model"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// DDBResponseCacheDAO shares cached responses between Lambda containers. The table's time to
+// live attribute is expected to be `expires`.
+type DDBResponseCacheDAO struct {
+	dynamoDB  DynamoDB
+	tableName string
+}
+
+func NewDDBResponseCacheDAO(dynamodb DynamoDB, tableName string) *DDBResponseCacheDAO {
+	return &DDBResponseCacheDAO{dynamodb, tableName}
+}
+
+func (dao *DDBResponseCacheDAO) Get(key string) (model.CachedResponse, error) {
+	getInput := &dynamodb.GetItemInput{
+		TableName: aws.String(dao.tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"cacheKey": {S: aws.String(key)},
+		},
+	}
+
+	output, err := dao.dynamoDB.GetItem(getInput)
+	if err != nil {
+		return model.CachedResponse{}, err
+	}
+	if len(output.Item) == 0 {
+		return model.CachedResponse{}, NewNotFoundError("response " + key + " is not cached")
+	}
+
+	var response model.CachedResponse
+	if err := dynamodbattribute.UnmarshalMap(output.Item, &response); err != nil {
+		return model.CachedResponse{}, err
+	}
+	return response, nil
+}
+
+func (dao *DDBResponseCacheDAO) Put(response model.CachedResponse) error {
+	item, err := dynamodbattribute.MarshalMap(response)
+	if err != nil {
+		return err
+	}
+
+	putInput := &dynamodb.PutItemInput{
+		TableName: aws.String(dao.tableName),
+		Item:      item,
+	}
+	_, err = dao.dynamoDB.PutItem(putInput)
+	return err
+}
+
+func (dao *DDBResponseCacheDAO) Delete(key string) error {
+	deleteInput := &dynamodb.DeleteItemInput{
+		TableName: aws.String(dao.tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"cacheKey": {S: aws.String(key)},
+		},
+	}
+	_, err := dao.dynamoDB.DeleteItem(deleteInput)
+	return err
+}
+
+// Keys scans the table, which is read entirely unless fn stops the scan.
+func (dao *DDBResponseCacheDAO) Keys(fn func(key string) bool) error {
+	scanInput := &dynamodb.ScanInput{
+		TableName:            aws.String(dao.tableName),
+		ProjectionExpression: aws.String("cacheKey"),
+	}
+
+	return dao.dynamoDB.ScanPages(scanInput, func(page *dynamodb.ScanOutput, last bool) bool {
+		for _, item := range page.Items {
+			if key, ok := item["cacheKey"]; ok && key.S != nil && !fn(*key.S) {
+				return false
+			}
+		}
+		return true
+	})
+}
@@ -0,0 +1,185 @@
+package datalayer
+
+import (
+	"errors"
+	"github.com/RadioCheckerApp/api/model"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"reflect"
+	"testing"
+)
+
+// MockResponseCacheDynamoDB simulates a response cache table containing the response `cached`.
+type MockResponseCacheDynamoDB struct{}
+
+// ScanPages returns the keys `cached` and `other` on two pages.
+func (ddb MockResponseCacheDynamoDB) ScanPages(input *dynamodb.ScanInput,
+	fn func(*dynamodb.ScanOutput, bool) bool) error {
+	if input.TableName == nil {
+		return errors.New("TableName must not be nil")
+	}
+	if input.ProjectionExpression == nil || *input.ProjectionExpression != "cacheKey" {
+		return errors.New("ProjectionExpression must be `cacheKey`")
+	}
+	pages := []*dynamodb.ScanOutput{
+		{Items: []map[string]*dynamodb.AttributeValue{{"cacheKey": {S: aws.String("cached")}}}},
+		{Items: []map[string]*dynamodb.AttributeValue{{"cacheKey": {S: aws.String("other")}}}},
+	}
+	for i, page := range pages {
+		if !fn(page, i == len(pages)-1) {
+			break
+		}
+	}
+	return nil
+}
+
+func (ddb MockResponseCacheDynamoDB) Query(input *dynamodb.QueryInput) (*dynamodb.QueryOutput,
+	error) {
+	return nil, errors.New("not supported")
+}
+
+func (ddb MockResponseCacheDynamoDB) GetItem(input *dynamodb.GetItemInput) (*dynamodb.
+	GetItemOutput, error) {
+	if input.TableName == nil {
+		return nil, errors.New("TableName must not be nil")
+	}
+	key, ok := input.Key["cacheKey"]
+	if !ok || key.S == nil {
+		return nil, errors.New("Key must contain `cacheKey`")
+	}
+	switch *key.S {
+	case "error":
+		return nil, errors.New("database error")
+	case "cached":
+		return &dynamodb.GetItemOutput{Item: map[string]*dynamodb.AttributeValue{
+			"cacheKey":     {S: aws.String("cached")},
+			"data":         {S: aws.String(`{"tracks":[]}`)},
+			"expires":      {N: aws.String("1537701181")},
+			"lastModified": {N: aws.String("1537700000")},
+		}}, nil
+	default:
+		return &dynamodb.GetItemOutput{}, nil
+	}
+}
+
+func (ddb MockResponseCacheDynamoDB) PutItem(input *dynamodb.PutItemInput) (*dynamodb.
+	PutItemOutput, error) {
+	if input.TableName == nil {
+		return nil, errors.New("TableName must not be nil")
+	}
+	key, ok := input.Item["cacheKey"]
+	if !ok || key.S == nil || *key.S == "" {
+		return nil, errors.New("Item must contain `cacheKey`")
+	}
+	if _, ok := input.Item["expires"]; ok && *key.S == "immutable" {
+		return nil, errors.New("Item must not contain `expires` if it never expires")
+	}
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (ddb MockResponseCacheDynamoDB) UpdateItem(input *dynamodb.UpdateItemInput) (*dynamodb.
+	UpdateItemOutput, error) {
+	return nil, errors.New("not supported")
+}
+
+func (ddb MockResponseCacheDynamoDB) DeleteItem(input *dynamodb.DeleteItemInput) (*dynamodb.
+	DeleteItemOutput, error) {
+	if input.TableName == nil {
+		return nil, errors.New("TableName must not be nil")
+	}
+	key, ok := input.Key["cacheKey"]
+	if !ok || key.S == nil {
+		return nil, errors.New("Key must contain `cacheKey`")
+	}
+	if *key.S == "error" {
+		return nil, errors.New("database error")
+	}
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+func (ddb MockResponseCacheDynamoDB) BatchWriteItem(input *dynamodb.BatchWriteItemInput) (*dynamodb.
+	BatchWriteItemOutput, error) {
+	return nil, errors.New("not supported")
+}
+
+func TestDDBResponseCacheDAO_Get(t *testing.T) {
+	dao := NewDDBResponseCacheDAO(MockResponseCacheDynamoDB{}, "testTable")
+
+	var tests = []struct {
+		key            string
+		expectedResult model.CachedResponse
+		expectedErr    bool
+	}{
+		{"cached", model.CachedResponse{"cached", `{"tracks":[]}`, 1537701181, 1537700000}, false},
+		{"unknown", model.CachedResponse{}, true},
+		{"error", model.CachedResponse{}, true},
+	}
+
+	for _, test := range tests {
+		result, err := dao.Get(test.key)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("Get(%q): got err (%v), expected err: %v", test.key, err, test.expectedErr)
+			continue
+		}
+		if test.key == "unknown" && !IsNotFound(err) {
+			t.Errorf("Get(%q): got err (%v), expected NotFoundError", test.key, err)
+		}
+		if result != test.expectedResult {
+			t.Errorf("Get(%q): got (%v), expected (%v)", test.key, result, test.expectedResult)
+		}
+	}
+}
+
+func TestDDBResponseCacheDAO_Put(t *testing.T) {
+	dao := NewDDBResponseCacheDAO(MockResponseCacheDynamoDB{}, "testTable")
+
+	var tests = []struct {
+		response    model.CachedResponse
+		expectedErr bool
+	}{
+		{model.CachedResponse{"current", `{"tracks":[]}`, 1537701181, 1537700000}, false},
+		{model.CachedResponse{"immutable", `{"tracks":[]}`, 0, 1537700000}, false},
+		{model.CachedResponse{"", `{"tracks":[]}`, 0, 0}, true},
+	}
+
+	for _, test := range tests {
+		if err := dao.Put(test.response); (err != nil) != test.expectedErr {
+			t.Errorf("Put(%v): got err (%v), expected err: %v", test.response, err,
+				test.expectedErr)
+		}
+	}
+}
+
+func TestDDBResponseCacheDAO_Delete(t *testing.T) {
+	dao := NewDDBResponseCacheDAO(MockResponseCacheDynamoDB{}, "testTable")
+
+	for key, expectedErr := range map[string]bool{"cached": false, "unknown": false,
+		"error": true} {
+		if err := dao.Delete(key); (err != nil) != expectedErr {
+			t.Errorf("Delete(%q): got err (%v), expected err: %v", key, err, expectedErr)
+		}
+	}
+}
+
+func TestDDBResponseCacheDAO_Keys(t *testing.T) {
+	dao := NewDDBResponseCacheDAO(MockResponseCacheDynamoDB{}, "testTable")
+
+	var keys []string
+	err := dao.Keys(func(key string) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if err != nil || !reflect.DeepEqual(keys, []string{"cached", "other"}) {
+		t.Errorf("Keys(): got (%v, %v), expected ([cached other], nil)", keys, err)
+	}
+
+	// the scan stops as soon as fn returns false
+	keys = nil
+	dao.Keys(func(key string) bool {
+		keys = append(keys, key)
+		return false
+	})
+	if !reflect.DeepEqual(keys, []string{"cached"}) {
+		t.Errorf("Keys(): got (%v), expected ([cached])", keys)
+	}
+}
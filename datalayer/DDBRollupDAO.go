@@ -0,0 +1,193 @@
+package datalayer
+
+import (
+	"errors"
+	"github.com/RadioCheckerApp/api/model"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"strconv"
+)
+
+// rollupKeySeparator joins station, artist and title to the sort key of a rollup. Sanitized
+// track records never contain tabs, hence the key is unambiguous.
+const rollupKeySeparator = "\t"
+
+// DDBRollupDAO stores rollups in a table with the partition key `day` and the sort key
+// `rollupKey`, so the rollups of a day can be read for a single station or for all stations.
+type DDBRollupDAO struct {
+	dynamoDB  DynamoDB
+	tableName string
+}
+
+func NewDDBRollupDAO(dynamodb DynamoDB, tableName string) *DDBRollupDAO {
+	return &DDBRollupDAO{dynamodb, tableName}
+}
+
+func (dao *DDBRollupDAO) GetRollups(day string) ([]model.TrackRollup, error) {
+	queryInput := &dynamodb.QueryInput{
+		TableName:              aws.String(dao.tableName),
+		KeyConditionExpression: aws.String("#d = :day"),
+		ExpressionAttributeNames: map[string]*string{
+			"#d": aws.String("day"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":day": {S: aws.String(day)},
+		},
+	}
+
+	return dao.executeQuery(queryInput)
+}
+
+func (dao *DDBRollupDAO) GetRollupsByStation(station, day string) ([]model.TrackRollup, error) {
+	queryInput := &dynamodb.QueryInput{
+		TableName:              aws.String(dao.tableName),
+		KeyConditionExpression: aws.String("#d = :day AND begins_with(rollupKey, :station)"),
+		ExpressionAttributeNames: map[string]*string{
+			"#d": aws.String("day"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":day":     {S: aws.String(day)},
+			":station": {S: aws.String(station + rollupKeySeparator)},
+		},
+	}
+
+	return dao.executeQuery(queryInput)
+}
+
+// executeQuery reads all pages of the query's result; a day may hold more rollups than a single
+// page.
+func (dao *DDBRollupDAO) executeQuery(input *dynamodb.QueryInput) ([]model.TrackRollup, error) {
+	rollups := make([]model.TrackRollup, 0)
+	for {
+		output, err := dao.dynamoDB.Query(input)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []model.TrackRollup
+		if err := dynamodbattribute.UnmarshalListOfMaps(output.Items, &page); err != nil {
+			return nil, err
+		}
+		rollups = append(rollups, page...)
+
+		if len(output.LastEvaluatedKey) == 0 {
+			return rollups, nil
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+}
+
+func (dao *DDBRollupDAO) AddTrackRecord(day string, trackRecord model.TrackRecord) error {
+	key := dao.key(day, trackRecord.StationId, trackRecord.Track)
+	airtime := strconv.FormatInt(trackRecord.Timestamp, 10)
+
+	updateExpression := "SET #sid = :stationId, #a = :artist, #t = :title, lastAirtime = :airtime"
+	values := map[string]*dynamodb.AttributeValue{
+		":stationId": {S: aws.String(trackRecord.StationId)},
+		":artist":    {S: aws.String(trackRecord.Artist)},
+		":title":     {S: aws.String(trackRecord.Title)},
+		":airtime":   {N: aws.String(airtime)},
+		":one":       {N: aws.String("1")},
+	}
+	// the display form of the most recent play is kept, Rebuild determines the most common one
+	if trackRecord.DisplayArtist != "" {
+		updateExpression += ", display_artist = :displayArtist"
+		values[":displayArtist"] = &dynamodb.AttributeValue{S: aws.String(trackRecord.DisplayArtist)}
+	}
+	if trackRecord.DisplayTitle != "" {
+		updateExpression += ", display_title = :displayTitle"
+		values[":displayTitle"] = &dynamodb.AttributeValue{S: aws.String(trackRecord.DisplayTitle)}
+	}
+
+	updateInput := &dynamodb.UpdateItemInput{
+		TableName:        aws.String(dao.tableName),
+		Key:              key,
+		UpdateExpression: aws.String(updateExpression + " ADD plays :one"),
+		// lastAirtime must not be overwritten by a track record reported late
+		ConditionExpression: aws.String(
+			"attribute_not_exists(lastAirtime) OR lastAirtime < :airtime"),
+		ExpressionAttributeNames: map[string]*string{
+			"#sid": aws.String("stationId"),
+			"#a":   aws.String("artist"),
+			"#t":   aws.String("title"),
+		},
+		ExpressionAttributeValues: values,
+	}
+
+	_, err := dao.dynamoDB.UpdateItem(updateInput)
+	if !isConditionalCheckFailed(err) {
+		return err
+	}
+
+	// the rollup exists and holds a more recent play, hence only the counter is updated
+	updateInput = &dynamodb.UpdateItemInput{
+		TableName:        aws.String(dao.tableName),
+		Key:              key,
+		UpdateExpression: aws.String("ADD plays :one"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":one": {N: aws.String("1")},
+		},
+	}
+	_, err = dao.dynamoDB.UpdateItem(updateInput)
+	return err
+}
+
+func (dao *DDBRollupDAO) ReplaceRollups(station, day string, rollups []model.TrackRollup) error {
+	for _, rollup := range rollups {
+		if rollup.StationId != station || rollup.Day != day {
+			return errors.New("rollups must belong to station " + station + " and day " + day)
+		}
+	}
+
+	existingRollups, err := dao.GetRollupsByStation(station, day)
+	if err != nil {
+		return err
+	}
+
+	replaced := make(map[model.Track]bool)
+	for _, rollup := range rollups {
+		if err := dao.put(rollup); err != nil {
+			return err
+		}
+		replaced[rollup.Track] = true
+	}
+
+	for _, rollup := range existingRollups {
+		if replaced[rollup.Track] {
+			continue
+		}
+		deleteInput := &dynamodb.DeleteItemInput{
+			TableName: aws.String(dao.tableName),
+			Key:       dao.key(day, station, rollup.Track),
+		}
+		if _, err := dao.dynamoDB.DeleteItem(deleteInput); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (dao *DDBRollupDAO) put(rollup model.TrackRollup) error {
+	item, err := dynamodbattribute.MarshalMap(rollup)
+	if err != nil {
+		return err
+	}
+	item["rollupKey"] = dao.key(rollup.Day, rollup.StationId, rollup.Track)["rollupKey"]
+
+	putInput := &dynamodb.PutItemInput{
+		TableName: aws.String(dao.tableName),
+		Item:      item,
+	}
+	_, err = dao.dynamoDB.PutItem(putInput)
+	return err
+}
+
+func (dao *DDBRollupDAO) key(day, station string,
+	track model.Track) map[string]*dynamodb.AttributeValue {
+	rollupKey := station + rollupKeySeparator + track.Artist + rollupKeySeparator + track.Title
+	return map[string]*dynamodb.AttributeValue{
+		"day":       {S: aws.String(day)},
+		"rollupKey": {S: aws.String(rollupKey)},
+	}
+}
@@ -0,0 +1,198 @@
+package datalayer
+
+import (
+	"errors"
+	"github.com/RadioCheckerApp/api/model"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"reflect"
+	"testing"
+)
+
+// MockRollupsDynamoDB simulates a rollups table holding two rollups of `fm4` on 2018-09-23, served
+// in two pages, and records the updated, put and deleted items.
+type MockRollupsDynamoDB struct {
+	updates *[]string
+	puts    *[]string
+	deletes *[]string
+}
+
+func newMockRollupsDynamoDB() MockRollupsDynamoDB {
+	return MockRollupsDynamoDB{new([]string), new([]string), new([]string)}
+}
+
+var mockRollupItems = []map[string]*dynamodb.AttributeValue{
+	{
+		"day":         {S: aws.String("2018-09-23")},
+		"rollupKey":   {S: aws.String("fm4\trhcp\tcalifornication")},
+		"stationId":   {S: aws.String("fm4")},
+		"artist":      {S: aws.String("rhcp")},
+		"title":       {S: aws.String("californication")},
+		"plays":       {N: aws.String("3")},
+		"lastAirtime": {N: aws.String("1537704781")},
+	},
+	{
+		"day":         {S: aws.String("2018-09-23")},
+		"rollupKey":   {S: aws.String("fm4\tcardi b\ti like it")},
+		"stationId":   {S: aws.String("fm4")},
+		"artist":      {S: aws.String("cardi b")},
+		"title":       {S: aws.String("i like it")},
+		"plays":       {N: aws.String("1")},
+		"lastAirtime": {N: aws.String("1537700000")},
+	},
+}
+
+func (ddb MockRollupsDynamoDB) ScanPages(input *dynamodb.ScanInput,
+	fn func(*dynamodb.ScanOutput, bool) bool) error {
+	return errors.New("not supported")
+}
+
+func (ddb MockRollupsDynamoDB) Query(input *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+	if input.TableName == nil {
+		return nil, errors.New("TableName must not be nil")
+	}
+	day := input.ExpressionAttributeValues[":day"]
+	if day == nil || day.S == nil {
+		return nil, errors.New("ExpressionAttributeValues must contain `:day`")
+	}
+	switch {
+	case *day.S == "error":
+		return nil, errors.New("database error")
+	case *day.S != "2018-09-23":
+		return &dynamodb.QueryOutput{}, nil
+	case input.ExclusiveStartKey == nil:
+		return &dynamodb.QueryOutput{
+			Items:            mockRollupItems[:1],
+			LastEvaluatedKey: mockRollupItems[0],
+		}, nil
+	default:
+		return &dynamodb.QueryOutput{Items: mockRollupItems[1:]}, nil
+	}
+}
+
+func (ddb MockRollupsDynamoDB) GetItem(input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput,
+	error) {
+	return nil, errors.New("not supported")
+}
+
+func (ddb MockRollupsDynamoDB) PutItem(input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput,
+	error) {
+	key, ok := input.Item["rollupKey"]
+	if !ok || key.S == nil || input.Item["day"] == nil {
+		return nil, errors.New("Item must contain `day` and `rollupKey`")
+	}
+	*ddb.puts = append(*ddb.puts, *key.S)
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+// UpdateItem fails the condition for track records older than the stored play of `californication`.
+func (ddb MockRollupsDynamoDB) UpdateItem(input *dynamodb.UpdateItemInput) (*dynamodb.
+	UpdateItemOutput, error) {
+	key := *input.Key["rollupKey"].S
+	if airtime, ok := input.ExpressionAttributeValues[":airtime"]; ok &&
+		key == "fm4\trhcp\tcalifornication" && *airtime.N < "1537704781" {
+		return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "", nil)
+	}
+	*ddb.updates = append(*ddb.updates, key+"\t"+*input.UpdateExpression)
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+func (ddb MockRollupsDynamoDB) DeleteItem(input *dynamodb.DeleteItemInput) (*dynamodb.
+	DeleteItemOutput, error) {
+	*ddb.deletes = append(*ddb.deletes, *input.Key["rollupKey"].S)
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+func (ddb MockRollupsDynamoDB) BatchWriteItem(input *dynamodb.BatchWriteItemInput) (*dynamodb.
+	BatchWriteItemOutput, error) {
+	return nil, errors.New("not supported")
+}
+
+func TestDDBRollupDAO_GetRollups(t *testing.T) {
+	dao := NewDDBRollupDAO(newMockRollupsDynamoDB(), "testTable")
+
+	var tests = []struct {
+		day            string
+		expectedResult []model.TrackRollup
+		expectedErr    bool
+	}{
+		{"2018-09-23", []model.TrackRollup{
+			{"2018-09-23", "fm4", model.Track{"rhcp", "californication"}, 3, 1537704781,
+				model.TrackDisplay{}},
+			{"2018-09-23", "fm4", model.Track{"cardi b", "i like it"}, 1, 1537700000,
+				model.TrackDisplay{}},
+		}, false},
+		{"2018-09-24", []model.TrackRollup{}, false},
+		{"error", nil, true},
+	}
+
+	for _, test := range tests {
+		result, err := dao.GetRollups(test.day)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("GetRollups(%q): got err (%v), expected err: %v", test.day, err,
+				test.expectedErr)
+			continue
+		}
+		if !reflect.DeepEqual(result, test.expectedResult) {
+			t.Errorf("GetRollups(%q): got (%v), expected (%v)", test.day, result,
+				test.expectedResult)
+		}
+	}
+}
+
+func TestDDBRollupDAO_AddTrackRecord(t *testing.T) {
+	ddb := newMockRollupsDynamoDB()
+	dao := NewDDBRollupDAO(ddb, "testTable")
+
+	trackRecords := []model.TrackRecord{
+		{StationId: "fm4", Timestamp: 1537710000, Track: model.Track{"rhcp", "californication"}},
+		{StationId: "fm4", Timestamp: 1537690000, Track: model.Track{"rhcp", "californication"}},
+		{StationId: "fm4", Timestamp: 1537720000, Track: model.Track{"ty dolla $ign", "or nah"},
+			TrackDisplay: model.TrackDisplay{DisplayArtist: "Ty Dolla $ign"}},
+	}
+	for _, trackRecord := range trackRecords {
+		if err := dao.AddTrackRecord("2018-09-23", trackRecord); err != nil {
+			t.Errorf("AddTrackRecord(%v): got err (%v), expected nil", trackRecord, err)
+		}
+	}
+
+	expected := []string{
+		"fm4\trhcp\tcalifornication\tSET #sid = :stationId, #a = :artist, #t = :title, " +
+			"lastAirtime = :airtime ADD plays :one",
+		// the older track record must not replace lastAirtime
+		"fm4\trhcp\tcalifornication\tADD plays :one",
+		"fm4\tty dolla $ign\tor nah\tSET #sid = :stationId, #a = :artist, #t = :title, " +
+			"lastAirtime = :airtime, display_artist = :displayArtist ADD plays :one",
+	}
+	if !reflect.DeepEqual(*ddb.updates, expected) {
+		t.Errorf("AddTrackRecord(): got updates %q, expected %q", *ddb.updates, expected)
+	}
+}
+
+func TestDDBRollupDAO_ReplaceRollups(t *testing.T) {
+	ddb := newMockRollupsDynamoDB()
+	dao := NewDDBRollupDAO(ddb, "testTable")
+
+	rollups := []model.TrackRollup{
+		{"2018-09-23", "fm4", model.Track{"rhcp", "californication"}, 4, 1537710000,
+			model.TrackDisplay{}},
+		{"2018-09-23", "fm4", model.Track{"mø", "final song"}, 1, 1537705000,
+			model.TrackDisplay{}},
+	}
+	if err := dao.ReplaceRollups("fm4", "2018-09-23", rollups); err != nil {
+		t.Fatalf("ReplaceRollups(): got err (%v), expected nil", err)
+	}
+
+	expectedPuts := []string{"fm4\trhcp\tcalifornication", "fm4\tmø\tfinal song"}
+	expectedDeletes := []string{"fm4\tcardi b\ti like it"}
+	if !reflect.DeepEqual(*ddb.puts, expectedPuts) ||
+		!reflect.DeepEqual(*ddb.deletes, expectedDeletes) {
+		t.Errorf("ReplaceRollups(): got puts %q and deletes %q, expected %q and %q", *ddb.puts,
+			*ddb.deletes, expectedPuts, expectedDeletes)
+	}
+
+	if err := dao.ReplaceRollups("oe3", "2018-09-23", rollups); err == nil {
+		t.Error("ReplaceRollups(\"oe3\"): got no error, expected error for rollups of fm4")
+	}
+}
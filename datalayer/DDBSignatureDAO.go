@@ -0,0 +1,40 @@
+package datalayer
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"strconv"
+	"time"
+)
+
+// DDBSignatureDAO stores signatures along with their expiry as unix time in `expires`, which is
+// meant to be the table's TTL attribute. DynamoDB deletes expired items with a delay, hence they
+// are overwritten regardless.
+type DDBSignatureDAO struct {
+	dynamoDB  DynamoDB
+	tableName string
+}
+
+func NewDDBSignatureDAO(dynamodb DynamoDB, tableName string) *DDBSignatureDAO {
+	return &DDBSignatureDAO{dynamodb, tableName}
+}
+
+func (dao *DDBSignatureDAO) Record(signature string, now, expires time.Time) error {
+	putInput := &dynamodb.PutItemInput{
+		TableName: aws.String(dao.tableName),
+		Item: map[string]*dynamodb.AttributeValue{
+			"signature": {S: aws.String(signature)},
+			"expires":   {N: aws.String(strconv.FormatInt(expires.Unix(), 10))},
+		},
+		ConditionExpression: aws.String("attribute_not_exists(signature) OR expires < :now"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":now": {N: aws.String(strconv.FormatInt(now.Unix(), 10))},
+		},
+	}
+
+	_, err := dao.dynamoDB.PutItem(putInput)
+	if isConditionalCheckFailed(err) {
+		return NewAlreadyExistsError("signature has been recorded already")
+	}
+	return err
+}
@@ -0,0 +1,87 @@
+package datalayer
+
+import (
+	"errors"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"testing"
+	"time"
+)
+
+// MockSignaturesDynamoDB simulates a signatures table containing the unexpired signature
+// `c0ffee`.
+type MockSignaturesDynamoDB struct{}
+
+func (ddb MockSignaturesDynamoDB) ScanPages(input *dynamodb.ScanInput,
+	fn func(*dynamodb.ScanOutput, bool) bool) error {
+	return errors.New("not supported")
+}
+
+func (ddb MockSignaturesDynamoDB) Query(input *dynamodb.QueryInput) (*dynamodb.QueryOutput,
+	error) {
+	return nil, errors.New("not supported")
+}
+
+func (ddb MockSignaturesDynamoDB) GetItem(input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput,
+	error) {
+	return nil, errors.New("not supported")
+}
+
+func (ddb MockSignaturesDynamoDB) PutItem(input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput,
+	error) {
+	if input.TableName == nil {
+		return nil, errors.New("TableName must not be nil")
+	}
+	if input.ConditionExpression == nil ||
+		*input.ConditionExpression != "attribute_not_exists(signature) OR expires < :now" {
+		return nil, errors.New("ConditionExpression must guard unexpired signatures")
+	}
+	if input.Item["expires"].N == nil || input.ExpressionAttributeValues[":now"].N == nil {
+		return nil, errors.New("Item must contain `expires` and values must contain `:now`")
+	}
+	switch *input.Item["signature"].S {
+	case "c0ffee":
+		return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "", nil)
+	case "error":
+		return nil, errors.New("database error")
+	}
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (ddb MockSignaturesDynamoDB) UpdateItem(input *dynamodb.UpdateItemInput) (*dynamodb.
+	UpdateItemOutput, error) {
+	return nil, errors.New("not supported")
+}
+
+func (ddb MockSignaturesDynamoDB) DeleteItem(input *dynamodb.DeleteItemInput) (*dynamodb.
+	DeleteItemOutput, error) {
+	return nil, errors.New("not supported")
+}
+
+func (ddb MockSignaturesDynamoDB) BatchWriteItem(input *dynamodb.BatchWriteItemInput) (*dynamodb.
+	BatchWriteItemOutput, error) {
+	return nil, errors.New("not supported")
+}
+
+func TestDDBSignatureDAO_Record(t *testing.T) {
+	signatureDAO := NewDDBSignatureDAO(MockSignaturesDynamoDB{}, "testTable")
+	now := time.Unix(1537701181, 0)
+
+	var tests = []struct {
+		signature        string
+		expectedConflict bool
+		expectedErr      bool
+	}{
+		{"decaf", false, false},
+		{"c0ffee", true, true},
+		{"error", false, true},
+	}
+
+	for _, test := range tests {
+		err := signatureDAO.Record(test.signature, now, now.Add(5*time.Minute))
+		if (err != nil) != test.expectedErr || IsAlreadyExists(err) != test.expectedConflict {
+			t.Errorf("Record(%q): got err (%v), expected err: %v, conflict: %v", test.signature,
+				err, test.expectedErr, test.expectedConflict)
+		}
+	}
+}
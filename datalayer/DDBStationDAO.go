@@ -3,6 +3,7 @@ package datalayer
 import (
 	"github.com/RadioCheckerApp/api/model"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"log"
@@ -36,3 +37,83 @@ func (dao *DDBStationDAO) GetAll() ([]model.Station, error) {
 
 	return stations, err
 }
+
+func (dao *DDBStationDAO) Get(stationId string) (model.Station, error) {
+	getInput := &dynamodb.GetItemInput{
+		TableName: aws.String(dao.tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"stationId": {S: aws.String(stationId)},
+		},
+	}
+
+	output, err := dao.dynamoDB.GetItem(getInput)
+	if err != nil {
+		return model.Station{}, err
+	}
+	if len(output.Item) == 0 {
+		return model.Station{}, NewNotFoundError("station " + stationId + " does not exist")
+	}
+
+	var station model.Station
+	if err := dynamodbattribute.UnmarshalMap(output.Item, &station); err != nil {
+		return model.Station{}, err
+	}
+	return station, nil
+}
+
+func (dao *DDBStationDAO) Create(station model.Station) error {
+	err := dao.put(station, "attribute_not_exists(stationId)")
+	if isConditionalCheckFailed(err) {
+		return NewAlreadyExistsError("station " + station.ID + " already exists")
+	}
+	return err
+}
+
+func (dao *DDBStationDAO) Update(station model.Station) error {
+	err := dao.put(station, "attribute_exists(stationId)")
+	if isConditionalCheckFailed(err) {
+		return NewNotFoundError("station " + station.ID + " does not exist")
+	}
+	return err
+}
+
+func (dao *DDBStationDAO) Deactivate(stationId string) error {
+	updateInput := &dynamodb.UpdateItemInput{
+		TableName: aws.String(dao.tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"stationId": {S: aws.String(stationId)},
+		},
+		UpdateExpression:    aws.String("SET active = :active"),
+		ConditionExpression: aws.String("attribute_exists(stationId)"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":active": {BOOL: aws.Bool(false)},
+		},
+	}
+
+	_, err := dao.dynamoDB.UpdateItem(updateInput)
+	if isConditionalCheckFailed(err) {
+		return NewNotFoundError("station " + stationId + " does not exist")
+	}
+	return err
+}
+
+func (dao *DDBStationDAO) put(station model.Station, conditionExpression string) error {
+	attributeMap, err := dynamodbattribute.MarshalMap(station)
+	if err != nil {
+		return err
+	}
+
+	putInput := &dynamodb.PutItemInput{
+		TableName:           aws.String(dao.tableName),
+		Item:                attributeMap,
+		ConditionExpression: aws.String(conditionExpression),
+	}
+
+	_, err = dao.dynamoDB.PutItem(putInput)
+	return err
+}
+
+func isConditionalCheckFailed(err error) bool {
+	awsErr, ok := err.(awserr.Error)
+	return ok && awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException
+}
@@ -0,0 +1,243 @@
+package datalayer
+
+import (
+	"errors"
+	"github.com/RadioCheckerApp/api/model"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"reflect"
+	"testing"
+)
+
+// MockStationsDynamoDB simulates a stations table containing the stations `kronehit` (active) and
+// `hitradio-oe3` (inactive).
+type MockStationsDynamoDB struct{}
+
+var mockStationItems = map[string]map[string]*dynamodb.AttributeValue{
+	"kronehit": {
+		"stationId":   {S: aws.String("kronehit")},
+		"name":        {S: aws.String("Kronehit")},
+		"description": {S: aws.String("We are the most music")},
+		"active":      {BOOL: aws.Bool(true)},
+		"country":     {S: aws.String("AT")},
+		"genres":      {L: []*dynamodb.AttributeValue{{S: aws.String("pop")}}},
+		"frequencies": {L: []*dynamodb.AttributeValue{{M: map[string]*dynamodb.AttributeValue{
+			"city": {S: aws.String("Wien")},
+			"mhz":  {N: aws.String("104.6")},
+		}}}},
+	},
+	"hitradio-oe3": {
+		"stationId": {S: aws.String("hitradio-oe3")},
+		"name":      {S: aws.String("Hitradio Ö3")},
+		"active":    {BOOL: aws.Bool(false)},
+	},
+}
+
+func (ddb MockStationsDynamoDB) ScanPages(input *dynamodb.ScanInput,
+	fn func(*dynamodb.ScanOutput, bool) bool) error {
+	if input.TableName == nil {
+		return errors.New("TableName must not be nil")
+	}
+	fn(&dynamodb.ScanOutput{Items: []map[string]*dynamodb.AttributeValue{
+		mockStationItems["kronehit"],
+		mockStationItems["hitradio-oe3"],
+	}}, true)
+	return nil
+}
+
+func (ddb MockStationsDynamoDB) Query(input *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+	return nil, errors.New("not supported")
+}
+
+func (ddb MockStationsDynamoDB) GetItem(input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput,
+	error) {
+	if input.TableName == nil {
+		return nil, errors.New("TableName must not be nil")
+	}
+	key, ok := input.Key["stationId"]
+	if !ok || key.S == nil {
+		return nil, errors.New("Key must contain `stationId`")
+	}
+	if *key.S == "error" {
+		return nil, errors.New("database error")
+	}
+	return &dynamodb.GetItemOutput{Item: mockStationItems[*key.S]}, nil
+}
+
+func (ddb MockStationsDynamoDB) PutItem(input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput,
+	error) {
+	if input.TableName == nil {
+		return nil, errors.New("TableName must not be nil")
+	}
+	stationId, ok := input.Item["stationId"]
+	if !ok || stationId.S == nil {
+		return nil, errors.New("Item must contain `stationId`")
+	}
+	for _, value := range input.Item {
+		if value.S != nil && *value.S == "" {
+			return nil, errors.New("Item must not contain empty strings")
+		}
+	}
+	_, exists := mockStationItems[*stationId.S]
+	if input.ConditionExpression == nil {
+		return nil, errors.New("ConditionExpression must not be nil")
+	}
+	switch *input.ConditionExpression {
+	case "attribute_not_exists(stationId)":
+		if exists {
+			return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "", nil)
+		}
+	case "attribute_exists(stationId)":
+		if !exists {
+			return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "", nil)
+		}
+	default:
+		return nil, errors.New("unexpected ConditionExpression")
+	}
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (ddb MockStationsDynamoDB) UpdateItem(input *dynamodb.UpdateItemInput) (*dynamodb.
+	UpdateItemOutput, error) {
+	if input.TableName == nil {
+		return nil, errors.New("TableName must not be nil")
+	}
+	if input.UpdateExpression == nil || *input.UpdateExpression != "SET active = :active" {
+		return nil, errors.New("UpdateExpression must be `SET active = :active`")
+	}
+	if value, ok := input.ExpressionAttributeValues[":active"]; !ok || value.BOOL == nil ||
+		*value.BOOL {
+		return nil, errors.New("ExpressionAttributeValues must set `:active` to false")
+	}
+	if _, exists := mockStationItems[*input.Key["stationId"].S]; !exists {
+		return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "", nil)
+	}
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+func (ddb MockStationsDynamoDB) DeleteItem(input *dynamodb.DeleteItemInput) (*dynamodb.
+	DeleteItemOutput, error) {
+	return nil, errors.New("not supported")
+}
+
+func (ddb MockStationsDynamoDB) BatchWriteItem(input *dynamodb.BatchWriteItemInput) (*dynamodb.
+	BatchWriteItemOutput, error) {
+	return nil, errors.New("not supported")
+}
+
+var kronehitStation = model.Station{
+	ID:          "kronehit",
+	Name:        "Kronehit",
+	Description: "We are the most music",
+	Active:      true,
+	Country:     "AT",
+	Genres:      []string{"pop"},
+	Frequencies: []model.Frequency{{"Wien", 104.6}},
+}
+
+func TestDDBStationDAO_GetAll(t *testing.T) {
+	stationDAO := NewDDBStationDAO(MockStationsDynamoDB{}, "testTable")
+
+	expectedStations := []model.Station{
+		kronehitStation,
+		{ID: "hitradio-oe3", Name: "Hitradio Ö3"},
+	}
+
+	stations, err := stationDAO.GetAll()
+	if err != nil || !reflect.DeepEqual(stations, expectedStations) {
+		t.Errorf("GetAll(): got (%v, %v), expected (%v, nil)", stations, err, expectedStations)
+	}
+}
+
+func TestDDBStationDAO_Get(t *testing.T) {
+	stationDAO := NewDDBStationDAO(MockStationsDynamoDB{}, "testTable")
+
+	var tests = []struct {
+		stationId      string
+		expectedResult model.Station
+		expectedErr    bool
+	}{
+		{"kronehit", kronehitStation, false},
+		{"hitradio-oe3", model.Station{ID: "hitradio-oe3", Name: "Hitradio Ö3"}, false},
+		{"unknown", model.Station{}, true},
+		{"error", model.Station{}, true},
+	}
+
+	for _, test := range tests {
+		result, err := stationDAO.Get(test.stationId)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("Get(%q): got err (%v), expected err: %v", test.stationId, err,
+				test.expectedErr)
+			continue
+		}
+		if test.stationId == "unknown" && !IsNotFound(err) {
+			t.Errorf("Get(%q): got err (%v), expected NotFoundError", test.stationId, err)
+		}
+		if !reflect.DeepEqual(result, test.expectedResult) {
+			t.Errorf("Get(%q): got (%v), expected (%v)", test.stationId, result,
+				test.expectedResult)
+		}
+	}
+}
+
+func TestDDBStationDAO_Create(t *testing.T) {
+	stationDAO := NewDDBStationDAO(MockStationsDynamoDB{}, "testTable")
+
+	var tests = []struct {
+		station     model.Station
+		expectedErr bool
+	}{
+		{model.Station{ID: "fm4", Name: "FM4", Active: true}, false},
+		{model.Station{ID: "fm4", Name: "FM4", Description: "Alternative", Active: true}, false},
+		{model.Station{ID: "fm4", Name: "FM4", Active: true, Country: "AT", Language: "en",
+			Genres: []string{"alternative"}, Source: "fm4-api"}, false},
+		{model.Station{ID: "kronehit", Name: "Kronehit", Active: true}, true},
+	}
+
+	for _, test := range tests {
+		err := stationDAO.Create(test.station)
+		if (err != nil) != test.expectedErr || IsAlreadyExists(err) != test.expectedErr {
+			t.Errorf("Create(%v): got err (%v), expected conflict: %v", test.station, err,
+				test.expectedErr)
+		}
+	}
+}
+
+func TestDDBStationDAO_Update(t *testing.T) {
+	stationDAO := NewDDBStationDAO(MockStationsDynamoDB{}, "testTable")
+
+	var tests = []struct {
+		station     model.Station
+		expectedErr bool
+	}{
+		{model.Station{ID: "kronehit", Name: "Kronehit", Active: true}, false},
+		{model.Station{ID: "fm4", Name: "FM4", Active: true}, true},
+	}
+
+	for _, test := range tests {
+		if err := stationDAO.Update(test.station); (err != nil) != test.expectedErr {
+			t.Errorf("Update(%v): got err (%v), expected err: %v", test.station, err,
+				test.expectedErr)
+		}
+	}
+}
+
+func TestDDBStationDAO_Deactivate(t *testing.T) {
+	stationDAO := NewDDBStationDAO(MockStationsDynamoDB{}, "testTable")
+
+	var tests = []struct {
+		stationId   string
+		expectedErr bool
+	}{
+		{"kronehit", false},
+		{"fm4", true},
+	}
+
+	for _, test := range tests {
+		if err := stationDAO.Deactivate(test.stationId); (err != nil) != test.expectedErr {
+			t.Errorf("Deactivate(%q): got err (%v), expected err: %v", test.stationId, err,
+				test.expectedErr)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package datalayer
+
+import (
+	"github.com/RadioCheckerApp/api/model"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"log"
+)
+
+type DDBStationGroupDAO struct {
+	dynamoDB  DynamoDB
+	tableName string
+}
+
+func NewDDBStationGroupDAO(dynamodb DynamoDB, tableName string) *DDBStationGroupDAO {
+	return &DDBStationGroupDAO{dynamodb, tableName}
+}
+
+func (dao *DDBStationGroupDAO) GetAll() ([]model.StationGroup, error) {
+	groups := make([]model.StationGroup, 0)
+
+	scanInput := &dynamodb.ScanInput{
+		TableName: aws.String(dao.tableName),
+	}
+
+	err := dao.dynamoDB.ScanPages(scanInput, func(page *dynamodb.ScanOutput, last bool) bool {
+		var grps []model.StationGroup
+		err := dynamodbattribute.UnmarshalListOfMaps(page.Items, &grps)
+		if err != nil {
+			log.Printf("failed to unmarshal DynamoDB scan items: %v", err)
+		}
+		groups = append(groups, grps...)
+		return true
+	})
+
+	return groups, err
+}
+
+func (dao *DDBStationGroupDAO) Get(groupId string) (model.StationGroup, error) {
+	getInput := &dynamodb.GetItemInput{
+		TableName: aws.String(dao.tableName),
+		Key:       dao.key(groupId),
+	}
+
+	output, err := dao.dynamoDB.GetItem(getInput)
+	if err != nil {
+		return model.StationGroup{}, err
+	}
+	if len(output.Item) == 0 {
+		return model.StationGroup{}, NewNotFoundError("group " + groupId + " does not exist")
+	}
+
+	var group model.StationGroup
+	if err := dynamodbattribute.UnmarshalMap(output.Item, &group); err != nil {
+		return model.StationGroup{}, err
+	}
+	return group, nil
+}
+
+func (dao *DDBStationGroupDAO) Create(group model.StationGroup) error {
+	err := dao.put(group, "attribute_not_exists(groupId)")
+	if isConditionalCheckFailed(err) {
+		return NewAlreadyExistsError("group " + group.ID + " already exists")
+	}
+	return err
+}
+
+func (dao *DDBStationGroupDAO) Update(group model.StationGroup) error {
+	err := dao.put(group, "attribute_exists(groupId)")
+	if isConditionalCheckFailed(err) {
+		return NewNotFoundError("group " + group.ID + " does not exist")
+	}
+	return err
+}
+
+func (dao *DDBStationGroupDAO) Delete(groupId string) error {
+	deleteInput := &dynamodb.DeleteItemInput{
+		TableName:           aws.String(dao.tableName),
+		Key:                 dao.key(groupId),
+		ConditionExpression: aws.String("attribute_exists(groupId)"),
+	}
+
+	_, err := dao.dynamoDB.DeleteItem(deleteInput)
+	if isConditionalCheckFailed(err) {
+		return NewNotFoundError("group " + groupId + " does not exist")
+	}
+	return err
+}
+
+func (dao *DDBStationGroupDAO) key(groupId string) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		"groupId": {S: aws.String(groupId)},
+	}
+}
+
+func (dao *DDBStationGroupDAO) put(group model.StationGroup, conditionExpression string) error {
+	attributeMap, err := dynamodbattribute.MarshalMap(group)
+	if err != nil {
+		return err
+	}
+
+	putInput := &dynamodb.PutItemInput{
+		TableName:           aws.String(dao.tableName),
+		Item:                attributeMap,
+		ConditionExpression: aws.String(conditionExpression),
+	}
+
+	_, err = dao.dynamoDB.PutItem(putInput)
+	return err
+}
@@ -0,0 +1,222 @@
+package datalayer
+
+import (
+	"errors"
+	"github.com/RadioCheckerApp/api/model"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"reflect"
+	"testing"
+)
+
+// MockStationGroupsDynamoDB simulates a station groups table containing the group `orf`.
+type MockStationGroupsDynamoDB struct{}
+
+var mockStationGroupItems = map[string]map[string]*dynamodb.AttributeValue{
+	"orf": {
+		"groupId": {S: aws.String("orf")},
+		"name":    {S: aws.String("ORF")},
+		"stations": {L: []*dynamodb.AttributeValue{
+			{S: aws.String("orf-wien")},
+			{S: aws.String("orf-tirol")},
+		}},
+	},
+}
+
+func (ddb MockStationGroupsDynamoDB) ScanPages(input *dynamodb.ScanInput,
+	fn func(*dynamodb.ScanOutput, bool) bool) error {
+	if input.TableName == nil {
+		return errors.New("TableName must not be nil")
+	}
+	fn(&dynamodb.ScanOutput{Items: []map[string]*dynamodb.AttributeValue{
+		mockStationGroupItems["orf"],
+	}}, true)
+	return nil
+}
+
+func (ddb MockStationGroupsDynamoDB) Query(input *dynamodb.QueryInput) (*dynamodb.QueryOutput,
+	error) {
+	return nil, errors.New("not supported")
+}
+
+func (ddb MockStationGroupsDynamoDB) GetItem(input *dynamodb.GetItemInput) (*dynamodb.
+	GetItemOutput, error) {
+	if input.TableName == nil {
+		return nil, errors.New("TableName must not be nil")
+	}
+	key, ok := input.Key["groupId"]
+	if !ok || key.S == nil {
+		return nil, errors.New("Key must contain `groupId`")
+	}
+	if *key.S == "error" {
+		return nil, errors.New("database error")
+	}
+	return &dynamodb.GetItemOutput{Item: mockStationGroupItems[*key.S]}, nil
+}
+
+func (ddb MockStationGroupsDynamoDB) PutItem(input *dynamodb.PutItemInput) (*dynamodb.
+	PutItemOutput, error) {
+	if input.TableName == nil {
+		return nil, errors.New("TableName must not be nil")
+	}
+	groupId, ok := input.Item["groupId"]
+	if !ok || groupId.S == nil {
+		return nil, errors.New("Item must contain `groupId`")
+	}
+	for _, value := range input.Item {
+		if value.S != nil && *value.S == "" {
+			return nil, errors.New("Item must not contain empty strings")
+		}
+	}
+	if input.ConditionExpression == nil {
+		return nil, errors.New("ConditionExpression must not be nil")
+	}
+	_, exists := mockStationGroupItems[*groupId.S]
+	switch *input.ConditionExpression {
+	case "attribute_not_exists(groupId)":
+		if exists {
+			return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "", nil)
+		}
+	case "attribute_exists(groupId)":
+		if !exists {
+			return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "", nil)
+		}
+	default:
+		return nil, errors.New("unexpected ConditionExpression")
+	}
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (ddb MockStationGroupsDynamoDB) UpdateItem(input *dynamodb.UpdateItemInput) (*dynamodb.
+	UpdateItemOutput, error) {
+	return nil, errors.New("not supported")
+}
+
+func (ddb MockStationGroupsDynamoDB) DeleteItem(input *dynamodb.DeleteItemInput) (*dynamodb.
+	DeleteItemOutput, error) {
+	if input.TableName == nil {
+		return nil, errors.New("TableName must not be nil")
+	}
+	if input.ConditionExpression == nil ||
+		*input.ConditionExpression != "attribute_exists(groupId)" {
+		return nil, errors.New("ConditionExpression must be `attribute_exists(groupId)`")
+	}
+	if _, exists := mockStationGroupItems[*input.Key["groupId"].S]; !exists {
+		return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "", nil)
+	}
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+func (ddb MockStationGroupsDynamoDB) BatchWriteItem(input *dynamodb.BatchWriteItemInput) (*dynamodb.
+	BatchWriteItemOutput, error) {
+	return nil, errors.New("not supported")
+}
+
+var orfGroup = model.StationGroup{ID: "orf", Name: "ORF", Stations: []string{"orf-wien",
+	"orf-tirol"}}
+
+func TestDDBStationGroupDAO_GetAll(t *testing.T) {
+	groupDAO := NewDDBStationGroupDAO(MockStationGroupsDynamoDB{}, "testTable")
+
+	expectedGroups := []model.StationGroup{orfGroup}
+
+	groups, err := groupDAO.GetAll()
+	if err != nil || !reflect.DeepEqual(groups, expectedGroups) {
+		t.Errorf("GetAll(): got (%v, %v), expected (%v, nil)", groups, err, expectedGroups)
+	}
+}
+
+func TestDDBStationGroupDAO_Get(t *testing.T) {
+	groupDAO := NewDDBStationGroupDAO(MockStationGroupsDynamoDB{}, "testTable")
+
+	var tests = []struct {
+		groupId        string
+		expectedResult model.StationGroup
+		expectedErr    bool
+	}{
+		{"orf", orfGroup, false},
+		{"unknown", model.StationGroup{}, true},
+		{"error", model.StationGroup{}, true},
+	}
+
+	for _, test := range tests {
+		result, err := groupDAO.Get(test.groupId)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("Get(%q): got err (%v), expected err: %v", test.groupId, err,
+				test.expectedErr)
+			continue
+		}
+		if test.groupId == "unknown" && !IsNotFound(err) {
+			t.Errorf("Get(%q): got err (%v), expected NotFoundError", test.groupId, err)
+		}
+		if !reflect.DeepEqual(result, test.expectedResult) {
+			t.Errorf("Get(%q): got (%v), expected (%v)", test.groupId, result,
+				test.expectedResult)
+		}
+	}
+}
+
+func TestDDBStationGroupDAO_Create(t *testing.T) {
+	groupDAO := NewDDBStationGroupDAO(MockStationGroupsDynamoDB{}, "testTable")
+
+	var tests = []struct {
+		group       model.StationGroup
+		expectedErr bool
+	}{
+		{model.StationGroup{ID: "kronehit", Name: "Kronehit", Stations: []string{"kronehit"}},
+			false},
+		{orfGroup, true},
+	}
+
+	for _, test := range tests {
+		err := groupDAO.Create(test.group)
+		if (err != nil) != test.expectedErr || IsAlreadyExists(err) != test.expectedErr {
+			t.Errorf("Create(%v): got err (%v), expected conflict: %v", test.group, err,
+				test.expectedErr)
+		}
+	}
+}
+
+func TestDDBStationGroupDAO_Update(t *testing.T) {
+	groupDAO := NewDDBStationGroupDAO(MockStationGroupsDynamoDB{}, "testTable")
+
+	var tests = []struct {
+		group       model.StationGroup
+		expectedErr bool
+	}{
+		{orfGroup, false},
+		{model.StationGroup{ID: "kronehit", Name: "Kronehit", Stations: []string{"kronehit"}},
+			true},
+	}
+
+	for _, test := range tests {
+		err := groupDAO.Update(test.group)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("Update(%v): got err (%v), expected err: %v", test.group, err,
+				test.expectedErr)
+		}
+		if test.expectedErr && !IsNotFound(err) {
+			t.Errorf("Update(%v): got err (%v), expected NotFoundError", test.group, err)
+		}
+	}
+}
+
+func TestDDBStationGroupDAO_Delete(t *testing.T) {
+	groupDAO := NewDDBStationGroupDAO(MockStationGroupsDynamoDB{}, "testTable")
+
+	var tests = []struct {
+		groupId     string
+		expectedErr bool
+	}{
+		{"orf", false},
+		{"kronehit", true},
+	}
+
+	for _, test := range tests {
+		if err := groupDAO.Delete(test.groupId); (err != nil) != test.expectedErr {
+			t.Errorf("Delete(%q): got err (%v), expected err: %v", test.groupId, err,
+				test.expectedErr)
+		}
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -24,7 +26,35 @@ func (dao *DDBTrackRecordDAO) GetTrackRecords(startDate, endDate time.Time) ([]m
 	if err := valiDate(startDate, endDate); err != nil {
 		return nil, err
 	}
+	return dao.queryType(model.TypeTrack, startDate, endDate)
+}
+
+// GetRecords queries the type-airtime index once per type and merges the results by airtime.
+func (dao *DDBTrackRecordDAO) GetRecords(types []string, startDate,
+	endDate time.Time) ([]model.TrackRecord, error) {
+	if err := valiDate(startDate, endDate); err != nil {
+		return nil, err
+	}
+
+	trackRecords := make([]model.TrackRecord, 0)
+	for _, recordType := range types {
+		records, err := dao.queryType(recordType, startDate, endDate)
+		if err != nil {
+			return nil, err
+		}
+		trackRecords = append(trackRecords, records...)
+	}
+	sort.Slice(trackRecords, func(i, j int) bool {
+		if trackRecords[i].Timestamp != trackRecords[j].Timestamp {
+			return trackRecords[i].Timestamp < trackRecords[j].Timestamp
+		}
+		return trackRecords[i].StationId < trackRecords[j].StationId
+	})
+	return trackRecords, nil
+}
 
+func (dao *DDBTrackRecordDAO) queryType(recordType string, startDate,
+	endDate time.Time) ([]model.TrackRecord, error) {
 	queryInput := &dynamodb.QueryInput{
 		TableName: aws.String(dao.tableName),
 		IndexName: aws.String(dao.gsiTypeAirtime),
@@ -34,7 +64,7 @@ func (dao *DDBTrackRecordDAO) GetTrackRecords(startDate, endDate time.Time) ([]m
 			"#t": aws.String("type"),
 		},
 		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
-			":type":       {S: aws.String("track")},
+			":type":       {S: aws.String(recordType)},
 			":lowerBound": {N: aws.String(strconv.FormatInt(startDate.Unix(), 10))},
 			":upperBound": {N: aws.String(strconv.FormatInt(endDate.Unix(), 10))},
 		},
@@ -62,13 +92,55 @@ func (dao *DDBTrackRecordDAO) GetTrackRecordsByStation(station string, startDate
 			":stationId":  {S: aws.String(station)},
 			":lowerBound": {N: aws.String(strconv.FormatInt(startDate.Unix(), 10))},
 			":upperBound": {N: aws.String(strconv.FormatInt(endDate.Unix(), 10))},
-			":type":       {S: aws.String("track")},
+			":type":       {S: aws.String(model.TypeTrack)},
 		},
 	}
 
 	return dao.executeQuery(queryInput)
 }
 
+func (dao *DDBTrackRecordDAO) GetRecordsByStation(station string, types []string, startDate,
+	endDate time.Time) ([]model.TrackRecord, error) {
+	if err := valiDate(startDate, endDate); err != nil {
+		return nil, err
+	}
+	if len(types) == 0 {
+		return make([]model.TrackRecord, 0), nil
+	}
+
+	values := map[string]*dynamodb.AttributeValue{
+		":stationId":  {S: aws.String(station)},
+		":lowerBound": {N: aws.String(strconv.FormatInt(startDate.Unix(), 10))},
+		":upperBound": {N: aws.String(strconv.FormatInt(endDate.Unix(), 10))},
+	}
+	placeholders := make([]string, len(types))
+	for i, recordType := range types {
+		placeholders[i] = ":type" + strconv.Itoa(i)
+		values[placeholders[i]] = &dynamodb.AttributeValue{S: aws.String(recordType)}
+	}
+
+	queryInput := &dynamodb.QueryInput{
+		TableName: aws.String(dao.tableName),
+		KeyConditionExpression: aws.String(
+			"#sid = :stationId AND airtime BETWEEN :lowerBound AND :upperBound"),
+		FilterExpression: aws.String("#t IN (" + strings.Join(placeholders, ", ") + ")"),
+		ExpressionAttributeNames: map[string]*string{
+			"#sid": aws.String("stationId"),
+			"#t":   aws.String("type"),
+		},
+		ExpressionAttributeValues: values,
+	}
+
+	return dao.executeQuery(queryInput)
+}
+
+// mostRecentPageSize and mostRecentPages bound the search for the most recent track: the records
+// of the other types aired after it are skipped page by page.
+const (
+	mostRecentPageSize = 10
+	mostRecentPages    = 10
+)
+
 func (dao *DDBTrackRecordDAO) GetMostRecentTrackRecordByStation(station string) (model.
 	TrackRecord, error) {
 	queryInput := &dynamodb.QueryInput{
@@ -78,34 +150,55 @@ func (dao *DDBTrackRecordDAO) GetMostRecentTrackRecordByStation(station string)
 			":stationId": {S: aws.String(station)},
 		},
 		ScanIndexForward: aws.Bool(false), // descending order, defined by sort key
-		Limit:            aws.Int64(1),    // top result only
+		Limit:            aws.Int64(mostRecentPageSize),
 	}
 
-	trackRecords, err := dao.executeQuery(queryInput)
-	if err != nil {
-		return model.TrackRecord{}, err
+	// a filter expression is applied after the limit, hence other types are skipped here
+	for page := 0; page < mostRecentPages; page++ {
+		output, err := dao.dynamoDB.Query(queryInput)
+		if err != nil {
+			return model.TrackRecord{}, err
+		}
+		trackRecords := make([]model.TrackRecord, 0)
+		if err := dynamodbattribute.UnmarshalListOfMaps(output.Items, &trackRecords); err != nil {
+			return model.TrackRecord{}, err
+		}
+		for _, trackRecord := range trackRecords {
+			if trackRecord.Type == model.TypeTrack {
+				return trackRecord, nil
+			}
+		}
+		if len(output.LastEvaluatedKey) == 0 {
+			break
+		}
+		queryInput.ExclusiveStartKey = output.LastEvaluatedKey
 	}
-	if len(trackRecords) == 0 {
-		return model.TrackRecord{},
-			errors.New("no track records in database for station " + station)
-	}
-	return trackRecords[0], nil
+	return model.TrackRecord{},
+		NewNotFoundError("no track records in database for station " + station)
 }
 
+// executeQuery reads all pages of the query's result; a query returns at most 1 MB per page, which
+// a busy station's records of a single day may exceed.
 func (dao *DDBTrackRecordDAO) executeQuery(input *dynamodb.QueryInput) ([]model.TrackRecord,
 	error) {
-	output, err := dao.dynamoDB.Query(input)
-	if err != nil {
-		return nil, err
-	}
-
 	trackRecords := make([]model.TrackRecord, 0)
-	err = dynamodbattribute.UnmarshalListOfMaps(output.Items, &trackRecords)
-	if err != nil {
-		return nil, err
-	}
+	for {
+		output, err := dao.dynamoDB.Query(input)
+		if err != nil {
+			return nil, err
+		}
 
-	return trackRecords, nil
+		var page []model.TrackRecord
+		if err := dynamodbattribute.UnmarshalListOfMaps(output.Items, &page); err != nil {
+			return nil, err
+		}
+		trackRecords = append(trackRecords, page...)
+
+		if len(output.LastEvaluatedKey) == 0 {
+			return trackRecords, nil
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
 }
 
 func valiDate(startDate, endDate time.Time) error {
@@ -116,20 +209,119 @@ func valiDate(startDate, endDate time.Time) error {
 }
 
 func (dao *DDBTrackRecordDAO) CreateTrackRecord(trackRecord model.TrackRecord) error {
+	// put item only if the primary key is unique (https://stackoverflow.com/a/32833726/5801146)
+	// "attribute_not_existing" is looking for AN EXISTING ITEM WITH THE SAME PRIMARY KEY and an
+	// attribute (any value) with the provided name (stationId)
+	err := dao.put(trackRecord, "attribute_not_exists(stationId)")
+	if isConditionalCheckFailed(err) {
+		return NewAlreadyExistsError("track record " + trackRecordPath(trackRecord.StationId,
+			trackRecord.Timestamp) + " already exists")
+	}
+	return err
+}
+
+// UpdateTrackRecord replaces the track record with the same station and airtime.
+func (dao *DDBTrackRecordDAO) UpdateTrackRecord(trackRecord model.TrackRecord) error {
+	err := dao.put(trackRecord, "attribute_exists(stationId)")
+	if isConditionalCheckFailed(err) {
+		return NewNotFoundError("track record " + trackRecordPath(trackRecord.StationId,
+			trackRecord.Timestamp) + " does not exist")
+	}
+	return err
+}
+
+func (dao *DDBTrackRecordDAO) DeleteTrackRecord(station string, airtime int64) error {
+	deleteInput := &dynamodb.DeleteItemInput{
+		TableName: aws.String(dao.tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"stationId": {S: aws.String(station)},
+			"airtime":   {N: aws.String(strconv.FormatInt(airtime, 10))},
+		},
+		ConditionExpression: aws.String("attribute_exists(stationId)"),
+	}
+
+	_, err := dao.dynamoDB.DeleteItem(deleteInput)
+	if isConditionalCheckFailed(err) {
+		return NewNotFoundError("track record " + trackRecordPath(station, airtime) +
+			" does not exist")
+	}
+	return err
+}
+
+func (dao *DDBTrackRecordDAO) put(trackRecord model.TrackRecord, conditionExpression string) error {
 	attributeMap, err := dynamodbattribute.MarshalMap(trackRecord)
 	if err != nil {
 		return err
 	}
 
 	putInput := &dynamodb.PutItemInput{
-		TableName: aws.String(dao.tableName),
-		Item:      attributeMap,
-		// put item only if the primary key is unique (https://stackoverflow.com/a/32833726/5801146)
-		// "attribute_not_existing" is looking for AN EXISTING ITEM WITH THE SAME PRIMARY KEY and an
-		// attribute (any value) with the provided name (stationId)
-		ConditionExpression: aws.String("attribute_not_exists(stationId)"),
+		TableName:           aws.String(dao.tableName),
+		Item:                attributeMap,
+		ConditionExpression: aws.String(conditionExpression),
 	}
 
 	_, err = dao.dynamoDB.PutItem(putInput)
 	return err
 }
+
+// batchWriteSize is the maximum number of items written by a single BatchWriteItem request.
+const batchWriteSize = 25
+
+const batchWriteAttempts = 5
+
+// batchWriteDelay is the delay before the first retry of unprocessed items; it doubles with every
+// further retry. It is replaced by tests.
+var batchWriteDelay = 100 * time.Millisecond
+
+func (dao *DDBTrackRecordDAO) PutTrackRecords(trackRecords []model.TrackRecord) error {
+	for start := 0; start < len(trackRecords); start += batchWriteSize {
+		end := start + batchWriteSize
+		if end > len(trackRecords) {
+			end = len(trackRecords)
+		}
+
+		writeRequests := make([]*dynamodb.WriteRequest, 0, end-start)
+		for _, trackRecord := range trackRecords[start:end] {
+			attributeMap, err := dynamodbattribute.MarshalMap(trackRecord)
+			if err != nil {
+				return err
+			}
+			writeRequests = append(writeRequests,
+				&dynamodb.WriteRequest{PutRequest: &dynamodb.PutRequest{Item: attributeMap}})
+		}
+		if err := dao.batchWrite(writeRequests); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// batchWrite retries the items DynamoDB leaves unprocessed, e. g. if the table's capacity is
+// exceeded, with exponential backoff.
+func (dao *DDBTrackRecordDAO) batchWrite(writeRequests []*dynamodb.WriteRequest) error {
+	pending := map[string][]*dynamodb.WriteRequest{dao.tableName: writeRequests}
+	delay := batchWriteDelay
+	for attempt := 1; ; attempt++ {
+		output, err := dao.dynamoDB.BatchWriteItem(&dynamodb.BatchWriteItemInput{
+			RequestItems: pending,
+		})
+		if err != nil {
+			return err
+		}
+		if len(output.UnprocessedItems[dao.tableName]) == 0 {
+			return nil
+		}
+		if attempt == batchWriteAttempts {
+			return errors.New(strconv.Itoa(len(output.UnprocessedItems[dao.tableName])) +
+				" track records left unprocessed after " + strconv.Itoa(attempt) + " attempts")
+		}
+		pending = output.UnprocessedItems
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// trackRecordPath identifies a track record like the API's paths do, e. g. `fm4/1537701181`.
+func trackRecordPath(station string, airtime int64) string {
+	return station + "/" + strconv.FormatInt(airtime, 10)
+}
@@ -3,7 +3,13 @@ package datalayer
 import (
 	"errors"
 	"github.com/RadioCheckerApp/api/model"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -107,12 +113,65 @@ func (ddb MockDynamoDB) PutItem(input *dynamodb.PutItemInput) (*dynamodb.PutItem
 	}
 
 	if input.ConditionExpression == nil ||
-		*input.ConditionExpression != "attribute_not_exists(stationId)" {
-		return nil, errors.New("ConditionExpression must be `attribute_not_exists(stationId)`")
+		(*input.ConditionExpression != "attribute_not_exists(stationId)" &&
+			*input.ConditionExpression != "attribute_exists(stationId)") {
+		return nil, errors.New("ConditionExpression must be `attribute_not_exists(stationId)` " +
+			"or `attribute_exists(stationId)`")
+	}
+	// the station `conflict` fails every condition
+	if *input.Item["stationId"].S == "conflict" {
+		return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "", nil)
 	}
 	return nil, nil
 }
 
+func (ddb MockDynamoDB) GetItem(input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+	return &dynamodb.GetItemOutput{}, nil
+}
+
+func (ddb MockDynamoDB) UpdateItem(input *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput,
+	error) {
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+func (ddb MockDynamoDB) DeleteItem(input *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput,
+	error) {
+	if input.Key["stationId"] == nil || input.Key["airtime"] == nil {
+		return nil, errors.New("Key must contain `stationId` and `airtime`")
+	}
+	if *input.Key["stationId"].S == "conflict" {
+		return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "", nil)
+	}
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+// BatchWriteItem never processes track records of the station `throttled`.
+func (ddb MockDynamoDB) BatchWriteItem(input *dynamodb.BatchWriteItemInput) (*dynamodb.
+	BatchWriteItemOutput, error) {
+	writeRequests, ok := input.RequestItems["testTable"]
+	if !ok || len(input.RequestItems) != 1 {
+		return nil, errors.New("RequestItems must contain the table `testTable` only")
+	}
+	if len(writeRequests) > 25 {
+		return nil, errors.New("RequestItems must not contain more than 25 write requests")
+	}
+
+	var unprocessed []*dynamodb.WriteRequest
+	for _, writeRequest := range writeRequests {
+		if writeRequest.PutRequest == nil || len(writeRequest.PutRequest.Item) < 5 {
+			return nil, errors.New("write requests must put items of at least 5 mappings")
+		}
+		if *writeRequest.PutRequest.Item["stationId"].S == "throttled" {
+			unprocessed = append(unprocessed, writeRequest)
+		}
+	}
+	output := &dynamodb.BatchWriteItemOutput{}
+	if len(unprocessed) > 0 {
+		output.UnprocessedItems = map[string][]*dynamodb.WriteRequest{"testTable": unprocessed}
+	}
+	return output, nil
+}
+
 type MockDynamoDBLimitedQuery struct{}
 
 func (ddb MockDynamoDBLimitedQuery) ScanPages(input *dynamodb.ScanInput,
@@ -186,6 +245,26 @@ func (ddb MockDynamoDBLimitedQuery) PutItem(input *dynamodb.PutItemInput) (*dyna
 	return nil, nil
 }
 
+func (ddb MockDynamoDBLimitedQuery) GetItem(input *dynamodb.GetItemInput) (*dynamodb.
+	GetItemOutput, error) {
+	return &dynamodb.GetItemOutput{}, nil
+}
+
+func (ddb MockDynamoDBLimitedQuery) UpdateItem(input *dynamodb.UpdateItemInput) (*dynamodb.
+	UpdateItemOutput, error) {
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+func (ddb MockDynamoDBLimitedQuery) DeleteItem(input *dynamodb.DeleteItemInput) (*dynamodb.
+	DeleteItemOutput, error) {
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+func (ddb MockDynamoDBLimitedQuery) BatchWriteItem(input *dynamodb.BatchWriteItemInput) (*dynamodb.
+	BatchWriteItemOutput, error) {
+	return &dynamodb.BatchWriteItemOutput{}, nil
+}
+
 func TestDDBTrackRecordDAO_GetTrackRecordsSuccess(t *testing.T) {
 	trackRecordDAO := NewDDBTrackRecordDAO(
 		MockDynamoDB{},
@@ -195,8 +274,8 @@ func TestDDBTrackRecordDAO_GetTrackRecordsSuccess(t *testing.T) {
 	endDate := time.Now()
 
 	expectedTrackRecords := []model.TrackRecord{
-		{"station-a", 1532897851, "track", model.Track{"Mø", "Final Song"}},
-		{"station-b", 1532897892, "track", model.Track{"Jack Ü ft. Skrillex & Diplo",
+		{StationId: "station-a", Timestamp: 1532897851, Type: "track", Track: model.Track{"Mø", "Final Song"}},
+		{StationId: "station-b", Timestamp: 1532897892, Type: "track", Track: model.Track{"Jack Ü ft. Skrillex & Diplo",
 			"Where Are Ü Now"}},
 	}
 
@@ -245,8 +324,8 @@ func TestDDBTrackRecordDAO_GetTrackRecordsByStationSuccess(t *testing.T) {
 	endDate := time.Now()
 
 	expectedTrackRecords := []model.TrackRecord{
-		{"station-a", 1532897851, "track", model.Track{"Mø", "Final Song"}},
-		{"station-b", 1532897892, "track", model.Track{"Jack Ü ft. Skrillex & Diplo",
+		{StationId: "station-a", Timestamp: 1532897851, Type: "track", Track: model.Track{"Mø", "Final Song"}},
+		{StationId: "station-b", Timestamp: 1532897892, Type: "track", Track: model.Track{"Jack Ü ft. Skrillex & Diplo",
 			"Where Are Ü Now"}},
 	}
 
@@ -292,10 +371,10 @@ func TestDDBTrackRecordDAO_GetMostRecentTrackRecordByStationSuccess(t *testing.T
 	station := "station-a"
 
 	expectedTrackRecord := model.TrackRecord{
-		"station-a",
-		1234567890,
-		"track",
-		model.Track{"rhcp", "californication"},
+		StationId: "station-a",
+		Timestamp: 1234567890,
+		Type:      "track",
+		Track:     model.Track{"rhcp", "californication"},
 	}
 
 	trackRecord, err := trackRecordDAO.GetMostRecentTrackRecordByStation(station)
@@ -315,13 +394,24 @@ func TestDDBTrackRecordDAO_GetMostRecentTrackRecordByStationSuccess(t *testing.T
 func TestDDBTrackRecordDAO_GetMostRecentTrackRecordByStationFail(t *testing.T) {
 	trackRecordDAO := NewDDBTrackRecordDAO(MockDynamoDBLimitedQuery{}, "testTable", "gsi")
 
-	var tests = []string{"notracksstation", "error"}
+	var tests = []struct {
+		station          string
+		expectedNotFound bool
+	}{
+		{"notracksstation", true},
+		{"error", false},
+	}
 
 	for _, test := range tests {
-		trackRecord, err := trackRecordDAO.GetMostRecentTrackRecordByStation(test)
+		trackRecord, err := trackRecordDAO.GetMostRecentTrackRecordByStation(test.station)
 		if err == nil {
 			t.Errorf("(%q) GetMostRecentTrackRecordByStation(%q): got (%q, %v), expected (nil, error)",
-				trackRecordDAO, test, trackRecord, err)
+				trackRecordDAO, test.station, trackRecord, err)
+			continue
+		}
+		if IsNotFound(err) != test.expectedNotFound {
+			t.Errorf("(%q) GetMostRecentTrackRecordByStation(%q): got IsNotFound(%v) = %v, expected %v",
+				trackRecordDAO, test.station, err, IsNotFound(err), test.expectedNotFound)
 		}
 	}
 }
@@ -330,7 +420,7 @@ func TestDDBTrackRecordDAO_CreateTrackRecord(t *testing.T) {
 	trackRecordDAO := NewDDBTrackRecordDAO(MockDynamoDB{}, "testTable", "gsi")
 
 	var tests = []model.TrackRecord{
-		{"station-a", time.Now().Unix(), "track", model.Track{"RHCP", "Californication"}},
+		{StationId: "station-a", Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "Californication"}},
 	}
 
 	for _, testRecord := range tests {
@@ -341,3 +431,251 @@ func TestDDBTrackRecordDAO_CreateTrackRecord(t *testing.T) {
 		}
 	}
 }
+
+// TestTrackRecordAttributes ensures the metadata is stored as attributes of its own, which are
+// omitted if empty.
+func TestTrackRecordAttributes(t *testing.T) {
+	trackRecord := model.TrackRecord{StationId: "oe3", Timestamp: 1537703000, Type: "track",
+		Track: model.Track{"mø", "final song"},
+		TrackMetadata: model.TrackMetadata{Duration: 235, ISRC: "GBARL1500898",
+			CoverURL: "https://example.com/cover.jpg"},
+		TrackDisplay: model.TrackDisplay{DisplayArtist: "MØ"}}
+
+	item, err := dynamodbattribute.MarshalMap(trackRecord)
+	if err != nil {
+		t.Fatalf("MarshalMap(): got err (%v), expected nil", err)
+	}
+	names := make([]string, 0, len(item))
+	for name := range item {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	expected := []string{"airtime", "artist", "cover_url", "display_artist", "duration", "isrc",
+		"stationId", "title", "type"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("MarshalMap(): got attributes %v, expected %v", names, expected)
+	}
+
+	var result model.TrackRecord
+	if err := dynamodbattribute.UnmarshalMap(item, &result); err != nil || result != trackRecord {
+		t.Errorf("UnmarshalMap(): got (%v, %v), expected (%v, nil)", result, err, trackRecord)
+	}
+}
+
+func TestDDBTrackRecordDAO_CreateTrackRecord_AlreadyExists(t *testing.T) {
+	trackRecordDAO := NewDDBTrackRecordDAO(MockDynamoDB{}, "testTable", "gsi")
+	trackRecord := model.TrackRecord{StationId: "conflict", Timestamp: time.Now().Unix(),
+		Type: "track", Track: model.Track{"RHCP", "Californication"}}
+
+	if err := trackRecordDAO.CreateTrackRecord(trackRecord); !IsAlreadyExists(err) {
+		t.Errorf("CreateTrackRecord(%v): got err (%v), expected AlreadyExistsError", trackRecord,
+			err)
+	}
+}
+
+func TestDDBTrackRecordDAO_UpdateTrackRecord(t *testing.T) {
+	trackRecordDAO := NewDDBTrackRecordDAO(MockDynamoDB{}, "testTable", "gsi")
+
+	var tests = []struct {
+		trackRecord      model.TrackRecord
+		expectedErr      bool
+		expectedNotFound bool
+	}{
+		{model.TrackRecord{StationId: "station-a", Timestamp: 1537701181, Type: "track",
+			Track: model.Track{"rhcp", "californication"}}, false, false},
+		{model.TrackRecord{StationId: "conflict", Timestamp: 1537701181, Type: "track",
+			Track: model.Track{"rhcp", "californication"}}, true, true},
+	}
+
+	for _, test := range tests {
+		err := trackRecordDAO.UpdateTrackRecord(test.trackRecord)
+		if (err != nil) != test.expectedErr || IsNotFound(err) != test.expectedNotFound {
+			t.Errorf("UpdateTrackRecord(%v): got err (%v), expected err: %v (not found: %v)",
+				test.trackRecord, err, test.expectedErr, test.expectedNotFound)
+		}
+	}
+}
+
+func TestDDBTrackRecordDAO_DeleteTrackRecord(t *testing.T) {
+	trackRecordDAO := NewDDBTrackRecordDAO(MockDynamoDB{}, "testTable", "gsi")
+
+	if err := trackRecordDAO.DeleteTrackRecord("station-a", 1537701181); err != nil {
+		t.Errorf("DeleteTrackRecord(\"station-a\", 1537701181): got err (%v), expected nil", err)
+	}
+	if err := trackRecordDAO.DeleteTrackRecord("conflict", 1537701181); !IsNotFound(err) {
+		t.Errorf("DeleteTrackRecord(\"conflict\", 1537701181): got err (%v), expected "+
+			"NotFoundError", err)
+	}
+}
+
+func TestDDBTrackRecordDAO_PutTrackRecords(t *testing.T) {
+	batchWriteDelay = 0
+	trackRecordDAO := NewDDBTrackRecordDAO(MockDynamoDB{}, "testTable", "gsi")
+
+	trackRecords := make([]model.TrackRecord, 60)
+	for i := range trackRecords {
+		trackRecords[i] = model.TrackRecord{StationId: "station-a", Timestamp: int64(1537701181 + i),
+			Type: "track", Track: model.Track{"rhcp", "californication"}}
+	}
+	if err := trackRecordDAO.PutTrackRecords(trackRecords); err != nil {
+		t.Errorf("PutTrackRecords(60 track records): got err (%v), expected nil", err)
+	}
+
+	trackRecords[42].StationId = "throttled"
+	if err := trackRecordDAO.PutTrackRecords(trackRecords); err == nil {
+		t.Error("PutTrackRecords(): got no error, expected error for unprocessed track records")
+	}
+}
+
+// MockTypedDynamoDB holds records of several types and evaluates queries of the table and the
+// type-airtime index on them; queries are served in pages of `Limit` items, or of `pageSize` items
+// in ascending order if it is set.
+type MockTypedDynamoDB struct {
+	MockDynamoDB
+	trackRecords []model.TrackRecord
+	pageSize     int64
+}
+
+func (ddb MockTypedDynamoDB) Query(input *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+	values := input.ExpressionAttributeValues
+	number := func(key string, fallback int64) int64 {
+		if value, ok := values[key]; ok {
+			n, _ := strconv.ParseInt(*value.N, 10, 64)
+			return n
+		}
+		return fallback
+	}
+	types := make([]string, 0)
+	for key, value := range values {
+		if strings.HasPrefix(key, ":type") {
+			types = append(types, *value.S)
+		}
+	}
+
+	descending := input.ScanIndexForward != nil && !*input.ScanIndexForward
+	matches := make([]model.TrackRecord, 0)
+	for _, trackRecord := range ddb.trackRecords {
+		if station, ok := values[":stationId"]; ok && trackRecord.StationId != *station.S {
+			continue
+		}
+		if len(types) > 0 && !containsType(types, trackRecord.Type) {
+			continue
+		}
+		if trackRecord.Timestamp < number(":lowerBound", 0) ||
+			trackRecord.Timestamp > number(":upperBound", math.MaxInt64) {
+			continue
+		}
+		if input.ExclusiveStartKey != nil {
+			startKey, _ := strconv.ParseInt(*input.ExclusiveStartKey["airtime"].N, 10, 64)
+			if (descending && trackRecord.Timestamp >= startKey) ||
+				(!descending && trackRecord.Timestamp <= startKey) {
+				continue
+			}
+		}
+		matches = append(matches, trackRecord)
+	}
+	if descending {
+		sort.Slice(matches, func(i, j int) bool { return matches[i].Timestamp > matches[j].Timestamp })
+	} else {
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].Timestamp < matches[j].Timestamp
+		})
+	}
+
+	output := &dynamodb.QueryOutput{}
+	limit := ddb.pageSize
+	if input.Limit != nil {
+		limit = *input.Limit
+	}
+	if limit > 0 && int64(len(matches)) > limit {
+		matches = matches[:limit]
+		output.LastEvaluatedKey, _ = dynamodbattribute.MarshalMap(matches[len(matches)-1])
+	}
+	for _, trackRecord := range matches {
+		item, _ := dynamodbattribute.MarshalMap(trackRecord)
+		output.Items = append(output.Items, item)
+	}
+	return output, nil
+}
+
+func newMockTypedDynamoDB() MockTypedDynamoDB {
+	trackRecords := []model.TrackRecord{
+		{StationId: "fm4", Timestamp: 1537700000, Type: "track",
+			Track: model.Track{"rhcp", "californication"}},
+		{StationId: "oe3", Timestamp: 1537700000, Type: "ad", Track: model.Track{"", "red bull"}},
+		{StationId: "fm4", Timestamp: 1537700200, Type: "news"},
+	}
+	// a long talk show without music, split into more items than the most recent track lookup
+	// reads at once
+	for i := 0; i < 15; i++ {
+		trackRecords = append(trackRecords, model.TrackRecord{StationId: "fm4",
+			Timestamp: int64(1537700300 + i*60), Type: "talk"})
+	}
+	return MockTypedDynamoDB{trackRecords: trackRecords}
+}
+
+func TestDDBTrackRecordDAO_GetRecords(t *testing.T) {
+	ddb := newMockTypedDynamoDB()
+	trackRecordDAO := NewDDBTrackRecordDAO(ddb, "testTable", "gsi")
+	startDate, endDate := time.Unix(1537690000, 0), time.Unix(1537700250, 0)
+
+	var tests = []struct {
+		station  string
+		types    []string
+		expected []model.TrackRecord
+	}{
+		{"", []string{"news", "ad", "track"}, ddb.trackRecords[:3]},
+		{"", []string{"ad"}, ddb.trackRecords[1:2]},
+		{"fm4", []string{"track", "news"}, []model.TrackRecord{ddb.trackRecords[0],
+			ddb.trackRecords[2]}},
+		{"fm4", []string{}, []model.TrackRecord{}},
+	}
+
+	for i, test := range tests {
+		var result []model.TrackRecord
+		var err error
+		if test.station == "" {
+			result, err = trackRecordDAO.GetRecords(test.types, startDate, endDate)
+		} else {
+			result, err = trackRecordDAO.GetRecordsByStation(test.station, test.types, startDate,
+				endDate)
+		}
+		if err != nil || !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("#%d GetRecords(%q, %v): got (%v, %v), expected (%v, nil)", i, test.station,
+				test.types, result, err, test.expected)
+		}
+	}
+
+	if _, err := trackRecordDAO.GetRecords([]string{"ad"}, endDate, startDate); err == nil {
+		t.Error("GetRecords(): got no error, expected error for startDate after endDate")
+	}
+}
+
+func TestDDBTrackRecordDAO_GetRecordsByStation_Pages(t *testing.T) {
+	ddb := newMockTypedDynamoDB()
+	ddb.pageSize = 4
+	trackRecordDAO := NewDDBTrackRecordDAO(ddb, "testTable", "gsi")
+
+	// the news and the talk show are spread across four pages
+	result, err := trackRecordDAO.GetRecordsByStation("fm4", []string{"news", "talk"},
+		time.Unix(1537690000, 0), time.Unix(1537710000, 0))
+	if err != nil || !reflect.DeepEqual(result, ddb.trackRecords[2:]) {
+		t.Errorf("GetRecordsByStation(\"fm4\"): got (%v, %v), expected (%v, nil)", result, err,
+			ddb.trackRecords[2:])
+	}
+}
+
+func TestDDBTrackRecordDAO_GetMostRecentTrackRecordByStation_SkipsOtherTypes(t *testing.T) {
+	ddb := newMockTypedDynamoDB()
+	trackRecordDAO := NewDDBTrackRecordDAO(ddb, "testTable", "gsi")
+
+	if result, err := trackRecordDAO.GetMostRecentTrackRecordByStation("fm4"); err != nil ||
+		result != ddb.trackRecords[0] {
+		t.Errorf("GetMostRecentTrackRecordByStation(\"fm4\"): got (%v, %v), expected (%v, nil)",
+			result, err, ddb.trackRecords[0])
+	}
+	if _, err := trackRecordDAO.GetMostRecentTrackRecordByStation("oe3"); !IsNotFound(err) {
+		t.Errorf("GetMostRecentTrackRecordByStation(\"oe3\"): got err (%v), expected "+
+			"NotFoundError", err)
+	}
+}
@@ -0,0 +1,44 @@
+package datalayer
+
+import (
+	"github.com/RadioCheckerApp/api/model"
+	"sync"
+)
+
+// MemoryCredentialDAO keeps credentials in memory. It serves local setups and tests which lack a
+// credentials table.
+type MemoryCredentialDAO struct {
+	mutex       sync.RWMutex
+	credentials map[string]model.Credential
+}
+
+func NewMemoryCredentialDAO(credentials ...model.Credential) *MemoryCredentialDAO {
+	dao := &MemoryCredentialDAO{credentials: make(map[string]model.Credential)}
+	for _, credential := range credentials {
+		dao.credentials[credential.TokenHash] = credential
+	}
+	return dao
+}
+
+func (dao *MemoryCredentialDAO) GetByTokenHash(tokenHash string) (model.Credential, error) {
+	dao.mutex.RLock()
+	defer dao.mutex.RUnlock()
+
+	credential, ok := dao.credentials[tokenHash]
+	if !ok {
+		return model.Credential{}, NewNotFoundError("credential does not exist")
+	}
+	return credential, nil
+}
+
+func (dao *MemoryCredentialDAO) Create(credential model.Credential) error {
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+
+	if _, ok := dao.credentials[credential.TokenHash]; ok {
+		return NewAlreadyExistsError("credential for principal " + credential.PrincipalID +
+			" already exists")
+	}
+	dao.credentials[credential.TokenHash] = credential
+	return nil
+}
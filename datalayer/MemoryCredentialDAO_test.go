@@ -0,0 +1,38 @@
+package datalayer
+
+import (
+	"github.com/RadioCheckerApp/api/model"
+	"reflect"
+	"testing"
+)
+
+func TestMemoryCredentialDAO(t *testing.T) {
+	credentialDAO := NewMemoryCredentialDAO(oe3Credential)
+
+	credential, err := credentialDAO.GetByTokenHash(oe3Credential.TokenHash)
+	if err != nil || !reflect.DeepEqual(credential, oe3Credential) {
+		t.Errorf("GetByTokenHash(%q): got (%v, %v), expected (%v, nil)",
+			oe3Credential.TokenHash, credential, err, oe3Credential)
+	}
+
+	fm4Credential := model.Credential{model.HashToken("fm4-token"), "fm4-crawler",
+		[]string{"fm4"}}
+	if _, err := credentialDAO.GetByTokenHash(fm4Credential.TokenHash); !IsNotFound(err) {
+		t.Errorf("GetByTokenHash(%q): got err (%v), expected NotFoundError",
+			fm4Credential.TokenHash, err)
+	}
+
+	if err := credentialDAO.Create(fm4Credential); err != nil {
+		t.Errorf("Create(%v): got err (%v), expected nil", fm4Credential, err)
+	}
+	if err := credentialDAO.Create(fm4Credential); !IsAlreadyExists(err) {
+		t.Errorf("Create(%v): got (%v), expected conflict for duplicated credential", fm4Credential,
+			err)
+	}
+
+	credential, err = credentialDAO.GetByTokenHash(fm4Credential.TokenHash)
+	if err != nil || !reflect.DeepEqual(credential, fm4Credential) {
+		t.Errorf("GetByTokenHash(%q): got (%v, %v), expected (%v, nil)",
+			fm4Credential.TokenHash, credential, err, fm4Credential)
+	}
+}
@@ -0,0 +1,91 @@
+package datalayer
+
+import (
+	"container/list"
+	"errors"
+	"github.com/RadioCheckerApp/api/model"
+	"sync"
+)
+
+// MemoryResponseCacheDAO keeps the most recently used responses in memory. Once its capacity is
+// reached, the least recently used response is evicted.
+type MemoryResponseCacheDAO struct {
+	mutex    sync.Mutex
+	capacity int
+	order    *list.List
+	elements map[string]*list.Element
+}
+
+func NewMemoryResponseCacheDAO(capacity int) (*MemoryResponseCacheDAO, error) {
+	if capacity <= 0 {
+		return nil, errors.New("capacity must be positive")
+	}
+	return &MemoryResponseCacheDAO{
+		capacity: capacity,
+		order:    list.New(),
+		elements: make(map[string]*list.Element),
+	}, nil
+}
+
+func (dao *MemoryResponseCacheDAO) Get(key string) (model.CachedResponse, error) {
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+
+	element, ok := dao.elements[key]
+	if !ok {
+		return model.CachedResponse{}, NewNotFoundError("response " + key + " is not cached")
+	}
+	dao.order.MoveToFront(element)
+	return element.Value.(model.CachedResponse), nil
+}
+
+func (dao *MemoryResponseCacheDAO) Put(response model.CachedResponse) error {
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+
+	if element, ok := dao.elements[response.Key]; ok {
+		element.Value = response
+		dao.order.MoveToFront(element)
+		return nil
+	}
+
+	dao.elements[response.Key] = dao.order.PushFront(response)
+	if dao.order.Len() > dao.capacity {
+		oldest := dao.order.Back()
+		dao.order.Remove(oldest)
+		delete(dao.elements, oldest.Value.(model.CachedResponse).Key)
+	}
+	return nil
+}
+
+func (dao *MemoryResponseCacheDAO) Delete(key string) error {
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+
+	if element, ok := dao.elements[key]; ok {
+		dao.order.Remove(element)
+		delete(dao.elements, key)
+	}
+	return nil
+}
+
+// Keys passes the keys from the most to the least recently used response. fn must not call the
+// DAO's other methods.
+func (dao *MemoryResponseCacheDAO) Keys(fn func(key string) bool) error {
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+
+	for element := dao.order.Front(); element != nil; element = element.Next() {
+		if !fn(element.Value.(model.CachedResponse).Key) {
+			break
+		}
+	}
+	return nil
+}
+
+// Len returns the number of cached responses.
+func (dao *MemoryResponseCacheDAO) Len() int {
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+	return dao.order.Len()
+}
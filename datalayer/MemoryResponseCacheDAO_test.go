@@ -0,0 +1,73 @@
+package datalayer
+
+import (
+	"github.com/RadioCheckerApp/api/model"
+	"reflect"
+	"testing"
+)
+
+func TestNewMemoryResponseCacheDAO(t *testing.T) {
+	if _, err := NewMemoryResponseCacheDAO(0); err == nil {
+		t.Error("NewMemoryResponseCacheDAO(0): got no error, expected error")
+	}
+}
+
+func TestMemoryResponseCacheDAO(t *testing.T) {
+	dao, _ := NewMemoryResponseCacheDAO(2)
+
+	first := model.CachedResponse{Key: "first", Data: "1"}
+	second := model.CachedResponse{Key: "second", Data: "2"}
+	third := model.CachedResponse{Key: "third", Data: "3"}
+
+	dao.Put(first)
+	dao.Put(second)
+	// reading `first` makes `second` the least recently used response
+	if response, err := dao.Get("first"); err != nil || response != first {
+		t.Errorf("Get(\"first\"): got (%v, %v), expected (%v, nil)", response, err, first)
+	}
+	dao.Put(third)
+
+	if _, err := dao.Get("second"); !IsNotFound(err) {
+		t.Errorf("Get(\"second\"): got err (%v), expected NotFoundError", err)
+	}
+	for _, expected := range []model.CachedResponse{first, third} {
+		if response, err := dao.Get(expected.Key); err != nil || response != expected {
+			t.Errorf("Get(%q): got (%v, %v), expected (%v, nil)", expected.Key, response, err,
+				expected)
+		}
+	}
+
+	updated := model.CachedResponse{Key: "first", Data: "updated"}
+	dao.Put(updated)
+	if response, _ := dao.Get("first"); response != updated || dao.Len() != 2 {
+		t.Errorf("Put(%v): got (%v) and %d responses, expected (%v) and 2 responses", updated,
+			response, dao.Len(), updated)
+	}
+}
+
+func TestMemoryResponseCacheDAO_Delete(t *testing.T) {
+	dao, _ := NewMemoryResponseCacheDAO(3)
+	for _, key := range []string{"first", "second", "third"} {
+		dao.Put(model.CachedResponse{Key: key})
+	}
+
+	if err := dao.Delete("second"); err != nil {
+		t.Errorf("Delete(\"second\"): got err (%v), expected none", err)
+	}
+	if err := dao.Delete("unknown"); err != nil {
+		t.Errorf("Delete(\"unknown\"): got err (%v), expected none", err)
+	}
+	if _, err := dao.Get("second"); !IsNotFound(err) || dao.Len() != 2 {
+		t.Errorf("Get(\"second\"): got err (%v) and %d responses, expected NotFoundError and 2 "+
+			"responses", err, dao.Len())
+	}
+
+	var keys []string
+	dao.Keys(func(key string) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if !reflect.DeepEqual(keys, []string{"third", "first"}) {
+		t.Errorf("Keys(): got (%v), expected ([third first])", keys)
+	}
+}
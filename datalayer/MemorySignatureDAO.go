@@ -0,0 +1,33 @@
+package datalayer
+
+import (
+	"sync"
+	"time"
+)
+
+// MemorySignatureDAO keeps signatures in memory. It serves local setups and tests which lack a
+// signatures table; expired signatures are dropped whenever a signature is recorded.
+type MemorySignatureDAO struct {
+	mutex      sync.Mutex
+	signatures map[string]time.Time
+}
+
+func NewMemorySignatureDAO() *MemorySignatureDAO {
+	return &MemorySignatureDAO{signatures: make(map[string]time.Time)}
+}
+
+func (dao *MemorySignatureDAO) Record(signature string, now, expires time.Time) error {
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+
+	for recorded, recordedExpires := range dao.signatures {
+		if recordedExpires.Before(now) {
+			delete(dao.signatures, recorded)
+		}
+	}
+	if _, ok := dao.signatures[signature]; ok {
+		return NewAlreadyExistsError("signature has been recorded already")
+	}
+	dao.signatures[signature] = expires
+	return nil
+}
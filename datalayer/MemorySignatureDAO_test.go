@@ -0,0 +1,28 @@
+package datalayer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemorySignatureDAO(t *testing.T) {
+	signatureDAO := NewMemorySignatureDAO()
+	now := time.Unix(1537701181, 0)
+	expires := now.Add(5 * time.Minute)
+
+	if err := signatureDAO.Record("c0ffee", now, expires); err != nil {
+		t.Errorf("Record(\"c0ffee\"): got err (%v), expected nil", err)
+	}
+	if err := signatureDAO.Record("c0ffee", now.Add(time.Minute), expires); !IsAlreadyExists(err) {
+		t.Errorf("Record(\"c0ffee\"): got err (%v), expected AlreadyExistsError", err)
+	}
+	if err := signatureDAO.Record("decaf", now, expires); err != nil {
+		t.Errorf("Record(\"decaf\"): got err (%v), expected nil", err)
+	}
+
+	// expired signatures may be recorded again
+	later := expires.Add(time.Second)
+	if err := signatureDAO.Record("c0ffee", later, later.Add(5*time.Minute)); err != nil {
+		t.Errorf("Record(\"c0ffee\") after expiry: got err (%v), expected nil", err)
+	}
+}
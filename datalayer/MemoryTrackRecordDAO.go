@@ -0,0 +1,163 @@
+package datalayer
+
+import (
+	"github.com/RadioCheckerApp/api/model"
+	"sort"
+	"sync"
+	"time"
+)
+
+type trackRecordKey struct {
+	station string
+	airtime int64
+}
+
+// MemoryTrackRecordDAO keeps track records in memory. Like the DynamoDB table, it serves track
+// records in the order of their airtime. It serves local setups and tests of tools processing
+// track records.
+type MemoryTrackRecordDAO struct {
+	mutex        sync.RWMutex
+	trackRecords map[trackRecordKey]model.TrackRecord
+}
+
+func NewMemoryTrackRecordDAO(trackRecords ...model.TrackRecord) *MemoryTrackRecordDAO {
+	dao := &MemoryTrackRecordDAO{trackRecords: make(map[trackRecordKey]model.TrackRecord)}
+	for _, trackRecord := range trackRecords {
+		dao.trackRecords[trackRecordKey{trackRecord.StationId, trackRecord.Timestamp}] = trackRecord
+	}
+	return dao
+}
+
+func (dao *MemoryTrackRecordDAO) GetTrackRecords(startDate,
+	endDate time.Time) ([]model.TrackRecord, error) {
+	return dao.filter(startDate, endDate, func(trackRecord model.TrackRecord) bool {
+		return trackRecord.Type == model.TypeTrack
+	})
+}
+
+func (dao *MemoryTrackRecordDAO) GetTrackRecordsByStation(station string, startDate,
+	endDate time.Time) ([]model.TrackRecord, error) {
+	return dao.filter(startDate, endDate, func(trackRecord model.TrackRecord) bool {
+		return trackRecord.StationId == station && trackRecord.Type == model.TypeTrack
+	})
+}
+
+func (dao *MemoryTrackRecordDAO) GetRecords(types []string, startDate,
+	endDate time.Time) ([]model.TrackRecord, error) {
+	return dao.filter(startDate, endDate, func(trackRecord model.TrackRecord) bool {
+		return containsType(types, trackRecord.Type)
+	})
+}
+
+func (dao *MemoryTrackRecordDAO) GetRecordsByStation(station string, types []string, startDate,
+	endDate time.Time) ([]model.TrackRecord, error) {
+	return dao.filter(startDate, endDate, func(trackRecord model.TrackRecord) bool {
+		return trackRecord.StationId == station && containsType(types, trackRecord.Type)
+	})
+}
+
+func (dao *MemoryTrackRecordDAO) GetMostRecentTrackRecordByStation(station string) (model.
+	TrackRecord, error) {
+	dao.mutex.RLock()
+	defer dao.mutex.RUnlock()
+
+	var mostRecent model.TrackRecord
+	found := false
+	for key, trackRecord := range dao.trackRecords {
+		if key.station == station && trackRecord.Type == model.TypeTrack &&
+			(!found || key.airtime > mostRecent.Timestamp) {
+			mostRecent = trackRecord
+			found = true
+		}
+	}
+	if !found {
+		return model.TrackRecord{},
+			NewNotFoundError("no track records in database for station " + station)
+	}
+	return mostRecent, nil
+}
+
+func (dao *MemoryTrackRecordDAO) CreateTrackRecord(trackRecord model.TrackRecord) error {
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+
+	key := trackRecordKey{trackRecord.StationId, trackRecord.Timestamp}
+	if _, ok := dao.trackRecords[key]; ok {
+		return NewAlreadyExistsError("track record " + trackRecordPath(key.station, key.airtime) +
+			" already exists")
+	}
+	dao.trackRecords[key] = trackRecord
+	return nil
+}
+
+func (dao *MemoryTrackRecordDAO) UpdateTrackRecord(trackRecord model.TrackRecord) error {
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+
+	key := trackRecordKey{trackRecord.StationId, trackRecord.Timestamp}
+	if _, ok := dao.trackRecords[key]; !ok {
+		return NewNotFoundError("track record " + trackRecordPath(key.station, key.airtime) +
+			" does not exist")
+	}
+	dao.trackRecords[key] = trackRecord
+	return nil
+}
+
+func (dao *MemoryTrackRecordDAO) DeleteTrackRecord(station string, airtime int64) error {
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+
+	key := trackRecordKey{station, airtime}
+	if _, ok := dao.trackRecords[key]; !ok {
+		return NewNotFoundError("track record " + trackRecordPath(station, airtime) +
+			" does not exist")
+	}
+	delete(dao.trackRecords, key)
+	return nil
+}
+
+func (dao *MemoryTrackRecordDAO) PutTrackRecords(trackRecords []model.TrackRecord) error {
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+
+	for _, trackRecord := range trackRecords {
+		dao.trackRecords[trackRecordKey{trackRecord.StationId, trackRecord.Timestamp}] = trackRecord
+	}
+	return nil
+}
+
+// filter returns the matching track records aired between `startDate` and `endDate`, ordered by
+// airtime and station.
+func (dao *MemoryTrackRecordDAO) filter(startDate, endDate time.Time,
+	match func(model.TrackRecord) bool) ([]model.TrackRecord, error) {
+	if err := valiDate(startDate, endDate); err != nil {
+		return nil, err
+	}
+
+	dao.mutex.RLock()
+	defer dao.mutex.RUnlock()
+
+	trackRecords := make([]model.TrackRecord, 0)
+	for _, trackRecord := range dao.trackRecords {
+		if trackRecord.Timestamp >= startDate.Unix() && trackRecord.Timestamp <= endDate.Unix() &&
+			match(trackRecord) {
+			trackRecords = append(trackRecords, trackRecord)
+		}
+	}
+	sort.Slice(trackRecords, func(i, j int) bool {
+		if trackRecords[i].Timestamp != trackRecords[j].Timestamp {
+			return trackRecords[i].Timestamp < trackRecords[j].Timestamp
+		}
+		return trackRecords[i].StationId < trackRecords[j].StationId
+	})
+	return trackRecords, nil
+}
+
+func containsType(types []string, recordType string) bool {
+	for _, t := range types {
+		if t == recordType {
+			return true
+		}
+	}
+	return false
+}
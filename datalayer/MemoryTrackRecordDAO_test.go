@@ -0,0 +1,99 @@
+package datalayer
+
+import (
+	"github.com/RadioCheckerApp/api/model"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMemoryTrackRecordDAO(t *testing.T) {
+	first := model.TrackRecord{StationId: "fm4", Timestamp: 1537701181, Type: "track",
+		Track: model.Track{"rhcp", "californication"}}
+	second := model.TrackRecord{StationId: "oe3", Timestamp: 1537701181, Type: "track",
+		Track: model.Track{"mø", "final song"}}
+	third := model.TrackRecord{StationId: "fm4", Timestamp: 1537704781, Type: "track",
+		Track: model.Track{"cardi b", "i like it"}}
+	dao := NewMemoryTrackRecordDAO(third, second, first)
+
+	startDate, endDate := time.Unix(1537700000, 0), time.Unix(1537710000, 0)
+	if result, err := dao.GetTrackRecords(startDate, endDate); err != nil ||
+		!reflect.DeepEqual(result, []model.TrackRecord{first, second, third}) {
+		t.Errorf("GetTrackRecords(): got (%v, %v), expected records ordered by airtime", result,
+			err)
+	}
+	result, _ := dao.GetTrackRecordsByStation("fm4", startDate, time.Unix(1537704780, 0))
+	if !reflect.DeepEqual(result, []model.TrackRecord{first}) {
+		t.Errorf("GetTrackRecordsByStation(): got %v, expected [%v]", result, first)
+	}
+	if _, err := dao.GetTrackRecords(endDate, startDate); err == nil {
+		t.Error("GetTrackRecords(): got no error, expected error for startDate after endDate")
+	}
+	if result, err := dao.GetMostRecentTrackRecordByStation("fm4"); err != nil || result != third {
+		t.Errorf("GetMostRecentTrackRecordByStation(\"fm4\"): got (%v, %v), expected (%v, nil)",
+			result, err, third)
+	}
+
+	if err := dao.CreateTrackRecord(first); !IsAlreadyExists(err) {
+		t.Errorf("CreateTrackRecord(%v): got err (%v), expected AlreadyExistsError", first, err)
+	}
+	updated := first
+	updated.Title = "dani california"
+	if err := dao.UpdateTrackRecord(updated); err != nil {
+		t.Errorf("UpdateTrackRecord(%v): got err (%v), expected nil", updated, err)
+	}
+	if err := dao.DeleteTrackRecord("oe3", 1537701181); err != nil {
+		t.Errorf("DeleteTrackRecord(\"oe3\", 1537701181): got err (%v), expected nil", err)
+	}
+	if result, _ := dao.GetTrackRecords(startDate, endDate); !reflect.DeepEqual(result,
+		[]model.TrackRecord{updated, third}) {
+		t.Errorf("GetTrackRecords(): got %v, expected [%v %v]", result, updated, third)
+	}
+
+	if err := dao.UpdateTrackRecord(second); !IsNotFound(err) {
+		t.Errorf("UpdateTrackRecord(%v): got err (%v), expected NotFoundError", second, err)
+	}
+	if err := dao.DeleteTrackRecord("oe3", 1537701181); !IsNotFound(err) {
+		t.Errorf("DeleteTrackRecord(\"oe3\", 1537701181): got err (%v), expected NotFoundError",
+			err)
+	}
+	if _, err := dao.GetMostRecentTrackRecordByStation("oe3"); !IsNotFound(err) {
+		t.Errorf("GetMostRecentTrackRecordByStation(\"oe3\"): got err (%v), expected "+
+			"NotFoundError", err)
+	}
+
+	// PutTrackRecords replaces existing track records
+	if err := dao.PutTrackRecords([]model.TrackRecord{first, second}); err != nil {
+		t.Errorf("PutTrackRecords(): got err (%v), expected nil", err)
+	}
+	if result, _ := dao.GetTrackRecords(startDate, endDate); !reflect.DeepEqual(result,
+		[]model.TrackRecord{first, second, third}) {
+		t.Errorf("GetTrackRecords(): got %v, expected [%v %v %v]", result, first, second, third)
+	}
+}
+
+func TestMemoryTrackRecordDAO_GetRecords(t *testing.T) {
+	track := model.TrackRecord{StationId: "fm4", Timestamp: 1537701181, Type: "track",
+		Track: model.Track{"rhcp", "californication"}}
+	news := model.TrackRecord{StationId: "fm4", Timestamp: 1537701400, Type: "news"}
+	ad := model.TrackRecord{StationId: "oe3", Timestamp: 1537701300, Type: "ad",
+		Track: model.Track{"", "red bull"}}
+	dao := NewMemoryTrackRecordDAO(track, news, ad)
+
+	startDate, endDate := time.Unix(1537700000, 0), time.Unix(1537710000, 0)
+	if result, _ := dao.GetTrackRecords(startDate, endDate); !reflect.DeepEqual(result,
+		[]model.TrackRecord{track}) {
+		t.Errorf("GetTrackRecords(): got %v, expected tracks only", result)
+	}
+	if result, _ := dao.GetRecords([]string{"news", "ad"}, startDate, endDate); !reflect.DeepEqual(
+		result, []model.TrackRecord{ad, news}) {
+		t.Errorf("GetRecords(news, ad): got %v, expected [%v %v]", result, ad, news)
+	}
+	if result, _ := dao.GetRecordsByStation("fm4", model.RecordTypes, startDate,
+		endDate); !reflect.DeepEqual(result, []model.TrackRecord{track, news}) {
+		t.Errorf("GetRecordsByStation(\"fm4\"): got %v, expected [%v %v]", result, track, news)
+	}
+	if result, _ := dao.GetMostRecentTrackRecordByStation("fm4"); result != track {
+		t.Errorf("GetMostRecentTrackRecordByStation(\"fm4\"): got %v, expected %v", result, track)
+	}
+}
@@ -0,0 +1,38 @@
+package datalayer
+
+// NotFoundError signals that the requested item does not exist in the database, as opposed to
+// the database failing to serve the request.
+type NotFoundError struct {
+	message string
+}
+
+func NewNotFoundError(message string) NotFoundError {
+	return NotFoundError{message}
+}
+
+func (err NotFoundError) Error() string {
+	return err.message
+}
+
+func IsNotFound(err error) bool {
+	_, ok := err.(NotFoundError)
+	return ok
+}
+
+// AlreadyExistsError signals that an item with the same key exists in the database already.
+type AlreadyExistsError struct {
+	message string
+}
+
+func NewAlreadyExistsError(message string) AlreadyExistsError {
+	return AlreadyExistsError{message}
+}
+
+func (err AlreadyExistsError) Error() string {
+	return err.message
+}
+
+func IsAlreadyExists(err error) bool {
+	_, ok := err.(AlreadyExistsError)
+	return ok
+}
@@ -0,0 +1,14 @@
+package datalayer
+
+import "github.com/RadioCheckerApp/api/model"
+
+// ResponseCacheDAO stores the results of cacheable workers. Get returns a NotFoundError for
+// unknown keys; expired responses may still be returned and have to be checked by the caller.
+type ResponseCacheDAO interface {
+	Get(key string) (model.CachedResponse, error)
+	Put(response model.CachedResponse) error
+	// Delete removes the response, unknown keys are ignored.
+	Delete(key string) error
+	// Keys passes the keys of all cached responses to fn until fn returns false.
+	Keys(fn func(key string) bool) error
+}
@@ -0,0 +1,13 @@
+package datalayer
+
+import "github.com/RadioCheckerApp/api/model"
+
+// RollupDAO stores the daily play counts of the stations' tracks.
+type RollupDAO interface {
+	GetRollups(day string) ([]model.TrackRollup, error)
+	GetRollupsByStation(station, day string) ([]model.TrackRollup, error)
+	// AddTrackRecord counts the play of the track record in the given day's rollups.
+	AddTrackRecord(day string, trackRecord model.TrackRecord) error
+	// ReplaceRollups replaces all rollups of the station's day by the given rollups.
+	ReplaceRollups(station, day string, rollups []model.TrackRollup) error
+}
@@ -0,0 +1,11 @@
+package datalayer
+
+import "time"
+
+// SignatureDAO records the signatures of HMAC signed requests, so that each signed request is
+// accepted once. Signatures expire once their request has left the replay window.
+type SignatureDAO interface {
+	// Record stores the signature until `expires`. It returns an AlreadyExistsError if the
+	// signature has been recorded before and has not expired at `now`.
+	Record(signature string, now, expires time.Time) error
+}
@@ -4,4 +4,8 @@ import "github.com/RadioCheckerApp/api/model"
 
 type StationDAO interface {
 	GetAll() ([]model.Station, error)
+	Get(stationId string) (model.Station, error)
+	Create(station model.Station) error
+	Update(station model.Station) error
+	Deactivate(stationId string) error
 }
@@ -0,0 +1,11 @@
+package datalayer
+
+import "github.com/RadioCheckerApp/api/model"
+
+type StationGroupDAO interface {
+	GetAll() ([]model.StationGroup, error)
+	Get(groupId string) (model.StationGroup, error)
+	Create(group model.StationGroup) error
+	Update(group model.StationGroup) error
+	Delete(groupId string) error
+}
@@ -9,6 +9,18 @@ type TrackRecordDAO interface {
 	GetTrackRecords(startDate, endDate time.Time) ([]model.TrackRecord, error)
 	GetTrackRecordsByStation(station string, startDate, endDate time.Time) ([]model.TrackRecord,
 		error)
+	// GetRecords and GetRecordsByStation serve the records of the given types, e. g. ads and news,
+	// whereas the other getters serve tracks only.
+	GetRecords(types []string, startDate, endDate time.Time) ([]model.TrackRecord, error)
+	GetRecordsByStation(station string, types []string, startDate,
+		endDate time.Time) ([]model.TrackRecord, error)
 	GetMostRecentTrackRecordByStation(station string) (model.TrackRecord, error)
+	// CreateTrackRecord returns an AlreadyExistsError if the station already has a track record
+	// with the same airtime.
 	CreateTrackRecord(trackRecord model.TrackRecord) error
+	UpdateTrackRecord(trackRecord model.TrackRecord) error
+	DeleteTrackRecord(station string, airtime int64) error
+	// PutTrackRecords writes the track records in batches. Unlike CreateTrackRecord, it replaces
+	// existing track records of the same station and airtime, hence callers have to skip them.
+	PutTrackRecords(trackRecords []model.TrackRecord) error
 }
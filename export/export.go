@@ -0,0 +1,58 @@
+package export
+
+import (
+	"errors"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"time"
+)
+
+// DefaultChunk is the period of track records read by a single query. A chunk's track records
+// must fit into a single page of the query.
+const DefaultChunk = time.Hour
+
+// Export writes the records of the given types of `station`, or of all stations if the station is
+// empty, aired between `startDate` and `endDate` in the order of their airtime. It returns the
+// number of exported records.
+func Export(dao datalayer.TrackRecordDAO, station string, types []string, startDate,
+	endDate time.Time, chunk time.Duration, writer Writer) (int, error) {
+	if dao == nil || writer == nil {
+		return 0, errors.New("dao and writer must not be nil")
+	}
+	if chunk < time.Second {
+		return 0, errors.New("chunk must be at least one second")
+	}
+	if startDate.After(endDate) {
+		return 0, errors.New("startDate must be before endDate")
+	}
+
+	count := 0
+	for chunkStart := startDate; !chunkStart.After(endDate); {
+		chunkEnd := chunkStart.Add(chunk - time.Second)
+		if chunkEnd.After(endDate) {
+			chunkEnd = endDate
+		}
+
+		trackRecords, err := read(dao, station, types, chunkStart, chunkEnd)
+		if err != nil {
+			return count, err
+		}
+		for _, trackRecord := range trackRecords {
+			if err := writer.Write(trackRecord); err != nil {
+				return count, err
+			}
+			count++
+		}
+
+		chunkStart = chunkEnd.Add(time.Second)
+	}
+	return count, writer.Flush()
+}
+
+func read(dao datalayer.TrackRecordDAO, station string, types []string, startDate,
+	endDate time.Time) ([]model.TrackRecord, error) {
+	if station == "" {
+		return dao.GetRecords(types, startDate, endDate)
+	}
+	return dao.GetRecordsByStation(station, types, startDate, endDate)
+}
@@ -0,0 +1,61 @@
+package export
+
+import (
+	"bytes"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"testing"
+	"time"
+)
+
+func TestExport(t *testing.T) {
+	dao := datalayer.NewMemoryTrackRecordDAO(
+		model.TrackRecord{StationId: "fm4", Timestamp: 1537708000, Type: "track",
+			Track: model.Track{"cardi b", "i like it"}},
+		model.TrackRecord{StationId: "oe3", Timestamp: 1537703000, Type: "track",
+			Track: model.Track{"mø", "final song"}},
+		model.TrackRecord{StationId: "fm4", Timestamp: 1537700000, Type: "track",
+			Track: model.Track{"rhcp", "californication"}},
+		model.TrackRecord{StationId: "fm4", Timestamp: 1537704000, Type: "ad",
+			Track: model.Track{"", "red bull"}},
+	)
+	startDate, endDate := time.Unix(1537696800, 0), time.Unix(1537711199, 0)
+
+	var tests = []struct {
+		station       string
+		types         []string
+		startDate     time.Time
+		expectedCount int
+		expected      string
+		expectedErr   bool
+	}{
+		{"", []string{"track"}, startDate, 3, testCSVHeader +
+			"fm4,1537700000,track,rhcp,californication,,,,,,\n" +
+			"oe3,1537703000,track,mø,final song,,,,,,\n" +
+			"fm4,1537708000,track,cardi b,i like it,,,,,,\n", false},
+		{"fm4", []string{"track"}, startDate, 2, testCSVHeader +
+			"fm4,1537700000,track,rhcp,californication,,,,,,\n" +
+			"fm4,1537708000,track,cardi b,i like it,,,,,,\n", false},
+		{"fm4", []string{"ad", "track"}, startDate, 3, testCSVHeader +
+			"fm4,1537700000,track,rhcp,californication,,,,,,\n" +
+			"fm4,1537704000,ad,,red bull,,,,,,\n" +
+			"fm4,1537708000,track,cardi b,i like it,,,,,,\n", false},
+		{"", []string{"track"}, endDate.Add(time.Second), 0, "", true},
+	}
+
+	for _, test := range tests {
+		output := &bytes.Buffer{}
+		writer, _ := NewWriter(CSV, output)
+		count, err := Export(dao, test.station, test.types, test.startDate, endDate, DefaultChunk,
+			writer)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("Export(%q): got err (%v), expected err: %v", test.station, err,
+				test.expectedErr)
+			continue
+		}
+		if count != test.expectedCount || output.String() != test.expected {
+			t.Errorf("Export(%q): got (%d, %q), expected (%d, %q)", test.station, count,
+				output.String(), test.expectedCount, test.expected)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/RadioCheckerApp/api/model"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reader decodes track records one by one. Read returns the track record along with its number,
+// i. e. its position among the file's records starting at 1, and io.EOF after the last track
+// record. A malformed record is reported by a RecordError, reading may continue with the next one.
+//
+// Besides the files written by Writer, readers accept playlists of other sources: the station may
+// be named `station` instead of `stationId`, the type defaults to `track`, the metadata columns
+// are optional and the airtime may be given as RFC 3339 date (`2015-03-01T14:02:00+01:00`) instead
+// of unix time.
+type Reader interface {
+	Read() (trackRecord model.TrackRecord, number int, err error)
+}
+
+// RecordError reports a malformed record.
+type RecordError struct {
+	Number int
+	Err    error
+}
+
+func (err RecordError) Error() string {
+	return fmt.Sprintf("record %d: %v", err.Number, err.Err)
+}
+
+func NewReader(format Format, r io.Reader) (Reader, error) {
+	if r == nil {
+		return nil, errors.New("r must not be nil")
+	}
+	switch format {
+	case CSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		return &csvReader{reader: reader}, nil
+	case JSONL:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		return &jsonlReader{scanner: scanner}, nil
+	default:
+		return nil, errors.New("unsupported import format `" + string(format) + "`")
+	}
+}
+
+type csvReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+	number  int
+}
+
+func (r *csvReader) Read() (model.TrackRecord, int, error) {
+	if r.columns == nil {
+		if err := r.readHeader(); err != nil {
+			return model.TrackRecord{}, 0, err
+		}
+	}
+
+	row, err := r.reader.Read()
+	if err == io.EOF {
+		return model.TrackRecord{}, r.number, io.EOF
+	}
+	r.number++
+	if _, ok := err.(*csv.ParseError); ok {
+		return model.TrackRecord{}, r.number, RecordError{r.number, err}
+	}
+	if err != nil {
+		return model.TrackRecord{}, r.number, err
+	}
+
+	field := func(name string) string {
+		if i, ok := r.columns[name]; ok && i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+	airtime, err := parseAirtime(field("airtime"))
+	if err != nil {
+		return model.TrackRecord{}, r.number, RecordError{r.number, err}
+	}
+	duration, err := parseOptionalInt("duration", field("duration"))
+	if err != nil {
+		return model.TrackRecord{}, r.number, RecordError{r.number, err}
+	}
+	year, err := parseOptionalInt("year", field("year"))
+	if err != nil {
+		return model.TrackRecord{}, r.number, RecordError{r.number, err}
+	}
+	trackRecord := model.TrackRecord{StationId: field("stationId"), Timestamp: airtime,
+		Type: field("type"), Track: model.Track{Artist: field("artist"), Title: field("title")},
+		TrackMetadata: model.TrackMetadata{Album: field("album"), Duration: duration,
+			ISRC: field("isrc"), Label: field("label"), Year: year, CoverURL: field("cover_url")}}
+	if trackRecord.StationId == "" {
+		trackRecord.StationId = field("station")
+	}
+	if trackRecord.Type == "" {
+		trackRecord.Type = "track"
+	}
+	return trackRecord, r.number, nil
+}
+
+// readHeader maps the column names to their index. The header has to name the columns `airtime`,
+// `artist`, `title` and either `stationId` or `station`.
+func (r *csvReader) readHeader() error {
+	header, err := r.reader.Read()
+	if err == io.EOF {
+		return io.EOF
+	}
+	if err != nil {
+		return errors.New("unable to read CSV header: " + err.Error())
+	}
+
+	r.columns = make(map[string]int)
+	for i, name := range header {
+		r.columns[strings.TrimSpace(name)] = i
+	}
+	_, hasStationId := r.columns["stationId"]
+	_, hasStation := r.columns["station"]
+	for _, name := range []string{"airtime", "artist", "title"} {
+		if _, ok := r.columns[name]; !ok || !(hasStationId || hasStation) {
+			return errors.New("CSV header must name the columns `stationId`, `airtime`, " +
+				"`artist` and `title`")
+		}
+	}
+	return nil
+}
+
+type jsonlReader struct {
+	scanner *bufio.Scanner
+	number  int
+}
+
+// jsonlRecord accepts the airtime as number or string.
+type jsonlRecord struct {
+	StationId string          `json:"stationId"`
+	Station   string          `json:"station"`
+	Airtime   json.RawMessage `json:"airtime"`
+	Type      string          `json:"type"`
+	Artist    string          `json:"artist"`
+	Title     string          `json:"title"`
+	model.TrackMetadata
+	// the display form of exported track records is kept when they are imported again
+	model.TrackDisplay
+}
+
+func (r *jsonlReader) Read() (model.TrackRecord, int, error) {
+	for r.scanner.Scan() {
+		line := strings.TrimSpace(r.scanner.Text())
+		if line == "" {
+			continue
+		}
+		r.number++
+
+		var record jsonlRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			return model.TrackRecord{}, r.number, RecordError{r.number, err}
+		}
+		var airtimeStr string
+		if err := json.Unmarshal(record.Airtime, &airtimeStr); err != nil {
+			airtimeStr = string(record.Airtime)
+		}
+		airtime, err := parseAirtime(airtimeStr)
+		if err != nil {
+			return model.TrackRecord{}, r.number, RecordError{r.number, err}
+		}
+
+		trackRecord := model.TrackRecord{StationId: record.StationId, Timestamp: airtime,
+			Type: record.Type, Track: model.Track{Artist: record.Artist, Title: record.Title},
+			TrackMetadata: record.TrackMetadata, TrackDisplay: record.TrackDisplay}
+		if trackRecord.StationId == "" {
+			trackRecord.StationId = record.Station
+		}
+		if trackRecord.Type == "" {
+			trackRecord.Type = "track"
+		}
+		return trackRecord, r.number, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return model.TrackRecord{}, r.number, err
+	}
+	return model.TrackRecord{}, r.number, io.EOF
+}
+
+func parseOptionalInt(name, str string) (int, error) {
+	str = strings.TrimSpace(str)
+	if str == "" {
+		return 0, nil
+	}
+	i, err := strconv.Atoi(str)
+	if err != nil {
+		return 0, errors.New(name + " `" + str + "` is no number")
+	}
+	return i, nil
+}
+
+func parseAirtime(airtimeStr string) (int64, error) {
+	airtimeStr = strings.TrimSpace(airtimeStr)
+	if airtime, err := strconv.ParseInt(airtimeStr, 10, 64); err == nil {
+		return airtime, nil
+	}
+	airtime, err := time.Parse(time.RFC3339, airtimeStr)
+	if err != nil {
+		return 0, errors.New("airtime `" + airtimeStr + "` is neither unix time nor RFC 3339")
+	}
+	return airtime.Unix(), nil
+}
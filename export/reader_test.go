@@ -0,0 +1,85 @@
+package export
+
+import (
+	"github.com/RadioCheckerApp/api/model"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReader(t *testing.T) {
+	rise := model.TrackRecord{StationId: "fm4", Timestamp: 1425214920, Type: "track",
+		Track: model.Track{"Jonas Blue, Jack & Jack", "Rise"}}
+	finalSong := model.TrackRecord{StationId: "oe3", Timestamp: 1425215000, Type: "track",
+		Track: model.Track{"MØ", "Final Song"}}
+	finalSongMetadata := finalSong
+	finalSongMetadata.TrackMetadata = model.TrackMetadata{Duration: 235, ISRC: "GBARL1500898",
+		Year: 2015}
+
+	var tests = []struct {
+		format          Format
+		input           string
+		expected        []model.TrackRecord
+		expectedInvalid []int
+	}{
+		{CSV, "stationId,airtime,type,artist,title\n" +
+			"fm4,1425214920,track,\"Jonas Blue, Jack & Jack\",Rise\n" +
+			"oe3,1425215000,track,MØ,Final Song\n",
+			[]model.TrackRecord{rise, finalSong}, nil},
+		// columns in any order, RFC 3339 airtimes and invalid airtimes
+		{CSV, "title,artist,airtime,station\n" +
+			"Rise,\"Jonas Blue, Jack & Jack\",2015-03-01T14:02:00+01:00,fm4\n" +
+			"Final Song,MØ,yesterday,oe3\n" +
+			"Final Song,MØ,1425215000,oe3\n",
+			[]model.TrackRecord{rise, finalSong}, []int{2}},
+		{JSONL, `{"stationId":"fm4","airtime":1425214920,"type":"track",` +
+			`"artist":"Jonas Blue, Jack & Jack","title":"Rise"}` + "\n\n" +
+			`{"station":"oe3","airtime":"2015-03-01T14:03:20+01:00","artist":"MØ",` +
+			`"title":"Final Song"}` + "\n",
+			[]model.TrackRecord{rise, finalSong}, nil},
+		{JSONL, `{"stationId":"fm4","airtime":1425214920,"artist":"Jonas Blue, Jack & Jack",` +
+			`"title":"Rise"}` + "\n" + `{"stationId":"oe3"` + "\n" + `{"stationId":"oe3"}` + "\n",
+			[]model.TrackRecord{rise}, []int{2, 3}},
+		// metadata
+		{CSV, "station,airtime,artist,title,duration,isrc,year\n" +
+			"oe3,1425215000,MØ,Final Song,235,GBARL1500898,2015\n" +
+			"oe3,1425215000,MØ,Final Song,3:55,GBARL1500898,2015\n",
+			[]model.TrackRecord{finalSongMetadata}, []int{2}},
+		{JSONL, `{"station":"oe3","airtime":1425215000,"artist":"MØ","title":"Final Song",` +
+			`"duration":235,"isrc":"GBARL1500898","year":2015}` + "\n",
+			[]model.TrackRecord{finalSongMetadata}, nil},
+	}
+
+	for i, test := range tests {
+		reader, _ := NewReader(test.format, strings.NewReader(test.input))
+		trackRecords := []model.TrackRecord{}
+		invalid := []int(nil)
+		for {
+			trackRecord, number, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if _, ok := err.(RecordError); ok {
+				invalid = append(invalid, number)
+				continue
+			}
+			if err != nil {
+				t.Fatalf("#%d Read(): got err (%v), expected nil", i, err)
+			}
+			trackRecords = append(trackRecords, trackRecord)
+		}
+		if !reflect.DeepEqual(trackRecords, test.expected) ||
+			!reflect.DeepEqual(invalid, test.expectedInvalid) {
+			t.Errorf("#%d Read(): got %v and invalid records %v, expected %v and %v", i,
+				trackRecords, invalid, test.expected, test.expectedInvalid)
+		}
+	}
+}
+
+func TestReader_InvalidHeader(t *testing.T) {
+	reader, _ := NewReader(CSV, strings.NewReader("station,airtime,song\nfm4,1425214920,Rise\n"))
+	if _, _, err := reader.Read(); err == nil || err == io.EOF {
+		t.Errorf("Read(): got err (%v), expected error for missing columns", err)
+	}
+}
@@ -0,0 +1,119 @@
+// Package export encodes track records for offline analysis and decodes playlists to import. Track
+// records are read and written one by one, hence long periods never have to fit into memory.
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"github.com/RadioCheckerApp/api/model"
+	"io"
+	"strconv"
+)
+
+type Format string
+
+const (
+	CSV   Format = "csv"
+	JSONL Format = "jsonl"
+)
+
+func ParseFormat(format string) (Format, error) {
+	switch Format(format) {
+	case CSV, JSONL:
+		return Format(format), nil
+	default:
+		return "", errors.New("invalid export format provided, expected `csv` or `jsonl`")
+	}
+}
+
+func (format Format) ContentType() string {
+	if format == JSONL {
+		return "application/x-ndjson"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Writer encodes track records one by one. Flush has to be called after the last track record.
+type Writer interface {
+	Write(trackRecord model.TrackRecord) error
+	Flush() error
+}
+
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	if w == nil {
+		return nil, errors.New("w must not be nil")
+	}
+	switch format {
+	case CSV:
+		return &csvWriter{csv.NewWriter(w), false}, nil
+	case JSONL:
+		buffered := bufio.NewWriter(w)
+		encoder := json.NewEncoder(buffered)
+		// exports are not embedded into HTML, hence artists like `jack & jack` are kept readable
+		encoder.SetEscapeHTML(false)
+		return jsonlWriter{buffered, encoder}, nil
+	default:
+		return nil, errors.New("unsupported export format `" + string(format) + "`")
+	}
+}
+
+// csvHeader names the columns like the fields of the API's track records. Missing metadata is
+// written as empty column.
+var csvHeader = []string{"stationId", "airtime", "type", "artist", "title", "album", "duration",
+	"isrc", "label", "year", "cover_url"}
+
+// csvWriter writes the header before the first track record, or on Flush if there is none.
+type csvWriter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func (w *csvWriter) Write(trackRecord model.TrackRecord) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	return w.writer.Write([]string{trackRecord.StationId,
+		strconv.FormatInt(trackRecord.Timestamp, 10), trackRecord.Type, trackRecord.Artist,
+		trackRecord.Title, trackRecord.Album, formatOptionalInt(trackRecord.Duration),
+		trackRecord.ISRC, trackRecord.Label, formatOptionalInt(trackRecord.Year),
+		trackRecord.CoverURL})
+}
+
+func formatOptionalInt(i int) string {
+	if i == 0 {
+		return ""
+	}
+	return strconv.Itoa(i)
+}
+
+func (w *csvWriter) Flush() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func (w *csvWriter) writeHeader() error {
+	if w.headerWritten {
+		return nil
+	}
+	w.headerWritten = true
+	return w.writer.Write(csvHeader)
+}
+
+// jsonlWriter writes one JSON encoded track record per line.
+type jsonlWriter struct {
+	buffered *bufio.Writer
+	encoder  *json.Encoder
+}
+
+func (w jsonlWriter) Write(trackRecord model.TrackRecord) error {
+	return w.encoder.Encode(trackRecord)
+}
+
+func (w jsonlWriter) Flush() error {
+	return w.buffered.Flush()
+}
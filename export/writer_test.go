@@ -0,0 +1,72 @@
+package export
+
+import (
+	"bytes"
+	"github.com/RadioCheckerApp/api/model"
+	"testing"
+)
+
+const testCSVHeader = "stationId,airtime,type,artist,title,album,duration,isrc,label,year," +
+	"cover_url\n"
+
+func TestParseFormat(t *testing.T) {
+	var tests = []struct {
+		format      string
+		expected    Format
+		expectedErr bool
+	}{
+		{"csv", CSV, false},
+		{"jsonl", JSONL, false},
+		{"parquet", "", true},
+		{"", "", true},
+	}
+
+	for _, test := range tests {
+		result, err := ParseFormat(test.format)
+		if result != test.expected || (err != nil) != test.expectedErr {
+			t.Errorf("ParseFormat(%q): got (%q, %v), expected (%q, err: %v)", test.format, result,
+				err, test.expected, test.expectedErr)
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	trackRecords := []model.TrackRecord{
+		{StationId: "fm4", Timestamp: 1537700000, Type: "track",
+			Track: model.Track{"jonas blue, jack & jack", "rise"}},
+		{StationId: "oe3", Timestamp: 1537703000, Type: "track", Track: model.Track{"mø", "final song"},
+			TrackMetadata: model.TrackMetadata{Duration: 235, ISRC: "GBARL1500898"},
+			PrincipalID:   "crawler"},
+	}
+
+	var tests = []struct {
+		format       Format
+		trackRecords []model.TrackRecord
+		expected     string
+	}{
+		{CSV, trackRecords, testCSVHeader +
+			"fm4,1537700000,track,\"jonas blue, jack & jack\",rise,,,,,,\n" +
+			"oe3,1537703000,track,mø,final song,,235,GBARL1500898,,,\n"},
+		{CSV, nil, testCSVHeader},
+		{JSONL, trackRecords,
+			`{"stationId":"fm4","airtime":1537700000,"type":"track","artist":"jonas blue, jack & ` +
+				`jack","title":"rise"}` + "\n" +
+				`{"stationId":"oe3","airtime":1537703000,"type":"track","artist":"mø",` +
+				`"title":"final song","duration":235,"isrc":"GBARL1500898"}` + "\n"},
+		{JSONL, nil, ""},
+	}
+
+	for _, test := range tests {
+		output := &bytes.Buffer{}
+		writer, _ := NewWriter(test.format, output)
+		for _, trackRecord := range test.trackRecords {
+			if err := writer.Write(trackRecord); err != nil {
+				t.Errorf("%s Write(%v): got err (%v), expected nil", test.format, trackRecord, err)
+			}
+		}
+		if err := writer.Flush(); err != nil || output.String() != test.expected {
+			t.Errorf("%s Flush(): got (%q, %v), expected (%q, nil)", test.format, output.String(),
+				err, test.expected)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"math"
+	"time"
+)
+
+// MaxItemDuration bounds the duration estimated for a record. A record is assumed to last until
+// the next record of the station, but gaps, e. g. while a crawler was down, must not be attributed
+// to the record preceding them.
+const MaxItemDuration = 15 * time.Minute
+
+// HourlyAirtime holds the seconds of an hour attributed to each record type. The shares relate to
+// the seconds covered by records; MusicShare is the share of tracks, TalkShare the one of news and
+// talk.
+type HourlyAirtime struct {
+	Start      time.Time        `json:"start"`
+	Seconds    map[string]int64 `json:"seconds_by_type"`
+	MusicShare float64          `json:"music_share"`
+	TalkShare  float64          `json:"talk_share"`
+}
+
+type Airtime struct {
+	Station      string          `json:"station"`
+	Date         string          `json:"date"`
+	Hours        []HourlyAirtime `json:"hours"`
+	LastModified time.Time       `json:"-"`
+}
+
+// CalculateAirtime distributes the airtime between `startDate` and `endDate` (inclusive) to the
+// records of a single station, ordered by airtime, hour by hour. Records aired before `startDate`
+// count for the part of their duration reaching into the period.
+func CalculateAirtime(trackRecords []TrackRecord, startDate, endDate time.Time) []HourlyAirtime {
+	periodStart, periodEnd := startDate.Unix(), endDate.Unix()+1
+	hours := make([]HourlyAirtime, 0)
+	for start := startDate; start.Unix() < periodEnd; start = start.Add(time.Hour) {
+		hours = append(hours, HourlyAirtime{Start: start, Seconds: make(map[string]int64)})
+	}
+
+	for i, trackRecord := range trackRecords {
+		start := trackRecord.Timestamp
+		end := start + int64(MaxItemDuration/time.Second)
+		if i+1 < len(trackRecords) && trackRecords[i+1].Timestamp < end {
+			end = trackRecords[i+1].Timestamp
+		}
+		if start < periodStart {
+			start = periodStart
+		}
+		if end > periodEnd {
+			end = periodEnd
+		}
+
+		for start < end {
+			hour := (start - periodStart) / 3600
+			hourEnd := periodStart + (hour+1)*3600
+			if hourEnd > end {
+				hourEnd = end
+			}
+			hours[hour].Seconds[trackRecord.Type] += hourEnd - start
+			start = hourEnd
+		}
+	}
+
+	for i := range hours {
+		var total int64
+		for _, seconds := range hours[i].Seconds {
+			total += seconds
+		}
+		if total > 0 {
+			hours[i].MusicShare = share(hours[i].Seconds[TypeTrack], total)
+			hours[i].TalkShare = share(hours[i].Seconds[TypeNews]+hours[i].Seconds[TypeTalk], total)
+		}
+	}
+	return hours
+}
+
+// share returns the ratio rounded to three decimals.
+func share(seconds, total int64) float64 {
+	return math.Round(float64(seconds)/float64(total)*1000) / 1000
+}
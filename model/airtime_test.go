@@ -0,0 +1,44 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCalculateAirtime(t *testing.T) {
+	startDate := time.Date(2018, 9, 23, 14, 0, 0, 0, time.UTC)
+	endDate := startDate.Add(2*time.Hour - time.Second)
+	at := func(minutes int) int64 { return startDate.Add(time.Duration(minutes) * time.Minute).Unix() }
+
+	trackRecords := []TrackRecord{
+		// reaches 5 minutes into the period
+		{Timestamp: at(-5), Type: "track"},
+		{Timestamp: at(5), Type: "ad"},
+		{Timestamp: at(8), Type: "news"},
+		// 15 minutes at most, the gap until the next record is not covered
+		{Timestamp: at(13), Type: "track"},
+		{Timestamp: at(50), Type: "track"},
+		// split across the hours
+		{Timestamp: at(58), Type: "talk"},
+		{Timestamp: at(64), Type: "jingle"},
+		{Timestamp: at(65), Type: "track"},
+	}
+
+	expected := []HourlyAirtime{
+		{startDate, map[string]int64{"track": (5 + 15 + 8) * 60, "ad": 3 * 60, "news": 5 * 60,
+			"talk": 2 * 60}, 0.737, 0.184},
+		{startDate.Add(time.Hour), map[string]int64{"talk": 4 * 60, "jingle": 60,
+			"track": 15 * 60}, 0.75, 0.2},
+	}
+	if result := CalculateAirtime(trackRecords, startDate, endDate); !reflect.DeepEqual(result,
+		expected) {
+		t.Errorf("CalculateAirtime(): got %v, expected %v", result, expected)
+	}
+
+	empty := []HourlyAirtime{{startDate, map[string]int64{}, 0, 0},
+		{startDate.Add(time.Hour), map[string]int64{}, 0, 0}}
+	if result := CalculateAirtime(nil, startDate, endDate); !reflect.DeepEqual(result, empty) {
+		t.Errorf("CalculateAirtime(nil): got %v, expected %v", result, empty)
+	}
+}
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type APIResponseMessage struct {
 	Success bool        `json:"success"`
 	Data    interface{} `json:"data,omitempty"`
@@ -12,3 +14,35 @@ func NewAPIResponseMessage(data interface{}, err error) APIResponseMessage {
 	}
 	return APIResponseMessage{true, data, ""}
 }
+
+// LastModified returns the timestamp of the most recent track record the data is derived from,
+// or the zero time if it is unknown.
+func LastModified(data interface{}) time.Time {
+	switch data := data.(type) {
+	case TrackRecord:
+		return time.Unix(data.Timestamp, 0)
+	case Tracks:
+		return data.LastModified
+	case CountedTracks:
+		return data.LastModified
+	case CountedArtists:
+		return data.LastModified
+	case MatchedTracks:
+		return data.LastModified
+	case GroupTracks:
+		return data.LastModified
+	case Airtime:
+		return data.LastModified
+	case EncodedData:
+		return data.LastModified
+	default:
+		return time.Time{}
+	}
+}
+
+// RawData is served as is rather than wrapped into an APIResponseMessage, e. g. exports of track
+// records.
+type RawData struct {
+	ContentType string
+	Body        []byte
+}
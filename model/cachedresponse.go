@@ -0,0 +1,31 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CachedResponse is the JSON encoded result of a worker, stored by a response cache.
+type CachedResponse struct {
+	Key  string `json:"key" dynamodbav:"cacheKey"`
+	Data string `json:"data" dynamodbav:"data"`
+	// Expires is the unix time the response expires at, 0 if it never expires. DynamoDB uses the
+	// attribute to delete expired items.
+	Expires int64 `json:"expires,omitempty" dynamodbav:"expires,omitempty"`
+	// LastModified is the unix time of the most recent track record the data is derived from.
+	LastModified int64 `json:"lastModified,omitempty" dynamodbav:"lastModified,omitempty"`
+}
+
+func (response CachedResponse) Expired(now time.Time) bool {
+	return response.Expires != 0 && now.Unix() >= response.Expires
+}
+
+// EncodedData is data which has already been encoded to JSON, e. g. the data of a CachedResponse.
+type EncodedData struct {
+	Data         json.RawMessage
+	LastModified time.Time
+}
+
+func (data EncodedData) MarshalJSON() ([]byte, error) {
+	return data.Data, nil
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCachedResponse_Expired(t *testing.T) {
+	now := time.Unix(1537701181, 0)
+
+	var tests = []struct {
+		expires  int64
+		expected bool
+	}{
+		{0, false},
+		{1537701182, false},
+		{1537701181, true},
+		{1537701180, true},
+	}
+
+	for _, test := range tests {
+		response := CachedResponse{Key: "key", Expires: test.expires}
+		if expired := response.Expired(now); expired != test.expected {
+			t.Errorf("(%v).Expired(%v): got %v, expected %v", response, now, expired,
+				test.expected)
+		}
+	}
+}
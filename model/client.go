@@ -0,0 +1,61 @@
+package model
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// Tier determines the rate limits a client of the read endpoints is subject to.
+type Tier string
+
+const (
+	TierFree     Tier = "free"
+	TierPartner  Tier = "partner"
+	TierInternal Tier = "internal"
+)
+
+func ParseTier(str string) (Tier, error) {
+	switch tier := Tier(strings.ToLower(strings.TrimSpace(str))); tier {
+	case TierFree, TierPartner, TierInternal:
+		return tier, nil
+	}
+	return "", errors.New("tier must be one of `free`, `partner` or `internal`")
+}
+
+// Client identifies a consumer of the read endpoints by its API key. Just like credentials, only
+// the SHA-256 hash of the key is stored.
+type Client struct {
+	KeyHash  string `json:"keyHash"`
+	ClientID string `json:"clientId"`
+	Tier     Tier   `json:"tier"`
+}
+
+var clientIDRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+func (client *Client) Sanitize() error {
+	client.KeyHash = strings.ToLower(strings.TrimSpace(client.KeyHash))
+	if !tokenHashRegexp.MatchString(client.KeyHash) {
+		return errors.New("keyHash must be a hex encoded SHA-256 hash")
+	}
+
+	client.ClientID = strings.ToLower(strings.TrimSpace(client.ClientID))
+	if !clientIDRegexp.MatchString(client.ClientID) {
+		return errors.New("clientId contains invalid format")
+	}
+
+	tier, err := ParseTier(string(client.Tier))
+	if err != nil {
+		return err
+	}
+	client.Tier = tier
+	return nil
+}
+
+// RateLimit describes the state of a client's rate limit after a request has been counted.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	// Reset is the unix timestamp at which the client's full limit is available again.
+	Reset int64
+}
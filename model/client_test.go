@@ -0,0 +1,55 @@
+package model
+
+import "testing"
+
+func TestParseTier(t *testing.T) {
+	var tests = []struct {
+		input       string
+		expected    Tier
+		expectedErr bool
+	}{
+		{"free", TierFree, false},
+		{" Partner ", TierPartner, false},
+		{"INTERNAL", TierInternal, false},
+		{"premium", "", true},
+		{"", "", true},
+	}
+
+	for _, test := range tests {
+		result, err := ParseTier(test.input)
+		if (err != nil) != test.expectedErr || result != test.expected {
+			t.Errorf("ParseTier(%q): got (%q, %v), expected (%q, err: %v)", test.input, result,
+				err, test.expected, test.expectedErr)
+		}
+	}
+}
+
+func TestClient_Sanitize(t *testing.T) {
+	keyHash := HashToken("api-key")
+
+	var tests = []struct {
+		input       Client
+		expected    Client
+		expectedErr bool
+	}{
+		{Client{keyHash, "radio-app", "free"}, Client{keyHash, "radio-app", TierFree}, false},
+		{Client{" " + keyHash, "Radio-App", "PARTNER"}, Client{keyHash, "radio-app", TierPartner},
+			false},
+		{Client{"api-key", "radio-app", "free"}, Client{}, true},
+		{Client{keyHash, "radio app", "free"}, Client{}, true},
+		{Client{keyHash, "radio-app", ""}, Client{}, true},
+	}
+
+	for _, test := range tests {
+		client := test.input
+		err := client.Sanitize()
+		if (err != nil) != test.expectedErr {
+			t.Errorf("Sanitize(%v): got err (%v), expected err: %v", test.input, err,
+				test.expectedErr)
+			continue
+		}
+		if err == nil && client != test.expected {
+			t.Errorf("Sanitize(%v): got (%v), expected (%v)", test.input, client, test.expected)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// Credential grants a crawler write access to the track records of the listed stations. Only the
+// SHA-256 hash of the crawler's token is stored, never the token itself.
+type Credential struct {
+	TokenHash   string   `json:"tokenHash"`
+	PrincipalID string   `json:"principalId"`
+	Stations    []string `json:"stations"`
+}
+
+var (
+	tokenHashRegexp   = regexp.MustCompile(`^[0-9a-f]{64}$`)
+	principalIDRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+)
+
+// HashToken returns the hex encoded SHA-256 hash of a crawler's token.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (credential *Credential) Sanitize() error {
+	credential.TokenHash = strings.ToLower(strings.TrimSpace(credential.TokenHash))
+	if !tokenHashRegexp.MatchString(credential.TokenHash) {
+		return errors.New("tokenHash must be a hex encoded SHA-256 hash")
+	}
+
+	credential.PrincipalID = strings.ToLower(strings.TrimSpace(credential.PrincipalID))
+	if !principalIDRegexp.MatchString(credential.PrincipalID) {
+		return errors.New("principalId contains invalid format")
+	}
+
+	if len(credential.Stations) == 0 {
+		return errors.New("credential must be bound to at least one station")
+	}
+	for i, station := range credential.Stations {
+		stationId, err := sanitizeStationId(station)
+		if err != nil {
+			return err
+		}
+		credential.Stations[i] = stationId
+	}
+	return nil
+}
+
+// AllowsStation reports whether the credential grants write access to the station.
+func (credential Credential) AllowsStation(stationId string) bool {
+	for _, station := range credential.Stations {
+		if station == stationId {
+			return true
+		}
+	}
+	return false
+}
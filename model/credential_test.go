@@ -0,0 +1,67 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHashToken(t *testing.T) {
+	expected := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	if hash := HashToken("test"); hash != expected {
+		t.Errorf("HashToken(\"test\"): got `%s`, expected `%s`", hash, expected)
+	}
+}
+
+func TestCredential_Sanitize_Success(t *testing.T) {
+	hash := HashToken("test")
+
+	input := &Credential{" 9F86D081884C7D659A2FEAA0C55AD015A3BF4F1B2B0B822CD15D6C15B0F00A08 ",
+		" OE3-Crawler", []string{"Hitradio-OE3 ", "fm4"}}
+	expected := &Credential{hash, "oe3-crawler", []string{"hitradio-oe3", "fm4"}}
+
+	if err := input.Sanitize(); err != nil {
+		t.Errorf("Sanitize(): Expected no error, got `%s`.", err.Error())
+	}
+	if !reflect.DeepEqual(input, expected) {
+		t.Errorf("Sanitize(): Expected `%v`, got `%v`.", expected, input)
+	}
+}
+
+func TestCredential_Sanitize_Err(t *testing.T) {
+	hash := HashToken("test")
+
+	var tests = []Credential{
+		{"test", "oe3-crawler", []string{"hitradio-oe3"}},
+		{hash, "", []string{"hitradio-oe3"}},
+		{hash, "oe3 crawler", []string{"hitradio-oe3"}},
+		{hash, "oe3-crawler", nil},
+		{hash, "oe3-crawler", []string{"hitradio oe3"}},
+	}
+
+	for i, test := range tests {
+		if err := test.Sanitize(); err == nil {
+			t.Errorf("#%d Sanitize(): Expected error, got `%v` for `%v`.", i, err, test)
+		}
+	}
+}
+
+func TestCredential_AllowsStation(t *testing.T) {
+	credential := Credential{HashToken("test"), "oe3-crawler", []string{"hitradio-oe3", "fm4"}}
+
+	var tests = []struct {
+		stationId string
+		expected  bool
+	}{
+		{"hitradio-oe3", true},
+		{"fm4", true},
+		{"kronehit", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		if result := credential.AllowsStation(test.stationId); result != test.expected {
+			t.Errorf("AllowsStation(%q): got %v, expected %v", test.stationId, result,
+				test.expected)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	RolePrimary  = "primary"
+	RoleFeatured = "featured"
+)
+
+// Credit is an artist credited by a track, either as one of its primary artists or as a featured
+// artist.
+type Credit struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+var (
+	// featuringRegexp separates the primary artists from the featured ones, e. g. `a feat. b`.
+	featuringRegexp = regexp.MustCompile(`(?i)\s+(?:feat\.?|ft\.?|featuring)\s+`)
+	// creditSeparatorRegexp separates artists sharing a role, e. g. `a x b`, `a vs. b` or `a / b`.
+	// Separators require whitespace around them, so `axwell /\ ingrosso` stays a single artist.
+	creditSeparatorRegexp = regexp.MustCompile(`(?i)\s+(?:x|/|vs\.?)\s+`)
+	// ambiguousSeparatorRegexp separates artists like creditSeparatorRegexp, but also appears in
+	// the names of single acts, e. g. `simon & garfunkel` or `earth, wind & fire`.
+	ambiguousSeparatorRegexp = regexp.MustCompile(`\s*,\s*|\s+&\s+`)
+	// titleFeaturingRegexp finds the featured artists titles name in parentheses or brackets, e. g.
+	// `despacito (feat. justin bieber)`.
+	titleFeaturingRegexp = regexp.MustCompile(
+		`(?i)[(\[]\s*(?:feat\.?|ft\.?|featuring|with)\s+([^)\]]+)[)\]]`)
+)
+
+// ParseCredits splits artist and title into the credited artists: the artists preceding a
+// featuring are primary artists, the ones following it and the ones named in the title are
+// featured. Artists are credited once, in their first role; names keep their casing. Names
+// joined by `&` or `,` are credited as a single act, since that is how bands are named; see
+// Credit.Split.
+func ParseCredits(artist, title string) []Credit {
+	credits := make([]Credit, 0)
+	credited := make(map[string]bool)
+	add := func(names, role string) {
+		for _, name := range creditSeparatorRegexp.Split(names, -1) {
+			name = discardWhitespaces(name)
+			if name == "" || credited[strings.ToLower(name)] {
+				continue
+			}
+			credited[strings.ToLower(name)] = true
+			credits = append(credits, Credit{name, role})
+		}
+	}
+
+	parts := featuringRegexp.Split(artist, -1)
+	add(parts[0], RolePrimary)
+	for _, featured := range parts[1:] {
+		add(featured, RoleFeatured)
+	}
+	for _, match := range titleFeaturingRegexp.FindAllStringSubmatch(title, -1) {
+		add(match[1], RoleFeatured)
+	}
+	return credits
+}
+
+// Split splits the credit at `&` and `,` into the artists it may name, e. g. `nico & vinz`. Whether
+// they are separate artists or a single act is up to the caller; a credit without these
+// separators is returned unchanged.
+func (credit Credit) Split() []Credit {
+	credits := make([]Credit, 0, 1)
+	for _, name := range ambiguousSeparatorRegexp.Split(credit.Name, -1) {
+		if name = discardWhitespaces(name); name != "" {
+			credits = append(credits, Credit{name, credit.Role})
+		}
+	}
+	return credits
+}
+
+// creditedTrack is the served form of a track, which lists the artists it credits. Credits are
+// derived from artist and title whenever a track is served instead of being stored with the track
+// records: Track identifies plays as a comparable map key and cannot hold a list, and derived
+// credits follow changes of the parsing without rewriting the track records.
+type creditedTrack struct {
+	Track
+	Credits []Credit `json:"credits"`
+}
+
+func creditTracks(tracks []Track) []creditedTrack {
+	if tracks == nil {
+		return nil
+	}
+	credited := make([]creditedTrack, len(tracks))
+	for i, track := range tracks {
+		credited[i] = creditedTrack{track, track.Credits()}
+	}
+	return credited
+}
+
+// Credits returns the artists credited by the track, see ParseCredits.
+func (track Track) Credits() []Credit {
+	return ParseCredits(track.Artist, track.Title)
+}
+
+// CreditsArtist reports whether the track credits `artist` in any role. Artists are compared by
+// their folded keys (see FoldKey), i. e. ignoring casing and diacritics. Both a credit and the
+// artists it may name are matched (see Credit.Split), e. g. `nico & vinz` and `vinz`; the
+// complete artist string is matched as well.
+func (track Track) CreditsArtist(artist string) bool {
+	artist = FoldKey(artist)
+	if artist == "" {
+		return false
+	}
+	if FoldKey(track.Artist) == artist {
+		return true
+	}
+	for _, credit := range track.Credits() {
+		if FoldKey(credit.Name) == artist {
+			return true
+		}
+		for _, part := range credit.Split() {
+			if FoldKey(part.Name) == artist {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCredits(t *testing.T) {
+	var tests = []struct {
+		artist   string
+		title    string
+		expected []Credit
+	}{
+		{"rhcp", "californication", []Credit{{"rhcp", RolePrimary}}},
+		{"Felix Jaehn Feat. Jasmin Thompson", "Ain't Nobody", []Credit{
+			{"Felix Jaehn", RolePrimary}, {"Jasmin Thompson", RoleFeatured}}},
+		// names joined by `&` or `,` are credited as a single act
+		{"nico & vinz feat. kid ink & bebe rexha", "that's how you know", []Credit{
+			{"nico & vinz", RolePrimary}, {"kid ink & bebe rexha", RoleFeatured}}},
+		{"luis fonsi, daddy yankee featuring justin bieber", "despacito (remix)", []Credit{
+			{"luis fonsi, daddy yankee", RolePrimary}, {"justin bieber", RoleFeatured}}},
+		{"Earth, Wind & Fire", "September", []Credit{{"Earth, Wind & Fire", RolePrimary}}},
+		{"armin van buuren vs. vini vici", "great spirit", []Credit{
+			{"armin van buuren", RolePrimary}, {"vini vici", RolePrimary}}},
+		{"calvin harris x dua lipa / sam smith", "promises", []Credit{
+			{"calvin harris", RolePrimary}, {"dua lipa", RolePrimary}, {"sam smith", RolePrimary}}},
+		{"ed sheeran", "south of the border (feat. camila cabello x cardi b)", []Credit{
+			{"ed sheeran", RolePrimary}, {"camila cabello", RoleFeatured},
+			{"cardi b", RoleFeatured}}},
+		{"drake ft. rihanna", "too good [with rihanna]", []Credit{
+			{"drake", RolePrimary}, {"rihanna", RoleFeatured}}},
+		// separators require whitespace around them
+		{"axwell /\\ ingrosso", "more than you know", []Credit{
+			{"axwell /\\ ingrosso", RolePrimary}}},
+		{"lil nas x", "old town road", []Credit{{"lil nas x", RolePrimary}}},
+		{"jonas blue x jonas blue", "rise", []Credit{{"jonas blue", RolePrimary}}},
+	}
+
+	for _, test := range tests {
+		if result := ParseCredits(test.artist, test.title); !reflect.DeepEqual(result,
+			test.expected) {
+			t.Errorf("ParseCredits(%q, %q): got %v, expected %v", test.artist, test.title, result,
+				test.expected)
+		}
+	}
+}
+
+func TestCredit_Split(t *testing.T) {
+	var tests = []struct {
+		credit   Credit
+		expected []Credit
+	}{
+		{Credit{"Earth, Wind & Fire", RolePrimary}, []Credit{{"Earth", RolePrimary},
+			{"Wind", RolePrimary}, {"Fire", RolePrimary}}},
+		{Credit{"kid ink & bebe rexha", RoleFeatured}, []Credit{{"kid ink", RoleFeatured},
+			{"bebe rexha", RoleFeatured}}},
+		{Credit{"rhcp", RolePrimary}, []Credit{{"rhcp", RolePrimary}}},
+		// separators require whitespace around them
+		{Credit{"ac&dc", RolePrimary}, []Credit{{"ac&dc", RolePrimary}}},
+	}
+
+	for _, test := range tests {
+		if result := test.credit.Split(); !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("%v.Split(): got %v, expected %v", test.credit, result, test.expected)
+		}
+	}
+}
+
+func TestTrack_CreditsArtist(t *testing.T) {
+	var tests = []struct {
+		track    Track
+		artist   string
+		expected bool
+	}{
+		{Track{"nico & vinz feat. kid ink & bebe rexha", "that's how you know"}, "Kid Ink", true},
+		{Track{"nico & vinz feat. kid ink & bebe rexha", "that's how you know"}, "vinz", true},
+		{Track{"nico & vinz feat. kid ink & bebe rexha", "that's how you know"}, "kid", false},
+		{Track{"ed sheeran", "south of the border (feat. cardi b)"}, " cardi  b ", true},
+		{Track{"earth, wind & fire", "september"}, "earth, wind &amp; fire", true},
+		{Track{"earth, wind & fire", "september"}, "fire", true},
+		{Track{"nico & vinz feat. kid ink & bebe rexha", "that's how you know"}, "nico & vinz",
+			true},
+		{Track{"beyoncé feat. jay-z", "crazy in love"}, "Beyonce", true},
+		{Track{"beyonce", "halo"}, "BEYONCÉ", true},
+		{Track{"rhcp", "californication"}, "", false},
+	}
+
+	for _, test := range tests {
+		if result := test.track.CreditsArtist(test.artist); result != test.expected {
+			t.Errorf("%v.CreditsArtist(%q): got %v, expected %v", test.track, test.artist, result,
+				test.expected)
+		}
+	}
+}
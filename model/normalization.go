@@ -0,0 +1,162 @@
+package model
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// NormalizationRule is a step of the normalization pipeline, which merges the variants of a track
+// stations report, e. g. `rhcp` and `red hot chili peppers`. Rules run after the built-in
+// sanitization, hence they see lowercase strings; patterns match case-insensitively, suffixes and
+// aliases are lowercased when the rules are compiled.
+type NormalizationRule struct {
+	// Type is `regex`, `suffix` or `alias`.
+	Type string `yaml:"type" json:"type"`
+	// Field is `artist` or `title`; rules of type `regex` and `suffix` apply to both if it is
+	// empty.
+	Field string `yaml:"field" json:"field"`
+	// Pattern (RE2 syntax) and Replacement (`$1` refers to the first group) configure `regex`
+	// rules. The replacement is inserted as written, so its literal text should be lowercase.
+	Pattern     string `yaml:"pattern" json:"pattern"`
+	Replacement string `yaml:"replacement" json:"replacement"`
+	// Suffixes are stripped by `suffix` rules, e. g. `(radio edit)`.
+	Suffixes []string `yaml:"suffixes" json:"suffixes"`
+	// Aliases maps complete values to their canonical form for `alias` rules.
+	Aliases map[string]string `yaml:"aliases" json:"aliases"`
+}
+
+// NormalizationRules holds the rules of all stations and the rule sets of single stations. A
+// station's rules run after the common ones.
+type NormalizationRules struct {
+	Common   []NormalizationRule            `yaml:"common" json:"common"`
+	Stations map[string][]NormalizationRule `yaml:"stations" json:"stations"`
+}
+
+// Normalizer is the compiled normalization pipeline. The nil Normalizer leaves tracks unchanged.
+type Normalizer struct {
+	common   []normalizationStep
+	stations map[string][]normalizationStep
+}
+
+type normalizationStep func(track *Track)
+
+func NewNormalizer(rules NormalizationRules) (*Normalizer, error) {
+	common, err := compileRules(rules.Common)
+	if err != nil {
+		return nil, err
+	}
+	normalizer := &Normalizer{common, make(map[string][]normalizationStep)}
+	for station, stationRules := range rules.Stations {
+		steps, err := compileRules(stationRules)
+		if err != nil {
+			return nil, errors.New("normalization rules of station `" + station + "`: " +
+				err.Error())
+		}
+		normalizer.stations[station] = steps
+	}
+	return normalizer, nil
+}
+
+// Normalize applies the common rules and the rules of `station` to the sanitized track.
+func (normalizer *Normalizer) Normalize(station string, track *Track) {
+	if normalizer == nil {
+		return
+	}
+	for _, step := range normalizer.common {
+		step(track)
+	}
+	for _, step := range normalizer.stations[station] {
+		step(track)
+	}
+}
+
+func compileRules(rules []NormalizationRule) ([]normalizationStep, error) {
+	steps := make([]normalizationStep, 0, len(rules))
+	for i, rule := range rules {
+		step, err := compileRule(rule)
+		if err != nil {
+			return nil, errors.New("rule " + strconv.Itoa(i+1) + ": " + err.Error())
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+func compileRule(rule NormalizationRule) (normalizationStep, error) {
+	if rule.Field != "" && rule.Field != "artist" && rule.Field != "title" {
+		return nil, errors.New("field must be `artist`, `title` or empty")
+	}
+
+	var apply func(value string) string
+	switch rule.Type {
+	case "regex":
+		// lowercasing the pattern would turn escapes like `\D` into `\d`
+		pattern, err := regexp.Compile("(?i)" + rule.Pattern)
+		if err != nil || rule.Pattern == "" {
+			return nil, errors.New("pattern `" + rule.Pattern + "` is invalid")
+		}
+		apply = func(value string) string {
+			return pattern.ReplaceAllString(value, rule.Replacement)
+		}
+	case "suffix":
+		suffixes := make([]string, 0, len(rule.Suffixes))
+		for _, suffix := range rule.Suffixes {
+			if suffix = cleanString(suffix); suffix != "" {
+				suffixes = append(suffixes, suffix)
+			}
+		}
+		if len(suffixes) == 0 {
+			return nil, errors.New("suffixes must not be empty")
+		}
+		apply = func(value string) string {
+			return stripSuffixes(value, suffixes)
+		}
+	case "alias":
+		if rule.Field == "" {
+			return nil, errors.New("alias rules require a field")
+		}
+		aliases := make(map[string]string, len(rule.Aliases))
+		for alias, canonical := range rule.Aliases {
+			aliases[cleanString(alias)] = cleanString(canonical)
+		}
+		apply = func(value string) string {
+			if canonical, ok := aliases[value]; ok {
+				return canonical
+			}
+			return value
+		}
+	default:
+		return nil, errors.New("type `" + rule.Type + "` is not supported, expected `regex`, " +
+			"`suffix` or `alias`")
+	}
+
+	return func(track *Track) {
+		// a rule must not erase a field, the track would be rejected otherwise
+		if rule.Field != "title" {
+			if artist := discardWhitespaces(apply(track.Artist)); artist != "" {
+				track.Artist = artist
+			}
+		}
+		if rule.Field != "artist" {
+			if title := discardWhitespaces(apply(track.Title)); title != "" {
+				track.Title = title
+			}
+		}
+	}, nil
+}
+
+// stripSuffixes removes the suffixes until none of them is left, e. g. `(radio edit) (remastered)`.
+func stripSuffixes(value string, suffixes []string) string {
+	for stripped := true; stripped; {
+		stripped = false
+		for _, suffix := range suffixes {
+			if strings.HasSuffix(value, suffix) && len(value) > len(suffix) {
+				value = strings.TrimSpace(strings.TrimSuffix(value, suffix))
+				stripped = true
+			}
+		}
+	}
+	return value
+}
@@ -0,0 +1,141 @@
+package model
+
+import (
+	"testing"
+)
+
+var testNormalizationRules = NormalizationRules{
+	Common: []NormalizationRule{
+		{Type: "suffix", Field: "title", Suffixes: []string{"(Radio Edit)", "(Remastered)"}},
+		{Type: "regex", Field: "artist", Pattern: `\s+(feat\.|ft\.|featuring)\s+`,
+			Replacement: " feat. "},
+		{Type: "alias", Field: "artist", Aliases: map[string]string{
+			"RHCP":        "Red Hot Chili Peppers",
+			"The Weeknd ": "the weeknd",
+		}},
+	},
+	Stations: map[string][]NormalizationRule{
+		"fm4": {
+			{Type: "regex", Field: "title", Pattern: `^(.+) \(fm4 session\)$`, Replacement: "$1"},
+			{Type: "regex", Field: "title", Pattern: `\s+\(\D+ Edit\)$`},
+			{Type: "regex", Field: "artist", Pattern: `^(?P<Lead>.+) X (?P<Guest>.+)$`,
+				Replacement: "${Lead} & ${Guest}"},
+		},
+	},
+}
+
+func TestNewNormalizer(t *testing.T) {
+	var tests = []struct {
+		rules       NormalizationRules
+		expectedErr string
+	}{
+		{NormalizationRules{}, ""},
+		{testNormalizationRules, ""},
+		{NormalizationRules{Common: []NormalizationRule{{Type: "lowercase"}}},
+			"rule 1: type `lowercase` is not supported, expected `regex`, `suffix` or `alias`"},
+		{NormalizationRules{Common: []NormalizationRule{{Type: "suffix", Field: "album",
+			Suffixes: []string{"(live)"}}}}, "rule 1: field must be `artist`, `title` or empty"},
+		{NormalizationRules{Common: []NormalizationRule{{Type: "regex", Pattern: "("}}},
+			"rule 1: pattern `(` is invalid"},
+		{NormalizationRules{Common: []NormalizationRule{{Type: "regex"}}},
+			"rule 1: pattern `` is invalid"},
+		{NormalizationRules{Common: []NormalizationRule{{Type: "suffix",
+			Suffixes: []string{"(live)"}}, {Type: "suffix", Suffixes: []string{" "}}}},
+			"rule 2: suffixes must not be empty"},
+		{NormalizationRules{Common: []NormalizationRule{{Type: "alias",
+			Aliases: map[string]string{"rhcp": "red hot chili peppers"}}}},
+			"rule 1: alias rules require a field"},
+		{NormalizationRules{Stations: map[string][]NormalizationRule{"oe3": {{Type: "regex"}}}},
+			"normalization rules of station `oe3`: rule 1: pattern `` is invalid"},
+	}
+
+	for i, test := range tests {
+		_, err := NewNormalizer(test.rules)
+		if (err == nil && test.expectedErr != "") ||
+			(err != nil && err.Error() != test.expectedErr) {
+			t.Errorf("#%d NewNormalizer(): got err (%v), expected err (%s)", i, err,
+				test.expectedErr)
+		}
+	}
+}
+
+func TestNormalizer_Normalize(t *testing.T) {
+	normalizer, err := NewNormalizer(testNormalizationRules)
+	if err != nil {
+		t.Fatalf("NewNormalizer(): got err (%v), expected nil", err)
+	}
+
+	var tests = []struct {
+		normalizer *Normalizer
+		station    string
+		input      Track
+		expected   Track
+	}{
+		{normalizer, "oe3", Track{"rhcp", "californication"},
+			Track{"red hot chili peppers", "californication"}},
+		// aliases match complete values only
+		{normalizer, "oe3", Track{"rhcp & friends", "californication"},
+			Track{"rhcp & friends", "californication"}},
+		{normalizer, "oe3", Track{"the weeknd", "blinding lights (radio edit) (remastered)"},
+			Track{"the weeknd", "blinding lights"}},
+		{normalizer, "oe3", Track{"felix jaehn ft. jasmin thompson", "ain't nobody"},
+			Track{"felix jaehn feat. jasmin thompson", "ain't nobody"}},
+		// a rule must not erase a field
+		{normalizer, "oe3", Track{"queen", "(radio edit)"}, Track{"queen", "(radio edit)"}},
+		// station rules apply to their station only
+		{normalizer, "fm4", Track{"rhcp", "dark necessities (fm4 session)"},
+			Track{"red hot chili peppers", "dark necessities"}},
+		{normalizer, "oe3", Track{"rhcp", "dark necessities (fm4 session)"},
+			Track{"red hot chili peppers", "dark necessities (fm4 session)"}},
+		// uppercase escapes and named groups keep their meaning
+		{normalizer, "fm4", Track{"queen", "bohemian rhapsody (single edit)"},
+			Track{"queen", "bohemian rhapsody"}},
+		{normalizer, "fm4", Track{"queen", "bohemian rhapsody (2011 edit)"},
+			Track{"queen", "bohemian rhapsody (2011 edit)"}},
+		{normalizer, "fm4", Track{"sido x apache 207", "2002"}, Track{"sido & apache 207", "2002"}},
+		{nil, "oe3", Track{"rhcp", "californication"}, Track{"rhcp", "californication"}},
+	}
+
+	for i, test := range tests {
+		track := test.input
+		test.normalizer.Normalize(test.station, &track)
+		if track != test.expected {
+			t.Errorf("#%d Normalize(%s, %v): got %v, expected %v", i, test.station, test.input,
+				track, test.expected)
+		}
+	}
+}
+
+// TestNormalizer_Sanitized runs the normalization pipeline over the sanitization tests of tracks:
+// normalizing a sanitized input must yield the normalized expectation, and normalizing twice must
+// not change a track again.
+func TestNormalizer_Sanitized(t *testing.T) {
+	normalizer, _ := NewNormalizer(testNormalizationRules)
+	empty, _ := NewNormalizer(NormalizationRules{})
+
+	for i, test := range testsTrackSuccess {
+		input, expected := *test.input, *test.expected
+		if err := input.Sanitize(); err != nil {
+			t.Errorf("#%d Sanitize(): got err (%v), expected nil", i, err)
+			continue
+		}
+
+		unchanged := expected
+		empty.Normalize("fm4", &unchanged)
+		if unchanged != expected {
+			t.Errorf("#%d Normalize() without rules: got %v, expected %v", i, unchanged, expected)
+		}
+
+		normalizer.Normalize("fm4", &input)
+		normalizer.Normalize("fm4", &expected)
+		if input != expected {
+			t.Errorf("#%d Normalize(): got %v, expected %v", i, input, expected)
+		}
+
+		twice := input
+		normalizer.Normalize("fm4", &twice)
+		if twice != input {
+			t.Errorf("#%d Normalize() twice: got %v, expected %v", i, twice, input)
+		}
+	}
+}
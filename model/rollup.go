@@ -0,0 +1,74 @@
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+const RollupDayFormat = "2006-01-02"
+
+// TrackRollup holds how often a station played a track during a day. Rollups are maintained
+// alongside the track records, so charts of complete days don't require reading every play.
+type TrackRollup struct {
+	// Day is the date (`2006-01-02`) in the timezone days are calculated in.
+	Day       string `json:"day"`
+	StationId string `json:"stationId"`
+	Track
+	Plays int `json:"plays"`
+	// LastAirtime is the timestamp of the most recent play.
+	LastAirtime int64 `json:"lastAirtime"`
+	// TrackDisplay is the display form reported most often.
+	TrackDisplay
+}
+
+// FormatRollupDay returns the day of a rollup covering the given time. The time must be in the
+// timezone days are calculated in.
+func FormatRollupDay(date time.Time) string {
+	return date.Format(RollupDayFormat)
+}
+
+// RollUp counts the plays of the track records by station and track. The result is ordered by
+// station, artist and title.
+func RollUp(day string, trackRecords []TrackRecord) []TrackRollup {
+	type rollupKey struct {
+		stationId string
+		track     Track
+	}
+
+	indices := make(map[rollupKey]int)
+	rollups := make([]TrackRollup, 0)
+	casings := make([]Casings, 0)
+	for _, trackRecord := range trackRecords {
+		key := rollupKey{trackRecord.StationId, trackRecord.Track}
+		i, ok := indices[key]
+		if !ok {
+			i = len(rollups)
+			indices[key] = i
+			rollups = append(rollups, TrackRollup{day, trackRecord.StationId, trackRecord.Track, 0,
+				0, TrackDisplay{}})
+			casings = append(casings, make(Casings))
+		}
+		rollups[i].Plays++
+		if trackRecord.Timestamp > rollups[i].LastAirtime {
+			rollups[i].LastAirtime = trackRecord.Timestamp
+		}
+		casings[i].Add(trackRecord.Track, trackRecord.TrackDisplay, 1)
+	}
+
+	for i := range rollups {
+		display := casings[i].Display(rollups[i].Track)
+		rollups[i].TrackDisplay = TrackDisplay{displayForm(display.Artist, rollups[i].Artist),
+			displayForm(display.Title, rollups[i].Title)}
+	}
+
+	sort.Slice(rollups, func(i, j int) bool {
+		if rollups[i].StationId != rollups[j].StationId {
+			return rollups[i].StationId < rollups[j].StationId
+		}
+		if rollups[i].Artist != rollups[j].Artist {
+			return rollups[i].Artist < rollups[j].Artist
+		}
+		return rollups[i].Title < rollups[j].Title
+	})
+	return rollups
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRollUp(t *testing.T) {
+	var tests = []struct {
+		trackRecords []TrackRecord
+		expected     []TrackRollup
+	}{
+		{[]TrackRecord{}, []TrackRollup{}},
+		{
+			[]TrackRecord{
+				{StationId: "fm4", Timestamp: 1537701181, Track: Track{"rhcp", "californication"}},
+				{StationId: "oe3", Timestamp: 1537701181, Track: Track{"rhcp", "californication"}},
+				{StationId: "fm4", Timestamp: 1537704781, Track: Track{"rhcp", "californication"}},
+				{StationId: "fm4", Timestamp: 1537700000, Track: Track{"cardi b", "i like it"}},
+				{StationId: "fm4", Timestamp: 1537702000, Track: Track{"rhcp", "californication"}},
+			},
+			[]TrackRollup{
+				{"2018-09-23", "fm4", Track{"cardi b", "i like it"}, 1, 1537700000,
+					TrackDisplay{}},
+				{"2018-09-23", "fm4", Track{"rhcp", "californication"}, 3, 1537704781,
+					TrackDisplay{}},
+				{"2018-09-23", "oe3", Track{"rhcp", "californication"}, 1, 1537701181,
+					TrackDisplay{}},
+			},
+		},
+		// the display form reported most often is kept
+		{
+			[]TrackRecord{
+				{StationId: "fm4", Timestamp: 1537701181, Track: Track{"rhcp", "californication"},
+					TrackDisplay: TrackDisplay{"RHCP", "Californication"}},
+				{StationId: "fm4", Timestamp: 1537704781, Track: Track{"rhcp", "californication"},
+					TrackDisplay: TrackDisplay{"Rhcp", "Californication"}},
+				{StationId: "fm4", Timestamp: 1537702000, Track: Track{"rhcp", "californication"},
+					TrackDisplay: TrackDisplay{"RHCP", ""}},
+			},
+			[]TrackRollup{
+				{"2018-09-23", "fm4", Track{"rhcp", "californication"}, 3, 1537704781,
+					TrackDisplay{"RHCP", "Californication"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		if result := RollUp("2018-09-23", test.trackRecords); !reflect.DeepEqual(result,
+			test.expected) {
+			t.Errorf("RollUp(%v): got %v, expected %v", test.trackRecords, result, test.expected)
+		}
+	}
+}
+
+func TestFormatRollupDay(t *testing.T) {
+	location, _ := time.LoadLocation("Europe/Vienna")
+	date := time.Date(2018, 9, 23, 0, 30, 0, 0, location)
+
+	if day := FormatRollupDay(date); day != "2018-09-23" {
+		t.Errorf("FormatRollupDay(%v): got %q, expected %q", date, day, "2018-09-23")
+	}
+}
@@ -1,12 +1,148 @@
 package model
 
+import (
+	"errors"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
 type Station struct {
-	ID          string `json:"stationId"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Active      bool   `json:"active"`
+	ID          string      `json:"stationId"`
+	Name        string      `json:"name"`
+	Description string      `json:"description" dynamodbav:"description,omitempty"`
+	Active      bool        `json:"active"`
+	LogoURL     string      `json:"logo_url,omitempty" dynamodbav:"logo_url,omitempty"`
+	Website     string      `json:"website,omitempty" dynamodbav:"website,omitempty"`
+	StreamURLs  []string    `json:"stream_urls,omitempty" dynamodbav:"stream_urls,omitempty"`
+	Country     string      `json:"country,omitempty" dynamodbav:"country,omitempty"`
+	Region      string      `json:"region,omitempty" dynamodbav:"region,omitempty"`
+	Language    string      `json:"language,omitempty" dynamodbav:"language,omitempty"`
+	Genres      []string    `json:"genres,omitempty" dynamodbav:"genres,omitempty"`
+	Frequencies []Frequency `json:"frequencies,omitempty" dynamodbav:"frequencies,omitempty"`
+	Source      string      `json:"source,omitempty" dynamodbav:"source,omitempty"`
+}
+
+// Frequency is the FM frequency (in MHz) a station broadcasts on in a specific city.
+type Frequency struct {
+	City string  `json:"city"`
+	MHz  float64 `json:"mhz"`
 }
 
 type Stations struct {
 	Stations []Station `json:"stations"`
 }
+
+var (
+	countryCodeRegexp  = regexp.MustCompile(`^[A-Z]{2}$`)
+	languageCodeRegexp = regexp.MustCompile(`^[a-z]{2}$`)
+	sourceRegexp       = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+)
+
+func (station *Station) Sanitize() error {
+	stationId, err := sanitizeStationId(station.ID)
+	if err != nil {
+		return err
+	}
+	station.ID = stationId
+
+	station.Name = discardWhitespaces(html.UnescapeString(station.Name))
+	if station.Name == "" {
+		return errors.New("name contains invalid data")
+	}
+
+	station.Description = discardWhitespaces(html.UnescapeString(station.Description))
+
+	if station.LogoURL, err = sanitizeURL(station.LogoURL); err != nil {
+		return errors.New("logo_url contains invalid data")
+	}
+	if station.Website, err = sanitizeURL(station.Website); err != nil {
+		return errors.New("website contains invalid data")
+	}
+	for i, streamURL := range station.StreamURLs {
+		if station.StreamURLs[i], err = sanitizeURL(streamURL); err != nil ||
+			station.StreamURLs[i] == "" {
+			return errors.New("stream_urls contains invalid data")
+		}
+	}
+
+	station.Country = strings.ToUpper(strings.TrimSpace(station.Country))
+	if station.Country != "" && !countryCodeRegexp.MatchString(station.Country) {
+		return errors.New("country must be an ISO 3166-1 alpha-2 code")
+	}
+	station.Region = discardWhitespaces(html.UnescapeString(station.Region))
+	station.Language = strings.ToLower(strings.TrimSpace(station.Language))
+	if station.Language != "" && !languageCodeRegexp.MatchString(station.Language) {
+		return errors.New("language must be an ISO 639-1 code")
+	}
+
+	station.Genres = sanitizeGenres(station.Genres)
+
+	for i := range station.Frequencies {
+		frequency := &station.Frequencies[i]
+		frequency.City = discardWhitespaces(html.UnescapeString(frequency.City))
+		if frequency.City == "" {
+			return errors.New("frequencies contains an entry without city")
+		}
+		if frequency.MHz <= 0 {
+			return errors.New("frequencies contains an invalid frequency")
+		}
+	}
+
+	station.Source = strings.ToLower(strings.TrimSpace(station.Source))
+	if station.Source != "" && !sourceRegexp.MatchString(station.Source) {
+		return errors.New("source contains invalid format")
+	}
+	return nil
+}
+
+// HasGenre reports whether the station is tagged with `genre` (case-insensitive).
+func (station Station) HasGenre(genre string) bool {
+	genre = strings.ToLower(strings.TrimSpace(genre))
+	for _, stationGenre := range station.Genres {
+		if stationGenre == genre {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeURL accepts empty strings as well as absolute http(s) URLs.
+func sanitizeURL(str string) (string, error) {
+	str = strings.TrimSpace(str)
+	if str == "" {
+		return str, nil
+	}
+	u, err := url.Parse(str)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return str, errors.New("invalid url")
+	}
+	return u.String(), nil
+}
+
+// sanitizeGenres lowercases the genre tags, removes empty tags and duplicates and keeps the
+// original order.
+func sanitizeGenres(genres []string) []string {
+	if genres == nil {
+		return nil
+	}
+	sanitized := make([]string, 0, len(genres))
+	seen := make(map[string]bool)
+	for _, genre := range genres {
+		genre = strings.ToLower(discardWhitespaces(html.UnescapeString(genre)))
+		if genre == "" || seen[genre] {
+			continue
+		}
+		seen[genre] = true
+		sanitized = append(sanitized, genre)
+	}
+	return sanitized
+}
+
+// StationStatus extends a station with information about its most recent activity.
+type StationStatus struct {
+	Station
+	LastSeen   int64 `json:"last_seen,omitempty"`
+	PlaysToday int   `json:"plays_today"`
+}
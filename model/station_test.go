@@ -0,0 +1,91 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStation_Sanitize_Success(t *testing.T) {
+	var tests = []struct {
+		input    *Station
+		expected *Station
+	}{
+		{
+			&Station{ID: "kronehit", Name: "Kronehit", Description: "We are the most music", Active: true},
+			&Station{ID: "kronehit", Name: "Kronehit", Description: "We are the most music", Active: true},
+		},
+		{
+			&Station{ID: " HITRADIO-OE3 ", Name: "  Hitradio   Ö3 "},
+			&Station{ID: "hitradio-oe3", Name: "Hitradio Ö3"},
+		},
+		{
+			&Station{ID: "&nbsp;fm4", Name: "FM4", Description: "Radio &amp; more", Active: true},
+			&Station{ID: "fm4", Name: "FM4", Description: "Radio & more", Active: true},
+		},
+		{
+			&Station{
+				ID:          "kronehit",
+				Name:        "Kronehit",
+				Active:      true,
+				LogoURL:     " https://www.kronehit.at/logo.png ",
+				Website:     "https://www.kronehit.at",
+				StreamURLs:  []string{"http://onair.krone.at/kronehit.mp3"},
+				Country:     " at",
+				Region:      "  Wien ",
+				Language:    "DE",
+				Genres:      []string{"Pop", " pop", "", "Dance &amp; Electronic"},
+				Frequencies: []Frequency{{" Wien ", 104.6}, {"Graz", 102.5}},
+				Source:      " Kronehit-API ",
+			},
+			&Station{
+				ID:          "kronehit",
+				Name:        "Kronehit",
+				Active:      true,
+				LogoURL:     "https://www.kronehit.at/logo.png",
+				Website:     "https://www.kronehit.at",
+				StreamURLs:  []string{"http://onair.krone.at/kronehit.mp3"},
+				Country:     "AT",
+				Region:      "Wien",
+				Language:    "de",
+				Genres:      []string{"pop", "dance & electronic"},
+				Frequencies: []Frequency{{"Wien", 104.6}, {"Graz", 102.5}},
+				Source:      "kronehit-api",
+			},
+		},
+	}
+
+	for i, test := range tests {
+		if err := test.input.Sanitize(); err != nil {
+			t.Errorf("#%d Sanitize(): Expected no error, got `%s`.", i, err.Error())
+			continue
+		}
+		if !reflect.DeepEqual(*test.input, *test.expected) {
+			t.Errorf("#%d Sanitize(): Expected `%v`, got `%v`.", i, test.expected, test.input)
+		}
+	}
+}
+
+func TestStation_Sanitize_Err(t *testing.T) {
+	var tests = []Station{
+		{ID: "", Name: "Kronehit", Active: true},
+		{ID: "k", Name: "Kronehit", Active: true},
+		{ID: "krone hit", Name: "Kronehit", Active: true},
+		{ID: "1kronehit", Name: "Kronehit", Active: true},
+		{ID: "kronehit", Name: "", Active: true},
+		{ID: "kronehit", Name: "   ", Active: true},
+		{ID: "kronehit", Name: "Kronehit", LogoURL: "logo.png"},
+		{ID: "kronehit", Name: "Kronehit", Website: "ftp://kronehit.at"},
+		{ID: "kronehit", Name: "Kronehit", StreamURLs: []string{""}},
+		{ID: "kronehit", Name: "Kronehit", Country: "AUT"},
+		{ID: "kronehit", Name: "Kronehit", Language: "german"},
+		{ID: "kronehit", Name: "Kronehit", Frequencies: []Frequency{{"", 104.6}}},
+		{ID: "kronehit", Name: "Kronehit", Frequencies: []Frequency{{"Wien", 0}}},
+		{ID: "kronehit", Name: "Kronehit", Source: "kronehit api"},
+	}
+
+	for i, test := range tests {
+		if err := test.Sanitize(); err == nil {
+			t.Errorf("#%d Sanitize(): Expected error, got `%v` for `%v`.", i, err, test)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"errors"
+	"html"
+)
+
+// StationGroup bundles the regional variants of a radio network.
+type StationGroup struct {
+	ID          string   `json:"groupId"`
+	Name        string   `json:"name"`
+	Description string   `json:"description" dynamodbav:"description,omitempty"`
+	Stations    []string `json:"stations"`
+}
+
+type StationGroups struct {
+	Groups []StationGroup `json:"groups"`
+}
+
+func (group *StationGroup) Sanitize() error {
+	groupId, err := sanitizeStationId(group.ID)
+	if err != nil {
+		return errors.New("groupId contains invalid format")
+	}
+	group.ID = groupId
+
+	group.Name = discardWhitespaces(html.UnescapeString(group.Name))
+	if group.Name == "" {
+		return errors.New("name contains invalid data")
+	}
+
+	group.Description = discardWhitespaces(html.UnescapeString(group.Description))
+
+	stations := make([]string, 0, len(group.Stations))
+	seen := make(map[string]bool)
+	for _, station := range group.Stations {
+		stationId, err := sanitizeStationId(station)
+		if err != nil {
+			return err
+		}
+		if !seen[stationId] {
+			seen[stationId] = true
+			stations = append(stations, stationId)
+		}
+	}
+	if len(stations) == 0 {
+		return errors.New("group must contain at least one station")
+	}
+	group.Stations = stations
+	return nil
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStationGroup_Sanitize_Success(t *testing.T) {
+	var tests = []struct {
+		input    *StationGroup
+		expected *StationGroup
+	}{
+		{
+			&StationGroup{"oe3", "Hitradio Ö3", "", []string{"hitradio-oe3"}},
+			&StationGroup{"oe3", "Hitradio Ö3", "", []string{"hitradio-oe3"}},
+		},
+		{
+			&StationGroup{" ORF ", "  ORF &amp; Co ", "Regional ", []string{"orf-wien", " ORF-Tirol",
+				"orf-wien"}},
+			&StationGroup{"orf", "ORF & Co", "Regional", []string{"orf-wien", "orf-tirol"}},
+		},
+	}
+
+	for i, test := range tests {
+		if err := test.input.Sanitize(); err != nil {
+			t.Errorf("#%d Sanitize(): Expected no error, got `%s`.", i, err.Error())
+			continue
+		}
+		if !reflect.DeepEqual(*test.input, *test.expected) {
+			t.Errorf("#%d Sanitize(): Expected `%v`, got `%v`.", i, test.expected, test.input)
+		}
+	}
+}
+
+func TestStationGroup_Sanitize_Err(t *testing.T) {
+	var tests = []StationGroup{
+		{"", "ORF", "", []string{"orf-wien"}},
+		{"o r f", "ORF", "", []string{"orf-wien"}},
+		{"orf", " ", "", []string{"orf-wien"}},
+		{"orf", "ORF", "", nil},
+		{"orf", "ORF", "", []string{}},
+		{"orf", "ORF", "", []string{"orf wien"}},
+	}
+
+	for i, test := range tests {
+		if err := test.Sanitize(); err == nil {
+			t.Errorf("#%d Sanitize(): Expected error, got `%v` for `%v`.", i, err, test)
+		}
+	}
+}
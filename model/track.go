@@ -3,6 +3,7 @@ package model
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"regexp"
 	"time"
 )
@@ -50,17 +51,16 @@ func removeBranding(str string) string {
 	return r.ReplaceAllString(str, "")
 }
 
-// addPeriod ensures that certain words are followed by a period (.), e. g. `feat` => `feat.`
+// addPeriod ensures that certain words are followed by a period (.), e. g. `feat` => `feat.`. The
+// casing of the words is kept, so the display form of artists is treated alike.
 func addPeriod(str string) string {
-	// featuring (1)
-	r := regexp.MustCompile(` feat `)
-	str = r.ReplaceAllString(str, " feat. ")
-	// featuring (2)
-	r = regexp.MustCompile(` ft `)
-	str = r.ReplaceAllString(str, " ft. ")
-	// versus
-	r = regexp.MustCompile(` vs `)
-	return r.ReplaceAllString(str, " vs. ")
+	// featuring (`feat`, `ft`) and versus
+	r := regexp.MustCompile(`(?i) (feat|ft|vs) `)
+	// matches are adjacent if several words are separated by single spaces
+	for r.MatchString(str) {
+		str = r.ReplaceAllString(str, " $1. ")
+	}
+	return str
 }
 
 type CountedTrack struct {
@@ -73,11 +73,28 @@ type MatchedTrack struct {
 	Track           Track          `json:"track"`
 }
 
+// GroupTrack is a track played by the member stations of a station group. Counter holds the sum
+// of all plays, CountsByStation the plays per member station.
+type GroupTrack struct {
+	Counter         int            `json:"times_played"`
+	CountsByStation map[string]int `json:"plays_by_station"`
+	Track           Track          `json:"track"`
+}
+
+type CountedArtist struct {
+	Counter int    `json:"times_played"`
+	Artist  string `json:"artist"`
+}
+
+// The LastModified fields of the following types hold the timestamp of the most recent track record
+// the tracks are derived from. They are zero if no track record was found.
+
 type Tracks struct {
-	Station   string    `json:"station"`
-	StartDate time.Time `json:"omit"`
-	EndDate   time.Time `json:"omit"`
-	Tracks    []Track   `json:"tracks"`
+	Station      string    `json:"station"`
+	StartDate    time.Time `json:"omit"`
+	EndDate      time.Time `json:"omit"`
+	Tracks       []Track   `json:"tracks"`
+	LastModified time.Time `json:"-"`
 }
 
 type CountedTracks struct {
@@ -85,12 +102,68 @@ type CountedTracks struct {
 	StartDate     time.Time      `json:"omit"`
 	EndDate       time.Time      `json:"omit"`
 	CountedTracks []CountedTrack `json:"tracks"`
+	LastModified  time.Time      `json:"-"`
 }
 
 type MatchedTracks struct {
 	StartDate     time.Time      `json:"omit"`
 	EndDate       time.Time      `json:"omit"`
 	MatchedTracks []MatchedTrack `json:"tracks"`
+	LastModified  time.Time      `json:"-"`
+}
+
+// CountedArtists ranks the artists by the plays of the tracks crediting them; a play counts for
+// each artist credited.
+type CountedArtists struct {
+	Station        string          `json:"station"`
+	StartDate      time.Time       `json:"-"`
+	EndDate        time.Time       `json:"-"`
+	CountedArtists []CountedArtist `json:"artists"`
+	LastModified   time.Time       `json:"-"`
+}
+
+type GroupTracks struct {
+	Group        string       `json:"group"`
+	StartDate    time.Time    `json:"-"`
+	EndDate      time.Time    `json:"-"`
+	GroupTracks  []GroupTrack `json:"tracks"`
+	LastModified time.Time    `json:"-"`
+}
+
+// The MarshalJSON methods of tracks serve the artists they credit along with artist and title,
+// see creditedTrack.
+
+func (track CountedTrack) MarshalJSON() ([]byte, error) {
+	type Alias CountedTrack
+	return json.Marshal(&struct {
+		Alias
+		Track creditedTrack `json:"track"`
+	}{
+		Alias: (Alias)(track),
+		Track: creditedTrack{track.Track, track.Track.Credits()},
+	})
+}
+
+func (track MatchedTrack) MarshalJSON() ([]byte, error) {
+	type Alias MatchedTrack
+	return json.Marshal(&struct {
+		Alias
+		Track creditedTrack `json:"track"`
+	}{
+		Alias: (Alias)(track),
+		Track: creditedTrack{track.Track, track.Track.Credits()},
+	})
+}
+
+func (track GroupTrack) MarshalJSON() ([]byte, error) {
+	type Alias GroupTrack
+	return json.Marshal(&struct {
+		Alias
+		Track creditedTrack `json:"track"`
+	}{
+		Alias: (Alias)(track),
+		Track: creditedTrack{track.Track, track.Track.Credits()},
+	})
 }
 
 func (tracks Tracks) MarshalJSON() ([]byte, error) {
@@ -99,20 +172,26 @@ func (tracks Tracks) MarshalJSON() ([]byte, error) {
 		return json.Marshal(&struct {
 			Date string `json:"date"`
 			Alias
+			Tracks []creditedTrack `json:"tracks"`
 		}{
-			Date:  tracks.StartDate.Format(dateFormat),
-			Alias: (Alias)(tracks),
+			Date:   tracks.StartDate.Format(dateFormat),
+			Alias:  (Alias)(tracks),
+			Tracks: creditTracks(tracks.Tracks),
 		})
 	}
 
 	return json.Marshal(&struct {
 		StartDate string `json:"start_date"`
 		EndDate   string `json:"end_date"`
+		ISOWeek   string `json:"iso_week"`
 		Alias
+		Tracks []creditedTrack `json:"tracks"`
 	}{
 		StartDate: tracks.StartDate.Format(dateFormat),
 		EndDate:   tracks.EndDate.Format(dateFormat),
+		ISOWeek:   formatISOWeek(tracks.StartDate),
 		Alias:     (Alias)(tracks),
+		Tracks:    creditTracks(tracks.Tracks),
 	})
 }
 
@@ -131,10 +210,12 @@ func (tracks CountedTracks) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&struct {
 		StartDate string `json:"start_date"`
 		EndDate   string `json:"end_date"`
+		ISOWeek   string `json:"iso_week"`
 		Alias
 	}{
 		StartDate: tracks.StartDate.Format(dateFormat),
 		EndDate:   tracks.EndDate.Format(dateFormat),
+		ISOWeek:   formatISOWeek(tracks.StartDate),
 		Alias:     (Alias)(tracks),
 	})
 }
@@ -154,14 +235,73 @@ func (tracks MatchedTracks) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&struct {
 		StartDate string `json:"start_date"`
 		EndDate   string `json:"end_date"`
+		ISOWeek   string `json:"iso_week"`
 		Alias
 	}{
 		StartDate: tracks.StartDate.Format(dateFormat),
 		EndDate:   tracks.EndDate.Format(dateFormat),
+		ISOWeek:   formatISOWeek(tracks.StartDate),
 		Alias:     (Alias)(tracks),
 	})
 }
 
+func (tracks GroupTracks) MarshalJSON() ([]byte, error) {
+	type Alias GroupTracks
+	if equalDate(tracks.StartDate, tracks.EndDate) {
+		return json.Marshal(&struct {
+			Date string `json:"date"`
+			Alias
+		}{
+			Date:  tracks.StartDate.Format(dateFormat),
+			Alias: (Alias)(tracks),
+		})
+	}
+
+	return json.Marshal(&struct {
+		StartDate string `json:"start_date"`
+		EndDate   string `json:"end_date"`
+		ISOWeek   string `json:"iso_week"`
+		Alias
+	}{
+		StartDate: tracks.StartDate.Format(dateFormat),
+		EndDate:   tracks.EndDate.Format(dateFormat),
+		ISOWeek:   formatISOWeek(tracks.StartDate),
+		Alias:     (Alias)(tracks),
+	})
+}
+
+func (artists CountedArtists) MarshalJSON() ([]byte, error) {
+	type Alias CountedArtists
+	if equalDate(artists.StartDate, artists.EndDate) {
+		return json.Marshal(&struct {
+			Date string `json:"date"`
+			Alias
+		}{
+			Date:  artists.StartDate.Format(dateFormat),
+			Alias: (Alias)(artists),
+		})
+	}
+
+	return json.Marshal(&struct {
+		StartDate string `json:"start_date"`
+		EndDate   string `json:"end_date"`
+		ISOWeek   string `json:"iso_week"`
+		Alias
+	}{
+		StartDate: artists.StartDate.Format(dateFormat),
+		EndDate:   artists.EndDate.Format(dateFormat),
+		ISOWeek:   formatISOWeek(artists.StartDate),
+		Alias:     (Alias)(artists),
+	})
+}
+
+// formatISOWeek returns the ISO week (e. g. `2018-W07`) sharing the most days with the week
+// starting at `weekStartDate`. For weeks starting on Monday this is the exact ISO week.
+func formatISOWeek(weekStartDate time.Time) string {
+	year, week := weekStartDate.AddDate(0, 0, 3).ISOWeek()
+	return fmt.Sprintf("%04d-W%02d", year, week)
+}
+
 func equalDate(d1, d2 time.Time) bool {
 	return d1.Day() == d2.Day() &&
 		d1.Month() == d2.Month() &&
@@ -168,6 +168,10 @@ var dayEnd, _ = time.Parse(time.RFC3339, "2018-09-19T23:59:59+00:00")
 var weekStart, _ = time.Parse(time.RFC3339, "2018-09-17T00:00:00+00:00")
 var weekEnd, _ = time.Parse(time.RFC3339, "2018-09-23T23:59:59+00:00")
 
+// trackJSON is the served form of Track{"artist", "title"}.
+const trackJSON = "{\"artist\":\"artist\",\"title\":\"title\"," +
+	"\"credits\":[{\"name\":\"artist\",\"role\":\"primary\"}]}"
+
 func TestTracks_MarshalJSON(t *testing.T) {
 	var tests = []struct {
 		tracks          *Tracks
@@ -179,9 +183,10 @@ func TestTracks_MarshalJSON(t *testing.T) {
 				dayStart,
 				dayEnd,
 				[]Track{{"artist", "title"}},
+				time.Now(), // not serialized
 			},
 			"{\"date\":\"2018-09-19\",\"station\":\"test\"," +
-				"\"tracks\":[{\"artist\":\"artist\",\"title\":\"title\"}]}",
+				"\"tracks\":[" + trackJSON + "]}",
 		},
 		{
 			&Tracks{
@@ -189,9 +194,11 @@ func TestTracks_MarshalJSON(t *testing.T) {
 				weekStart,
 				weekEnd,
 				[]Track{{"artist", "title"}},
+				time.Now(), // not serialized
 			},
-			"{\"start_date\":\"2018-09-17\",\"end_date\":\"2018-09-23\",\"station\":\"test\"," +
-				"\"tracks\":[{\"artist\":\"artist\",\"title\":\"title\"}]}",
+			"{\"start_date\":\"2018-09-17\",\"end_date\":\"2018-09-23\",\"iso_week\":\"2018-W38\"," +
+				"\"station\":\"test\"," +
+				"\"tracks\":[" + trackJSON + "]}",
 		},
 	}
 
@@ -215,10 +222,10 @@ func TestCountedTracks_MarshalJSON(t *testing.T) {
 				dayStart,
 				dayEnd,
 				[]CountedTrack{{1, Track{"artist", "title"}}},
+				time.Now(), // not serialized
 			},
 			"{\"date\":\"2018-09-19\",\"station\":\"test\"," +
-				"\"tracks\":[{\"times_played\":1,\"track\":{\"artist\":\"artist\"," +
-				"\"title\":\"title\"}}]}",
+				"\"tracks\":[{\"times_played\":1,\"track\":" + trackJSON + "}]}",
 		},
 		{
 			&CountedTracks{
@@ -226,10 +233,23 @@ func TestCountedTracks_MarshalJSON(t *testing.T) {
 				weekStart,
 				weekEnd,
 				[]CountedTrack{{1, Track{"artist", "title"}}},
+				time.Now(), // not serialized
+			},
+			"{\"start_date\":\"2018-09-17\",\"end_date\":\"2018-09-23\",\"iso_week\":\"2018-W38\"," +
+				"\"station\":\"test\"," +
+				"\"tracks\":[{\"times_played\":1,\"track\":" + trackJSON + "}]}",
+		},
+		{
+			&CountedTracks{
+				"test",
+				weekStart.AddDate(0, 0, -1),
+				weekEnd.AddDate(0, 0, -1),
+				[]CountedTrack{{1, Track{"artist", "title"}}},
+				time.Now(), // not serialized
 			},
-			"{\"start_date\":\"2018-09-17\",\"end_date\":\"2018-09-23\",\"station\":\"test\"," +
-				"\"tracks\":[{\"times_played\":1,\"track\":{\"artist\":\"artist\"," +
-				"\"title\":\"title\"}}]}",
+			"{\"start_date\":\"2018-09-16\",\"end_date\":\"2018-09-22\",\"iso_week\":\"2018-W38\"," +
+				"\"station\":\"test\"," +
+				"\"tracks\":[{\"times_played\":1,\"track\":" + trackJSON + "}]}",
 		},
 	}
 
@@ -242,6 +262,32 @@ func TestCountedTracks_MarshalJSON(t *testing.T) {
 	}
 }
 
+func TestCountedArtists_MarshalJSON(t *testing.T) {
+	var tests = []struct {
+		artists         *CountedArtists
+		expectedJSONStr string
+	}{
+		{
+			&CountedArtists{"test", dayStart, dayEnd, []CountedArtist{{2, "Kid Ink"}}, time.Now()},
+			"{\"date\":\"2018-09-19\",\"station\":\"test\"," +
+				"\"artists\":[{\"times_played\":2,\"artist\":\"Kid Ink\"}]}",
+		},
+		{
+			&CountedArtists{"test", weekStart, weekEnd, []CountedArtist{}, time.Now()},
+			"{\"start_date\":\"2018-09-17\",\"end_date\":\"2018-09-23\",\"iso_week\":\"2018-W38\"," +
+				"\"station\":\"test\",\"artists\":[]}",
+		},
+	}
+
+	for _, test := range tests {
+		jsonStr, _ := json.Marshal(test.artists)
+		if string(jsonStr) != test.expectedJSONStr {
+			t.Errorf("json.Marshal(%v): got: \n`%s`, expected: \n`%s`",
+				test, jsonStr, test.expectedJSONStr)
+		}
+	}
+}
+
 func TestMatchedTracks_MarshalJSON(t *testing.T) {
 	var tests = []struct {
 		tracks          *MatchedTracks
@@ -252,20 +298,61 @@ func TestMatchedTracks_MarshalJSON(t *testing.T) {
 				dayStart,
 				dayEnd,
 				[]MatchedTrack{{map[string]int{"test": 1}, Track{"artist", "title"}}},
+				time.Now(), // not serialized
 			},
 			"{\"date\":\"2018-09-19\"," +
-				"\"tracks\":[{\"plays_by_station\":{\"test\":1},\"track\":{\"artist\":\"artist\"," +
-				"\"title\":\"title\"}}]}",
+				"\"tracks\":[{\"plays_by_station\":{\"test\":1},\"track\":" + trackJSON + "}]}",
 		},
 		{
 			&MatchedTracks{
 				weekStart,
 				weekEnd,
 				[]MatchedTrack{{map[string]int{"test": 1}, Track{"artist", "title"}}},
+				time.Now(), // not serialized
+			},
+			"{\"start_date\":\"2018-09-17\",\"end_date\":\"2018-09-23\",\"iso_week\":\"2018-W38\"," +
+				"\"tracks\":[{\"plays_by_station\":{\"test\":1},\"track\":" + trackJSON + "}]}",
+		},
+	}
+
+	for _, test := range tests {
+		jsonStr, _ := json.Marshal(test.tracks)
+		if string(jsonStr) != test.expectedJSONStr {
+			t.Errorf("json.Marshal(%v): got: \n`%s`, expected: \n`%s`",
+				test, jsonStr, test.expectedJSONStr)
+		}
+	}
+}
+
+func TestGroupTracks_MarshalJSON(t *testing.T) {
+	var tests = []struct {
+		tracks          *GroupTracks
+		expectedJSONStr string
+	}{
+		{
+			&GroupTracks{
+				"orf",
+				dayStart,
+				dayEnd,
+				[]GroupTrack{{3, map[string]int{"a": 1, "b": 2}, Track{"artist", "title"}}},
+				time.Now(), // not serialized
+			},
+			"{\"date\":\"2018-09-19\",\"group\":\"orf\"," +
+				"\"tracks\":[{\"times_played\":3,\"plays_by_station\":{\"a\":1,\"b\":2}," +
+				"\"track\":" + trackJSON + "}]}",
+		},
+		{
+			&GroupTracks{
+				"orf",
+				weekStart,
+				weekEnd,
+				[]GroupTrack{{3, map[string]int{"a": 1, "b": 2}, Track{"artist", "title"}}},
+				time.Now(), // not serialized
 			},
-			"{\"start_date\":\"2018-09-17\",\"end_date\":\"2018-09-23\"," +
-				"\"tracks\":[{\"plays_by_station\":{\"test\":1},\"track\":{\"artist\":\"artist\"," +
-				"\"title\":\"title\"}}]}",
+			"{\"start_date\":\"2018-09-17\",\"end_date\":\"2018-09-23\",\"iso_week\":\"2018-W38\"," +
+				"\"group\":\"orf\"," +
+				"\"tracks\":[{\"times_played\":3,\"plays_by_station\":{\"a\":1,\"b\":2}," +
+				"\"track\":" + trackJSON + "}]}",
 		},
 	}
 
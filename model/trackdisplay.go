@@ -0,0 +1,117 @@
+package model
+
+import (
+	"html"
+	"strings"
+)
+
+// TrackDisplay holds artist and title as stations report them, e. g. `Ty Dolla $ign`. Tracks are
+// grouped and searched by their lowercase form, the display form is served only. Empty fields
+// stand for the lowercase form, either since it was reported like this or since the sanitization
+// changed more than the casing, e. g. by an alias rule.
+type TrackDisplay struct {
+	DisplayArtist string `json:"display_artist,omitempty" dynamodbav:"display_artist,omitempty"`
+	DisplayTitle  string `json:"display_title,omitempty" dynamodbav:"display_title,omitempty"`
+}
+
+// sanitizeDisplay derives the display form of the sanitized record from the reported artist and
+// title.
+func (record *TrackRecord) sanitizeDisplay(artist, title string) {
+	artist = normalizeUnicode(html.UnescapeString(artist))
+	title = normalizeUnicode(html.UnescapeString(title))
+	artist = replaceURLs(discardWhitespaces(artist), "¯\\_(ツ)_/¯")
+	title = replaceURLs(discardWhitespaces(title), "¯\\_(ツ)_/¯")
+	if record.Type == TypeTrack {
+		artist = addPeriod(artist)
+		title = removeBranding(title)
+	}
+	record.DisplayArtist = displayForm(artist, record.Artist)
+	record.DisplayTitle = displayForm(title, record.Title)
+}
+
+func displayForm(display, normalized string) string {
+	if display == normalized || strings.ToLower(display) != normalized {
+		return ""
+	}
+	return display
+}
+
+type casingCounter struct {
+	artists map[string]int
+	titles  map[string]int
+}
+
+// Casings counts the plays of the display forms of tracks in order to serve each track in its most
+// common form.
+type Casings map[Track]casingCounter
+
+// Add counts the plays of a track reported in the given display form.
+func (casings Casings) Add(track Track, display TrackDisplay, plays int) {
+	counter := casings.counter(track)
+	counter.artists[display.DisplayArtist] += plays
+	counter.titles[display.DisplayTitle] += plays
+}
+
+func (casings Casings) counter(track Track) casingCounter {
+	counter, ok := casings[track]
+	if !ok {
+		counter = casingCounter{make(map[string]int), make(map[string]int)}
+		casings[track] = counter
+	}
+	return counter
+}
+
+// AddRollups counts the plays of the rollups.
+func (casings Casings) AddRollups(rollups []TrackRollup) {
+	for _, rollup := range rollups {
+		casings.Add(rollup.Track, rollup.TrackDisplay, rollup.Plays)
+	}
+}
+
+// Merge adds the plays counted by `other`.
+func (casings Casings) Merge(other Casings) {
+	for track, otherCounter := range other {
+		counter := casings.counter(track)
+		for artist, plays := range otherCounter.artists {
+			counter.artists[artist] += plays
+		}
+		for title, plays := range otherCounter.titles {
+			counter.titles[title] += plays
+		}
+	}
+}
+
+// Display returns the track in the display form of artist and title played most often. Ties are
+// resolved in favour of the lexically smallest form, so identical plays produce identical results.
+func (casings Casings) Display(track Track) Track {
+	counter, ok := casings[track]
+	if !ok {
+		return track
+	}
+	return Track{mostCommon(counter.artists, track.Artist), mostCommon(counter.titles, track.Title)}
+}
+
+func mostCommon(counts map[string]int, normalized string) string {
+	result, max := normalized, 0
+	for display, count := range counts {
+		if display == "" {
+			display = normalized
+		}
+		if count > max || (count == max && display < result) {
+			result, max = display, count
+		}
+	}
+	return result
+}
+
+// fill sets the empty display forms to the ones of `other` and reports whether any has been set.
+func (display *TrackDisplay) fill(other TrackDisplay) bool {
+	filled := false
+	if display.DisplayArtist == "" && other.DisplayArtist != "" {
+		display.DisplayArtist, filled = other.DisplayArtist, true
+	}
+	if display.DisplayTitle == "" && other.DisplayTitle != "" {
+		display.DisplayTitle, filled = other.DisplayTitle, true
+	}
+	return filled
+}
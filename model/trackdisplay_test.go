@@ -0,0 +1,91 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestTrackRecord_Sanitize_Display(t *testing.T) {
+	normalizer, _ := NewNormalizer(testNormalizationRules)
+	rules := DefaultTrackRecordRules
+	rules.Normalizer = normalizer
+
+	var tests = []struct {
+		input    TrackRecord
+		expected TrackDisplay
+	}{
+		{TrackRecord{Track: Track{" Ty  Dolla $ign ", "Or Nah (Branding)"}},
+			TrackDisplay{"Ty Dolla $ign", "Or Nah"}},
+		{TrackRecord{Track: Track{"Felix Jaehn Feat Jasmin Thompson", "Ain't Nobody"}},
+			TrackDisplay{"Felix Jaehn Feat. Jasmin Thompson", "Ain't Nobody"}},
+		{TrackRecord{Track: Track{"Nico &amp; Vinz", "That's How You Know"}},
+			TrackDisplay{"Nico & Vinz", "That's How You Know"}},
+		// lowercase input needs no display form
+		{TrackRecord{Track: Track{"cardi b", "I Like It"}}, TrackDisplay{"", "I Like It"}},
+		// normalization rules changing more than the casing drop the display form
+		{TrackRecord{Track: Track{"RHCP", "Californication (Radio Edit)"}}, TrackDisplay{}},
+		// sanitizing a stored track record keeps its display form
+		{TrackRecord{Track: Track{"ty dolla $ign", "or nah"},
+			TrackDisplay: TrackDisplay{"Ty Dolla $ign", "Or Nah"}},
+			TrackDisplay{"Ty Dolla $ign", "Or Nah"}},
+		// the display form is normalized like the lowercase form
+		{TrackRecord{Track: Track{"Ｂｅｙｏｎｃé\u200b", "Don’t Hurt Yourself"}},
+			TrackDisplay{"Beyoncé", "Don't Hurt Yourself"}},
+		{TrackRecord{Type: "ad", Track: Track{"", "Red Bull (Branding)"}},
+			TrackDisplay{"", "Red Bull (Branding)"}},
+	}
+
+	for i, test := range tests {
+		record := test.input
+		record.StationId = "fm4"
+		record.Timestamp = timestamp
+		if record.Type == "" {
+			record.Type = TypeTrack
+		}
+		if err := record.SanitizeWithRules(rules); err != nil ||
+			record.TrackDisplay != test.expected {
+			t.Errorf("#%d SanitizeWithRules(): got (%v, %v), expected (%v, nil)", i,
+				record.TrackDisplay, err, test.expected)
+		}
+	}
+}
+
+func TestCasings_Display(t *testing.T) {
+	track := Track{"ty dolla $ign", "or nah"}
+	casings := make(Casings)
+	casings.Add(track, TrackDisplay{"Ty Dolla $ign", "Or Nah"}, 2)
+	casings.Add(track, TrackDisplay{"TY DOLLA $IGN", ""}, 3)
+
+	tie := make(Casings)
+	tie.Add(Track{"mø", "final song"}, TrackDisplay{"Mø", "Final song"}, 1)
+	tie.Add(Track{"mø", "final song"}, TrackDisplay{"MØ", "Final Song"}, 1)
+
+	other := make(Casings)
+	other.Add(track, TrackDisplay{"Ty Dolla $ign", "Or Nah"}, 2)
+	other.Add(Track{"cardi b", "i like it"}, TrackDisplay{"Cardi B", "I Like It"}, 1)
+
+	var tests = []struct {
+		casings  Casings
+		track    Track
+		expected Track
+	}{
+		// artist and title are chosen independently, lowercase plays count for the lowercase form
+		{casings, track, Track{"TY DOLLA $IGN", "or nah"}},
+		{casings, Track{"mø", "final song"}, Track{"mø", "final song"}},
+		{tie, Track{"mø", "final song"}, Track{"MØ", "Final Song"}},
+		{Casings{}, track, track},
+	}
+
+	for i, test := range tests {
+		if result := test.casings.Display(test.track); result != test.expected {
+			t.Errorf("#%d Display(%v): got %v, expected %v", i, test.track, result, test.expected)
+		}
+	}
+
+	casings.Merge(other)
+	expected := []Track{{"Ty Dolla $ign", "Or Nah"}, {"Cardi B", "I Like It"}}
+	for i, track := range []Track{track, {"cardi b", "i like it"}} {
+		if result := casings.Display(track); result != expected[i] {
+			t.Errorf("Display(%v) after Merge(): got %v, expected %v", track, result, expected[i])
+		}
+	}
+}
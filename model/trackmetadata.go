@@ -0,0 +1,98 @@
+package model
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// TrackMetadata describes the recording aired by a track record; all fields are optional. Unlike
+// artist and title it is no part of a track's identity, since stations report the same recording
+// with varying albums or durations and plays have to be counted together.
+type TrackMetadata struct {
+	Album string `json:"album,omitempty" dynamodbav:"album,omitempty"`
+	// Duration is given in seconds.
+	Duration int    `json:"duration,omitempty" dynamodbav:"duration,omitempty"`
+	ISRC     string `json:"isrc,omitempty" dynamodbav:"isrc,omitempty"`
+	Label    string `json:"label,omitempty" dynamodbav:"label,omitempty"`
+	Year     int    `json:"year,omitempty" dynamodbav:"year,omitempty"`
+	CoverURL string `json:"cover_url,omitempty" dynamodbav:"cover_url,omitempty"`
+}
+
+// MaxDuration bounds the duration of a single item; longer durations are considered a crawler's
+// mistake, e. g. milliseconds reported as seconds.
+const MaxDuration = 2 * time.Hour
+
+// firstRecordingYear rejects release years before commercial recordings existed.
+const firstRecordingYear = 1890
+
+// ISRCs consist of the country code, the registrant code, the year of reference and the
+// designation code (ISO 3901).
+var isrcRegexp = regexp.MustCompile(`^[A-Z]{2}[A-Z0-9]{3}[0-9]{7}$`)
+
+// Sanitize cleans the metadata and clears the fields holding invalid values: the metadata is
+// optional, hence a crawler's mistake in one of them must not lose the play. The problems of the
+// cleared fields are returned. Album and label keep their reported casing.
+func (metadata *TrackMetadata) Sanitize() []error {
+	metadata.Album = sanitizeDisplayString(metadata.Album)
+	metadata.Label = sanitizeDisplayString(metadata.Label)
+
+	var problems []error
+	if metadata.Duration < 0 || time.Duration(metadata.Duration)*time.Second > MaxDuration {
+		problems = append(problems, fmt.Errorf("duration %d is out of bounds", metadata.Duration))
+		metadata.Duration = 0
+	}
+
+	// ISRCs are often written with hyphens, e. g. `US-RC1-76-07839`
+	metadata.ISRC = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(metadata.ISRC))
+	if metadata.ISRC != "" && !isrcRegexp.MatchString(metadata.ISRC) {
+		problems = append(problems, fmt.Errorf("isrc `%s` contains invalid format", metadata.ISRC))
+		metadata.ISRC = ""
+	}
+
+	if metadata.Year != 0 &&
+		(metadata.Year < firstRecordingYear || metadata.Year > time.Now().Year()+1) {
+		problems = append(problems, fmt.Errorf("year %d is out of bounds", metadata.Year))
+		metadata.Year = 0
+	}
+
+	coverURL, err := sanitizeURL(metadata.CoverURL)
+	if err != nil {
+		problems = append(problems, fmt.Errorf("cover_url `%s` contains invalid data",
+			metadata.CoverURL))
+		coverURL = ""
+	}
+	metadata.CoverURL = coverURL
+	return problems
+}
+
+// sanitizeDisplayString cleans a free text field like cleanString does, but keeps its casing.
+func sanitizeDisplayString(str string) string {
+	str = discardWhitespaces(normalizeUnicode(html.UnescapeString(str)))
+	return replaceURLs(str, "¯\\_(ツ)_/¯")
+}
+
+// fill sets the metadata's empty fields to the values of `other` and reports whether any field has
+// been set.
+func (metadata *TrackMetadata) fill(other TrackMetadata) bool {
+	filled := false
+	fillString := func(field *string, value string) {
+		if *field == "" && value != "" {
+			*field, filled = value, true
+		}
+	}
+	fillInt := func(field *int, value int) {
+		if *field == 0 && value != 0 {
+			*field, filled = value, true
+		}
+	}
+	fillString(&metadata.Album, other.Album)
+	fillInt(&metadata.Duration, other.Duration)
+	fillString(&metadata.ISRC, other.ISRC)
+	fillString(&metadata.Label, other.Label)
+	fillInt(&metadata.Year, other.Year)
+	fillString(&metadata.CoverURL, other.CoverURL)
+	return filled
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrackMetadata_Sanitize(t *testing.T) {
+	var tests = []struct {
+		input            TrackMetadata
+		expected         TrackMetadata
+		expectedProblems int
+	}{
+		{TrackMetadata{}, TrackMetadata{}, 0},
+		// album and label keep their casing
+		{
+			TrackMetadata{" Californication ", 321, "us-wb1-99-00690", "Warner  Bros. &amp; Co",
+				1999, " https://example.com/covers/californication.jpg"},
+			TrackMetadata{"Californication", 321, "USWB19900690", "Warner Bros. & Co", 1999,
+				"https://example.com/covers/californication.jpg"},
+			0,
+		},
+		// invalid fields are cleared, the others are kept
+		{TrackMetadata{Album: "Californication", Duration: -1},
+			TrackMetadata{Album: "Californication"}, 1},
+		{TrackMetadata{Duration: 321000}, TrackMetadata{}, 1},
+		{TrackMetadata{ISRC: "USWB1990069", Year: 1999}, TrackMetadata{Year: 1999}, 1},
+		{TrackMetadata{ISRC: "US-WB1-99-0069X"}, TrackMetadata{}, 1},
+		{TrackMetadata{Year: 99}, TrackMetadata{}, 1},
+		{TrackMetadata{Year: time.Now().Year() + 2}, TrackMetadata{}, 1},
+		{TrackMetadata{CoverURL: "ftp://example.com/cover.jpg"}, TrackMetadata{}, 1},
+		{TrackMetadata{"Californication", -1, "invalid", "Warner", 99, "ftp://example.com"},
+			TrackMetadata{Album: "Californication", Label: "Warner"}, 4},
+	}
+
+	for i, test := range tests {
+		metadata := test.input
+		problems := metadata.Sanitize()
+		if len(problems) != test.expectedProblems || metadata != test.expected {
+			t.Errorf("#%d Sanitize(): got (%+v, %v), expected (%+v, %d problems)", i, metadata,
+				problems, test.expected, test.expectedProblems)
+		}
+	}
+}
+
+func TestTrackRecord_Sanitize_InvalidMetadata(t *testing.T) {
+	record := TrackRecord{StationId: "fm4", Timestamp: timestamp, Type: "track",
+		Track:         Track{"RHCP", "Californication"},
+		TrackMetadata: TrackMetadata{Album: "Californication", Duration: 321000}}
+	expected := TrackMetadata{Album: "Californication"}
+
+	if err := record.Sanitize(); err != nil || record.TrackMetadata != expected {
+		t.Errorf("Sanitize(): got (%+v, %v), expected (%+v, nil)", record.TrackMetadata, err,
+			expected)
+	}
+}
@@ -3,48 +3,149 @@ package model
 import (
 	"errors"
 	"html"
+	"log"
 	"regexp"
 	"strings"
 	"time"
 )
 
+// Record types: tracks are music, the other types cover the items aired in between. Only tracks
+// count as plays; the other types serve analytics of a station's program.
+const (
+	TypeTrack  = "track"
+	TypeAd     = "ad"
+	TypeNews   = "news"
+	TypeJingle = "jingle"
+	TypeTalk   = "talk"
+)
+
+var RecordTypes = []string{TypeTrack, TypeAd, TypeNews, TypeJingle, TypeTalk}
+
+func IsRecordType(recordType string) bool {
+	for _, known := range RecordTypes {
+		if recordType == known {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseRecordTypes parses a comma-separated list of record types, e. g. `track,ad`.
+func ParseRecordTypes(str string) ([]string, error) {
+	types := make([]string, 0)
+	for _, recordType := range strings.Split(str, ",") {
+		recordType = cleanString(recordType)
+		if !IsRecordType(recordType) {
+			return nil, errors.New("type `" + recordType + "` is not supported")
+		}
+		duplicate := false
+		for _, parsed := range types {
+			duplicate = duplicate || parsed == recordType
+		}
+		if !duplicate {
+			types = append(types, recordType)
+		}
+	}
+	return types, nil
+}
+
 type TrackRecord struct {
 	StationId string `json:"stationId"`
 	Timestamp int64  `json:"airtime"`
 	Type      string `json:"type"`
 	Track
+	TrackMetadata
+	TrackDisplay
+	// PrincipalID identifies the crawler which reported the track record. It is persisted for
+	// auditing purposes only and never served.
+	PrincipalID string `json:"-" dynamodbav:"principalId,omitempty"`
+}
+
+// TrackRecordRules bound the airtime of accepted track records.
+type TrackRecordRules struct {
+	// FutureTolerance is how far track records may lie in the future, since some APIs also return
+	// the tracks of the near future -- and we won't trash them, right?
+	FutureTolerance time.Duration
+	// Earliest rejects track records aired before RadioChecker existed.
+	Earliest time.Time
+	// Normalizer merges the variants of tracks, it may be nil.
+	Normalizer *Normalizer
+}
+
+var DefaultTrackRecordRules = TrackRecordRules{
+	FutureTolerance: 30 * time.Minute,
+	Earliest:        time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC),
 }
 
 func (record *TrackRecord) Sanitize() error {
+	return record.SanitizeWithRules(DefaultTrackRecordRules)
+}
+
+func (record *TrackRecord) SanitizeWithRules(rules TrackRecordRules) error {
 	if err := record.sanitizeStationId(); err != nil {
 		return err
 	}
-	if err := record.sanitizeTimestamp(); err != nil {
+	if err := record.sanitizeTimestamp(rules); err != nil {
 		return err
 	}
 	if err := record.sanitizeType(); err != nil {
 		return err
 	}
-	return record.Track.Sanitize()
+	// stored track records are sanitized again after the rules changed, their display form is
+	// all that is left of the reported casing
+	reported := record.Track
+	if record.DisplayArtist != "" {
+		reported.Artist = record.DisplayArtist
+	}
+	if record.DisplayTitle != "" {
+		reported.Title = record.DisplayTitle
+	}
+	if err := record.sanitizeContent(); err != nil {
+		return err
+	}
+	if record.Type == TypeTrack {
+		rules.Normalizer.Normalize(record.StationId, &record.Track)
+	}
+	record.sanitizeDisplay(reported.Artist, reported.Title)
+	for _, problem := range record.TrackMetadata.Sanitize() {
+		log.Printf("WARNING: cleared metadata of track record %s/%d: %v", record.StationId,
+			record.Timestamp, problem)
+	}
+	return nil
+}
+
+// Merge completes the track record by the metadata and display form of a duplicate reporting the
+// same play: fields the track record lacks are taken from the duplicate, fields it holds are kept.
+// It reports whether the track record has changed.
+func (record *TrackRecord) Merge(duplicate TrackRecord) bool {
+	filledMetadata := record.TrackMetadata.fill(duplicate.TrackMetadata)
+	filledDisplay := record.TrackDisplay.fill(duplicate.TrackDisplay)
+	return filledMetadata || filledDisplay
 }
 
 func (record *TrackRecord) sanitizeStationId() error {
-	record.StationId = cleanString(record.StationId)
+	stationId, err := sanitizeStationId(record.StationId)
+	record.StationId = stationId
+	return err
+}
+
+// sanitizeStationId cleans the provided station ID and validates its format. Station IDs are
+// shared by track records and stations, hence both rely on this function.
+func sanitizeStationId(stationId string) (string, error) {
+	stationId = cleanString(stationId)
 	r := regexp.MustCompile(`^[a-z][a-z0-9-]+$`)
-	if !r.MatchString(record.StationId) {
-		return errors.New("stationId contains invalid format")
+	if !r.MatchString(stationId) {
+		return stationId, errors.New("stationId contains invalid format")
 	}
-	return nil
+	return stationId, nil
 }
 
-func (record *TrackRecord) sanitizeTimestamp() error {
+func (record *TrackRecord) sanitizeTimestamp(rules TrackRecordRules) error {
 	airtime := time.Unix(record.Timestamp, 0)
-	// tracks are allowed to lie max. 30min in the future, since some APIs also return the tracks of
-	// the near future -- and we won't trash them, right?
-	if airtime.After(time.Now().Add(30 * time.Minute)) {
+	if airtime.After(time.Now().Add(rules.FutureTolerance)) {
 		return errors.New("timestamp lies in the future")
 	}
-	if airtime.Before(time.Date(2016, 1, 1, 0, 0, 0, 0, airtime.Location())) {
+	if airtime.Before(rules.Earliest) {
 		return errors.New("timestamp is older than RadioChecker")
 	}
 	return nil
@@ -52,8 +153,24 @@ func (record *TrackRecord) sanitizeTimestamp() error {
 
 func (record *TrackRecord) sanitizeType() error {
 	record.Type = cleanString(record.Type)
-	if record.Type != "track" {
-		return errors.New("type is not `track`")
+	if !IsRecordType(record.Type) {
+		return errors.New("type is not supported")
+	}
+	return nil
+}
+
+// sanitizeContent validates the artist and title according to the record's type: tracks require
+// both, ads require a title naming the advertiser or spot. News, jingles and talk may come
+// without any, since many stations don't publish details about them.
+func (record *TrackRecord) sanitizeContent() error {
+	if record.Type == TypeTrack {
+		return record.Track.Sanitize()
+	}
+
+	record.Artist = replaceURLs(cleanString(record.Artist), "¯\\_(ツ)_/¯")
+	record.Title = replaceURLs(cleanString(record.Title), "¯\\_(ツ)_/¯")
+	if record.Type == TypeAd && record.Title == "" {
+		return errors.New("title of ad contains invalid data")
 	}
 	return nil
 }
@@ -61,6 +178,8 @@ func (record *TrackRecord) sanitizeType() error {
 func cleanString(str string) string {
 	str = strings.ToLower(str)     // html.UnescapeString() requires lowercase input
 	str = html.UnescapeString(str) // unescapes HTML special characters to their original form
+	// compatibility forms may decompose to uppercase letters, e. g. `ℌ` => `H`
+	str = strings.ToLower(normalizeUnicode(str))
 	str = discardWhitespaces(str)
 	return str
 }
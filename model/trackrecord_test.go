@@ -17,30 +17,42 @@ var timestampFutureValid = time.Now().Add(29 * time.Minute).Unix()
 var testsTrackRecordSuccess = []testTrackRecordSuccess{
 	// stationId
 	{
-		&TrackRecord{"&nbsp;station-a", timestamp, "track", Track{"RHCP", "Californication"}},
-		&TrackRecord{"station-a", timestamp, "track", Track{"rhcp", "californication"}},
+		&TrackRecord{StationId: "&nbsp;station-a", Timestamp: timestamp, Type: "track", Track: Track{"RHCP", "Californication"}},
+		&TrackRecord{StationId: "station-a", Timestamp: timestamp, Type: "track", Track: Track{"rhcp", "californication"}, TrackDisplay: TrackDisplay{"RHCP", "Californication"}},
 	},
 	{
-		&TrackRecord{"AB", timestamp, "track", Track{"Felix Jaehn Feat. Jasmin Thompson", "Ain't Nobody (Loves Me Better)"}},
-		&TrackRecord{"ab", timestamp, "track", Track{"felix jaehn feat. jasmin thompson", "ain't nobody (loves me better)"}},
+		&TrackRecord{StationId: "AB", Timestamp: timestamp, Type: "track", Track: Track{"Felix Jaehn Feat. Jasmin Thompson", "Ain't Nobody (Loves Me Better)"}},
+		&TrackRecord{StationId: "ab", Timestamp: timestamp, Type: "track", Track: Track{"felix jaehn feat. jasmin thompson", "ain't nobody (loves me better)"}, TrackDisplay: TrackDisplay{"Felix Jaehn Feat. Jasmin Thompson", "Ain't Nobody (Loves Me Better)"}},
 	},
 	{
-		&TrackRecord{"hitradio-oe3", timestamp, "track", Track{"Axwell /\\ Ingrosso", "+++ The Shit +++"}},
-		&TrackRecord{"hitradio-oe3", timestamp, "track", Track{"axwell /\\ ingrosso", "+++ the shit +++"}},
+		&TrackRecord{StationId: "hitradio-oe3", Timestamp: timestamp, Type: "track", Track: Track{"Axwell /\\ Ingrosso", "+++ The Shit +++"}},
+		&TrackRecord{StationId: "hitradio-oe3", Timestamp: timestamp, Type: "track", Track: Track{"axwell /\\ ingrosso", "+++ the shit +++"}, TrackDisplay: TrackDisplay{"Axwell /\\ Ingrosso", "+++ The Shit +++"}},
 	},
 	{
-		&TrackRecord{"station24", timestamp, "TRACK", Track{"RHCP", "Californication"}},
-		&TrackRecord{"station24", timestamp, "track", Track{"rhcp", "californication"}},
+		&TrackRecord{StationId: "station24", Timestamp: timestamp, Type: "TRACK", Track: Track{"RHCP", "Californication"}},
+		&TrackRecord{StationId: "station24", Timestamp: timestamp, Type: "track", Track: Track{"rhcp", "californication"}, TrackDisplay: TrackDisplay{"RHCP", "Californication"}},
 	},
 	// timestamp
 	{
-		&TrackRecord{"hitradio-oe3", timestampFutureValid, "track", Track{"DOLLAR $IGN", "MØNE¥"}},
-		&TrackRecord{"hitradio-oe3", timestampFutureValid, "track", Track{"dollar $ign", "møne¥"}},
+		&TrackRecord{StationId: "hitradio-oe3", Timestamp: timestampFutureValid, Type: "track", Track: Track{"DOLLAR $IGN", "MØNE¥"}},
+		&TrackRecord{StationId: "hitradio-oe3", Timestamp: timestampFutureValid, Type: "track", Track: Track{"dollar $ign", "møne¥"}, TrackDisplay: TrackDisplay{"DOLLAR $IGN", "MØNE¥"}},
 	},
 	// type
 	{
-		&TrackRecord{"station-a", timestamp, "TRACK", Track{"Nico &amp; Vinz feat. Kid Ink &amp; Bebe Rexha", "That's How You Know"}},
-		&TrackRecord{"station-a", timestamp, "track", Track{"nico & vinz feat. kid ink & bebe rexha", "that's how you know"}},
+		&TrackRecord{StationId: "station-a", Timestamp: timestamp, Type: "TRACK", Track: Track{"Nico &amp; Vinz feat. Kid Ink &amp; Bebe Rexha", "That's How You Know"}},
+		&TrackRecord{StationId: "station-a", Timestamp: timestamp, Type: "track", Track: Track{"nico & vinz feat. kid ink & bebe rexha", "that's how you know"}, TrackDisplay: TrackDisplay{"Nico & Vinz feat. Kid Ink & Bebe Rexha", "That's How You Know"}},
+	},
+	{
+		&TrackRecord{StationId: "station-a", Timestamp: timestamp, Type: "Ad", Track: Track{"", "Red Bull (Branding)"}},
+		&TrackRecord{StationId: "station-a", Timestamp: timestamp, Type: "ad", Track: Track{"", "red bull (branding)"}, TrackDisplay: TrackDisplay{"", "Red Bull (Branding)"}},
+	},
+	{
+		&TrackRecord{StationId: "station-a", Timestamp: timestamp, Type: "news", Track: Track{" ", ""}},
+		&TrackRecord{StationId: "station-a", Timestamp: timestamp, Type: "news", Track: Track{"", ""}},
+	},
+	{
+		&TrackRecord{StationId: "station-a", Timestamp: timestamp, Type: "talk", Track: Track{"Stermann &amp; Grissemann", "Salon Helga"}},
+		&TrackRecord{StationId: "station-a", Timestamp: timestamp, Type: "talk", Track: Track{"stermann & grissemann", "salon helga"}, TrackDisplay: TrackDisplay{"Stermann & Grissemann", "Salon Helga"}},
 	},
 }
 
@@ -61,20 +73,23 @@ func TestTrackRecord_Sanitize_Success(t *testing.T) {
 
 var testsTrackRecordErr = []TrackRecord{
 	// stationId
-	{"station%20a", timestamp, "TRACK", Track{"RHCP", "Californication"}},
-	{"A", timestamp, "TRACK", Track{"RHCP", "Californication"}},
-	{"", timestamp, "TRACK", Track{"RHCP", "Californication"}},
+	{StationId: "station%20a", Timestamp: timestamp, Type: "TRACK", Track: Track{"RHCP", "Californication"}},
+	{StationId: "A", Timestamp: timestamp, Type: "TRACK", Track: Track{"RHCP", "Californication"}},
+	{StationId: "", Timestamp: timestamp, Type: "TRACK", Track: Track{"RHCP", "Californication"}},
 	// timestamp
-	{"station-a", time.Now().Add(time.Hour).Unix(), "TRACK", Track{"RHCP", "Californication"}},
-	{"station-a", time.Now().Add(31 * time.Minute).Unix(), "TRACK", Track{"RHCP", "Californication"}},
-	{"station-a", time.Now().AddDate(-10, 0, 0).Unix(), "TRACK", Track{"RHCP", "Californication"}},
-	{"station-a", 5432955, "TRACK", Track{"RHCP", "Californication"}},
+	{StationId: "station-a", Timestamp: time.Now().Add(time.Hour).Unix(), Type: "TRACK", Track: Track{"RHCP", "Californication"}},
+	{StationId: "station-a", Timestamp: time.Now().Add(31 * time.Minute).Unix(), Type: "TRACK", Track: Track{"RHCP", "Californication"}},
+	{StationId: "station-a", Timestamp: time.Now().AddDate(-10, 0, 0).Unix(), Type: "TRACK", Track: Track{"RHCP", "Californication"}},
+	{StationId: "station-a", Timestamp: 5432955, Type: "TRACK", Track: Track{"RHCP", "Californication"}},
 	// type
-	{"station-a", timestamp, "", Track{"RHCP", "Californication"}},
-	{"station-a", timestamp, "song", Track{"RHCP", "Californication"}},
-	{"station-a", timestamp, "so--ng", Track{"RHCP", "Californication"}},
+	{StationId: "station-a", Timestamp: timestamp, Type: "", Track: Track{"RHCP", "Californication"}},
+	{StationId: "station-a", Timestamp: timestamp, Type: "song", Track: Track{"RHCP", "Californication"}},
+	{StationId: "station-a", Timestamp: timestamp, Type: "so--ng", Track: Track{"RHCP", "Californication"}},
+	{StationId: "station-a", Timestamp: timestamp, Type: "commercial", Track: Track{"", "Red Bull"}},
 	// track
-	{"station", timestamp, "track", Track{" ", ""}},
+	{StationId: "station", Timestamp: timestamp, Type: "track", Track: Track{" ", ""}},
+	// ad
+	{StationId: "station", Timestamp: timestamp, Type: "ad", Track: Track{"Red Bull", " "}},
 }
 
 func TestTrackRecord_Sanitize_Err(t *testing.T) {
@@ -85,3 +100,95 @@ func TestTrackRecord_Sanitize_Err(t *testing.T) {
 		}
 	}
 }
+
+func TestTrackRecord_SanitizeWithRules(t *testing.T) {
+	rules := TrackRecordRules{
+		FutureTolerance: time.Hour,
+		Earliest:        time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	var tests = []struct {
+		timestamp   int64
+		expectedErr bool
+	}{
+		{timestamp, false},
+		{time.Now().Add(45 * time.Minute).Unix(), false},
+		{time.Now().Add(61 * time.Minute).Unix(), true},
+		{time.Date(2017, 12, 31, 23, 59, 59, 0, time.UTC).Unix(), true},
+	}
+
+	for i, test := range tests {
+		record := TrackRecord{StationId: "station-a", Timestamp: test.timestamp, Type: "track",
+			Track: Track{"RHCP", "Californication"}}
+		if err := record.SanitizeWithRules(rules); (err != nil) != test.expectedErr {
+			t.Errorf("#%d SanitizeWithRules(): got err (%v), expected err: %v", i, err,
+				test.expectedErr)
+		}
+	}
+
+	rules.Normalizer, _ = NewNormalizer(testNormalizationRules)
+	record := TrackRecord{StationId: "station-a", Timestamp: timestamp, Type: "track",
+		Track: Track{"RHCP", "Californication (Radio Edit)"}}
+	expected := Track{"red hot chili peppers", "californication"}
+	if err := record.SanitizeWithRules(rules); err != nil || record.Track != expected {
+		t.Errorf("SanitizeWithRules() with normalizer: got (%v, %v), expected (%v, nil)",
+			record.Track, err, expected)
+	}
+}
+
+func TestTrackRecord_Merge(t *testing.T) {
+	recorded := TrackRecord{StationId: "fm4", Timestamp: timestamp, Type: "track",
+		Track:         Track{"rhcp", "californication"},
+		TrackMetadata: TrackMetadata{Album: "Californication", Year: 1999},
+		TrackDisplay:  TrackDisplay{DisplayArtist: "RHCP"}}
+
+	var tests = []struct {
+		duplicate       TrackRecord
+		expected        TrackRecord
+		expectedChanged bool
+	}{
+		// fields the track record holds are kept
+		{TrackRecord{TrackMetadata: TrackMetadata{Album: "Greatest Hits", Year: 2003},
+			TrackDisplay: TrackDisplay{DisplayArtist: "Red Hot Chili Peppers"}}, recorded, false},
+		{TrackRecord{}, recorded, false},
+		{TrackRecord{TrackMetadata: TrackMetadata{Album: "Greatest Hits", Duration: 321,
+			ISRC: "USWB19900690", Label: "Warner", CoverURL: "https://covers.example.com/1.jpg"},
+			TrackDisplay: TrackDisplay{DisplayTitle: "Californication"}},
+			TrackRecord{StationId: "fm4", Timestamp: timestamp, Type: "track",
+				Track: Track{"rhcp", "californication"},
+				TrackMetadata: TrackMetadata{Album: "Californication", Duration: 321,
+					ISRC: "USWB19900690", Label: "Warner", Year: 1999,
+					CoverURL: "https://covers.example.com/1.jpg"},
+				TrackDisplay: TrackDisplay{"RHCP", "Californication"}}, true},
+	}
+
+	for i, test := range tests {
+		record := recorded
+		changed := record.Merge(test.duplicate)
+		if record != test.expected || changed != test.expectedChanged {
+			t.Errorf("#%d Merge(%v): got (%v, %v), expected (%v, %v)", i, test.duplicate, record,
+				changed, test.expected, test.expectedChanged)
+		}
+	}
+}
+
+func TestParseRecordTypes(t *testing.T) {
+	var tests = []struct {
+		input       string
+		expected    []string
+		expectedErr bool
+	}{
+		{"track", []string{"track"}, false},
+		{"News, AD,news", []string{"news", "ad"}, false},
+		{"track,song", nil, true},
+		{"", nil, true},
+	}
+
+	for _, test := range tests {
+		result, err := ParseRecordTypes(test.input)
+		if (err != nil) != test.expectedErr || !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("ParseRecordTypes(%q): got (%v, %v), expected (%v, err: %v)", test.input,
+				result, err, test.expected, test.expectedErr)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"golang.org/x/text/unicode/norm"
+	"strings"
+	"unicode"
+)
+
+// punctuationReplacer unifies the typographic variants of quotes, apostrophes and dashes, e. g.
+// `don’t` => `don't`. Primes and the acute accent are replaced before the NFKC normalization
+// would decompose them.
+var punctuationReplacer = strings.NewReplacer(
+	"‘", "'", "’", "'", "‚", "'", "‛", "'", "′", "'", "‵", "'", "´", "'", "ʼ", "'",
+	"“", `"`, "”", `"`, "„", `"`, "‟", `"`, "″", `"`, "‶", `"`, "«", `"`, "»", `"`,
+	"‐", "-", "‑", "-", "‒", "-", "–", "-", "—", "-", "―", "-", "−", "-",
+)
+
+// foldReplacer maps the letters without a canonical decomposition to their base letters.
+var foldReplacer = strings.NewReplacer(
+	"ø", "o", "đ", "d", "ł", "l", "ħ", "h", "ı", "i", "ß", "ss", "æ", "ae", "œ", "oe",
+)
+
+// normalizeUnicode removes invisible characters, unifies typographic punctuation and applies the
+// NFKC normalization, so that e. g. full-width letters or ligatures turn into their common form.
+// Control characters other than whitespaces are removed; whitespaces are left to
+// discardWhitespaces.
+func normalizeUnicode(str string) string {
+	str = strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Cf, r) || (unicode.IsControl(r) && !unicode.IsSpace(r)) {
+			return -1
+		}
+		return r
+	}, str)
+	return norm.NFKC.String(punctuationReplacer.Replace(str))
+}
+
+// foldDiacritics strips the diacritics of a lowercase string, e. g. `beyoncé` => `beyonce`.
+func foldDiacritics(str string) string {
+	str = strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return r
+	}, norm.NFD.String(str))
+	return norm.NFC.String(foldReplacer.Replace(str))
+}
+
+// FoldKey returns the sanitized, lowercase form of `str` without diacritics. Strings which only
+// differ by their accents share the same key, e. g. `Beyoncé` and `beyonce`.
+func FoldKey(str string) string {
+	return foldDiacritics(cleanString(str))
+}
+
+// FoldedKey returns the track keyed by the folded artist and title, see FoldKey.
+func (track Track) FoldedKey() Track {
+	return Track{FoldKey(track.Artist), FoldKey(track.Title)}
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestCleanString_Unicode(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		// NFC and NFKC
+		{"Beyonce\u0301", "beyoncé"},
+		{"Ｋｉｄ　Ｉｎｋ", "kid ink"},
+		{"ﬁnal song", "final song"},
+		{"ℌello", "hello"},
+		// zero-width and control characters
+		{"kid\u200b ink\ufeff", "kid ink"},
+		{"ed\u00adsheeran", "edsheeran"},
+		{"rhcp\x00\x1b", "rhcp"},
+		{"dani\tcalifornia\n", "dani california"},
+		// typographic punctuation
+		{"Don’t Stop ‘Til You Get Enough", "don't stop 'til you get enough"},
+		{"don&rsquo;t", "don't"},
+		{"„Atemlos“ durch die Nacht", `"atemlos" durch die nacht`},
+		{"Jay‐Z – Empire State Of Mind — Live", "jay-z - empire state of mind - live"},
+		{"MØ", "mø"},
+	}
+
+	for _, test := range tests {
+		if result := cleanString(test.input); result != test.expected {
+			t.Errorf("cleanString(%q): got %q, expected %q", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestFoldKey(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"Beyoncé", "beyonce"},
+		{"Beyonce\u0301", "beyonce"},
+		{"Déjà Vu", "deja vu"},
+		{"Sigur Rós", "sigur ros"},
+		{"MØ", "mo"},
+		{"Die Ärzte", "die arzte"},
+		{"Straße", "strasse"},
+		{"Ｍｏｔöｒｈｅａｄ", "motorhead"},
+		{"Tiësto", "tiesto"},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		if result := FoldKey(test.input); result != test.expected {
+			t.Errorf("FoldKey(%q): got %q, expected %q", test.input, result, test.expected)
+		}
+	}
+
+	track := Track{"beyoncé", "déjà vu"}
+	if result := track.FoldedKey(); result != (Track{"beyonce", "deja vu"}) {
+		t.Errorf("%v.FoldedKey(): got %v, expected %v", track, result, Track{"beyonce", "deja vu"})
+	}
+}
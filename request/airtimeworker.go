@@ -0,0 +1,56 @@
+package request
+
+import (
+	"errors"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"time"
+)
+
+// AirtimeWorker estimates how the airtime of a station's day is shared by music and the other
+// record types, hour by hour.
+type AirtimeWorker struct {
+	dao     datalayer.TrackRecordDAO
+	station string
+	date    time.Time
+}
+
+func NewAirtimeWorker(dao datalayer.TrackRecordDAO, station string,
+	date time.Time) (AirtimeWorker, error) {
+	if dao == nil {
+		return AirtimeWorker{}, errors.New("dao must not be nil")
+	}
+	if station == "" {
+		return AirtimeWorker{}, errors.New("station must not be empty")
+	}
+	return AirtimeWorker{dao, station, date}, nil
+}
+
+func (worker AirtimeWorker) HandleRequest() (interface{}, error) {
+	startDate, endDate := worker.Period()
+	// the record aired last before midnight lasts into the day
+	trackRecords, err := worker.dao.GetRecordsByStation(worker.station, model.RecordTypes,
+		startDate.Add(-model.MaxItemDuration), endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	airtime := model.Airtime{
+		Station: worker.station,
+		Date:    startDate.Format("2006-01-02"),
+		Hours:   model.CalculateAirtime(trackRecords, startDate, endDate),
+	}
+	if len(trackRecords) > 0 {
+		airtime.LastModified = time.Unix(trackRecords[len(trackRecords)-1].Timestamp, 0)
+	}
+	return airtime, nil
+}
+
+func (worker AirtimeWorker) Period() (time.Time, time.Time) {
+	return calculateDayBoundaries(worker.date)
+}
+
+func (worker AirtimeWorker) CacheKey() string {
+	startDate, _ := worker.Period()
+	return cacheKey("airtime", worker.station, formatCacheKeyDate(startDate))
+}
@@ -0,0 +1,55 @@
+package request
+
+import (
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"testing"
+	"time"
+)
+
+func TestNewAirtimeWorker(t *testing.T) {
+	date := time.Date(2018, 9, 23, 0, 0, 0, 0, time.UTC)
+	if _, err := NewAirtimeWorker(nil, "fm4", date); err == nil {
+		t.Error("NewAirtimeWorker(nil): got no error, expected error")
+	}
+	if _, err := NewAirtimeWorker(MockTrackRecordDAO{}, "", date); err == nil {
+		t.Error("NewAirtimeWorker(\"\"): got no error, expected error")
+	}
+}
+
+func TestAirtimeWorker_HandleRequest(t *testing.T) {
+	location, _ := time.LoadLocation("Europe/Vienna")
+	date := time.Date(2018, 9, 23, 0, 0, 0, 0, location)
+	dao := datalayer.NewMemoryTrackRecordDAO(
+		// aired the day before, lasts into the first hour
+		model.TrackRecord{StationId: "fm4", Timestamp: date.Add(-10 * time.Minute).Unix(),
+			Type: "track", Track: model.Track{"rhcp", "californication"}},
+		model.TrackRecord{StationId: "fm4", Timestamp: date.Add(2 * time.Minute).Unix(),
+			Type: "news"},
+		model.TrackRecord{StationId: "fm4", Timestamp: date.Add(6 * time.Minute).Unix(),
+			Type: "track", Track: model.Track{"cardi b", "i like it"}},
+		model.TrackRecord{StationId: "oe3", Timestamp: date.Add(time.Minute).Unix(), Type: "ad",
+			Track: model.Track{"", "red bull"}},
+	)
+
+	worker, _ := NewAirtimeWorker(dao, "fm4", date.Add(15*time.Hour))
+	result, err := worker.HandleRequest()
+	if err != nil {
+		t.Fatalf("HandleRequest(): got err (%v), expected nil", err)
+	}
+	airtime := result.(model.Airtime)
+	if airtime.Date != "2018-09-23" || len(airtime.Hours) != 24 ||
+		!airtime.LastModified.Equal(date.Add(6*time.Minute)) {
+		t.Fatalf("HandleRequest(): got %+v, expected 24 hours of 2018-09-23", airtime)
+	}
+	first := airtime.Hours[0]
+	if first.Seconds["track"] != 17*60 || first.Seconds["news"] != 4*60 ||
+		first.Seconds["ad"] != 0 || first.MusicShare != 0.81 {
+		t.Errorf("HandleRequest(): got first hour %+v, expected 17 minutes of music and 4 minutes "+
+			"of news", first)
+	}
+
+	if key := worker.CacheKey(); key != "airtime/fm4/2018-09-23T00:00:00+02:00" {
+		t.Errorf("CacheKey(): got %q, expected airtime/fm4/2018-09-23T00:00:00+02:00", key)
+	}
+}
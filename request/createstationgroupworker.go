@@ -0,0 +1,33 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+)
+
+type CreateStationGroupWorker struct {
+	dao   datalayer.StationGroupDAO
+	group model.StationGroup
+}
+
+func NewCreateStationGroupWorker(dao datalayer.StationGroupDAO,
+	group model.StationGroup) (CreateStationGroupWorker, error) {
+	if dao == nil {
+		return CreateStationGroupWorker{}, errors.New("dao must not be nil")
+	}
+	return CreateStationGroupWorker{dao, group}, nil
+}
+
+func (worker CreateStationGroupWorker) HandleRequest() (interface{}, error) {
+	if err := worker.group.Sanitize(); err != nil {
+		return nil, NewValidationError(err)
+	}
+
+	if err := worker.dao.Create(worker.group); err != nil {
+		return nil, err
+	}
+
+	return fmt.Sprintf("group created: /groups/%s", worker.group.ID), nil
+}
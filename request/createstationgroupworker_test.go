@@ -0,0 +1,89 @@
+package request
+
+import (
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"reflect"
+	"testing"
+)
+
+func TestNewCreateStationGroupWorker(t *testing.T) {
+	group := model.StationGroup{"austria", "Austria", "", []string{"kronehit"}}
+
+	var tests = []struct {
+		dao         datalayer.StationGroupDAO
+		expectedErr bool
+	}{
+		{MockStationGroupDAOSuccess{}, false},
+		{nil, true},
+	}
+
+	for _, test := range tests {
+		result, err := NewCreateStationGroupWorker(test.dao, group)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("NewCreateStationGroupWorker(%v, %v): got err (%v), expected err: %v",
+				test.dao, group, err, test.expectedErr)
+			continue
+		}
+		expectedResult := CreateStationGroupWorker{test.dao, group}
+		if err == nil && !reflect.DeepEqual(result, expectedResult) {
+			t.Errorf("NewCreateStationGroupWorker(%v, %v): got result (%v), expected (%v)",
+				test.dao, group, result, expectedResult)
+		}
+	}
+}
+
+func TestCreateStationGroupWorker_HandleRequest(t *testing.T) {
+	var tests = []struct {
+		worker         CreateStationGroupWorker
+		expectedResult string
+		expectedErr    bool
+	}{
+		// success
+		{
+			CreateStationGroupWorker{MockStationGroupDAOSuccess{},
+				model.StationGroup{"ORF", " ORF ", "", []string{"orf-wien"}}},
+			"group created: /groups/orf",
+			false,
+		},
+		// group exists already
+		{
+			CreateStationGroupWorker{MockStationGroupDAOSuccess{},
+				model.StationGroup{"austria", "Austria", "", []string{"kronehit"}}},
+			"ignored",
+			true,
+		},
+		// no member stations
+		{
+			CreateStationGroupWorker{MockStationGroupDAOSuccess{},
+				model.StationGroup{"austria", "Austria", "", []string{}}},
+			"ignored",
+			true,
+		},
+		// database error
+		{
+			CreateStationGroupWorker{MockStationGroupDAOFail{},
+				model.StationGroup{"austria", "Austria", "", []string{"kronehit"}}},
+			"ignored",
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		result, err := test.worker.HandleRequest()
+		if (err != nil) != test.expectedErr {
+			t.Errorf("(%v).HandleRequest(): got err (%v), expected err: %v",
+				test.worker, err, test.expectedErr)
+			continue
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if result != test.expectedResult {
+			t.Errorf("(%v).HandleRequest(): got result (%v), expected (%v)",
+				test.worker, result, test.expectedResult)
+		}
+	}
+}
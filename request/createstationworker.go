@@ -0,0 +1,36 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+)
+
+type CreateStationWorker struct {
+	dao     datalayer.StationDAO
+	station model.Station
+}
+
+func NewCreateStationWorker(dao datalayer.StationDAO, station model.Station) (CreateStationWorker,
+	error) {
+	if dao == nil {
+		return CreateStationWorker{}, errors.New("dao must not be nil")
+	}
+	return CreateStationWorker{dao, station}, nil
+}
+
+func (worker CreateStationWorker) HandleRequest() (interface{}, error) {
+	if err := worker.station.Sanitize(); err != nil {
+		return nil, NewValidationError(err)
+	}
+
+	// newly created stations immediately accept tracks
+	worker.station.Active = true
+
+	if err := worker.dao.Create(worker.station); err != nil {
+		return nil, err
+	}
+
+	return fmt.Sprintf("station created: /stations/%s", worker.station.ID), nil
+}
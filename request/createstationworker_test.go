@@ -0,0 +1,90 @@
+package request
+
+import (
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"reflect"
+	"testing"
+)
+
+func TestNewCreateStationWorker(t *testing.T) {
+	var tests = []struct {
+		dao         datalayer.StationDAO
+		station     model.Station
+		expectedErr bool
+	}{
+		{MockStationDAOSuccess{}, model.Station{ID: "fm4", Name: "FM4"}, false},
+		{nil, model.Station{ID: "fm4", Name: "FM4"}, true},
+	}
+
+	for _, test := range tests {
+		result, err := NewCreateStationWorker(test.dao, test.station)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("NewCreateStationWorker(%v, %v): got err (%v), expected err: %v",
+				test.dao, test.station, err, test.expectedErr)
+			continue
+		}
+		expectedResult := CreateStationWorker{test.dao, test.station}
+		if err == nil && !reflect.DeepEqual(result, expectedResult) {
+			t.Errorf("NewCreateStationWorker(%v, %v): got result (%v), expected (%v)",
+				test.dao, test.station, result, expectedResult)
+		}
+	}
+}
+
+func TestCreateStationWorker_HandleRequest(t *testing.T) {
+	var tests = []struct {
+		worker         CreateStationWorker
+		expectedResult string
+		expectedErr    bool
+	}{
+		// success
+		{
+			CreateStationWorker{MockStationDAOSuccess{}, model.Station{ID: "FM4", Name: " FM4 "}},
+			"station created: /stations/fm4",
+			false,
+		},
+		// station exists already
+		{
+			CreateStationWorker{MockStationDAOSuccess{}, model.Station{ID: "kronehit", Name: "Kronehit"}},
+			"ignored",
+			true,
+		},
+		// invalid station ID
+		{
+			CreateStationWorker{MockStationDAOSuccess{}, model.Station{ID: "fm 4", Name: "FM4"}},
+			"ignored",
+			true,
+		},
+		// missing name
+		{
+			CreateStationWorker{MockStationDAOSuccess{}, model.Station{ID: "fm4", Name: ""}},
+			"ignored",
+			true,
+		},
+		// database error
+		{
+			CreateStationWorker{MockStationDAOFail{}, model.Station{ID: "fm4", Name: "FM4"}},
+			"ignored",
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		result, err := test.worker.HandleRequest()
+		if (err != nil) != test.expectedErr {
+			t.Errorf("(%v).HandleRequest(): got err (%v), expected err: %v",
+				test.worker, err, test.expectedErr)
+			continue
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if result != test.expectedResult {
+			t.Errorf("(%v).HandleRequest(): got result (%v), expected (%v)",
+				test.worker, result, test.expectedResult)
+		}
+	}
+}
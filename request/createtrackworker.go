@@ -5,48 +5,112 @@ import (
 	"fmt"
 	"github.com/RadioCheckerApp/api/datalayer"
 	"github.com/RadioCheckerApp/api/model"
+	"log"
+	"time"
 )
 
-var stationsCache = make(map[string]bool)
-
 type CreateTrackWorker struct {
 	trackRecordDAO datalayer.TrackRecordDAO
-	stationsDAO    datalayer.StationDAO
+	stations       *StationCache
+	rollups        *Rollups
 	trackRecord    model.TrackRecord
+	rules          model.TrackRecordRules
+	duplicates     DuplicatePolicy
+}
+
+// DuplicateError rejects a track record reporting a play which has been recorded already, see
+// DuplicatePolicy.
+type DuplicateError struct {
+	Original model.TrackRecord
+}
+
+func (err DuplicateError) Error() string {
+	return fmt.Sprintf("duplicate: play has been recorded already as /stations/%s/tracks/%d",
+		err.Original.StationId, err.Original.Timestamp)
 }
 
-func NewCreateTrackWorker(trDAO datalayer.TrackRecordDAO, sDAO datalayer.StationDAO,
-	trackRecord model.TrackRecord) (CreateTrackWorker, error) {
-	if trDAO == nil || sDAO == nil {
-		return CreateTrackWorker{}, errors.New("daos must not be nil")
+// NewCreateTrackWorker creates the worker persisting a track record. Rollups may be nil if no
+// rollups are maintained.
+func NewCreateTrackWorker(trDAO datalayer.TrackRecordDAO, stations *StationCache,
+	rollups *Rollups, trackRecord model.TrackRecord, rules model.TrackRecordRules,
+	duplicates DuplicatePolicy) (CreateTrackWorker, error) {
+	if trDAO == nil {
+		return CreateTrackWorker{}, errors.New("dao must not be nil")
 	}
-	return CreateTrackWorker{trDAO, sDAO, trackRecord}, nil
+	if stations == nil {
+		return CreateTrackWorker{}, errors.New("station cache must not be nil")
+	}
+	return CreateTrackWorker{trDAO, stations, rollups, trackRecord, rules, duplicates}, nil
 }
 
 func (worker CreateTrackWorker) HandleRequest() (interface{}, error) {
-	if err := worker.trackRecord.Sanitize(); err != nil {
-		return nil, err
+	if err := worker.trackRecord.SanitizeWithRules(worker.rules); err != nil {
+		return nil, NewValidationError(err)
 	}
 
-	if len(stationsCache) == 0 {
-		worker.populateStationsCache()
+	if err := worker.stations.CheckActive(worker.trackRecord.StationId); err != nil {
+		return nil, err
 	}
 
-	if !stationsCache[worker.trackRecord.StationId] {
-		return nil, errors.New("invalid stationId provided")
+	original, err := worker.findDuplicate()
+	if err != nil {
+		return nil, err
+	}
+	if original != nil {
+		if !worker.duplicates.Merge {
+			return nil, DuplicateError{*original}
+		}
+		if original.Merge(worker.trackRecord) {
+			if err := worker.trackRecordDAO.UpdateTrackRecord(*original); err != nil {
+				return nil, err
+			}
+		}
+		return fmt.Sprintf("duplicate: merged into /stations/%s/tracks/%d", original.StationId,
+			original.Timestamp), nil
 	}
 
 	if err := worker.trackRecordDAO.CreateTrackRecord(worker.trackRecord); err != nil {
 		return nil, err
 	}
 
-	return fmt.Sprintf("track created: /stations/%s/tracks/%d",
+	// the track record has been persisted, hence a rollup failing to update must not fail the
+	// request; the day's rollups have to be rebuilt instead
+	if worker.rollups != nil && worker.trackRecord.Type == model.TypeTrack {
+		if err := worker.rollups.Add(worker.trackRecord); err != nil {
+			log.Printf("WARNING: unable to update rollups of station `%s` for %d: %v",
+				worker.trackRecord.StationId, worker.trackRecord.Timestamp, err)
+		}
+	}
+
+	return fmt.Sprintf("%s created: /stations/%s/tracks/%d", worker.trackRecord.Type,
 		worker.trackRecord.StationId, worker.trackRecord.Timestamp), nil
 }
 
-func (worker CreateTrackWorker) populateStationsCache() {
-	stations, _ := worker.stationsDAO.GetAll()
-	for _, station := range stations {
-		stationsCache[station.ID] = true
+// findDuplicate returns the station's most recent track record if the track record duplicates it.
+// Crawlers polling twice report a play right after it has been recorded, hence comparing the most
+// recent track record suffices. Tracks are compared by their folded key, since track records
+// sanitized by former rules may still carry diacritics. Other types, e. g. jingles, legitimately
+// repeat within minutes.
+func (worker CreateTrackWorker) findDuplicate() (*model.TrackRecord, error) {
+	if worker.duplicates.Window <= 0 || worker.trackRecord.Type != model.TypeTrack {
+		return nil, nil
+	}
+	mostRecent, err := worker.trackRecordDAO.GetMostRecentTrackRecordByStation(
+		worker.trackRecord.StationId)
+	if datalayer.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	distance := time.Duration(worker.trackRecord.Timestamp-mostRecent.Timestamp) * time.Second
+	if distance < 0 {
+		distance = -distance
+	}
+	if distance > worker.duplicates.Window ||
+		mostRecent.Track.FoldedKey() != worker.trackRecord.Track.FoldedKey() {
+		return nil, nil
 	}
+	return &mostRecent, nil
 }
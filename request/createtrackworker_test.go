@@ -9,44 +9,54 @@ import (
 	"time"
 )
 
+func newTestStationCache(dao datalayer.StationDAO) *StationCache {
+	cache, _ := NewStationCache(dao, time.Minute)
+	return cache
+}
+
 func TestNewCreateTrackWorker(t *testing.T) {
+	stations := newTestStationCache(MockStationDAOSuccess{})
+
 	var tests = []struct {
 		trDAO       datalayer.TrackRecordDAO
-		sDAO        datalayer.StationDAO
+		stations    *StationCache
 		trackRecord model.TrackRecord
 		expectedErr bool
 	}{
 		{
 			MockTrackRecordDAO{},
-			MockStationDAOSuccess{},
+			stations,
 			model.TrackRecord{
-				"station-a",
-				time.Now().Unix(),
-				"track",
-				model.Track{"RHCP", "Californication"}},
+				StationId: "station-a",
+				Timestamp: time.Now().Unix(),
+				Type:      "track",
+				Track:     model.Track{"RHCP", "Californication"}},
 			false,
 		},
-		{nil, MockStationDAOSuccess{}, model.TrackRecord{}, true},
+		{nil, stations, model.TrackRecord{}, true},
 		{MockTrackRecordDAO{}, nil, model.TrackRecord{}, true},
 	}
 
 	for _, test := range tests {
-		result, err := NewCreateTrackWorker(test.trDAO, test.sDAO, test.trackRecord)
+		result, err := NewCreateTrackWorker(test.trDAO, test.stations, nil, test.trackRecord,
+			model.DefaultTrackRecordRules, DefaultDuplicatePolicy)
 		if (err != nil) != test.expectedErr {
-			t.Errorf("NewCreateTrackWorker(%q, %q, %q): got err (%v), expected err: %v",
-				test.trDAO, test.sDAO, test.trackRecord, err, test.expectedErr)
+			t.Errorf("NewCreateTrackWorker(%q, %v, %q): got err (%v), expected err: %v",
+				test.trDAO, test.stations, test.trackRecord, err, test.expectedErr)
 			continue
 		}
-		expectedResult := CreateTrackWorker{test.trDAO, test.sDAO, test.trackRecord}
+		expectedResult := CreateTrackWorker{test.trDAO, test.stations, nil, test.trackRecord,
+			model.DefaultTrackRecordRules, DefaultDuplicatePolicy}
 		if err == nil && !reflect.DeepEqual(result, expectedResult) {
-			t.Errorf("NewDaySearchWorker(%q, %q, %q): got result (%v), expected (%v)",
-				test.trDAO, test.sDAO, test.trackRecord, result, expectedResult)
+			t.Errorf("NewDaySearchWorker(%q, %v, %q): got result (%v), expected (%v)",
+				test.trDAO, test.stations, test.trackRecord, result, expectedResult)
 		}
 	}
 }
 
 func TestCreateTrackWorker_HandleRequest(t *testing.T) {
 	var timestamp = time.Now().Unix()
+	stations := newTestStationCache(MockStationDAOSuccess{})
 
 	var tests = []struct {
 		worker         CreateTrackWorker
@@ -57,12 +67,15 @@ func TestCreateTrackWorker_HandleRequest(t *testing.T) {
 		{
 			CreateTrackWorker{
 				MockTrackRecordDAO{},
-				MockStationDAOSuccessEmpty{},
+				newTestStationCache(MockStationDAOSuccessEmpty{}),
+				nil,
 				model.TrackRecord{
-					"hitradio-oe3", timestamp,
-					"track",
-					model.Track{"RHCP", "Californication"},
+					StationId: "hitradio-oe3", Timestamp: timestamp,
+					Type:  "track",
+					Track: model.Track{"RHCP", "Californication"},
 				},
+				model.DefaultTrackRecordRules,
+				DefaultDuplicatePolicy,
 			},
 			"ignored",
 			true, // cache empty & MockStationDAOSuccessEmpty serves no stations
@@ -71,26 +84,66 @@ func TestCreateTrackWorker_HandleRequest(t *testing.T) {
 		{
 			CreateTrackWorker{
 				MockTrackRecordDAO{},
-				MockStationDAOSuccess{},
+				stations,
+				nil,
 				model.TrackRecord{
-					"hitradio-oe3", timestamp,
-					"track",
-					model.Track{"RHCP", "Californication"},
+					StationId: "kronehit", Timestamp: timestamp,
+					Type:  "track",
+					Track: model.Track{"RHCP", "Californication"},
 				},
+				model.DefaultTrackRecordRules,
+				DefaultDuplicatePolicy,
 			},
-			"track created: /stations/hitradio-oe3/tracks/" + fmt.Sprintf("%d", timestamp),
+			"track created: /stations/kronehit/tracks/" + fmt.Sprintf("%d", timestamp),
 			false,
 		},
+		// inactive station
+		{
+			CreateTrackWorker{
+				MockTrackRecordDAO{},
+				stations,
+				nil,
+				model.TrackRecord{
+					StationId: "hitradio-oe3", Timestamp: timestamp,
+					Type:  "track",
+					Track: model.Track{"RHCP", "Californication"},
+				},
+				model.DefaultTrackRecordRules,
+				DefaultDuplicatePolicy,
+			},
+			"ignored",
+			true,
+		},
+		// stations unavailable
+		{
+			CreateTrackWorker{
+				MockTrackRecordDAO{},
+				newTestStationCache(MockStationDAOFail{}),
+				nil,
+				model.TrackRecord{
+					StationId: "kronehit", Timestamp: timestamp,
+					Type:  "track",
+					Track: model.Track{"RHCP", "Californication"},
+				},
+				model.DefaultTrackRecordRules,
+				DefaultDuplicatePolicy,
+			},
+			"ignored",
+			true,
+		},
 		// database error
 		{
 			CreateTrackWorker{
 				MockTrackRecordDAO{},
-				MockStationDAOSuccess{},
+				stations,
+				nil,
 				model.TrackRecord{
-					"hitradio-oe3", timestamp,
-					"track",
-					model.Track{"CAUTION:", "DATABASE ERROR"},
+					StationId: "kronehit", Timestamp: timestamp,
+					Type:  "track",
+					Track: model.Track{"CAUTION:", "DATABASE ERROR"},
 				},
+				model.DefaultTrackRecordRules,
+				DefaultDuplicatePolicy,
 			},
 			"ignored",
 			true,
@@ -99,12 +152,15 @@ func TestCreateTrackWorker_HandleRequest(t *testing.T) {
 		{
 			CreateTrackWorker{
 				MockTrackRecordDAO{},
-				MockStationDAOSuccess{},
+				stations,
+				nil,
 				model.TrackRecord{
-					"invalid station", timestamp,
-					"track",
-					model.Track{"RHCP", "Californication"},
+					StationId: "invalid station", Timestamp: timestamp,
+					Type:  "track",
+					Track: model.Track{"RHCP", "Californication"},
 				},
+				model.DefaultTrackRecordRules,
+				DefaultDuplicatePolicy,
 			},
 			"ignored",
 			true,
@@ -113,12 +169,16 @@ func TestCreateTrackWorker_HandleRequest(t *testing.T) {
 		{
 			CreateTrackWorker{
 				MockTrackRecordDAO{},
-				MockStationDAOSuccess{},
+				stations,
+				nil,
 				model.TrackRecord{
-					"hitradio-oe3", time.Now().Add(31 * time.Minute).Unix(),
-					"track",
-					model.Track{"RHCP", "Californication"},
+					StationId: "hitradio-oe3",
+					Timestamp: time.Now().Add(31 * time.Minute).Unix(),
+					Type:      "track",
+					Track:     model.Track{"RHCP", "Californication"},
 				},
+				model.DefaultTrackRecordRules,
+				DefaultDuplicatePolicy,
 			},
 			"ignored",
 			true,
@@ -127,12 +187,15 @@ func TestCreateTrackWorker_HandleRequest(t *testing.T) {
 		{
 			CreateTrackWorker{
 				MockTrackRecordDAO{},
-				MockStationDAOSuccess{},
+				stations,
+				nil,
 				model.TrackRecord{
-					"hitradio-oe3", timestamp,
-					"invalid type",
-					model.Track{"RHCP", "Californication"},
+					StationId: "hitradio-oe3", Timestamp: timestamp,
+					Type:  "invalid type",
+					Track: model.Track{"RHCP", "Californication"},
 				},
+				model.DefaultTrackRecordRules,
+				DefaultDuplicatePolicy,
 			},
 			"ignored",
 			true,
@@ -141,12 +204,15 @@ func TestCreateTrackWorker_HandleRequest(t *testing.T) {
 		{
 			CreateTrackWorker{
 				MockTrackRecordDAO{},
-				MockStationDAOSuccess{},
+				stations,
+				nil,
 				model.TrackRecord{
-					"hitradio-oe3", timestamp,
-					"track",
-					model.Track{"", "Californication"},
+					StationId: "hitradio-oe3", Timestamp: timestamp,
+					Type:  "track",
+					Track: model.Track{"", "Californication"},
 				},
+				model.DefaultTrackRecordRules,
+				DefaultDuplicatePolicy,
 			},
 			"ignored",
 			true,
@@ -155,12 +221,15 @@ func TestCreateTrackWorker_HandleRequest(t *testing.T) {
 		{
 			CreateTrackWorker{
 				MockTrackRecordDAO{},
-				MockStationDAOSuccess{},
+				stations,
+				nil,
 				model.TrackRecord{
-					"hitradio-oe3", timestamp,
-					"track",
-					model.Track{"RHCP", ""},
+					StationId: "hitradio-oe3", Timestamp: timestamp,
+					Type:  "track",
+					Track: model.Track{"RHCP", ""},
 				},
+				model.DefaultTrackRecordRules,
+				DefaultDuplicatePolicy,
 			},
 			"ignored",
 			true,
@@ -195,3 +264,66 @@ func TestCreateTrackWorker_HandleRequest(t *testing.T) {
 		}
 	}
 }
+
+func TestCreateTrackWorker_HandleRequest_Duplicates(t *testing.T) {
+	now := time.Now().Unix()
+	recorded := model.TrackRecord{StationId: "kronehit", Timestamp: now - 120, Type: "track",
+		Track: model.Track{"rhcp", "californication"}}
+	stations := newTestStationCache(MockStationDAOSuccess{})
+	merge := DuplicatePolicy{Window: 5 * time.Minute, Merge: true}
+
+	var tests = []struct {
+		timestamp      int64
+		track          model.Track
+		duplicates     DuplicatePolicy
+		expectedResult interface{}
+		expectedErr    error
+	}{
+		// reported again by a crawler polling twice
+		{now, model.Track{"RHCP", "Californication "}, DefaultDuplicatePolicy, nil,
+			DuplicateError{recorded}},
+		{now, model.Track{"RHCP", "Californication"}, merge,
+			fmt.Sprintf("duplicate: merged into /stations/kronehit/tracks/%d", now-120), nil},
+		// played again after the window
+		{now + 300, model.Track{"RHCP", "Californication"}, DefaultDuplicatePolicy,
+			fmt.Sprintf("track created: /stations/kronehit/tracks/%d", now+300), nil},
+		{now, model.Track{"RHCP", "Scar Tissue"}, DefaultDuplicatePolicy,
+			fmt.Sprintf("track created: /stations/kronehit/tracks/%d", now), nil},
+		{now, model.Track{"RHCP", "Californication"}, DuplicatePolicy{},
+			fmt.Sprintf("track created: /stations/kronehit/tracks/%d", now), nil},
+	}
+
+	for i, test := range tests {
+		dao := datalayer.NewMemoryTrackRecordDAO(recorded)
+		trackRecord := model.TrackRecord{StationId: "kronehit", Timestamp: test.timestamp,
+			Type: "track", Track: test.track}
+		worker, _ := NewCreateTrackWorker(dao, stations, nil, trackRecord,
+			model.DefaultTrackRecordRules, test.duplicates)
+		result, err := worker.HandleRequest()
+		if result != test.expectedResult || err != test.expectedErr {
+			t.Errorf("#%d HandleRequest(): got (%v, %v), expected (%v, %v)", i, result, err,
+				test.expectedResult, test.expectedErr)
+		}
+	}
+
+	// a merged duplicate completes the recorded track record, which has been sanitized by former
+	// rules keeping the diacritics
+	formerlyRecorded := recorded
+	formerlyRecorded.Track = model.Track{"rhcp", "califórnication"}
+	formerlyRecorded.TrackMetadata = model.TrackMetadata{Year: 1999}
+	dao := datalayer.NewMemoryTrackRecordDAO(formerlyRecorded)
+	trackRecord := model.TrackRecord{StationId: "kronehit", Timestamp: now, Type: "track",
+		Track: model.Track{"RHCP", "Californication"}, TrackMetadata: model.TrackMetadata{
+			Album: "Californication", Year: 2003}}
+	worker, _ := NewCreateTrackWorker(dao, stations, nil, trackRecord,
+		model.DefaultTrackRecordRules, merge)
+	if _, err := worker.HandleRequest(); err != nil {
+		t.Fatalf("HandleRequest(): got err (%v), expected nil", err)
+	}
+	expected := formerlyRecorded
+	expected.TrackMetadata = model.TrackMetadata{Album: "Californication", Year: 1999}
+	expected.TrackDisplay = model.TrackDisplay{"RHCP", "Californication"}
+	if stored, _ := dao.GetMostRecentTrackRecordByStation("kronehit"); stored != expected {
+		t.Errorf("HandleRequest(): got stored (%v), expected merged (%v)", stored, expected)
+	}
+}
@@ -10,12 +10,13 @@ type DaySearchWorker struct {
 	date time.Time
 }
 
-func NewDaySearchWorker(dao datalayer.TrackRecordDAO, query string,
+func NewDaySearchWorker(dao datalayer.TrackRecordDAO, rollups *Rollups, query, artist string,
 	date time.Time) (DaySearchWorker, error) {
-	searchWorker, err := NewSearchWorker(dao, query)
+	searchWorker, err := NewSearchWorker(dao, query, artist)
 	if err != nil {
 		return DaySearchWorker{}, err
 	}
+	searchWorker.rollups = rollups
 	return DaySearchWorker{searchWorker, date}, nil
 }
 
@@ -23,3 +24,12 @@ func (worker DaySearchWorker) HandleRequest() (interface{}, error) {
 	startDate, endDate := calculateDayBoundaries(worker.date)
 	return worker.Search(startDate, endDate)
 }
+
+func (worker DaySearchWorker) Period() (time.Time, time.Time) {
+	return calculateDayBoundaries(worker.date)
+}
+
+func (worker DaySearchWorker) CacheKey() string {
+	startDate, _ := worker.Period()
+	return cacheKey("search", worker.cacheKeyQuery(), "day", formatCacheKeyDate(startDate))
+}
@@ -27,14 +27,14 @@ func TestNewDaySearchWorker(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		result, err := NewDaySearchWorker(test.dao, test.query, test.date)
+		result, err := NewDaySearchWorker(test.dao, nil, test.query, "", test.date)
 		if (err != nil) != test.expectedErr {
 			t.Errorf("NewDaySearchWorker(%q, %q, %q): got err (%v), expected err: %v",
 				test.dao, test.query, test.date, err, test.expectedErr)
 			continue
 		}
 		expectedResult := DaySearchWorker{
-			SearchWorker{test.dao, strings.Split(strings.ToLower(test.query), queryStrKeywordsSeparator)},
+			SearchWorker{test.dao, nil, strings.Split(strings.ToLower(test.query), queryStrKeywordsSeparator), ""},
 			test.date,
 		}
 		if err == nil && !reflect.DeepEqual(result, expectedResult) {
@@ -53,32 +53,32 @@ func TestDaySearchWorker_HandleRequest(t *testing.T) {
 		expectedErr    bool
 	}{
 		{
-			DaySearchWorker{SearchWorker{MockTrackRecordDAO{}, []string{"californication"}}, date},
+			DaySearchWorker{SearchWorker{MockTrackRecordDAO{}, nil, []string{"californication"}, ""}, date},
 			matchedTracks0,
 			false,
 		},
 		{
-			DaySearchWorker{SearchWorker{MockTrackRecordDAO{}, []string{"cali"}}, date},
+			DaySearchWorker{SearchWorker{MockTrackRecordDAO{}, nil, []string{"cali"}, ""}, date},
 			matchedTracks1,
 			false,
 		},
 		{
-			DaySearchWorker{SearchWorker{MockTrackRecordDAO{}, []string{"maggie", "rhcp"}}, date},
+			DaySearchWorker{SearchWorker{MockTrackRecordDAO{}, nil, []string{"maggie", "rhcp"}, ""}, date},
 			matchedTracks2,
 			false,
 		},
 		{
-			DaySearchWorker{SearchWorker{MockTrackRecordDAO{}, []string{"ø"}}, date},
+			DaySearchWorker{SearchWorker{MockTrackRecordDAO{}, nil, []string{"mo"}, ""}, date},
 			matchedTracks3,
 			false,
 		},
 		{
-			DaySearchWorker{SearchWorker{MockTrackRecordDAO{}, []string{"no", "tracks", "query"}}, date},
+			DaySearchWorker{SearchWorker{MockTrackRecordDAO{}, nil, []string{"no", "tracks", "query"}, ""}, date},
 			model.MatchedTracks{},
 			false,
 		},
 		{
-			DaySearchWorker{SearchWorker{MockTrackRecordDAODayVerifier{}, []string{"nevermind"}},
+			DaySearchWorker{SearchWorker{MockTrackRecordDAODayVerifier{}, nil, []string{"nevermind"}, ""},
 				date},
 			model.MatchedTracks{},
 			false,
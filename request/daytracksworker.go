@@ -11,21 +11,27 @@ type DayTracksWorker struct {
 	filter Filter
 }
 
-func NewDayTracksWorker(dao datalayer.TrackRecordDAO, station string, date time.Time,
-	filter Filter) (DayTracksWorker, error) {
+func NewDayTracksWorker(dao datalayer.TrackRecordDAO, rollups *Rollups, station string,
+	date time.Time, filter Filter, ranking Ranking) (DayTracksWorker, error) {
 	tracksWorker, err := NewTracksWorker(dao, station)
 	if err != nil {
 		return DayTracksWorker{}, err
 	}
+	tracksWorker.rollups = rollups
+	tracksWorker.ranking = ranking
 	return DayTracksWorker{tracksWorker, date, filter}, nil
 }
 
 func (worker DayTracksWorker) HandleRequest() (interface{}, error) {
 	startDate, endDate := calculateDayBoundaries(worker.date)
-	if worker.filter == Top {
+	switch worker.filter {
+	case Artists:
+		return worker.TopArtists(startDate, endDate)
+	case Top:
 		return worker.TopTracks(startDate, endDate)
+	default:
+		return worker.AllTracks(startDate, endDate)
 	}
-	return worker.AllTracks(startDate, endDate)
 }
 
 func calculateDayBoundaries(date time.Time) (time.Time, time.Time) {
@@ -34,3 +40,13 @@ func calculateDayBoundaries(date time.Time) (time.Time, time.Time) {
 	endDate := startDate.AddDate(0, 0, 1).Add(-1 * time.Second)
 	return startDate, endDate
 }
+
+func (worker DayTracksWorker) Period() (time.Time, time.Time) {
+	return calculateDayBoundaries(worker.date)
+}
+
+func (worker DayTracksWorker) CacheKey() string {
+	startDate, _ := worker.Period()
+	return cacheKey("tracks", worker.station, "day", formatCacheKeyDate(startDate),
+		worker.filter.String(), worker.ranking.String())
+}
@@ -40,6 +40,28 @@ func (dao MockTrackRecordDAODayVerifier) CreateTrackRecord(trackRecord model.Tra
 	return nil
 }
 
+func (dao MockTrackRecordDAODayVerifier) UpdateTrackRecord(trackRecord model.TrackRecord) error {
+	return nil
+}
+
+func (dao MockTrackRecordDAODayVerifier) DeleteTrackRecord(station string, airtime int64) error {
+	return nil
+}
+
+func (dao MockTrackRecordDAODayVerifier) GetRecords(types []string, start,
+	end time.Time) ([]model.TrackRecord, error) {
+	return dao.GetTrackRecords(start, end)
+}
+
+func (dao MockTrackRecordDAODayVerifier) GetRecordsByStation(stationId string, types []string,
+	start, end time.Time) ([]model.TrackRecord, error) {
+	return dao.GetTrackRecordsByStation(stationId, start, end)
+}
+
+func (dao MockTrackRecordDAODayVerifier) PutTrackRecords(trackRecords []model.TrackRecord) error {
+	return nil
+}
+
 func TestNewDayTracksWorker(t *testing.T) {
 	var tests = []struct {
 		dao         datalayer.TrackRecordDAO
@@ -60,13 +82,14 @@ func TestNewDayTracksWorker(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		result, err := NewDayTracksWorker(test.dao, test.station, test.date, test.filter)
+		result, err := NewDayTracksWorker(test.dao, nil, test.station, test.date, test.filter,
+			DefaultRanking)
 		if (err != nil) != test.expectedErr {
 			t.Errorf("TestNewDayTracksWorker(%q, %q, %q, %q): got err (%v), expected err: %v",
 				test.dao, test.station, test.date, test.filter, err, test.expectedErr)
 			continue
 		}
-		expectedResult := DayTracksWorker{TracksWorker{test.dao, test.station}, test.date,
+		expectedResult := DayTracksWorker{TracksWorker{test.dao, nil, test.station, DefaultRanking}, test.date,
 			test.filter}
 		if err == nil && !reflect.DeepEqual(result, expectedResult) {
 			t.Errorf("TestNewDayTracksWorker(%q, %q, %q, %q): got result (%v), expected (%v)",
@@ -84,18 +107,18 @@ func TestDayTracksWorker_HandleRequest_TopTracks(t *testing.T) {
 		expectedErr    bool
 	}{
 		{
-			DayTracksWorker{TracksWorker{MockTrackRecordDAO{}, "station-A"}, date, Top},
+			DayTracksWorker{TracksWorker{MockTrackRecordDAO{}, nil, "station-A", DefaultRanking}, date, Top},
 			countedTracks,
 			false,
 		},
 		{
-			DayTracksWorker{TracksWorker{MockTrackRecordDAO{}, "notracksstation"}, date,
+			DayTracksWorker{TracksWorker{MockTrackRecordDAO{}, nil, "notracksstation", DefaultRanking}, date,
 				Top},
 			model.CountedTracks{},
 			false,
 		},
 		{
-			DayTracksWorker{TracksWorker{MockTrackRecordDAODayVerifier{}, "nevermind"}, date,
+			DayTracksWorker{TracksWorker{MockTrackRecordDAODayVerifier{}, nil, "nevermind", DefaultRanking}, date,
 				Top},
 			model.CountedTracks{},
 			false,
@@ -141,18 +164,18 @@ func TestDayTracksWorker_HandleRequest_AllTracks(t *testing.T) {
 		expectedErr    bool
 	}{
 		{
-			DayTracksWorker{TracksWorker{MockTrackRecordDAO{}, "station-A"}, date, All},
+			DayTracksWorker{TracksWorker{MockTrackRecordDAO{}, nil, "station-A", DefaultRanking}, date, All},
 			tracks,
 			false,
 		},
 		{
-			DayTracksWorker{TracksWorker{MockTrackRecordDAO{}, "notracksstation"}, date,
+			DayTracksWorker{TracksWorker{MockTrackRecordDAO{}, nil, "notracksstation", DefaultRanking}, date,
 				All},
 			model.Tracks{},
 			false,
 		},
 		{
-			DayTracksWorker{TracksWorker{MockTrackRecordDAODayVerifier{}, "nevermind"}, date,
+			DayTracksWorker{TracksWorker{MockTrackRecordDAODayVerifier{}, nil, "nevermind", DefaultRanking}, date,
 				All},
 			model.Tracks{},
 			false,
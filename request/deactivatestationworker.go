@@ -0,0 +1,30 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"github.com/RadioCheckerApp/api/datalayer"
+)
+
+type DeactivateStationWorker struct {
+	dao       datalayer.StationDAO
+	stationId string
+}
+
+func NewDeactivateStationWorker(dao datalayer.StationDAO,
+	stationId string) (DeactivateStationWorker, error) {
+	if dao == nil {
+		return DeactivateStationWorker{}, errors.New("dao must not be nil")
+	}
+	if stationId == "" {
+		return DeactivateStationWorker{}, errors.New("stationId must not be empty")
+	}
+	return DeactivateStationWorker{dao, stationId}, nil
+}
+
+func (worker DeactivateStationWorker) HandleRequest() (interface{}, error) {
+	if err := worker.dao.Deactivate(worker.stationId); err != nil {
+		return nil, err
+	}
+	return fmt.Sprintf("station deactivated: /stations/%s", worker.stationId), nil
+}
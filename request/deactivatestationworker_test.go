@@ -0,0 +1,67 @@
+package request
+
+import (
+	"github.com/RadioCheckerApp/api/datalayer"
+	"reflect"
+	"testing"
+)
+
+func TestNewDeactivateStationWorker(t *testing.T) {
+	var tests = []struct {
+		dao         datalayer.StationDAO
+		stationId   string
+		expectedErr bool
+	}{
+		{MockStationDAOSuccess{}, "kronehit", false},
+		{nil, "kronehit", true},
+		{MockStationDAOSuccess{}, "", true},
+	}
+
+	for _, test := range tests {
+		result, err := NewDeactivateStationWorker(test.dao, test.stationId)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("NewDeactivateStationWorker(%v, %q): got err (%v), expected err: %v",
+				test.dao, test.stationId, err, test.expectedErr)
+			continue
+		}
+		expectedResult := DeactivateStationWorker{test.dao, test.stationId}
+		if err == nil && !reflect.DeepEqual(result, expectedResult) {
+			t.Errorf("NewDeactivateStationWorker(%v, %q): got result (%v), expected (%v)",
+				test.dao, test.stationId, result, expectedResult)
+		}
+	}
+}
+
+func TestDeactivateStationWorker_HandleRequest(t *testing.T) {
+	var tests = []struct {
+		worker         DeactivateStationWorker
+		expectedResult string
+		expectedErr    bool
+	}{
+		{
+			DeactivateStationWorker{MockStationDAOSuccess{}, "kronehit"},
+			"station deactivated: /stations/kronehit",
+			false,
+		},
+		{DeactivateStationWorker{MockStationDAOSuccess{}, "fm4"}, "ignored", true},
+		{DeactivateStationWorker{MockStationDAOFail{}, "kronehit"}, "ignored", true},
+	}
+
+	for _, test := range tests {
+		result, err := test.worker.HandleRequest()
+		if (err != nil) != test.expectedErr {
+			t.Errorf("(%v).HandleRequest(): got err (%v), expected err: %v",
+				test.worker, err, test.expectedErr)
+			continue
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if result != test.expectedResult {
+			t.Errorf("(%v).HandleRequest(): got result (%v), expected (%v)",
+				test.worker, result, test.expectedResult)
+		}
+	}
+}
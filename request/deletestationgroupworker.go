@@ -0,0 +1,30 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"github.com/RadioCheckerApp/api/datalayer"
+)
+
+type DeleteStationGroupWorker struct {
+	dao     datalayer.StationGroupDAO
+	groupId string
+}
+
+func NewDeleteStationGroupWorker(dao datalayer.StationGroupDAO,
+	groupId string) (DeleteStationGroupWorker, error) {
+	if dao == nil {
+		return DeleteStationGroupWorker{}, errors.New("dao must not be nil")
+	}
+	if groupId == "" {
+		return DeleteStationGroupWorker{}, errors.New("groupId must not be empty")
+	}
+	return DeleteStationGroupWorker{dao, groupId}, nil
+}
+
+func (worker DeleteStationGroupWorker) HandleRequest() (interface{}, error) {
+	if err := worker.dao.Delete(worker.groupId); err != nil {
+		return nil, err
+	}
+	return fmt.Sprintf("group deleted: /groups/%s", worker.groupId), nil
+}
@@ -0,0 +1,67 @@
+package request
+
+import (
+	"github.com/RadioCheckerApp/api/datalayer"
+	"reflect"
+	"testing"
+)
+
+func TestNewDeleteStationGroupWorker(t *testing.T) {
+	var tests = []struct {
+		dao         datalayer.StationGroupDAO
+		groupId     string
+		expectedErr bool
+	}{
+		{MockStationGroupDAOSuccess{}, "austria", false},
+		{nil, "austria", true},
+		{MockStationGroupDAOSuccess{}, "", true},
+	}
+
+	for _, test := range tests {
+		result, err := NewDeleteStationGroupWorker(test.dao, test.groupId)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("NewDeleteStationGroupWorker(%v, %q): got err (%v), expected err: %v",
+				test.dao, test.groupId, err, test.expectedErr)
+			continue
+		}
+		expectedResult := DeleteStationGroupWorker{test.dao, test.groupId}
+		if err == nil && !reflect.DeepEqual(result, expectedResult) {
+			t.Errorf("NewDeleteStationGroupWorker(%v, %q): got result (%v), expected (%v)",
+				test.dao, test.groupId, result, expectedResult)
+		}
+	}
+}
+
+func TestDeleteStationGroupWorker_HandleRequest(t *testing.T) {
+	var tests = []struct {
+		worker         DeleteStationGroupWorker
+		expectedResult string
+		expectedErr    bool
+	}{
+		{
+			DeleteStationGroupWorker{MockStationGroupDAOSuccess{}, "austria"},
+			"group deleted: /groups/austria",
+			false,
+		},
+		{DeleteStationGroupWorker{MockStationGroupDAOSuccess{}, "orf"}, "ignored", true},
+		{DeleteStationGroupWorker{MockStationGroupDAOFail{}, "austria"}, "ignored", true},
+	}
+
+	for _, test := range tests {
+		result, err := test.worker.HandleRequest()
+		if (err != nil) != test.expectedErr {
+			t.Errorf("(%v).HandleRequest(): got err (%v), expected err: %v",
+				test.worker, err, test.expectedErr)
+			continue
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if result != test.expectedResult {
+			t.Errorf("(%v).HandleRequest(): got result (%v), expected (%v)",
+				test.worker, result, test.expectedResult)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package request
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/export"
+	"github.com/RadioCheckerApp/api/model"
+	"time"
+)
+
+// MaxExportDays bounds the period exported by the API: HandleRequest buffers the whole export in
+// memory, since Lambda responses cannot be streamed, and they must not exceed 6 MB. Longer periods
+// are exported by `rcadmin export`, which streams to its output.
+const MaxExportDays = 31
+
+type ExportWorker struct {
+	dao       datalayer.TrackRecordDAO
+	station   string
+	types     []string
+	startDate time.Time
+	endDate   time.Time
+	format    export.Format
+}
+
+func NewExportWorker(dao datalayer.TrackRecordDAO, station string, types []string, startDate,
+	endDate time.Time, format export.Format) (ExportWorker, error) {
+	if dao == nil {
+		return ExportWorker{}, errors.New("dao must not be nil")
+	}
+	if station == "" {
+		return ExportWorker{}, errors.New("station must not be empty")
+	}
+	if len(types) == 0 {
+		return ExportWorker{}, errors.New("types must not be empty")
+	}
+	if startDate.After(endDate) {
+		return ExportWorker{}, errors.New("`from` must not be after `to`")
+	}
+	if !endDate.Before(startDate.AddDate(0, 0, MaxExportDays)) {
+		return ExportWorker{}, fmt.Errorf("exports are limited to %d days", MaxExportDays)
+	}
+	return ExportWorker{dao, station, types, startDate, endDate, format}, nil
+}
+
+func (worker ExportWorker) HandleRequest() (interface{}, error) {
+	body := &bytes.Buffer{}
+	writer, err := export.NewWriter(worker.format, body)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := export.Export(worker.dao, worker.station, worker.types, worker.startDate,
+		worker.endDate, export.DefaultChunk, writer); err != nil {
+		return nil, err
+	}
+	return model.RawData{ContentType: worker.format.ContentType(), Body: body.Bytes()}, nil
+}
@@ -0,0 +1,80 @@
+package request
+
+import (
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/export"
+	"github.com/RadioCheckerApp/api/model"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewExportWorker(t *testing.T) {
+	startDate := time.Date(2018, 9, 1, 0, 0, 0, 0, DefaultSettings().Location)
+	monthEnd := startDate.AddDate(0, 1, 0).Add(-time.Second)
+	tracks := []string{"track"}
+
+	var tests = []struct {
+		dao         datalayer.TrackRecordDAO
+		station     string
+		types       []string
+		endDate     time.Time
+		expectedErr bool
+	}{
+		{MockTrackRecordDAO{}, "fm4", tracks, monthEnd, false},
+		{nil, "fm4", tracks, monthEnd, true},
+		{MockTrackRecordDAO{}, "", tracks, monthEnd, true},
+		{MockTrackRecordDAO{}, "fm4", []string{}, monthEnd, true},
+		{MockTrackRecordDAO{}, "fm4", tracks, startDate.Add(-time.Second), true},
+		// 31 days at most
+		{MockTrackRecordDAO{}, "fm4", tracks, startDate.AddDate(0, 0, 31).Add(-time.Second), false},
+		{MockTrackRecordDAO{}, "fm4", tracks, startDate.AddDate(0, 0, 32).Add(-time.Second), true},
+	}
+
+	for i, test := range tests {
+		_, err := NewExportWorker(test.dao, test.station, test.types, startDate, test.endDate,
+			export.CSV)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("#%d NewExportWorker(): got err (%v), expected err: %v", i, err,
+				test.expectedErr)
+		}
+	}
+}
+
+func TestExportWorker_HandleRequest(t *testing.T) {
+	dao := datalayer.NewMemoryTrackRecordDAO(
+		model.TrackRecord{StationId: "fm4", Timestamp: 1537708000, Type: "track",
+			Track: model.Track{"cardi b", "i like it"}},
+		model.TrackRecord{StationId: "oe3", Timestamp: 1537703000, Type: "track",
+			Track: model.Track{"mø", "final song"}},
+		model.TrackRecord{StationId: "fm4", Timestamp: 1537700000, Type: "track",
+			Track: model.Track{"rhcp", "californication"}},
+	)
+	startDate, endDate := time.Unix(1537653600, 0), time.Unix(1537739999, 0)
+
+	var tests = []struct {
+		format   export.Format
+		expected model.RawData
+	}{
+		{export.CSV, model.RawData{"text/csv; charset=utf-8",
+			[]byte("stationId,airtime,type,artist,title,album,duration,isrc,label,year," +
+				"cover_url\n" +
+				"fm4,1537700000,track,rhcp,californication,,,,,,\n" +
+				"fm4,1537708000,track,cardi b,i like it,,,,,,\n")}},
+		{export.JSONL, model.RawData{"application/x-ndjson", []byte(
+			`{"stationId":"fm4","airtime":1537700000,"type":"track","artist":"rhcp",` +
+				`"title":"californication"}` + "\n" +
+				`{"stationId":"fm4","airtime":1537708000,"type":"track","artist":"cardi b",` +
+				`"title":"i like it"}` + "\n")}},
+	}
+
+	for _, test := range tests {
+		worker, _ := NewExportWorker(dao, "fm4", []string{"track"}, startDate, endDate,
+			test.format)
+		result, err := worker.HandleRequest()
+		if err != nil || !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("%s HandleRequest(): got (%v, %v), expected (%v, nil)", test.format, result,
+				err, test.expected)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package request
+
+import (
+	"errors"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"sort"
+	"time"
+)
+
+// GroupTracksWorker aggregates the plays of all member stations of a station group. Rollups may be
+// nil, in which case all plays are counted from the track records.
+type GroupTracksWorker struct {
+	groupDAO       datalayer.StationGroupDAO
+	trackRecordDAO datalayer.TrackRecordDAO
+	rollups        *Rollups
+	groupId        string
+	startDate      time.Time
+	endDate        time.Time
+	filter         Filter
+	ranking        Ranking
+}
+
+func NewGroupTracksWorker(groupDAO datalayer.StationGroupDAO,
+	trackRecordDAO datalayer.TrackRecordDAO, rollups *Rollups, groupId string, startDate,
+	endDate time.Time, filter Filter, ranking Ranking) (GroupTracksWorker, error) {
+	if groupDAO == nil || trackRecordDAO == nil {
+		return GroupTracksWorker{}, errors.New("daos must not be nil")
+	}
+	if groupId == "" {
+		return GroupTracksWorker{}, errors.New("groupId must not be empty")
+	}
+	if filter != Top && filter != All {
+		return GroupTracksWorker{}, errors.New("invalid filter provided")
+	}
+	return GroupTracksWorker{groupDAO, trackRecordDAO, rollups, groupId, startDate, endDate,
+		filter, ranking}, nil
+}
+
+func (worker GroupTracksWorker) HandleRequest() (interface{}, error) {
+	group, err := worker.groupDAO.Get(worker.groupId)
+	if err != nil {
+		return model.GroupTracks{}, err
+	}
+
+	groupedTracks := make(groupedTracksContainer)
+	casings := make(model.Casings)
+	var lastModified time.Time
+	for _, station := range group.Stations {
+		tracksWorker, err := NewTracksWorker(worker.trackRecordDAO, station)
+		if err != nil {
+			return model.GroupTracks{}, err
+		}
+		tracksWorker.rollups = worker.rollups
+		countedTracks, stationCasings, stationLastModified, err := tracksWorker.countTracks(
+			worker.startDate, worker.endDate)
+		if err != nil {
+			return model.GroupTracks{}, err
+		}
+		casings.Merge(stationCasings)
+		if stationLastModified.After(lastModified) {
+			lastModified = stationLastModified
+		}
+		for track, count := range countedTracks {
+			if _, ok := groupedTracks[track]; !ok {
+				groupedTracks[track] = newStationsMap(group.Stations)
+			}
+			groupedTracks[track][station] += count
+		}
+	}
+
+	orderedTracks := buildGroupTracks(foldGroupTracks(groupedTracks, group.Stations))
+	if worker.filter == Top {
+		countedTracks := make([]model.CountedTrack, len(orderedTracks))
+		for i, groupTrack := range orderedTracks {
+			countedTracks[i] = model.CountedTrack{groupTrack.Counter, groupTrack.Track}
+		}
+		orderedTracks = orderedTracks[:findResultLimitIdx(countedTracks, worker.ranking)]
+	}
+	for i := range orderedTracks {
+		orderedTracks[i].Track = casings.Display(orderedTracks[i].Track)
+	}
+
+	return model.GroupTracks{
+		group.ID,
+		worker.startDate,
+		worker.endDate,
+		orderedTracks,
+		lastModified,
+	}, nil
+}
+
+func (worker GroupTracksWorker) Period() (time.Time, time.Time) {
+	return worker.startDate, worker.endDate
+}
+
+// CacheKey identifies the group's day or week by its first date. The key does not cover the
+// group's stations, hence results cached before the group is updated are served until the cache
+// version changes.
+func (worker GroupTracksWorker) CacheKey() string {
+	period := "day"
+	if worker.endDate.After(worker.startDate.AddDate(0, 0, 1)) {
+		period = "week"
+	}
+	return cacheKey("groups", worker.groupId, period, formatCacheKeyDate(worker.startDate),
+		worker.filter.String(), worker.ranking.String())
+}
+
+// foldGroupTracks merges the plays of the tracks sharing a folded key per station, see foldTracks.
+// The variant played most often by all stations represents them.
+func foldGroupTracks(groupedTracks groupedTracksContainer,
+	stations []string) groupedTracksContainer {
+	totals := make(map[model.Track]int, len(groupedTracks))
+	for track, countsByStation := range groupedTracks {
+		for _, count := range countsByStation {
+			totals[track] += count
+		}
+	}
+	variants := foldedVariants(totals)
+
+	foldedTracks := make(groupedTracksContainer)
+	for track, countsByStation := range groupedTracks {
+		variant := variants[track.FoldedKey()]
+		if _, ok := foldedTracks[variant]; !ok {
+			foldedTracks[variant] = newStationsMap(stations)
+		}
+		for station, count := range countsByStation {
+			foldedTracks[variant][station] += count
+		}
+	}
+	return foldedTracks
+}
+
+// buildGroupTracks sums up the plays per track and orders the tracks descendingly by their total
+// number of plays.
+func buildGroupTracks(groupedTracks groupedTracksContainer) []model.GroupTrack {
+	groupTracks := make([]model.GroupTrack, 0, len(groupedTracks))
+	for track, countsByStation := range groupedTracks {
+		counter := 0
+		for _, count := range countsByStation {
+			counter += count
+		}
+		groupTracks = append(groupTracks, model.GroupTrack{counter, countsByStation, track})
+	}
+
+	sort.Slice(groupTracks, func(i, j int) bool {
+		if groupTracks[i].Counter != groupTracks[j].Counter {
+			return groupTracks[i].Counter > groupTracks[j].Counter
+		}
+		return lessTrack(groupTracks[i].Track, groupTracks[j].Track)
+	})
+	return groupTracks
+}
@@ -0,0 +1,205 @@
+package request
+
+import (
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewGroupTracksWorker(t *testing.T) {
+	startDate, endDate := calculateDayBoundaries(time.Now())
+
+	var tests = []struct {
+		groupDAO       datalayer.StationGroupDAO
+		trackRecordDAO datalayer.TrackRecordDAO
+		groupId        string
+		filter         Filter
+		expectedErr    bool
+	}{
+		{MockStationGroupDAOSuccess{}, MockTrackRecordDAO{}, "austria", Top, false},
+		{MockStationGroupDAOSuccess{}, MockTrackRecordDAO{}, "austria", All, false},
+		{MockStationGroupDAOSuccess{}, MockTrackRecordDAO{}, "austria", Latest, true},
+		{MockStationGroupDAOSuccess{}, MockTrackRecordDAO{}, "", Top, true},
+		{nil, MockTrackRecordDAO{}, "austria", Top, true},
+		{MockStationGroupDAOSuccess{}, nil, "austria", Top, true},
+	}
+
+	for _, test := range tests {
+		result, err := NewGroupTracksWorker(test.groupDAO, test.trackRecordDAO, nil,
+			test.groupId, startDate, endDate, test.filter, DefaultRanking)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("NewGroupTracksWorker(%v, %v, %q, %d): got err (%v), expected err: %v",
+				test.groupDAO, test.trackRecordDAO, test.groupId, test.filter, err,
+				test.expectedErr)
+			continue
+		}
+		expectedResult := GroupTracksWorker{test.groupDAO, test.trackRecordDAO, nil,
+			test.groupId, startDate, endDate, test.filter, DefaultRanking}
+		if err == nil && !reflect.DeepEqual(result, expectedResult) {
+			t.Errorf("NewGroupTracksWorker(%v, %v, %q, %d): got result (%v), expected (%v)",
+				test.groupDAO, test.trackRecordDAO, test.groupId, test.filter, result,
+				expectedResult)
+		}
+	}
+}
+
+func TestGroupTracksWorker_HandleRequest(t *testing.T) {
+	startDate, endDate := calculateDayBoundaries(time.Now())
+	counts := func(kronehit, oe3 int) map[string]int {
+		return map[string]int{"kronehit": kronehit, "hitradio-oe3": oe3}
+	}
+
+	var tests = []struct {
+		worker         GroupTracksWorker
+		expectedResult model.GroupTracks
+		expectedErr    bool
+	}{
+		{
+			GroupTracksWorker{MockStationGroupDAOSuccess{}, MockTrackRecordDAO{}, nil, "austria",
+				startDate, endDate, Top, DefaultRanking},
+			model.GroupTracks{
+				"austria",
+				startDate,
+				endDate,
+				[]model.GroupTrack{
+					{6, counts(3, 3), model.Track{"RHCP", "Californication"}},
+					{4, counts(2, 2), model.Track{"Jonas Blue, Jack & Jack", "Rise"}},
+					{2, counts(1, 1), model.Track{"Cardi B", "I Like It"}},
+				},
+				time.Time{},
+			},
+			false,
+		},
+		// unknown group
+		{
+			GroupTracksWorker{MockStationGroupDAOSuccess{}, MockTrackRecordDAO{}, nil, "orf",
+				startDate, endDate, Top, DefaultRanking},
+			model.GroupTracks{},
+			true,
+		},
+		// group database error
+		{
+			GroupTracksWorker{MockStationGroupDAOFail{}, MockTrackRecordDAO{}, nil, "austria",
+				startDate, endDate, Top, DefaultRanking},
+			model.GroupTracks{},
+			true,
+		},
+		// track records database error
+		{
+			GroupTracksWorker{MockStationGroupDAOSuccess{}, MockTrackRecordDAO{}, nil, "austria",
+				endDate, startDate, All, DefaultRanking},
+			model.GroupTracks{},
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		result, err := test.worker.HandleRequest()
+		if (err != nil) != test.expectedErr {
+			t.Errorf("(%v).HandleRequest(): got err (%v), expected err: %v",
+				test.worker, err, test.expectedErr)
+			continue
+		}
+
+		if err != nil {
+			continue
+		}
+
+		// the mocked track records are played right now
+		groupTracks := result.(model.GroupTracks)
+		if time.Since(groupTracks.LastModified) > time.Minute {
+			t.Errorf("(%v).HandleRequest(): got last modified %v, expected the current time",
+				test.worker, groupTracks.LastModified)
+		}
+		groupTracks.LastModified = time.Time{}
+		if !reflect.DeepEqual(groupTracks, test.expectedResult) {
+			t.Errorf("(%v).HandleRequest(): got \n(%v), expected \n(%v)",
+				test.worker, result, test.expectedResult)
+		}
+	}
+}
+
+func TestGroupTracksWorker_HandleRequest_Folded(t *testing.T) {
+	location, _ := time.LoadLocation("Europe/Vienna")
+	now := time.Date(2018, 9, 18, 15, 0, 0, 0, location)
+	startDate, endDate := calculateDayBoundaries(now.AddDate(0, 0, -1))
+	rollups := newGroupRollups(now,
+		model.TrackRollup{StationId: "kronehit", Track: model.Track{"beyoncé", "halo"}, Plays: 3,
+			LastAirtime: 1537200000, TrackDisplay: model.TrackDisplay{"Beyoncé", "Halo"}},
+		model.TrackRollup{StationId: "hitradio-oe3", Track: model.Track{"beyonce", "halo"},
+			Plays: 1, LastAirtime: 1537210000, TrackDisplay: model.TrackDisplay{"Beyonce", "Halo"}},
+	)
+
+	for _, filter := range []Filter{Top, All} {
+		worker, _ := NewGroupTracksWorker(MockStationGroupDAOSuccess{}, MockTrackRecordDAO{},
+			rollups, "austria", startDate, endDate, filter, DefaultRanking)
+		result, err := worker.HandleRequest()
+		expected := model.GroupTracks{"austria", startDate, endDate, []model.GroupTrack{
+			{4, map[string]int{"kronehit": 3, "hitradio-oe3": 1}, model.Track{"Beyoncé", "Halo"}},
+		}, time.Unix(1537210000, 0)}
+		if err != nil || !reflect.DeepEqual(result, expected) {
+			t.Errorf("HandleRequest() with filter %v: got (%v, %v), expected (%v, nil)", filter,
+				result, err, expected)
+		}
+	}
+}
+
+func TestBuildGroupTracks(t *testing.T) {
+	groupedTracks := groupedTracksContainer{
+		model.Track{"b", "title"}:  {"station-a": 1, "station-b": 1},
+		model.Track{"a", "title"}:  {"station-a": 2, "station-b": 0},
+		model.Track{"c", "title"}:  {"station-a": 0, "station-b": 5},
+		model.Track{"a", "title2"}: {"station-a": 1, "station-b": 1},
+	}
+
+	expected := []model.GroupTrack{
+		{5, map[string]int{"station-a": 0, "station-b": 5}, model.Track{"c", "title"}},
+		{2, map[string]int{"station-a": 2, "station-b": 0}, model.Track{"a", "title"}},
+		{2, map[string]int{"station-a": 1, "station-b": 1}, model.Track{"a", "title2"}},
+		{2, map[string]int{"station-a": 1, "station-b": 1}, model.Track{"b", "title"}},
+	}
+
+	if result := buildGroupTracks(groupedTracks); !reflect.DeepEqual(result, expected) {
+		t.Errorf("buildGroupTracks(%v): got \n(%v), expected \n(%v)", groupedTracks, result,
+			expected)
+	}
+}
+
+// newGroupRollups returns rollups holding the given rollups of the day before `now`.
+func newGroupRollups(now time.Time, dayRollups ...model.TrackRollup) *Rollups {
+	day := model.FormatRollupDay(now.AddDate(0, 0, -1))
+	for i := range dayRollups {
+		dayRollups[i].Day = day
+	}
+	rollups, _ := NewRollups(MockRollupDAO{map[string][]model.TrackRollup{day: dayRollups}},
+		now.Location())
+	rollups.now = func() time.Time { return now }
+	return rollups
+}
+
+func TestGroupTracksWorker_HandleRequest_Rollups(t *testing.T) {
+	location, _ := time.LoadLocation("Europe/Vienna")
+	now := time.Date(2018, 9, 18, 15, 0, 0, 0, location)
+	startDate, endDate := calculateDayBoundaries(now.AddDate(0, 0, -1))
+	rollups := newGroupRollups(now,
+		model.TrackRollup{StationId: "kronehit", Track: model.Track{"rhcp", "californication"},
+			Plays: 4, LastAirtime: 1537200000,
+			TrackDisplay: model.TrackDisplay{"RHCP", "Californication"}},
+		model.TrackRollup{StationId: "hitradio-oe3", Track: model.Track{"rhcp", "californication"},
+			Plays: 2, LastAirtime: 1537210000,
+			TrackDisplay: model.TrackDisplay{"RHCP", "Californication"}},
+	)
+
+	worker, _ := NewGroupTracksWorker(MockStationGroupDAOSuccess{}, MockTrackRecordDAO{}, rollups,
+		"austria", startDate, endDate, Top, DefaultRanking)
+	result, err := worker.HandleRequest()
+	expected := model.GroupTracks{"austria", startDate, endDate, []model.GroupTrack{
+		{6, map[string]int{"kronehit": 4, "hitradio-oe3": 2},
+			model.Track{"RHCP", "Californication"}},
+	}, time.Unix(1537210000, 0)}
+	if err != nil || !reflect.DeepEqual(result, expected) {
+		t.Errorf("HandleRequest(): got (%v, %v), expected (%v, nil)", result, err, expected)
+	}
+}
@@ -0,0 +1,98 @@
+package request
+
+import (
+	"errors"
+	"github.com/RadioCheckerApp/api/model"
+	"math"
+	"sync"
+	"time"
+)
+
+var ErrRateLimitExceeded = errors.New("rate limit exceeded")
+
+// RateLimitPolicy configures a token bucket: a client may burst up to Capacity requests, the
+// bucket refills at one token per RefillInterval.
+type RateLimitPolicy struct {
+	Capacity       int
+	RefillInterval time.Duration
+}
+
+// bucketSweepInterval is how often the buckets of idle clients are dropped.
+const bucketSweepInterval = time.Minute
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+	// full is the time the bucket will have been refilled completely.
+	full time.Time
+}
+
+// RateLimiter keeps one token bucket per client. Buckets live in memory, hence limits are
+// enforced per Lambda container rather than globally; the API Gateway usage plan remains the
+// global upper bound. Buckets which have been refilled completely equal new ones, hence they are
+// dropped to keep the memory bounded by the clients active recently.
+type RateLimiter struct {
+	policies map[model.Tier]RateLimitPolicy
+	buckets  map[string]*tokenBucket
+	swept    time.Time
+	mutex    sync.Mutex
+	now      func() time.Time
+}
+
+func NewRateLimiter(policies map[model.Tier]RateLimitPolicy) *RateLimiter {
+	return &RateLimiter{policies: policies, buckets: make(map[string]*tokenBucket),
+		now: time.Now}
+}
+
+// Allow counts a request of the client against its tier's limit. Clients of unknown tiers are
+// treated as free clients.
+func (limiter *RateLimiter) Allow(clientID string, tier model.Tier) (model.RateLimit, error) {
+	policy, ok := limiter.policies[tier]
+	if !ok {
+		policy = limiter.policies[model.TierFree]
+	}
+	capacity := float64(policy.Capacity)
+	now := limiter.now()
+
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	if now.Sub(limiter.swept) >= bucketSweepInterval {
+		limiter.sweep(now)
+	}
+
+	bucket, ok := limiter.buckets[clientID]
+	if !ok {
+		bucket = &tokenBucket{capacity, now, now}
+		limiter.buckets[clientID] = bucket
+	}
+
+	refill := float64(now.Sub(bucket.updated)) / float64(policy.RefillInterval)
+	bucket.tokens = math.Min(capacity, bucket.tokens+refill)
+	bucket.updated = now
+
+	var err error
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+	} else {
+		err = ErrRateLimitExceeded
+	}
+
+	untilFull := time.Duration((capacity - bucket.tokens) * float64(policy.RefillInterval))
+	bucket.full = now.Add(untilFull)
+	return model.RateLimit{
+		Limit:     policy.Capacity,
+		Remaining: int(bucket.tokens),
+		Reset:     bucket.full.Unix(),
+	}, err
+}
+
+// sweep drops the buckets refilled completely by now. The caller must hold the mutex.
+func (limiter *RateLimiter) sweep(now time.Time) {
+	for clientID, bucket := range limiter.buckets {
+		if !bucket.full.After(now) {
+			delete(limiter.buckets, clientID)
+		}
+	}
+	limiter.swept = now
+}
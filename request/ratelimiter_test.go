@@ -0,0 +1,74 @@
+package request
+
+import (
+	"github.com/RadioCheckerApp/api/model"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	now := time.Unix(1537701181, 0)
+	limiter := NewRateLimiter(map[model.Tier]RateLimitPolicy{
+		model.TierFree:    {2, time.Second},
+		model.TierPartner: {3, time.Second},
+	})
+	limiter.now = func() time.Time { return now }
+
+	var tests = []struct {
+		clientID       string
+		tier           model.Tier
+		elapsed        time.Duration
+		expectedResult model.RateLimit
+		expectedErr    error
+	}{
+		{"radio-app", model.TierFree, 0, model.RateLimit{2, 1, 1537701182}, nil},
+		{"radio-app", model.TierFree, 0, model.RateLimit{2, 0, 1537701183}, nil},
+		{"radio-app", model.TierFree, 0, model.RateLimit{2, 0, 1537701183}, ErrRateLimitExceeded},
+		// buckets are kept per client
+		{"partner-app", model.TierPartner, 0, model.RateLimit{3, 2, 1537701182}, nil},
+		// unknown tiers fall back to the free tier
+		{"unknown-app", "premium", 0, model.RateLimit{2, 1, 1537701182}, nil},
+		// one token has been refilled
+		{"radio-app", model.TierFree, time.Second, model.RateLimit{2, 0, 1537701184}, nil},
+		{"radio-app", model.TierFree, 0, model.RateLimit{2, 0, 1537701184}, ErrRateLimitExceeded},
+		// the bucket never exceeds its capacity
+		{"radio-app", model.TierFree, time.Hour, model.RateLimit{2, 1, 1537704783}, nil},
+	}
+
+	for i, test := range tests {
+		now = now.Add(test.elapsed)
+		result, err := limiter.Allow(test.clientID, test.tier)
+		if err != test.expectedErr || result != test.expectedResult {
+			t.Errorf("#%d Allow(%q, %q): got (%v, %v), expected (%v, %v)", i, test.clientID,
+				test.tier, result, err, test.expectedResult, test.expectedErr)
+		}
+	}
+}
+
+func TestRateLimiter_Allow_Sweep(t *testing.T) {
+	now := time.Unix(1537701181, 0)
+	limiter := NewRateLimiter(map[model.Tier]RateLimitPolicy{
+		model.TierFree:    {2, time.Second},
+		model.TierPartner: {600, 2 * bucketSweepInterval},
+	})
+	limiter.now = func() time.Time { return now }
+
+	limiter.Allow("radio-app", model.TierFree)
+	limiter.Allow("partner-app", model.TierPartner)
+
+	// the free bucket has been refilled after a second, the partner bucket is still in use
+	now = now.Add(bucketSweepInterval)
+	limiter.Allow("other-app", model.TierFree)
+	if _, ok := limiter.buckets["radio-app"]; ok {
+		t.Error("Allow(): expected the refilled bucket of `radio-app` to be dropped")
+	}
+	if len(limiter.buckets) != 2 {
+		t.Errorf("Allow(): got %d buckets, expected 2", len(limiter.buckets))
+	}
+
+	// a dropped bucket starts full again, just like the refilled one
+	result, err := limiter.Allow("radio-app", model.TierFree)
+	if err != nil || result.Remaining != 1 {
+		t.Errorf("Allow(\"radio-app\"): got (%v, %v), expected 1 remaining", result, err)
+	}
+}
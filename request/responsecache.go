@@ -0,0 +1,238 @@
+package request
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"log"
+	"strings"
+	"time"
+)
+
+// Cacheable is implemented by workers whose result only depends on their parameters and the track
+// records of a period of time.
+type Cacheable interface {
+	Worker
+	// CacheKey identifies the result by the worker's normalized parameters.
+	CacheKey() string
+	// Period returns the first and the last second covered by the result.
+	Period() (startDate, endDate time.Time)
+}
+
+// ResponseCache serves the results of cacheable workers from its backends, which are consulted in
+// order: usually an in-memory cache followed by a DynamoDB table shared by all Lambda containers.
+// The result of a closed period never expires, results covering the present expire after the
+// cache's TTL. Keys are prefixed by the cache's version (see CacheVersion), hence results cached
+// by another version are not served; results of rewritten track records are deleted by Purge.
+type ResponseCache struct {
+	ttl      time.Duration
+	version  string
+	backends []datalayer.ResponseCacheDAO
+	now      func() time.Time
+}
+
+func NewResponseCache(ttl time.Duration, version string,
+	backends ...datalayer.ResponseCacheDAO) (*ResponseCache, error) {
+	if ttl < 0 {
+		return nil, errors.New("ttl must not be negative")
+	}
+	if strings.Contains(version, "/") {
+		return nil, errors.New("version must not contain `/`")
+	}
+	for _, backend := range backends {
+		if backend == nil {
+			return nil, errors.New("backends must not be nil")
+		}
+	}
+	return &ResponseCache{ttl, version, backends, time.Now}, nil
+}
+
+// CacheVersion identifies the data results are derived from by the generation, which is bumped
+// whenever cached results have to be dropped at once, and the normalization rules.
+func CacheVersion(generation int, rules model.NormalizationRules) string {
+	encodedRules, _ := json.Marshal(rules)
+	digest := sha256.Sum256(encodedRules)
+	return fmt.Sprintf("v%d-%s", generation, hex.EncodeToString(digest[:4]))
+}
+
+func (cache *ResponseCache) versionedKey(key string) string {
+	if cache.version == "" {
+		return key
+	}
+	return cache.version + "/" + key
+}
+
+// Purge deletes the cached results `affected` reports true for, e. g. after the track records of
+// a period have been rewritten. `affected` is passed the station and the period of the result;
+// search and group results cover several stations, hence their station is empty. Results cached
+// by any version are considered.
+func (cache *ResponseCache) Purge(affected func(station string, startDate,
+	endDate time.Time) bool) (int, error) {
+	purged := 0
+	for _, backend := range cache.backends {
+		var keys []string
+		err := backend.Keys(func(key string) bool {
+			station, startDate, endDate, ok := parseCacheKey(key)
+			if ok && affected(station, startDate, endDate) {
+				keys = append(keys, key)
+			}
+			return true
+		})
+		if err != nil {
+			return purged, err
+		}
+
+		for _, key := range keys {
+			if err := backend.Delete(key); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// Wrap returns a worker serving the given worker's result from the cache. Workers which are not
+// cacheable are returned unchanged.
+func (cache *ResponseCache) Wrap(worker Worker) Worker {
+	cacheable, ok := worker.(Cacheable)
+	if !ok || len(cache.backends) == 0 {
+		return worker
+	}
+	return CachedWorker{cacheable, cache}
+}
+
+// closed reports whether no more track records are expected for a period ending at endDate.
+// Crawlers report with a short delay, hence a period is closed one TTL after its end.
+func (cache *ResponseCache) closed(endDate time.Time) bool {
+	return cache.now().After(endDate.Add(cache.ttl))
+}
+
+func (cache *ResponseCache) get(key string) (model.CachedResponse, bool) {
+	for i, backend := range cache.backends {
+		response, err := backend.Get(key)
+		if err != nil {
+			if !datalayer.IsNotFound(err) {
+				log.Printf("WARNING: unable to read cached response `%s`: %v", key, err)
+			}
+			continue
+		}
+		if response.Expired(cache.now()) {
+			continue
+		}
+		// fill the faster backends consulted before
+		cache.put(response, cache.backends[:i])
+		return response, true
+	}
+	return model.CachedResponse{}, false
+}
+
+func (cache *ResponseCache) put(response model.CachedResponse,
+	backends []datalayer.ResponseCacheDAO) {
+	for _, backend := range backends {
+		if err := backend.Put(response); err != nil {
+			log.Printf("WARNING: unable to cache response `%s`: %v", response.Key, err)
+		}
+	}
+}
+
+// CachedWorker serves the result of a cacheable worker from a ResponseCache. The result is
+// returned as model.EncodedData, whether it has been served from the cache or not.
+type CachedWorker struct {
+	worker Cacheable
+	cache  *ResponseCache
+}
+
+func (worker CachedWorker) HandleRequest() (interface{}, error) {
+	key := worker.cache.versionedKey(worker.worker.CacheKey())
+	if response, ok := worker.cache.get(key); ok {
+		return newEncodedData(response), nil
+	}
+
+	data, err := worker.worker.HandleRequest()
+	if err != nil {
+		return nil, err
+	}
+	encodedData, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	lastModified := model.LastModified(data)
+
+	maxAge, immutable := worker.MaxAge()
+	if immutable || maxAge > 0 {
+		response := model.CachedResponse{Key: key, Data: string(encodedData)}
+		if !lastModified.IsZero() {
+			response.LastModified = lastModified.Unix()
+		}
+		if !immutable {
+			response.Expires = worker.cache.now().Add(maxAge).Unix()
+		}
+		worker.cache.put(response, worker.cache.backends)
+	}
+	return model.EncodedData{Data: encodedData, LastModified: lastModified}, nil
+}
+
+func newEncodedData(response model.CachedResponse) model.EncodedData {
+	data := model.EncodedData{Data: json.RawMessage(response.Data)}
+	if response.LastModified != 0 {
+		data.LastModified = time.Unix(response.LastModified, 0)
+	}
+	return data
+}
+
+// MaxAge returns how long clients may cache the worker's result. Results of closed periods are
+// immutable.
+func (worker CachedWorker) MaxAge() (maxAge time.Duration, immutable bool) {
+	_, endDate := worker.worker.Period()
+	if worker.cache.closed(endDate) {
+		return 0, true
+	}
+	return worker.cache.ttl, false
+}
+
+// cacheKey joins the normalized parameters of a request into a cache key.
+func cacheKey(params ...string) string {
+	return strings.Join(params, "/")
+}
+
+func formatCacheKeyDate(date time.Time) string {
+	return date.Format(time.RFC3339)
+}
+
+// cacheKeyKinds are the first parameters of the workers' cache keys.
+var cacheKeyKinds = map[string]bool{"tracks": true, "search": true, "airtime": true,
+	"groups": true}
+
+// parseCacheKey extracts the station and the period from a versioned cache key. The station of
+// search and group results is empty. Periods following `week` last seven days, all others a single
+// day.
+func parseCacheKey(key string) (station string, startDate, endDate time.Time, ok bool) {
+	params := strings.Split(key, "/")
+	if len(params) > 0 && !cacheKeyKinds[params[0]] {
+		params = params[1:]
+	}
+	if len(params) < 2 || !cacheKeyKinds[params[0]] {
+		return "", time.Time{}, time.Time{}, false
+	}
+	if params[0] != "search" && params[0] != "groups" {
+		station = params[1]
+	}
+
+	for i := 2; i < len(params); i++ {
+		date, err := time.Parse(time.RFC3339, params[i])
+		if err != nil {
+			continue
+		}
+		days := 1
+		if params[i-1] == "week" {
+			days = 7
+		}
+		return station, date, date.AddDate(0, 0, days).Add(-time.Second), true
+	}
+	return "", time.Time{}, time.Time{}, false
+}
@@ -0,0 +1,326 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// MockCacheableWorker counts how often its result is computed.
+type MockCacheableWorker struct {
+	key       string
+	startDate time.Time
+	endDate   time.Time
+	err       error
+	calls     *int
+}
+
+func (worker MockCacheableWorker) HandleRequest() (interface{}, error) {
+	*worker.calls++
+	if worker.err != nil {
+		return nil, worker.err
+	}
+	return map[string]int{"calls": *worker.calls}, nil
+}
+
+func (worker MockCacheableWorker) CacheKey() string {
+	return worker.key
+}
+
+func (worker MockCacheableWorker) Period() (time.Time, time.Time) {
+	return worker.startDate, worker.endDate
+}
+
+// MockResponseCacheDAOFail fails to read and store responses.
+type MockResponseCacheDAOFail struct{}
+
+func (dao MockResponseCacheDAOFail) Get(key string) (model.CachedResponse, error) {
+	return model.CachedResponse{}, errors.New("database error")
+}
+
+func (dao MockResponseCacheDAOFail) Put(response model.CachedResponse) error {
+	return errors.New("database error")
+}
+
+func (dao MockResponseCacheDAOFail) Delete(key string) error {
+	return errors.New("database error")
+}
+
+func (dao MockResponseCacheDAOFail) Keys(fn func(key string) bool) error {
+	return errors.New("database error")
+}
+
+func TestNewResponseCache(t *testing.T) {
+	memoryCache, _ := datalayer.NewMemoryResponseCacheDAO(10)
+
+	var tests = []struct {
+		ttl         time.Duration
+		version     string
+		backends    []datalayer.ResponseCacheDAO
+		expectedErr bool
+	}{
+		{time.Minute, "v1-0a1b2c3d", []datalayer.ResponseCacheDAO{memoryCache}, false},
+		{time.Minute, "", []datalayer.ResponseCacheDAO{memoryCache}, false},
+		{0, "", nil, false},
+		{-time.Minute, "", []datalayer.ResponseCacheDAO{memoryCache}, true},
+		{time.Minute, "", []datalayer.ResponseCacheDAO{memoryCache, nil}, true},
+		{time.Minute, "v1/tracks", []datalayer.ResponseCacheDAO{memoryCache}, true},
+	}
+
+	for _, test := range tests {
+		_, err := NewResponseCache(test.ttl, test.version, test.backends...)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("NewResponseCache(%v, %q, %v): got err (%v), expected err: %v", test.ttl,
+				test.version, test.backends, err, test.expectedErr)
+		}
+	}
+}
+
+func TestResponseCache_Wrap(t *testing.T) {
+	memoryCache, _ := datalayer.NewMemoryResponseCacheDAO(10)
+	cache, _ := NewResponseCache(time.Minute, "", memoryCache)
+	disabledCache, _ := NewResponseCache(time.Minute, "")
+	cacheable := MockCacheableWorker{key: "key", calls: new(int)}
+
+	if _, ok := cache.Wrap(cacheable).(CachedWorker); !ok {
+		t.Errorf("Wrap(%v): expected CachedWorker", cacheable)
+	}
+	if _, ok := cache.Wrap(MetaWorker{}).(MetaWorker); !ok {
+		t.Error("Wrap(MetaWorker{}): expected the worker to be returned unchanged")
+	}
+	if _, ok := disabledCache.Wrap(cacheable).(MockCacheableWorker); !ok {
+		t.Errorf("Wrap(%v): expected the worker to be returned unchanged without backends",
+			cacheable)
+	}
+}
+
+func TestCachedWorker_HandleRequest(t *testing.T) {
+	now := time.Date(2018, 9, 23, 12, 0, 0, 0, time.UTC)
+	today := time.Date(2018, 9, 23, 0, 0, 0, 0, time.UTC)
+	yesterday := today.AddDate(0, 0, -1)
+
+	memoryCache, _ := datalayer.NewMemoryResponseCacheDAO(10)
+	sharedCache, _ := datalayer.NewMemoryResponseCacheDAO(10)
+	cache, _ := NewResponseCache(time.Minute, "v1", MockResponseCacheDAOFail{}, memoryCache,
+		sharedCache)
+	cache.now = func() time.Time { return now }
+
+	calls := 0
+	var tests = []struct {
+		elapsed           time.Duration
+		worker            MockCacheableWorker
+		expectedData      string
+		expectedCalls     int
+		expectedMaxAge    time.Duration
+		expectedImmutable bool
+		expectedErr       bool
+	}{
+		// closed periods never expire
+		{0, MockCacheableWorker{"yesterday", yesterday, today.Add(-time.Second), nil, &calls},
+			`{"calls":1}`, 1, 0, true, false},
+		{24 * time.Hour, MockCacheableWorker{"yesterday", yesterday, today.Add(-time.Second),
+			nil, &calls}, `{"calls":1}`, 1, 0, true, false},
+		// the current period expires after the TTL
+		{0, MockCacheableWorker{"today", today.AddDate(0, 0, 1), today.AddDate(0, 0, 2), nil,
+			&calls}, `{"calls":2}`, 2, time.Minute, false, false},
+		{30 * time.Second, MockCacheableWorker{"today", today.AddDate(0, 0, 1),
+			today.AddDate(0, 0, 2), nil, &calls}, `{"calls":2}`, 2, time.Minute, false, false},
+		{time.Minute, MockCacheableWorker{"today", today.AddDate(0, 0, 1), today.AddDate(0, 0, 2),
+			nil, &calls}, `{"calls":3}`, 3, time.Minute, false, false},
+		// errors are not cached
+		{0, MockCacheableWorker{"error", yesterday, yesterday, errors.New("error"), &calls},
+			"", 4, 0, true, true},
+		{0, MockCacheableWorker{"error", yesterday, yesterday, errors.New("error"), &calls},
+			"", 5, 0, true, true},
+	}
+
+	for i, test := range tests {
+		now = now.Add(test.elapsed)
+		worker := cache.Wrap(test.worker).(CachedWorker)
+
+		result, err := worker.HandleRequest()
+		if (err != nil) != test.expectedErr {
+			t.Errorf("#%d HandleRequest(): got err (%v), expected err: %v", i, err,
+				test.expectedErr)
+			continue
+		}
+		if calls != test.expectedCalls {
+			t.Errorf("#%d HandleRequest(): got %d calls of the worker, expected %d", i, calls,
+				test.expectedCalls)
+		}
+		maxAge, immutable := worker.MaxAge()
+		if maxAge != test.expectedMaxAge || immutable != test.expectedImmutable {
+			t.Errorf("#%d MaxAge(): got (%v, %v), expected (%v, %v)", i, maxAge, immutable,
+				test.expectedMaxAge, test.expectedImmutable)
+		}
+		if err != nil {
+			continue
+		}
+		if data, ok := result.(model.EncodedData); !ok || string(data.Data) != test.expectedData {
+			t.Errorf("#%d HandleRequest(): got (%s), expected (%s)", i, result, test.expectedData)
+		}
+	}
+
+	// responses found in the shared cache fill the memory cache
+	sharedCache.Put(model.CachedResponse{Key: "v1/shared", Data: `{"shared":true}`,
+		LastModified: yesterday.Unix()})
+	shared := MockCacheableWorker{"shared", yesterday, yesterday, nil, &calls}
+	expected := model.EncodedData{json.RawMessage(`{"shared":true}`),
+		time.Unix(yesterday.Unix(), 0)}
+	if result, _ := cache.Wrap(shared).HandleRequest(); !reflect.DeepEqual(result, expected) {
+		t.Errorf("HandleRequest(): got (%v), expected response of shared cache (%v)", result,
+			expected)
+	}
+	if _, err := memoryCache.Get("v1/shared"); err != nil {
+		t.Errorf("memoryCache.Get(\"v1/shared\"): got err (%v), expected cached response", err)
+	}
+
+	// responses cached by other versions are not served
+	sharedCache.Put(model.CachedResponse{Key: "v0/outdated", Data: `{"outdated":true}`,
+		LastModified: yesterday.Unix()})
+	outdated := MockCacheableWorker{"outdated", yesterday, yesterday, nil, &calls}
+	if result, _ := cache.Wrap(outdated).HandleRequest(); reflect.DeepEqual(result.(model.
+		EncodedData).Data, json.RawMessage(`{"outdated":true}`)) {
+		t.Error("HandleRequest(): got response cached by another version, expected worker result")
+	}
+}
+
+func TestCacheVersion(t *testing.T) {
+	rules := model.NormalizationRules{Common: []model.NormalizationRule{{Type: "alias",
+		Field: "artist", Aliases: map[string]string{"pink": "p!nk"}}}}
+	version := CacheVersion(1, rules)
+
+	if !strings.HasPrefix(version, "v1-") || strings.Contains(version, "/") {
+		t.Errorf("CacheVersion(1, %v): got %q, expected `v1-<digest>`", rules, version)
+	}
+	if other := CacheVersion(2, rules); other == version {
+		t.Errorf("CacheVersion(2, %v): got %q, expected version to change", rules, other)
+	}
+	if other := CacheVersion(1, model.NormalizationRules{}); other == version {
+		t.Errorf("CacheVersion(1, {}): got %q, expected version to change", other)
+	}
+	if same := CacheVersion(1, rules); same != version {
+		t.Errorf("CacheVersion(1, %v): got %q, expected %q", rules, same, version)
+	}
+}
+
+func TestParseCacheKey(t *testing.T) {
+	location, _ := time.LoadLocation("Europe/Vienna")
+	monday := time.Date(2018, 9, 17, 0, 0, 0, 0, location)
+	wednesday := time.Date(2018, 9, 19, 0, 0, 0, 0, location)
+
+	var tests = []struct {
+		key               string
+		expectedStation   string
+		expectedStartDate time.Time
+		expectedEndDate   time.Time
+		expectedOk        bool
+	}{
+		{"tracks/fm4/day/2018-09-19T00:00:00+02:00/top/3-3", "fm4", wednesday,
+			wednesday.AddDate(0, 0, 1).Add(-time.Second), true},
+		{"v1-0a1b2c3d/tracks/fm4/week/2018-09-17T00:00:00+02:00/all/3-3", "fm4", monday,
+			monday.AddDate(0, 0, 7).Add(-time.Second), true},
+		{"v1/search/ed+sheeran/day/2018-09-19T00:00:00+02:00", "", wednesday,
+			wednesday.AddDate(0, 0, 1).Add(-time.Second), true},
+		{"v1/airtime/fm4/2018-09-19T00:00:00+02:00", "fm4", wednesday,
+			wednesday.AddDate(0, 0, 1).Add(-time.Second), true},
+		{"v1/groups/austria/week/2018-09-17T00:00:00+02:00/top/3-3", "", monday,
+			monday.AddDate(0, 0, 7).Add(-time.Second), true},
+		{"v1/tracks/fm4/day/yesterday/top/3-3", "", time.Time{}, time.Time{}, false},
+		{"v1/unknown/fm4/day/2018-09-19T00:00:00+02:00", "", time.Time{}, time.Time{}, false},
+		{"", "", time.Time{}, time.Time{}, false},
+	}
+
+	for _, test := range tests {
+		station, startDate, endDate, ok := parseCacheKey(test.key)
+		if station != test.expectedStation || !startDate.Equal(test.expectedStartDate) ||
+			!endDate.Equal(test.expectedEndDate) || ok != test.expectedOk {
+			t.Errorf("parseCacheKey(%q): got (%q, %v, %v, %v), expected (%q, %v, %v, %v)",
+				test.key, station, startDate, endDate, ok, test.expectedStation,
+				test.expectedStartDate, test.expectedEndDate, test.expectedOk)
+		}
+	}
+}
+
+func TestResponseCache_Purge(t *testing.T) {
+	day := time.Date(2018, 9, 19, 0, 0, 0, 0, time.UTC)
+	keys := []string{
+		"v1/tracks/fm4/day/2018-09-19T00:00:00Z/top/3-3",
+		"v0/tracks/fm4/week/2018-09-17T00:00:00Z/all/3-3",
+		"v1/tracks/oe3/day/2018-09-19T00:00:00Z/top/3-3",
+		"v1/tracks/fm4/day/2018-09-20T00:00:00Z/top/3-3",
+		"v1/search/ed+sheeran/day/2018-09-19T00:00:00Z",
+		"v1/airtime/fm4/2018-09-18T00:00:00Z",
+		"v1/groups/austria/week/2018-09-17T00:00:00Z/top/3-3",
+	}
+	memoryCache, _ := datalayer.NewMemoryResponseCacheDAO(10)
+	for _, key := range keys {
+		memoryCache.Put(model.CachedResponse{Key: key, Data: "{}"})
+	}
+	cache, _ := NewResponseCache(time.Minute, "v1", memoryCache)
+
+	// purges the results of fm4 and all stations overlapping with 2018-09-19
+	purged, err := cache.Purge(func(station string, startDate, endDate time.Time) bool {
+		return (station == "fm4" || station == "") && !startDate.After(day) &&
+			!endDate.Before(day)
+	})
+	if purged != 4 || err != nil {
+		t.Errorf("Purge(): got (%d, %v), expected (4, nil)", purged, err)
+	}
+	for i, key := range keys {
+		_, err := memoryCache.Get(key)
+		if expectedPurged := i == 0 || i == 1 || i == 4 || i == 6; datalayer.IsNotFound(err) !=
+			expectedPurged {
+			t.Errorf("Get(%q): got err (%v), expected purged: %v", key, err, expectedPurged)
+		}
+	}
+
+	failingCache, _ := NewResponseCache(time.Minute, "v1", MockResponseCacheDAOFail{})
+	if _, err := failingCache.Purge(func(string, time.Time, time.Time) bool {
+		return true
+	}); err == nil {
+		t.Error("Purge(): got no error, expected error of failing backend")
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	location, _ := time.LoadLocation("Europe/Vienna")
+	monday := time.Date(2018, 9, 17, 0, 0, 0, 0, location)
+	wednesday := time.Date(2018, 9, 19, 15, 0, 0, 0, location)
+
+	dayWorker, _ := NewDayTracksWorker(MockTrackRecordDAO{}, nil, "fm4", wednesday, Top, DefaultRanking)
+	weekWorker, _ := NewWeekTracksWorker(MockTrackRecordDAO{}, nil, "fm4", wednesday, All,
+		time.Monday, DefaultRanking)
+	mondayWeekWorker, _ := NewWeekTracksWorker(MockTrackRecordDAO{}, nil, "fm4", monday, All,
+		time.Monday, DefaultRanking)
+	searchWorker, _ := NewDaySearchWorker(MockTrackRecordDAO{}, nil, "Ed+Sheeran", "", wednesday)
+	dayStart, dayEnd := calculateDayBoundaries(wednesday)
+	groupDayWorker, _ := NewGroupTracksWorker(MockStationGroupDAOSuccess{}, MockTrackRecordDAO{},
+		nil, "austria", dayStart, dayEnd, Top, DefaultRanking)
+	weekStart, weekEnd := calculateWeekBoundaries(wednesday, time.Monday)
+	groupWeekWorker, _ := NewGroupTracksWorker(MockStationGroupDAOSuccess{}, MockTrackRecordDAO{},
+		nil, "austria", weekStart, weekEnd, All, DefaultRanking)
+
+	var tests = []struct {
+		worker      Cacheable
+		expectedKey string
+	}{
+		{dayWorker, "tracks/fm4/day/2018-09-19T00:00:00+02:00/top/3-3"},
+		{weekWorker, "tracks/fm4/week/2018-09-17T00:00:00+02:00/all/3-3"},
+		{mondayWeekWorker, "tracks/fm4/week/2018-09-17T00:00:00+02:00/all/3-3"},
+		{searchWorker, "search/ed+sheeran/day/2018-09-19T00:00:00+02:00"},
+		{groupDayWorker, "groups/austria/day/2018-09-19T00:00:00+02:00/top/3-3"},
+		{groupWeekWorker, "groups/austria/week/2018-09-17T00:00:00+02:00/all/3-3"},
+	}
+
+	for _, test := range tests {
+		if key := test.worker.CacheKey(); key != test.expectedKey {
+			t.Errorf("(%v).CacheKey(): got %q, expected %q", test.worker, key, test.expectedKey)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package request
+
+import (
+	"errors"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"time"
+)
+
+// Rollups maintains the daily play counts of the stations' tracks. Rollups are calculated in a
+// fixed location, which has to match the location of the requested days and weeks; after changing
+// the timezone the rollups have to be rebuilt.
+type Rollups struct {
+	dao      datalayer.RollupDAO
+	location *time.Location
+	now      func() time.Time
+}
+
+func NewRollups(dao datalayer.RollupDAO, location *time.Location) (*Rollups, error) {
+	if dao == nil {
+		return nil, errors.New("dao must not be nil")
+	}
+	if location == nil {
+		return nil, errors.New("location must not be nil")
+	}
+	return &Rollups{dao, location, time.Now}, nil
+}
+
+// Add counts the play of the track record in the rollups of its day.
+func (rollups *Rollups) Add(trackRecord model.TrackRecord) error {
+	day := model.FormatRollupDay(time.Unix(trackRecord.Timestamp, 0).In(rollups.location))
+	return rollups.dao.AddTrackRecord(day, trackRecord)
+}
+
+// Rebuild recalculates the rollups of the station's day containing `date` from its track records
+// and returns them.
+func (rollups *Rollups) Rebuild(trDAO datalayer.TrackRecordDAO, station string,
+	date time.Time) ([]model.TrackRollup, error) {
+	startDate, endDate := calculateDayBoundaries(date.In(rollups.location))
+	trackRecords, err := trDAO.GetTrackRecordsByStation(station, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	day := model.FormatRollupDay(startDate)
+	dayRollups := model.RollUp(day, trackRecords)
+	if err := rollups.dao.ReplaceRollups(station, day, dayRollups); err != nil {
+		return nil, err
+	}
+	return dayRollups, nil
+}
+
+type period struct {
+	startDate time.Time
+	endDate   time.Time
+}
+
+// split divides the period between `startDate` and `endDate` into the days which are covered
+// completely and have ended, and the remaining periods, i. e. the current day and days covered
+// partially. Adjacent remaining periods are joined.
+func (rollups *Rollups) split(startDate, endDate time.Time) ([]string, []period) {
+	days := make([]string, 0)
+	remainder := make([]period, 0)
+	now := rollups.now()
+
+	dayStart, _ := calculateDayBoundaries(startDate.In(rollups.location))
+	for !dayStart.After(endDate) {
+		nextDayStart := dayStart.AddDate(0, 0, 1)
+		dayEnd := nextDayStart.Add(-1 * time.Second)
+
+		if !dayStart.Before(startDate) && !dayEnd.After(endDate) && dayEnd.Before(now) {
+			days = append(days, model.FormatRollupDay(dayStart))
+			dayStart = nextDayStart
+			continue
+		}
+
+		p := period{maxTime(dayStart, startDate), minTime(dayEnd, endDate)}
+		if last := len(remainder) - 1; last >= 0 &&
+			remainder[last].endDate.Add(time.Second).Equal(p.startDate) {
+			remainder[last].endDate = p.endDate
+		} else {
+			remainder = append(remainder, p)
+		}
+		dayStart = nextDayStart
+	}
+	return days, remainder
+}
+
+// countPlays returns the plays of each station's tracks between `startDate` and `endDate`; an
+// empty station counts the plays of all stations. Complete days are read from the rollups, the
+// remaining period from the track records. Without rollups all plays are counted from the track
+// records.
+func countPlays(trDAO datalayer.TrackRecordDAO, rollups *Rollups, station string, startDate,
+	endDate time.Time) ([]model.TrackRollup, error) {
+	if startDate.After(endDate) {
+		return nil, errors.New("startDate must be before endDate")
+	}
+
+	days := []string{}
+	remainder := []period{{startDate, endDate}}
+	if rollups != nil {
+		days, remainder = rollups.split(startDate, endDate)
+	}
+
+	counted := make([]model.TrackRollup, 0)
+	for _, day := range days {
+		dayRollups, err := rollups.get(station, day)
+		if err != nil {
+			return nil, err
+		}
+		counted = append(counted, dayRollups...)
+	}
+
+	for _, p := range remainder {
+		var trackRecords []model.TrackRecord
+		var err error
+		if station == "" {
+			trackRecords, err = trDAO.GetTrackRecords(p.startDate, p.endDate)
+		} else {
+			trackRecords, err = trDAO.GetTrackRecordsByStation(station, p.startDate, p.endDate)
+		}
+		if err != nil {
+			return nil, err
+		}
+		counted = append(counted, model.RollUp("", trackRecords)...)
+	}
+
+	return mergeRollups(counted), nil
+}
+
+func (rollups *Rollups) get(station, day string) ([]model.TrackRollup, error) {
+	if station == "" {
+		return rollups.dao.GetRollups(day)
+	}
+	return rollups.dao.GetRollupsByStation(station, day)
+}
+
+// mergeRollups sums up the plays of the same station and track over several days.
+func mergeRollups(rollups []model.TrackRollup) []model.TrackRollup {
+	type mergeKey struct {
+		stationId string
+		track     model.Track
+	}
+
+	indices := make(map[mergeKey]int)
+	merged := make([]model.TrackRollup, 0)
+	for _, rollup := range rollups {
+		key := mergeKey{rollup.StationId, rollup.Track}
+		i, ok := indices[key]
+		if !ok {
+			indices[key] = len(merged)
+			rollup.Day = ""
+			merged = append(merged, rollup)
+			continue
+		}
+		merged[i].Plays += rollup.Plays
+		if rollup.LastAirtime > merged[i].LastAirtime {
+			merged[i].LastAirtime = rollup.LastAirtime
+		}
+	}
+	return merged
+}
+
+// findLastPlayed returns the time of the most recent play, the zero time if there are no plays.
+func findLastPlayed(rollups []model.TrackRollup) time.Time {
+	var newest int64
+	for _, rollup := range rollups {
+		if rollup.LastAirtime > newest {
+			newest = rollup.LastAirtime
+		}
+	}
+	if newest == 0 {
+		return time.Time{}
+	}
+	return time.Unix(newest, 0)
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
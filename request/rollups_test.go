@@ -0,0 +1,247 @@
+package request
+
+import (
+	"errors"
+	"github.com/RadioCheckerApp/api/model"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// MockRollupDAO holds rollups in memory; the day `2018-09-16` fails.
+type MockRollupDAO struct {
+	rollups map[string][]model.TrackRollup
+}
+
+func newMockRollupDAO() MockRollupDAO {
+	return MockRollupDAO{map[string][]model.TrackRollup{
+		"2018-09-17": {
+			{"2018-09-17", "fm4", model.Track{"RHCP", "Californication"}, 5, 1537200000,
+				model.TrackDisplay{}},
+			{"2018-09-17", "oe3", model.Track{"RHCP", "Californication"}, 2, 1537210000,
+				model.TrackDisplay{}},
+		},
+		"2018-09-18": {
+			{"2018-09-18", "fm4", model.Track{"RHCP", "Californication"}, 1, 1537290000,
+				model.TrackDisplay{}},
+			{"2018-09-18", "fm4", model.Track{"MØ", "Final Song"}, 2, 1537280000,
+				model.TrackDisplay{}},
+		},
+	}}
+}
+
+func (dao MockRollupDAO) GetRollups(day string) ([]model.TrackRollup, error) {
+	if day == "2018-09-16" {
+		return nil, errors.New("database error")
+	}
+	return append([]model.TrackRollup{}, dao.rollups[day]...), nil
+}
+
+func (dao MockRollupDAO) GetRollupsByStation(station, day string) ([]model.TrackRollup, error) {
+	rollups, err := dao.GetRollups(day)
+	stationRollups := make([]model.TrackRollup, 0)
+	for _, rollup := range rollups {
+		if rollup.StationId == station {
+			stationRollups = append(stationRollups, rollup)
+		}
+	}
+	return stationRollups, err
+}
+
+func (dao MockRollupDAO) AddTrackRecord(day string, trackRecord model.TrackRecord) error {
+	for i, rollup := range dao.rollups[day] {
+		if rollup.StationId == trackRecord.StationId && rollup.Track == trackRecord.Track {
+			dao.rollups[day][i].Plays++
+			return nil
+		}
+	}
+	dao.rollups[day] = append(dao.rollups[day], model.TrackRollup{day, trackRecord.StationId,
+		trackRecord.Track, 1, trackRecord.Timestamp, trackRecord.TrackDisplay})
+	return nil
+}
+
+func (dao MockRollupDAO) ReplaceRollups(station, day string, rollups []model.TrackRollup) error {
+	dao.rollups[day] = rollups
+	return nil
+}
+
+func newTestRollups(now time.Time) *Rollups {
+	rollups, _ := NewRollups(newMockRollupDAO(), now.Location())
+	rollups.now = func() time.Time { return now }
+	return rollups
+}
+
+func TestNewRollups(t *testing.T) {
+	if _, err := NewRollups(nil, time.UTC); err == nil {
+		t.Error("NewRollups(nil, UTC): got no error, expected error")
+	}
+	if _, err := NewRollups(newMockRollupDAO(), nil); err == nil {
+		t.Error("NewRollups(dao, nil): got no error, expected error")
+	}
+}
+
+func TestRollups_split(t *testing.T) {
+	location, _ := time.LoadLocation("Europe/Vienna")
+	now := time.Date(2018, 9, 19, 15, 0, 0, 0, location)
+	rollups := newTestRollups(now)
+	day := func(d int) time.Time {
+		return time.Date(2018, 9, d, 0, 0, 0, 0, location)
+	}
+
+	var tests = []struct {
+		startDate         time.Time
+		endDate           time.Time
+		expectedDays      []string
+		expectedRemainder []period
+	}{
+		// past day
+		{day(18), day(19).Add(-time.Second), []string{"2018-09-18"}, []period{}},
+		// current day
+		{day(19), day(20).Add(-time.Second), []string{},
+			[]period{{day(19), day(20).Add(-time.Second)}}},
+		// current week: the current and future days are joined
+		{day(17), day(24).Add(-time.Second), []string{"2018-09-17", "2018-09-18"},
+			[]period{{day(19), day(24).Add(-time.Second)}}},
+		// days covered partially
+		{day(17).Add(12 * time.Hour), day(18).Add(6 * time.Hour), []string{},
+			[]period{{day(17).Add(12 * time.Hour), day(18).Add(6 * time.Hour)}}},
+	}
+
+	for i, test := range tests {
+		days, remainder := rollups.split(test.startDate, test.endDate)
+		if !reflect.DeepEqual(days, test.expectedDays) ||
+			!reflect.DeepEqual(remainder, test.expectedRemainder) {
+			t.Errorf("#%d split(%v, %v): got (%v, %v), expected (%v, %v)", i, test.startDate,
+				test.endDate, days, remainder, test.expectedDays, test.expectedRemainder)
+		}
+	}
+}
+
+func TestCountPlays(t *testing.T) {
+	location, _ := time.LoadLocation("Europe/Vienna")
+	now := time.Date(2018, 9, 19, 15, 0, 0, 0, location)
+	rollups := newTestRollups(now)
+	startDate := time.Date(2018, 9, 17, 0, 0, 0, 0, location)
+
+	var tests = []struct {
+		rollups     *Rollups
+		station     string
+		startDate   time.Time
+		endDate     time.Time
+		expected    map[model.Track]int
+		expectedErr bool
+	}{
+		// complete days from the rollups, the current day from the track records
+		{rollups, "fm4", startDate, startDate.AddDate(0, 0, 3).Add(-time.Second),
+			map[model.Track]int{
+				{"RHCP", "Californication"}:         5 + 1 + 3,
+				{"MØ", "Final Song"}:                2,
+				{"Jonas Blue, Jack & Jack", "Rise"}: 2,
+				{"Cardi B", "I Like It"}:            1,
+			}, false},
+		{rollups, "", startDate, startDate.AddDate(0, 0, 1).Add(-time.Second),
+			map[model.Track]int{
+				{"RHCP", "Californication"}: 5 + 2,
+			}, false},
+		// without rollups all plays are read from the track records
+		{nil, "fm4", startDate, startDate.AddDate(0, 0, 3).Add(-time.Second),
+			map[model.Track]int{
+				{"RHCP", "Californication"}:         3,
+				{"Jonas Blue, Jack & Jack", "Rise"}: 2,
+				{"Cardi B", "I Like It"}:            1,
+			}, false},
+		{rollups, "fm4", startDate.AddDate(0, 0, -1), startDate.Add(-time.Second), nil, true},
+		{rollups, "fm4", startDate, startDate.Add(-time.Second), nil, true},
+	}
+
+	for i, test := range tests {
+		plays, err := countPlays(MockTrackRecordDAO{}, test.rollups, test.station, test.startDate,
+			test.endDate)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("#%d countPlays(): got err (%v), expected err: %v", i, err, test.expectedErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		result := make(map[model.Track]int)
+		for _, rollup := range plays {
+			result[rollup.Track] += rollup.Plays
+		}
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("#%d countPlays(): got %v, expected %v", i, result, test.expected)
+		}
+	}
+}
+
+func TestRollups_Rebuild(t *testing.T) {
+	location, _ := time.LoadLocation("Europe/Vienna")
+	date := time.Date(2018, 9, 17, 15, 0, 0, 0, location)
+	rollups := newTestRollups(date)
+
+	result, err := rollups.Rebuild(MockTrackRecordDAO{}, "fm4", date)
+	if err != nil {
+		t.Fatalf("Rebuild(): got err (%v), expected nil", err)
+	}
+	stored, _ := rollups.dao.GetRollupsByStation("fm4", "2018-09-17")
+	if len(result) != 3 || !reflect.DeepEqual(result, stored) {
+		t.Errorf("Rebuild(): got %v and stored %v, expected 3 stored rollups", result, stored)
+	}
+
+	if _, err := rollups.Rebuild(MockTrackRecordDAOWeekVerifier{}, "nevermind", date); err == nil {
+		t.Error("Rebuild(): got no error, expected error of the track record DAO")
+	}
+}
+
+func TestCreateTrackWorker_HandleRequest_Rollups(t *testing.T) {
+	location, _ := time.LoadLocation("Europe/Vienna")
+	now := time.Now().In(location)
+	rollups := newTestRollups(now)
+	trackRecord := model.TrackRecord{StationId: "kronehit", Timestamp: now.Unix(), Type: "track",
+		Track: model.Track{"RHCP", "Californication"}}
+
+	stations := newTestStationCache(MockStationDAOSuccess{})
+	worker, _ := NewCreateTrackWorker(MockTrackRecordDAO{}, stations, rollups, trackRecord,
+		model.DefaultTrackRecordRules, DefaultDuplicatePolicy)
+	if _, err := worker.HandleRequest(); err != nil {
+		t.Fatalf("HandleRequest(): got err (%v), expected nil", err)
+	}
+
+	day := model.FormatRollupDay(now)
+	stored, _ := rollups.dao.GetRollupsByStation("kronehit", day)
+	expected := []model.TrackRollup{{day, "kronehit", model.Track{"rhcp", "californication"}, 1,
+		now.Unix(), model.TrackDisplay{"RHCP", "Californication"}}}
+	if !reflect.DeepEqual(stored, expected) {
+		t.Errorf("HandleRequest(): got rollups %v, expected %v", stored, expected)
+	}
+
+	// other types than tracks are no plays
+	jingle := model.TrackRecord{StationId: "kronehit", Timestamp: now.Unix() + 1, Type: "jingle"}
+	worker, _ = NewCreateTrackWorker(MockTrackRecordDAO{}, stations, rollups, jingle,
+		model.DefaultTrackRecordRules, DefaultDuplicatePolicy)
+	if result, err := worker.HandleRequest(); err != nil {
+		t.Fatalf("HandleRequest(): got (%v, %v), expected jingle to be created", result, err)
+	}
+	if stored, _ = rollups.dao.GetRollupsByStation("kronehit", day); !reflect.DeepEqual(stored,
+		expected) {
+		t.Errorf("HandleRequest(): got rollups %v after jingle, expected %v", stored, expected)
+	}
+}
+
+func TestFindLastPlayed(t *testing.T) {
+	var tests = []struct {
+		rollups  []model.TrackRollup
+		expected time.Time
+	}{
+		{[]model.TrackRollup{}, time.Time{}},
+		{[]model.TrackRollup{{LastAirtime: 1537701181}, {LastAirtime: 1537704781},
+			{LastAirtime: 1537700000}}, time.Unix(1537704781, 0)},
+	}
+
+	for _, test := range tests {
+		if result := findLastPlayed(test.rollups); !result.Equal(test.expected) {
+			t.Errorf("findLastPlayed(%v): got %v, expected %v", test.rollups, result,
+				test.expected)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"github.com/RadioCheckerApp/api/datalayer"
 	"github.com/RadioCheckerApp/api/model"
+	"sort"
 	"strings"
 	"time"
 )
@@ -12,83 +13,115 @@ const queryStrKeywordsSeparator = "+"
 
 type groupedTracksContainer map[model.Track]map[string]int
 
+// SearchWorker finds the tracks matching any of the keywords, ignoring diacritics (see
+// model.FoldKey). If an artist is given, only tracks crediting the artist (see
+// model.Track.CreditsArtist) match; the keywords may be omitted then.
 type SearchWorker struct {
 	dao      datalayer.TrackRecordDAO
+	rollups  *Rollups
 	keywords []string
+	artist   string
 }
 
-func NewSearchWorker(dao datalayer.TrackRecordDAO, query string) (SearchWorker, error) {
+func NewSearchWorker(dao datalayer.TrackRecordDAO, query, artist string) (SearchWorker, error) {
 	if dao == nil {
 		return SearchWorker{}, errors.New("dao must not be nil")
 	}
-	if query == "" {
+	query, artist = model.FoldKey(query), model.FoldKey(artist)
+	if query == "" && artist == "" {
 		return SearchWorker{}, errors.New("query must not be empty")
 	}
-	lowercaseQuery := strings.ToLower(query)
-	return SearchWorker{dao, strings.Split(lowercaseQuery, queryStrKeywordsSeparator)}, nil
+	var keywords []string
+	if query != "" {
+		keywords = strings.Split(query, queryStrKeywordsSeparator)
+	}
+	return SearchWorker{dao, nil, keywords, artist}, nil
 }
 
 func (worker SearchWorker) Search(startDate, endDate time.Time) (model.MatchedTracks, error) {
-	trackRecords, err := worker.dao.GetTrackRecords(startDate, endDate)
+	plays, err := countPlays(worker.dao, worker.rollups, "", startDate, endDate)
 	if err != nil {
 		return model.MatchedTracks{}, err
 	}
 
-	matchedTrackRecords := worker.findMatchingTrackRecords(trackRecords)
-	if len(matchedTrackRecords) == 0 {
+	matchedPlays := worker.findMatchingPlays(plays)
+	if len(matchedPlays) == 0 {
 		return model.MatchedTracks{
 			startDate,
 			endDate,
 			[]model.MatchedTrack{},
+			time.Time{},
 		}, nil
 	}
 
-	stationIDs := extractStationIDs(matchedTrackRecords)
+	stationIDs := extractStationIDs(matchedPlays)
 
 	groupedTracks := make(groupedTracksContainer)
 
-	for _, trackRecord := range matchedTrackRecords {
-		if _, ok := groupedTracks[trackRecord.Track]; !ok {
-			groupedTracks[trackRecord.Track] = newStationsMap(stationIDs)
+	for _, rollup := range matchedPlays {
+		if _, ok := groupedTracks[rollup.Track]; !ok {
+			groupedTracks[rollup.Track] = newStationsMap(stationIDs)
 		}
-		groupedTracks[trackRecord.Track][trackRecord.StationId]++
+		groupedTracks[rollup.Track][rollup.StationId] += rollup.Plays
+	}
+
+	casings := make(model.Casings)
+	casings.AddRollups(matchedPlays)
+	matchedTracks := buildResultStructure(groupedTracks)
+	for i := range matchedTracks {
+		matchedTracks[i].Track = casings.Display(matchedTracks[i].Track)
 	}
 
 	return model.MatchedTracks{
 		startDate,
 		endDate,
-		buildResultStructure(groupedTracks),
+		matchedTracks,
+		findLastPlayed(matchedPlays),
 	}, nil
 }
 
-func (worker SearchWorker) findMatchingTrackRecords(trackRecords []model.TrackRecord) []model.
-	TrackRecord {
-	matchedTrackRecords := make([]model.TrackRecord, 0)
+func (worker SearchWorker) findMatchingPlays(plays []model.TrackRollup) []model.TrackRollup {
+	matchedPlays := make([]model.TrackRollup, 0)
 
-	for _, trackRecord := range trackRecords {
-		if worker.trackRecordMatchesQuery(trackRecord) {
-			matchedTrackRecords = append(matchedTrackRecords, trackRecord)
+	for _, rollup := range plays {
+		if worker.trackMatchesQuery(rollup.Track) {
+			matchedPlays = append(matchedPlays, rollup)
 		}
 	}
 
-	return matchedTrackRecords
+	return matchedPlays
 }
 
-func (worker SearchWorker) trackRecordMatchesQuery(trackRecord model.TrackRecord) bool {
-	title := strings.ToLower(trackRecord.Title)
-	artist := strings.ToLower(trackRecord.Artist)
+func (worker SearchWorker) trackMatchesQuery(track model.Track) bool {
+	if worker.artist != "" && !track.CreditsArtist(worker.artist) {
+		return false
+	}
+	if len(worker.keywords) == 0 {
+		return true
+	}
+
+	folded := track.FoldedKey()
 	for _, keyword := range worker.keywords {
-		if strings.Contains(title, keyword) || strings.Contains(artist, keyword) {
+		if strings.Contains(folded.Title, keyword) || strings.Contains(folded.Artist, keyword) {
 			return true
 		}
 	}
 	return false
 }
 
-func extractStationIDs(trackRecords []model.TrackRecord) []string {
+// cacheKeyQuery identifies the keywords and the artist searched for.
+func (worker SearchWorker) cacheKeyQuery() string {
+	query := strings.Join(worker.keywords, queryStrKeywordsSeparator)
+	if worker.artist != "" {
+		query += "&artist=" + worker.artist
+	}
+	return query
+}
+
+func extractStationIDs(plays []model.TrackRollup) []string {
 	groupedStationIDs := make(map[string]bool)
-	for _, trackRecord := range trackRecords {
-		groupedStationIDs[trackRecord.StationId] = true
+	for _, rollup := range plays {
+		groupedStationIDs[rollup.StationId] = true
 	}
 
 	stationIDs := make([]string, len(groupedStationIDs))
@@ -115,5 +148,8 @@ func buildResultStructure(groupedTracks groupedTracksContainer) []model.MatchedT
 		matchedTracks[i] = model.MatchedTrack{countsByStation, track}
 		i++
 	}
+	sort.Slice(matchedTracks, func(i, j int) bool {
+		return lessTrack(matchedTracks[i].Track, matchedTracks[j].Track)
+	})
 	return matchedTracks
 }
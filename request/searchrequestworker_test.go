@@ -4,33 +4,34 @@ import (
 	"github.com/RadioCheckerApp/api/datalayer"
 	"github.com/RadioCheckerApp/api/model"
 	"reflect"
-	"strings"
 	"testing"
 	"time"
 )
 
 func TestNewSearchWorker(t *testing.T) {
 	var tests = []struct {
-		dao         datalayer.TrackRecordDAO
-		queryStr    string
-		expectedErr bool
+		dao              datalayer.TrackRecordDAO
+		queryStr         string
+		expectedKeywords []string
+		expectedErr      bool
 	}{
-		{MockTrackRecordDAO{}, "The+Adventures+Of+Rain+Dance+Maggie", false},
-		{nil, "The+Adventures+Of+Rain+Dance+Maggie", true},
-		{MockTrackRecordDAO{}, "", true},
+		{MockTrackRecordDAO{}, "The+Adventures+Of+Rain+Dance+Maggie",
+			[]string{"the", "adventures", "of", "rain", "dance", "maggie"}, false},
+		{MockTrackRecordDAO{}, "Beyonc\u0065\u0301+Ｍø+Don’t\u200b Stop",
+			[]string{"beyonce", "mo", "don't stop"}, false},
+		{nil, "The+Adventures+Of+Rain+Dance+Maggie", nil, true},
+		{MockTrackRecordDAO{}, "", nil, true},
+		{MockTrackRecordDAO{}, " \u3000 ", nil, true},
 	}
 
 	for _, test := range tests {
-		result, err := NewSearchWorker(test.dao, test.queryStr)
+		result, err := NewSearchWorker(test.dao, test.queryStr, "")
 		if (err != nil) != test.expectedErr {
 			t.Errorf("TestNewSearchWorker(%q, %q): got err (%v), expected err: %v",
 				test.dao, test.queryStr, err, test.expectedErr)
 			continue
 		}
-		expectedResult := SearchWorker{
-			test.dao,
-			strings.Split(strings.ToLower(test.queryStr), queryStrKeywordsSeparator),
-		}
+		expectedResult := SearchWorker{test.dao, nil, test.expectedKeywords, ""}
 		if err == nil && !reflect.DeepEqual(result, expectedResult) {
 			t.Errorf("TestNewSearchWorker(%q, %q): got result (%v), expected (%v)",
 				test.dao, test.queryStr, result, expectedResult)
@@ -47,6 +48,7 @@ var matchedTracks0 = model.MatchedTracks{
 			model.Track{"RHCP", "Californication"},
 		},
 	},
+	time.Time{},
 }
 
 var matchedTracks1 = model.MatchedTracks{
@@ -62,6 +64,7 @@ var matchedTracks1 = model.MatchedTracks{
 			model.Track{"RHCP", "Dani California"},
 		},
 	},
+	time.Time{},
 }
 
 var matchedTracks2 = model.MatchedTracks{
@@ -81,6 +84,7 @@ var matchedTracks2 = model.MatchedTracks{
 			model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"},
 		},
 	},
+	time.Time{},
 }
 
 var matchedTracks3 = model.MatchedTracks{
@@ -92,6 +96,7 @@ var matchedTracks3 = model.MatchedTracks{
 			model.Track{"MØ", "Final Song"},
 		},
 	},
+	time.Time{},
 }
 
 func TestSearchWorker_Search(t *testing.T) {
@@ -118,52 +123,54 @@ func TestSearchWorker_Search(t *testing.T) {
 		expectedErr    bool
 	}{
 		{
-			SearchWorker{MockTrackRecordDAO{}, []string{"californication"}},
+			SearchWorker{MockTrackRecordDAO{}, nil, []string{"californication"}, ""},
 			startDate,
 			endDate,
 			matchedTracks0,
 			false,
 		},
 		{
-			SearchWorker{MockTrackRecordDAO{}, []string{"cali"}},
+			SearchWorker{MockTrackRecordDAO{}, nil, []string{"cali"}, ""},
 			startDate,
 			endDate,
 			matchedTracks1,
 			false,
 		},
 		{
-			SearchWorker{MockTrackRecordDAO{}, []string{"maggie", "rhcp"}},
+			SearchWorker{MockTrackRecordDAO{}, nil, []string{"maggie", "rhcp"}, ""},
 			startDate,
 			endDate,
 			matchedTracks2,
 			false,
 		},
 		{
-			SearchWorker{MockTrackRecordDAO{}, []string{"ø"}},
+			SearchWorker{MockTrackRecordDAO{}, nil, []string{"mo"}, ""},
 			startDate,
 			endDate,
 			matchedTracks3,
 			false,
 		},
 		{
-			SearchWorker{MockTrackRecordDAO{}, []string{"no", "tracks", "query"}},
+			SearchWorker{MockTrackRecordDAO{}, nil, []string{"no", "tracks", "query"}, ""},
 			startDate,
 			endDate,
 			model.MatchedTracks{
 				startDate,
 				endDate,
 				[]model.MatchedTrack{},
+				time.Time{},
 			},
 			false,
 		},
 		{
-			SearchWorker{MockTrackRecordDAO{}, []string{""}},
+			SearchWorker{MockTrackRecordDAO{}, nil, []string{""}, ""},
 			endDate,
 			startDate,
 			model.MatchedTracks{
 				startDate,
 				endDate,
 				[]model.MatchedTrack{},
+				time.Time{},
 			},
 			true,
 		},
@@ -172,7 +179,7 @@ func TestSearchWorker_Search(t *testing.T) {
 	for _, test := range tests {
 		result, err := test.worker.Search(test.startDate, test.endDate)
 		if (err != nil) != test.expectedErr {
-			t.Errorf("(%q).Search(%v, %v): got err (%v), expected err: %v",
+			t.Errorf("(%v).Search(%v, %v): got err (%v), expected err: %v",
 				test.worker, test.startDate, test.endDate, err, test.expectedErr)
 			continue
 		}
@@ -185,12 +192,12 @@ func TestSearchWorker_Search(t *testing.T) {
 
 		if result.StartDate != test.expectedResult.StartDate ||
 			result.EndDate != test.expectedResult.EndDate {
-			t.Errorf("(%q).Search(%v, %v): got result startdate: %v / enddate: %v",
+			t.Errorf("(%v).Search(%v, %v): got result startdate: %v / enddate: %v",
 				test.worker, test.startDate, test.endDate, result.StartDate, result.EndDate)
 		}
 
 		if len(result.MatchedTracks) != len(test.expectedResult.MatchedTracks) {
-			t.Errorf("(%q).Search(%v, %v): got result length (%d), expected (%d)",
+			t.Errorf("(%v).Search(%v, %v): got result length (%d), expected (%d)",
 				test.worker, test.startDate, test.endDate, len(result.MatchedTracks),
 				len(test.expectedResult.MatchedTracks))
 		}
@@ -204,10 +211,53 @@ func TestSearchWorker_Search(t *testing.T) {
 				}
 			}
 			if !match {
-				t.Errorf("(%q).Search(%v, %v): expected item (%q) is not element of result (%q)",
+				t.Errorf("(%v).Search(%v, %v): expected item (%q) is not element of result (%q)",
 					test.worker, test.startDate, test.endDate, expectedMatchedTrack,
 					test.expectedResult.MatchedTracks)
 			}
 		}
 	}
 }
+
+func TestSearchWorker_Search_Artist(t *testing.T) {
+	dao := datalayer.NewMemoryTrackRecordDAO(
+		model.TrackRecord{StationId: "fm4", Timestamp: 1537700000, Type: "track",
+			Track: model.Track{"nico & vinz feat. kid ink", "that's how you know"}},
+		model.TrackRecord{StationId: "oe3", Timestamp: 1537701000, Type: "track",
+			Track: model.Track{"ed sheeran", "south of the border (feat. kid ink)"}},
+		model.TrackRecord{StationId: "oe3", Timestamp: 1537702000, Type: "track",
+			Track: model.Track{"kid cudi", "pursuit of happiness"}},
+		model.TrackRecord{StationId: "oe3", Timestamp: 1537703000, Type: "track",
+			Track: model.Track{"beyoncé", "déjà vu"}},
+	)
+	startDate, endDate := time.Unix(1537600000, 0), time.Unix(1537800000, 0)
+
+	var tests = []struct {
+		query    string
+		artist   string
+		expected []model.Track
+	}{
+		{"", "Kid Ink", []model.Track{{"ed sheeran", "south of the border (feat. kid ink)"},
+			{"nico & vinz feat. kid ink", "that's how you know"}}},
+		{"border", "kid ink", []model.Track{{"ed sheeran", "south of the border (feat. kid ink)"}}},
+		{"", "kid", []model.Track{}},
+		{"kid", "", []model.Track{{"ed sheeran", "south of the border (feat. kid ink)"},
+			{"kid cudi", "pursuit of happiness"}, {"nico & vinz feat. kid ink", "that's how you know"}}},
+		// diacritics are ignored
+		{"deja", "Beyonce", []model.Track{{"beyoncé", "déjà vu"}}},
+		{"", "BEYONCÉ", []model.Track{{"beyoncé", "déjà vu"}}},
+	}
+
+	for _, test := range tests {
+		worker, _ := NewSearchWorker(dao, test.query, test.artist)
+		result, err := worker.Search(startDate, endDate)
+		tracks := make([]model.Track, 0)
+		for _, matchedTrack := range result.MatchedTracks {
+			tracks = append(tracks, matchedTrack.Track)
+		}
+		if err != nil || !reflect.DeepEqual(tracks, test.expected) {
+			t.Errorf("Search(q: %q, artist: %q): got (%v, %v), expected (%v, nil)", test.query,
+				test.artist, tracks, err, test.expected)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package request
+
+import (
+	"fmt"
+	"github.com/RadioCheckerApp/api/model"
+	"log"
+	"time"
+)
+
+// Ranking bounds the charts served by the `top` filter.
+type Ranking struct {
+	// TopRanks is the number of distinct play counts served.
+	TopRanks int
+	// MinPlays serves the complete chart if no track has been played more often.
+	MinPlays int
+}
+
+var DefaultRanking = Ranking{TopRanks: 3, MinPlays: 3}
+
+func (ranking Ranking) String() string {
+	return fmt.Sprintf("%d-%d", ranking.TopRanks, ranking.MinPlays)
+}
+
+// DuplicatePolicy detects track records reporting a play which has been recorded already with a
+// slightly different airtime, e. g. by a crawler polling twice.
+type DuplicatePolicy struct {
+	// Window is the maximum distance between the airtimes of duplicates, 0 disables the detection.
+	Window time.Duration
+	// Merge accepts duplicates without recording them again, the metadata and display form the
+	// recorded track record lacks are taken from the duplicate; otherwise duplicates are rejected.
+	Merge bool
+}
+
+var DefaultDuplicatePolicy = DuplicatePolicy{Window: 5 * time.Minute}
+
+// Settings carries the configurable behaviour the factories pass on to the workers they create.
+type Settings struct {
+	// Location is the timezone days and weeks are calculated in.
+	Location         *time.Location
+	Ranking          Ranking
+	TrackRecordRules model.TrackRecordRules
+	Duplicates       DuplicatePolicy
+}
+
+func DefaultSettings() Settings {
+	location, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		log.Fatal("unable to load timezone location `Europe/Berlin`")
+	}
+	return Settings{location, DefaultRanking, model.DefaultTrackRecordRules,
+		DefaultDuplicatePolicy}
+}
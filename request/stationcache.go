@@ -0,0 +1,102 @@
+package request
+
+import (
+	"errors"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"sync"
+	"time"
+)
+
+var ErrUnknownStation = errors.New("invalid stationId provided")
+var ErrInactiveStation = errors.New("station is not active")
+
+// missRefreshInterval throttles the refreshes triggered by unknown stations, so a crawler
+// reporting a misspelled station does not scan the stations table on every request.
+const missRefreshInterval = 10 * time.Second
+
+// StationCache keeps the active flag of all stations in memory. The cache is shared by all
+// requests served by a Lambda container, it is refreshed after its TTL has expired and whenever an
+// unknown station is looked up, so that newly created stations are accepted right away.
+type StationCache struct {
+	dao       datalayer.StationDAO
+	ttl       time.Duration
+	mutex     sync.RWMutex
+	active    map[string]bool
+	refreshed time.Time
+	now       func() time.Time
+}
+
+func NewStationCache(dao datalayer.StationDAO, ttl time.Duration) (*StationCache, error) {
+	if dao == nil {
+		return nil, errors.New("dao must not be nil")
+	}
+	if ttl < 0 {
+		return nil, errors.New("ttl must not be negative")
+	}
+	return &StationCache{dao: dao, ttl: ttl, now: time.Now}, nil
+}
+
+// CheckActive returns nil if the station exists and is active, ErrUnknownStation or
+// ErrInactiveStation otherwise. Errors of the underlying DAO are passed on.
+func (cache *StationCache) CheckActive(stationId string) error {
+	active, known, fresh := cache.lookup(stationId)
+	if !fresh || (!known && cache.missRefreshAllowed()) {
+		var err error
+		if active, known, err = cache.refresh(stationId); err != nil {
+			return err
+		}
+	}
+
+	if !known {
+		return ErrUnknownStation
+	}
+	if !active {
+		return ErrInactiveStation
+	}
+	return nil
+}
+
+func (cache *StationCache) lookup(stationId string) (active, known, fresh bool) {
+	cache.mutex.RLock()
+	defer cache.mutex.RUnlock()
+	active, known = cache.active[stationId]
+	fresh = cache.active != nil && cache.now().Sub(cache.refreshed) < cache.ttl
+	return active, known, fresh
+}
+
+func (cache *StationCache) missRefreshAllowed() bool {
+	cache.mutex.RLock()
+	defer cache.mutex.RUnlock()
+	return cache.now().Sub(cache.refreshed) >= missRefreshInterval
+}
+
+// refresh reloads all stations and returns the state of the requested station. Concurrent
+// refreshes are serialized; the DAO is not queried again if another request refreshed the cache
+// in the meantime.
+func (cache *StationCache) refresh(stationId string) (active, known bool, err error) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	start := cache.now()
+	if cache.active != nil && start.Sub(cache.refreshed) < cache.ttl {
+		if active, known = cache.active[stationId]; known {
+			return active, known, nil
+		}
+		if start.Sub(cache.refreshed) < missRefreshInterval {
+			return false, false, nil
+		}
+	}
+
+	stations, err := cache.dao.GetAll()
+	if err != nil {
+		return false, false, err
+	}
+
+	cache.active = make(map[string]bool, len(stations))
+	for _, station := range stations {
+		cache.active[station.ID] = station.Active
+	}
+	cache.refreshed = start
+	active, known = cache.active[stationId]
+	return active, known, nil
+}
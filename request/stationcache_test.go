@@ -0,0 +1,106 @@
+package request
+
+import (
+	"errors"
+	"github.com/RadioCheckerApp/api/model"
+	"sync"
+	"testing"
+	"time"
+)
+
+// MockStationDAOCounting serves the stations it holds and counts the calls of GetAll.
+type MockStationDAOCounting struct {
+	MockStationDAOSuccessEmpty
+	stations []model.Station
+	err      error
+	calls    int
+}
+
+func (dao *MockStationDAOCounting) GetAll() ([]model.Station, error) {
+	dao.calls++
+	return dao.stations, dao.err
+}
+
+func TestNewStationCache(t *testing.T) {
+	if _, err := NewStationCache(nil, time.Minute); err == nil {
+		t.Error("NewStationCache(nil, 1m): got no error, expected error")
+	}
+	if _, err := NewStationCache(MockStationDAOSuccess{}, -time.Minute); err == nil {
+		t.Error("NewStationCache(dao, -1m): got no error, expected error")
+	}
+	if cache, err := NewStationCache(MockStationDAOSuccess{}, 0); cache == nil || err != nil {
+		t.Errorf("NewStationCache(dao, 0): got (%v, %v), expected cache", cache, err)
+	}
+}
+
+func TestStationCache_CheckActive(t *testing.T) {
+	dao := &MockStationDAOCounting{stations: []model.Station{
+		{ID: "kronehit", Active: true},
+		{ID: "hitradio-oe3", Active: false},
+	}}
+	cache, _ := NewStationCache(dao, 5*time.Minute)
+	now := time.Unix(1537701181, 0)
+	cache.now = func() time.Time { return now }
+
+	var tests = []struct {
+		elapsed       time.Duration
+		stationId     string
+		addStation    string
+		daoErr        error
+		expectedErr   error
+		expectedCalls int
+	}{
+		// first lookup populates the cache
+		{0, "kronehit", "", nil, nil, 1},
+		{time.Minute, "kronehit", "", nil, nil, 1},
+		{0, "hitradio-oe3", "", nil, ErrInactiveStation, 1},
+		// unknown stations trigger a refresh, throttled to one per missRefreshInterval
+		{time.Second, "fm4", "", nil, ErrUnknownStation, 2},
+		{time.Second, "fm4", "", nil, ErrUnknownStation, 2},
+		{time.Second, "fm4", "fm4", nil, ErrUnknownStation, 2},
+		{missRefreshInterval, "fm4", "", nil, nil, 3},
+		// expired entries are refreshed, errors are passed on
+		{5 * time.Minute, "kronehit", "", errors.New("database error"), errors.New("database error"), 4},
+		{0, "kronehit", "", nil, nil, 5},
+	}
+
+	for i, test := range tests {
+		now = now.Add(test.elapsed)
+		if test.addStation != "" {
+			dao.stations = append(dao.stations, model.Station{ID: test.addStation, Active: true})
+		}
+		dao.err = test.daoErr
+
+		err := cache.CheckActive(test.stationId)
+		if (err == nil) != (test.expectedErr == nil) ||
+			(err != nil && err.Error() != test.expectedErr.Error()) {
+			t.Errorf("#%d CheckActive(%q): got err (%v), expected err (%v)", i, test.stationId, err,
+				test.expectedErr)
+		}
+		if dao.calls != test.expectedCalls {
+			t.Errorf("#%d CheckActive(%q): got %d calls of GetAll, expected %d", i,
+				test.stationId, dao.calls, test.expectedCalls)
+		}
+	}
+}
+
+func TestStationCache_CheckActive_Concurrent(t *testing.T) {
+	dao := &MockStationDAOCounting{stations: []model.Station{{ID: "kronehit", Active: true}}}
+	cache, _ := NewStationCache(dao, time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := cache.CheckActive("kronehit"); err != nil {
+				t.Errorf("CheckActive(\"kronehit\"): got err (%v), expected nil", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if dao.calls != 1 {
+		t.Errorf("CheckActive(): got %d calls of GetAll, expected 1", dao.calls)
+	}
+}
@@ -0,0 +1,23 @@
+package request
+
+import (
+	"errors"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+)
+
+type StationGroupsWorker struct {
+	dao datalayer.StationGroupDAO
+}
+
+func NewStationGroupsWorker(dao datalayer.StationGroupDAO) (StationGroupsWorker, error) {
+	if dao == nil {
+		return StationGroupsWorker{}, errors.New("dao must not be nil")
+	}
+	return StationGroupsWorker{dao}, nil
+}
+
+func (worker StationGroupsWorker) HandleRequest() (interface{}, error) {
+	groups, err := worker.dao.GetAll()
+	return model.StationGroups{groups}, err
+}
@@ -0,0 +1,121 @@
+package request
+
+import (
+	"errors"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"reflect"
+	"testing"
+)
+
+type MockStationGroupDAOSuccess struct{}
+
+func (dao MockStationGroupDAOSuccess) GetAll() ([]model.StationGroup, error) {
+	return []model.StationGroup{
+		{"austria", "Austria", "", []string{"kronehit", "hitradio-oe3"}},
+	}, nil
+}
+
+func (dao MockStationGroupDAOSuccess) Get(groupId string) (model.StationGroup, error) {
+	groups, _ := dao.GetAll()
+	for _, group := range groups {
+		if group.ID == groupId {
+			return group, nil
+		}
+	}
+	return model.StationGroup{}, datalayer.NewNotFoundError("group does not exist")
+}
+
+func (dao MockStationGroupDAOSuccess) Create(group model.StationGroup) error {
+	if _, err := dao.Get(group.ID); err == nil {
+		return datalayer.NewAlreadyExistsError("group already exists")
+	}
+	return nil
+}
+
+func (dao MockStationGroupDAOSuccess) Update(group model.StationGroup) error {
+	_, err := dao.Get(group.ID)
+	return err
+}
+
+func (dao MockStationGroupDAOSuccess) Delete(groupId string) error {
+	_, err := dao.Get(groupId)
+	return err
+}
+
+type MockStationGroupDAOFail struct{}
+
+func (dao MockStationGroupDAOFail) GetAll() ([]model.StationGroup, error) {
+	return nil, errors.New("error")
+}
+
+func (dao MockStationGroupDAOFail) Get(groupId string) (model.StationGroup, error) {
+	return model.StationGroup{}, errors.New("error")
+}
+
+func (dao MockStationGroupDAOFail) Create(group model.StationGroup) error {
+	return errors.New("error")
+}
+
+func (dao MockStationGroupDAOFail) Update(group model.StationGroup) error {
+	return errors.New("error")
+}
+
+func (dao MockStationGroupDAOFail) Delete(groupId string) error {
+	return errors.New("error")
+}
+
+func TestNewStationGroupsWorker(t *testing.T) {
+	var tests = []struct {
+		dao         datalayer.StationGroupDAO
+		expectedErr bool
+	}{
+		{MockStationGroupDAOSuccess{}, false},
+		{nil, true},
+	}
+
+	for _, test := range tests {
+		result, err := NewStationGroupsWorker(test.dao)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("NewStationGroupsWorker(%v): got err (%v), expected err: %v",
+				test.dao, err, test.expectedErr)
+			continue
+		}
+		expectedResult := StationGroupsWorker{test.dao}
+		if err == nil && !reflect.DeepEqual(result, expectedResult) {
+			t.Errorf("NewStationGroupsWorker(%v): got result (%v), expected (%v)",
+				test.dao, result, expectedResult)
+		}
+	}
+}
+
+func TestStationGroupsWorker_HandleRequest(t *testing.T) {
+	var tests = []struct {
+		worker         StationGroupsWorker
+		expectedResult model.StationGroups
+		expectedErr    bool
+	}{
+		{
+			StationGroupsWorker{MockStationGroupDAOSuccess{}},
+			model.StationGroups{[]model.StationGroup{
+				{"austria", "Austria", "", []string{"kronehit", "hitradio-oe3"}},
+			}},
+			false,
+		},
+		{StationGroupsWorker{MockStationGroupDAOFail{}}, model.StationGroups{}, true},
+	}
+
+	for _, test := range tests {
+		result, err := test.worker.HandleRequest()
+		if (err != nil) != test.expectedErr {
+			t.Errorf("(%v).HandleRequest(): got err (%v), expected err: %v",
+				test.worker, err, test.expectedErr)
+			continue
+		}
+
+		if err == nil && !reflect.DeepEqual(result, test.expectedResult) {
+			t.Errorf("(%v).HandleRequest(): got (%v), expected (%v)",
+				test.worker, result, test.expectedResult)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package request
+
+import (
+	"errors"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"time"
+)
+
+type StationStatusWorker struct {
+	stationDAO     datalayer.StationDAO
+	trackRecordDAO datalayer.TrackRecordDAO
+	stationId      string
+	location       *time.Location
+}
+
+func NewStationStatusWorker(sDAO datalayer.StationDAO, trDAO datalayer.TrackRecordDAO,
+	stationId string, location *time.Location) (StationStatusWorker, error) {
+	if sDAO == nil || trDAO == nil {
+		return StationStatusWorker{}, errors.New("daos must not be nil")
+	}
+	if stationId == "" {
+		return StationStatusWorker{}, errors.New("stationId must not be empty")
+	}
+	if location == nil {
+		return StationStatusWorker{}, errors.New("location must not be nil")
+	}
+	return StationStatusWorker{sDAO, trDAO, stationId, location}, nil
+}
+
+func (worker StationStatusWorker) HandleRequest() (interface{}, error) {
+	station, err := worker.stationDAO.Get(worker.stationId)
+	if err != nil {
+		return nil, err
+	}
+
+	startDate, endDate := calculateDayBoundaries(time.Now().In(worker.location))
+	trackRecordsToday, err := worker.trackRecordDAO.GetTrackRecordsByStation(station.ID,
+		startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	status := model.StationStatus{Station: station, PlaysToday: len(trackRecordsToday)}
+
+	mostRecentTrackRecord, err := worker.trackRecordDAO.GetMostRecentTrackRecordByStation(station.ID)
+	if err != nil && !datalayer.IsNotFound(err) {
+		return nil, err
+	}
+	status.LastSeen = mostRecentTrackRecord.Timestamp
+
+	return status, nil
+}
@@ -0,0 +1,113 @@
+package request
+
+import (
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"reflect"
+	"testing"
+)
+
+func TestNewStationStatusWorker(t *testing.T) {
+	location := DefaultSettings().Location
+
+	var tests = []struct {
+		sDAO        datalayer.StationDAO
+		trDAO       datalayer.TrackRecordDAO
+		stationId   string
+		expectedErr bool
+	}{
+		{MockStationDAOSuccess{}, MockTrackRecordDAO{}, "kronehit", false},
+		{nil, MockTrackRecordDAO{}, "kronehit", true},
+		{MockStationDAOSuccess{}, nil, "kronehit", true},
+		{MockStationDAOSuccess{}, MockTrackRecordDAO{}, "", true},
+	}
+
+	if _, err := NewStationStatusWorker(MockStationDAOSuccess{}, MockTrackRecordDAO{}, "kronehit",
+		nil); err == nil {
+		t.Error("NewStationStatusWorker(..., nil): got no error, expected error")
+	}
+
+	for _, test := range tests {
+		result, err := NewStationStatusWorker(test.sDAO, test.trDAO, test.stationId, location)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("NewStationStatusWorker(%v, %v, %q): got err (%v), expected err: %v",
+				test.sDAO, test.trDAO, test.stationId, err, test.expectedErr)
+			continue
+		}
+		expectedResult := StationStatusWorker{test.sDAO, test.trDAO, test.stationId, location}
+		if err == nil && !reflect.DeepEqual(result, expectedResult) {
+			t.Errorf("NewStationStatusWorker(%v, %v, %q): got result (%v), expected (%v)",
+				test.sDAO, test.trDAO, test.stationId, result, expectedResult)
+		}
+	}
+}
+
+type MockStationDAONoTracks struct {
+	MockStationDAOSuccess
+}
+
+func (dao MockStationDAONoTracks) Get(stationId string) (model.Station, error) {
+	return model.Station{ID: "notracksstation", Name: "No Tracks", Active: true}, nil
+}
+
+func TestStationStatusWorker_HandleRequest(t *testing.T) {
+	location := DefaultSettings().Location
+
+	var tests = []struct {
+		worker         StationStatusWorker
+		expectedResult model.StationStatus
+		expectedErr    bool
+	}{
+		{
+			StationStatusWorker{MockStationDAOSuccess{}, MockTrackRecordDAO{}, "kronehit",
+				location},
+			model.StationStatus{
+				model.Station{ID: "kronehit", Name: "Kronehit", Description: "We are the most music",
+					Active: true},
+				1234567890,
+				6,
+			},
+			false,
+		},
+		{
+			StationStatusWorker{MockStationDAONoTracks{}, MockTrackRecordDAO{}, "notracksstation",
+				location},
+			model.StationStatus{
+				model.Station{ID: "notracksstation", Name: "No Tracks", Active: true},
+				0,
+				0,
+			},
+			false,
+		},
+		{
+			StationStatusWorker{MockStationDAOSuccess{}, MockTrackRecordDAO{}, "unknown",
+				location},
+			model.StationStatus{},
+			true,
+		},
+		{
+			StationStatusWorker{MockStationDAOFail{}, MockTrackRecordDAO{}, "kronehit",
+				location},
+			model.StationStatus{},
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		result, err := test.worker.HandleRequest()
+		if (err != nil) != test.expectedErr {
+			t.Errorf("(%v).HandleRequest(): got err (%v), expected err: %v",
+				test.worker, err, test.expectedErr)
+			continue
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if !reflect.DeepEqual(result, test.expectedResult) {
+			t.Errorf("(%v).HandleRequest(): got (%v), expected (%v)",
+				test.worker, result, test.expectedResult)
+		}
+	}
+}
@@ -4,20 +4,53 @@ import (
 	"errors"
 	"github.com/RadioCheckerApp/api/datalayer"
 	"github.com/RadioCheckerApp/api/model"
+	"strings"
 )
 
 type StationsWorker struct {
-	dao datalayer.StationDAO
+	dao    datalayer.StationDAO
+	filter StationsFilter
 }
 
-func NewStationsWorker(dao datalayer.StationDAO) (StationsWorker, error) {
+// StationsFilter restricts the served stations. Unset criteria match every station.
+type StationsFilter struct {
+	Active   *bool
+	Country  string
+	Genre    string
+	Language string
+}
+
+func NewStationsWorker(dao datalayer.StationDAO, filter StationsFilter) (StationsWorker, error) {
 	if dao == nil {
 		return StationsWorker{}, errors.New("dao must not be nil")
 	}
-	return StationsWorker{dao}, nil
+	return StationsWorker{dao, filter}, nil
 }
 
 func (worker StationsWorker) HandleRequest() (interface{}, error) {
 	stations, err := worker.dao.GetAll()
-	return model.Stations{stations}, err
+	if err != nil {
+		return model.Stations{stations}, err
+	}
+
+	filteredStations := make([]model.Station, 0, len(stations))
+	for _, station := range stations {
+		if worker.filter.matches(station) {
+			filteredStations = append(filteredStations, station)
+		}
+	}
+	return model.Stations{filteredStations}, nil
+}
+
+func (filter StationsFilter) matches(station model.Station) bool {
+	if filter.Active != nil && *filter.Active != station.Active {
+		return false
+	}
+	if filter.Country != "" && !strings.EqualFold(filter.Country, station.Country) {
+		return false
+	}
+	if filter.Language != "" && !strings.EqualFold(filter.Language, station.Language) {
+		return false
+	}
+	return filter.Genre == "" || station.HasGenre(filter.Genre)
 }
@@ -12,23 +12,84 @@ type MockStationDAOSuccess struct{}
 
 func (dao MockStationDAOSuccess) GetAll() ([]model.Station, error) {
 	return []model.Station{
-		{"kronehit", "Kronehit", "We are the most music", true},
-		{"hitradio-oe3", "Hitradio Ö3", "", false},
+		{ID: "kronehit", Name: "Kronehit", Description: "We are the most music", Active: true},
+		{ID: "hitradio-oe3", Name: "Hitradio Ö3"},
 	}, nil
 }
 
+func (dao MockStationDAOSuccess) Get(stationId string) (model.Station, error) {
+	stations, _ := dao.GetAll()
+	for _, station := range stations {
+		if station.ID == stationId {
+			return station, nil
+		}
+	}
+	return model.Station{}, errors.New("station does not exist")
+}
+
+func (dao MockStationDAOSuccess) Create(station model.Station) error {
+	if _, err := dao.Get(station.ID); err == nil {
+		return datalayer.NewAlreadyExistsError("station already exists")
+	}
+	return nil
+}
+
+func (dao MockStationDAOSuccess) Update(station model.Station) error {
+	_, err := dao.Get(station.ID)
+	return err
+}
+
+func (dao MockStationDAOSuccess) Deactivate(stationId string) error {
+	_, err := dao.Get(stationId)
+	return err
+}
+
 type MockStationDAOSuccessEmpty struct{}
 
 func (dao MockStationDAOSuccessEmpty) GetAll() ([]model.Station, error) {
 	return []model.Station{}, nil
 }
 
+func (dao MockStationDAOSuccessEmpty) Get(stationId string) (model.Station, error) {
+	return model.Station{}, errors.New("station does not exist")
+}
+
+func (dao MockStationDAOSuccessEmpty) Create(station model.Station) error {
+	return nil
+}
+
+func (dao MockStationDAOSuccessEmpty) Update(station model.Station) error {
+	return errors.New("station does not exist")
+}
+
+func (dao MockStationDAOSuccessEmpty) Deactivate(stationId string) error {
+	return errors.New("station does not exist")
+}
+
 type MockStationDAOFail struct{}
 
 func (dao MockStationDAOFail) GetAll() ([]model.Station, error) {
 	return nil, errors.New("error")
 }
 
+func (dao MockStationDAOFail) Get(stationId string) (model.Station, error) {
+	return model.Station{}, errors.New("error")
+}
+
+func (dao MockStationDAOFail) Create(station model.Station) error {
+	return errors.New("error")
+}
+
+func (dao MockStationDAOFail) Update(station model.Station) error {
+	return errors.New("error")
+}
+
+func (dao MockStationDAOFail) Deactivate(stationId string) error {
+	return errors.New("error")
+}
+
+var activeTrue, activeFalse = true, false
+
 func TestNewStationsWorker(t *testing.T) {
 	var tests = []struct {
 		dao         datalayer.StationDAO
@@ -39,13 +100,13 @@ func TestNewStationsWorker(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		result, err := NewStationsWorker(test.dao)
+		result, err := NewStationsWorker(test.dao, StationsFilter{})
 		if (err != nil) != test.expectedErr {
 			t.Errorf("TestNewStationsWorker(%q): got err (%v), expected err: %v",
 				test.dao, err, test.expectedErr)
 			continue
 		}
-		expectedResult := StationsWorker{test.dao}
+		expectedResult := StationsWorker{test.dao, StationsFilter{}}
 		if err == nil && !reflect.DeepEqual(result, expectedResult) {
 			t.Errorf("TestNewStationsWorker(%q): got result (%v), expected (%v)",
 				test.dao, result, expectedResult)
@@ -60,22 +121,40 @@ func TestStationsWorker_HandleRequest(t *testing.T) {
 		expectedErr    bool
 	}{
 		{
-			StationsWorker{MockStationDAOSuccess{}},
+			StationsWorker{MockStationDAOSuccess{}, StationsFilter{}},
+			model.Stations{
+				[]model.Station{
+					{ID: "kronehit", Name: "Kronehit", Description: "We are the most music", Active: true},
+					{ID: "hitradio-oe3", Name: "Hitradio Ö3"},
+				},
+			},
+			false,
+		},
+		{
+			StationsWorker{MockStationDAOSuccess{}, StationsFilter{Active: &activeTrue}},
+			model.Stations{
+				[]model.Station{
+					{ID: "kronehit", Name: "Kronehit", Description: "We are the most music", Active: true},
+				},
+			},
+			false,
+		},
+		{
+			StationsWorker{MockStationDAOSuccess{}, StationsFilter{Active: &activeFalse}},
 			model.Stations{
 				[]model.Station{
-					{"kronehit", "Kronehit", "We are the most music", true},
-					{"hitradio-oe3", "Hitradio Ö3", "", false},
+					{ID: "hitradio-oe3", Name: "Hitradio Ö3"},
 				},
 			},
 			false,
 		},
 		{
-			StationsWorker{MockStationDAOSuccessEmpty{}},
+			StationsWorker{MockStationDAOSuccessEmpty{}, StationsFilter{}},
 			model.Stations{[]model.Station{}},
 			false,
 		},
 		{
-			StationsWorker{MockStationDAOFail{}},
+			StationsWorker{MockStationDAOFail{}, StationsFilter{}},
 			model.Stations{},
 			true,
 		},
@@ -108,3 +187,28 @@ func TestStationsWorker_HandleRequest(t *testing.T) {
 		}
 	}
 }
+
+func TestStationsFilter_matches(t *testing.T) {
+	station := model.Station{ID: "fm4", Name: "FM4", Active: true, Country: "AT",
+		Language: "en", Genres: []string{"alternative", "electronic"}}
+
+	var tests = []struct {
+		filter   StationsFilter
+		expected bool
+	}{
+		{StationsFilter{}, true},
+		{StationsFilter{Active: &activeTrue, Country: "AT"}, true},
+		{StationsFilter{Country: "at", Language: "EN", Genre: "Electronic"}, true},
+		{StationsFilter{Active: &activeFalse}, false},
+		{StationsFilter{Country: "DE"}, false},
+		{StationsFilter{Language: "de"}, false},
+		{StationsFilter{Genre: "pop"}, false},
+	}
+
+	for _, test := range tests {
+		if result := test.filter.matches(station); result != test.expected {
+			t.Errorf("StationsFilter (%v).matches(%v): got %v, expected %v",
+				test.filter, station, result, test.expected)
+		}
+	}
+}
@@ -5,12 +5,15 @@ import (
 	"github.com/RadioCheckerApp/api/datalayer"
 	"github.com/RadioCheckerApp/api/model"
 	"sort"
+	"strings"
 	"time"
 )
 
 type TracksWorker struct {
 	dao     datalayer.TrackRecordDAO
+	rollups *Rollups
 	station string
+	ranking Ranking
 }
 
 func NewTracksWorker(dao datalayer.TrackRecordDAO, station string) (TracksWorker, error) {
@@ -20,19 +23,15 @@ func NewTracksWorker(dao datalayer.TrackRecordDAO, station string) (TracksWorker
 	if station == "" {
 		return TracksWorker{}, errors.New("station must not be empty")
 	}
-	return TracksWorker{dao, station}, nil
+	return TracksWorker{dao, nil, station, DefaultRanking}, nil
 }
 
 func (worker TracksWorker) TopTracks(startDate, endDate time.Time) (model.CountedTracks, error) {
-	trackRecords, err := worker.dao.GetTrackRecordsByStation(worker.station, startDate, endDate)
+	groupedTracks, casings, lastModified, err := worker.countTracks(startDate, endDate)
 	if err != nil {
 		return model.CountedTracks{}, err
 	}
-
-	groupedTracks := make(map[model.Track]int)
-	for _, trackRecord := range trackRecords {
-		groupedTracks[trackRecord.Track]++
-	}
+	groupedTracks = foldTracks(groupedTracks)
 
 	orderedTracks := make([]model.CountedTrack, len(groupedTracks))
 	i := 0
@@ -42,28 +41,73 @@ func (worker TracksWorker) TopTracks(startDate, endDate time.Time) (model.Counte
 	}
 
 	sort.Slice(orderedTracks, func(i, j int) bool {
-		return orderedTracks[i].Counter > orderedTracks[j].Counter
+		if orderedTracks[i].Counter != orderedTracks[j].Counter {
+			return orderedTracks[i].Counter > orderedTracks[j].Counter
+		}
+		return lessTrack(orderedTracks[i].Track, orderedTracks[j].Track)
 	})
 
-	resultLimitIdx := findResultLimitIdx(orderedTracks)
+	resultLimitIdx := findResultLimitIdx(orderedTracks, worker.ranking)
+	for i := range orderedTracks[:resultLimitIdx] {
+		orderedTracks[i].Track = casings.Display(orderedTracks[i].Track)
+	}
 
 	return model.CountedTracks{
 		worker.station,
 		startDate,
 		endDate,
 		orderedTracks[:resultLimitIdx],
+		lastModified,
 	}, nil
 }
 
-func (worker TracksWorker) AllTracks(startDate, endDate time.Time) (model.Tracks, error) {
-	trackRecords, err := worker.dao.GetTrackRecordsByStation(worker.station, startDate, endDate)
+// countTracks returns how often each track has been played between `startDate` and `endDate`
+// along with the display forms of the tracks and the time of the most recent play.
+func (worker TracksWorker) countTracks(startDate, endDate time.Time) (map[model.Track]int,
+	model.Casings, time.Time, error) {
+	plays, err := countPlays(worker.dao, worker.rollups, worker.station, startDate, endDate)
 	if err != nil {
-		return model.Tracks{}, err
+		return nil, nil, time.Time{}, err
+	}
+
+	groupedTracks := make(map[model.Track]int)
+	for _, rollup := range plays {
+		groupedTracks[rollup.Track] += rollup.Plays
+	}
+	casings := make(model.Casings)
+	casings.AddRollups(plays)
+	return groupedTracks, casings, findLastPlayed(plays), nil
+}
+
+// foldTracks merges the counts of the tracks sharing a folded key (see model.Track.FoldedKey), e.
+// g. `beyoncé` and `beyonce`. The variant played most often represents them.
+func foldTracks(groupedTracks map[model.Track]int) map[model.Track]int {
+	variants := foldedVariants(groupedTracks)
+	foldedTracks := make(map[model.Track]int)
+	for track, count := range groupedTracks {
+		foldedTracks[variants[track.FoldedKey()]] += count
 	}
+	return foldedTracks
+}
 
-	distinctTracks := make(map[model.Track]bool, 0)
-	for _, trackRecord := range trackRecords {
-		distinctTracks[trackRecord.Track] = true
+// foldedVariants returns the variant played most often per folded key.
+func foldedVariants(groupedTracks map[model.Track]int) map[model.Track]model.Track {
+	variants := make(map[model.Track]model.Track)
+	for track, count := range groupedTracks {
+		key := track.FoldedKey()
+		variant, ok := variants[key]
+		if !ok || count > groupedTracks[variant] ||
+			(count == groupedTracks[variant] && lessTrack(track, variant)) {
+			variants[key] = track
+		}
+	}
+	return variants
+}
+
+func (worker TracksWorker) AllTracks(startDate, endDate time.Time) (model.Tracks, error) {
+	distinctTracks, casings, lastModified, err := worker.countTracks(startDate, endDate)
+	if err != nil {
+		return model.Tracks{}, err
 	}
 
 	tracks := make([]model.Track, len(distinctTracks))
@@ -72,15 +116,111 @@ func (worker TracksWorker) AllTracks(startDate, endDate time.Time) (model.Tracks
 		tracks[i] = track
 		i++
 	}
+	sort.Slice(tracks, func(i, j int) bool {
+		return lessTrack(tracks[i], tracks[j])
+	})
+	for i := range tracks {
+		tracks[i] = casings.Display(tracks[i])
+	}
 
 	return model.Tracks{
 		worker.station,
 		startDate,
 		endDate,
 		tracks,
+		lastModified,
+	}, nil
+}
+
+// TopArtists ranks the artists credited by the tracks played between `startDate` and `endDate`,
+// featured artists included. Each artist is served in the spelling played most often. A credit
+// naming several artists by `&` or `,` (see model.Credit.Split) counts for each of them only if
+// all of them are credited on their own in the period, e. g. `david guetta & sia`; otherwise it is
+// a single act like `simon & garfunkel`.
+func (worker TracksWorker) TopArtists(startDate, endDate time.Time) (model.CountedArtists, error) {
+	groupedTracks, casings, lastModified, err := worker.countTracks(startDate, endDate)
+	if err != nil {
+		return model.CountedArtists{}, err
+	}
+
+	credits := make(map[model.Track][]model.Credit, len(groupedTracks))
+	soloArtists := make(map[string]bool)
+	for track := range groupedTracks {
+		credits[track] = casings.Display(track).Credits()
+		for _, credit := range credits[track] {
+			if len(credit.Split()) == 1 {
+				soloArtists[strings.ToLower(credit.Name)] = true
+			}
+		}
+	}
+
+	spellings := make(map[string]map[string]int)
+	for track, count := range groupedTracks {
+		counted := make(map[string]bool)
+		for _, credit := range credits[track] {
+			for _, artist := range splitSoloArtists(credit, soloArtists) {
+				key := strings.ToLower(artist.Name)
+				if counted[key] {
+					continue
+				}
+				counted[key] = true
+				if _, ok := spellings[key]; !ok {
+					spellings[key] = make(map[string]int)
+				}
+				spellings[key][artist.Name] += count
+			}
+		}
+	}
+
+	orderedArtists := make([]model.CountedArtist, 0, len(spellings))
+	for _, counts := range spellings {
+		artist := model.CountedArtist{}
+		for name, count := range counts {
+			artist.Counter += count
+			if count > counts[artist.Artist] ||
+				(count == counts[artist.Artist] && name < artist.Artist) {
+				artist.Artist = name
+			}
+		}
+		orderedArtists = append(orderedArtists, artist)
+	}
+	sort.Slice(orderedArtists, func(i, j int) bool {
+		if orderedArtists[i].Counter != orderedArtists[j].Counter {
+			return orderedArtists[i].Counter > orderedArtists[j].Counter
+		}
+		return strings.ToLower(orderedArtists[i].Artist) < strings.ToLower(orderedArtists[j].Artist)
+	})
+
+	// artists are ranked like tracks
+	countedTracks := make([]model.CountedTrack, len(orderedArtists))
+	for i, artist := range orderedArtists {
+		countedTracks[i].Counter = artist.Counter
+	}
+	resultLimitIdx := findResultLimitIdx(countedTracks, worker.ranking)
+
+	return model.CountedArtists{
+		worker.station,
+		startDate,
+		endDate,
+		orderedArtists[:resultLimitIdx],
+		lastModified,
 	}, nil
 }
 
+// splitSoloArtists splits the credit into the artists it names if all of them are solo artists.
+func splitSoloArtists(credit model.Credit, soloArtists map[string]bool) []model.Credit {
+	artists := credit.Split()
+	if len(artists) < 2 {
+		return []model.Credit{credit}
+	}
+	for _, artist := range artists {
+		if !soloArtists[strings.ToLower(artist.Name)] {
+			return []model.Credit{credit}
+		}
+	}
+	return artists
+}
+
 func (worker TracksWorker) MostRecentTrackRecord() (model.TrackRecord, error) {
 	trackRecord, err := worker.dao.GetMostRecentTrackRecordByStation(worker.station)
 	if err != nil {
@@ -93,22 +233,34 @@ func (worker TracksWorker) HandleRequest() (interface{}, error) {
 	return worker.MostRecentTrackRecord()
 }
 
-func findResultLimitIdx(tracksOrderedDescendinglyByCounter []model.CountedTrack) int {
-	if len(tracksOrderedDescendinglyByCounter) <= 3 || tracksOrderedDescendinglyByCounter[0].Counter <= 3 {
+func findResultLimitIdx(tracksOrderedDescendinglyByCounter []model.CountedTrack,
+	ranking Ranking) int {
+	if len(tracksOrderedDescendinglyByCounter) <= ranking.TopRanks ||
+		tracksOrderedDescendinglyByCounter[0].Counter <= ranking.MinPlays {
 		return len(tracksOrderedDescendinglyByCounter)
 	}
 
 	prevCounter := tracksOrderedDescendinglyByCounter[0].Counter
 	foundRanks, limitIdx := 1, 1
-	for ; limitIdx < len(tracksOrderedDescendinglyByCounter) && foundRanks <= 3; limitIdx++ {
+	for ; limitIdx < len(tracksOrderedDescendinglyByCounter) &&
+		foundRanks <= ranking.TopRanks; limitIdx++ {
 		if prevCounter != tracksOrderedDescendinglyByCounter[limitIdx].Counter {
 			foundRanks++
 			prevCounter = tracksOrderedDescendinglyByCounter[limitIdx].Counter
 		}
 	}
 
-	if foundRanks > 3 {
+	if foundRanks > ranking.TopRanks {
 		return limitIdx - 1
 	}
 	return limitIdx
 }
+
+// lessTrack orders tracks by artist and title. Results are ordered deterministically, so that
+// identical results produce identical responses (and ETags).
+func lessTrack(a, b model.Track) bool {
+	if a.Artist != b.Artist {
+		return a.Artist < b.Artist
+	}
+	return a.Title < b.Title
+}
@@ -27,29 +27,29 @@ func (dao MockTrackRecordDAO) GetTrackRecordsByStation(stationId string, start t
 
 	if stationId == "getTrackRecords" {
 		trackRecords := []model.TrackRecord{
-			{"station-a", time.Now().Unix(), "track", model.Track{"RHCP", "Californication"}},
-			{"station-a", time.Now().Unix(), "track", model.Track{"Jonas Blue, Jack & Jack", "Rise"}},
-			{"station-a", time.Now().Unix(), "track", model.Track{"Cardi B", "I Like It"}},
-			{"station-a", time.Now().Unix(), "track", model.Track{"RHCP", "Californication"}},
-			{"station-a", time.Now().Unix(), "track", model.Track{"Jonas Blue, Jack & Jack", "Rise"}},
-			{"station-a", time.Now().Unix(), "track", model.Track{"RHCP", "Californication"}},
-			{"station-b", time.Now().Unix(), "track", model.Track{"RHCP", "Californication"}},
-			{"station-b", time.Now().Unix(), "track", model.Track{"MØ", "Final Song"}},
-			{"station-b", time.Now().Unix(), "track", model.Track{"RHCP", "Dani California"}},
-			{"station-b", time.Now().Unix(), "track", model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
-			{"station-c", time.Now().Unix(), "track", model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
-			{"station-c", time.Now().Unix(), "track", model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
+			{StationId: "station-a", Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "Californication"}},
+			{StationId: "station-a", Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"Jonas Blue, Jack & Jack", "Rise"}},
+			{StationId: "station-a", Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"Cardi B", "I Like It"}},
+			{StationId: "station-a", Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "Californication"}},
+			{StationId: "station-a", Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"Jonas Blue, Jack & Jack", "Rise"}},
+			{StationId: "station-a", Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "Californication"}},
+			{StationId: "station-b", Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "Californication"}},
+			{StationId: "station-b", Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"MØ", "Final Song"}},
+			{StationId: "station-b", Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "Dani California"}},
+			{StationId: "station-b", Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
+			{StationId: "station-c", Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
+			{StationId: "station-c", Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
 		}
 		return trackRecords, nil
 	}
 
 	trackRecords := []model.TrackRecord{
-		{stationId, time.Now().Unix(), "track", model.Track{"RHCP", "Californication"}},
-		{stationId, time.Now().Unix(), "track", model.Track{"Jonas Blue, Jack & Jack", "Rise"}},
-		{stationId, time.Now().Unix(), "track", model.Track{"Cardi B", "I Like It"}},
-		{stationId, time.Now().Unix(), "track", model.Track{"RHCP", "Californication"}},
-		{stationId, time.Now().Unix(), "track", model.Track{"Jonas Blue, Jack & Jack", "Rise"}},
-		{stationId, time.Now().Unix(), "track", model.Track{"RHCP", "Californication"}},
+		{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "Californication"}},
+		{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"Jonas Blue, Jack & Jack", "Rise"}},
+		{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"Cardi B", "I Like It"}},
+		{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "Californication"}},
+		{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"Jonas Blue, Jack & Jack", "Rise"}},
+		{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "Californication"}},
 	}
 	return trackRecords, nil
 }
@@ -57,9 +57,9 @@ func (dao MockTrackRecordDAO) GetTrackRecordsByStation(stationId string, start t
 func (dao MockTrackRecordDAO) GetMostRecentTrackRecordByStation(stationId string) (model.
 	TrackRecord, error) {
 	if stationId == "notracksstation" {
-		return model.TrackRecord{}, errors.New("no track records in database")
+		return model.TrackRecord{}, datalayer.NewNotFoundError("no track records in database")
 	}
-	return model.TrackRecord{stationId, 1234567890, "track", model.Track{"RHCP",
+	return model.TrackRecord{StationId: stationId, Timestamp: 1234567890, Type: "track", Track: model.Track{"RHCP",
 		"Californication"}}, nil
 }
 
@@ -70,6 +70,28 @@ func (dao MockTrackRecordDAO) CreateTrackRecord(trackRecord model.TrackRecord) e
 	return nil
 }
 
+func (dao MockTrackRecordDAO) UpdateTrackRecord(trackRecord model.TrackRecord) error {
+	return nil
+}
+
+func (dao MockTrackRecordDAO) DeleteTrackRecord(station string, airtime int64) error {
+	return nil
+}
+
+func (dao MockTrackRecordDAO) GetRecords(types []string, start,
+	end time.Time) ([]model.TrackRecord, error) {
+	return dao.GetTrackRecords(start, end)
+}
+
+func (dao MockTrackRecordDAO) GetRecordsByStation(stationId string, types []string,
+	start, end time.Time) ([]model.TrackRecord, error) {
+	return dao.GetTrackRecordsByStation(stationId, start, end)
+}
+
+func (dao MockTrackRecordDAO) PutTrackRecords(trackRecords []model.TrackRecord) error {
+	return nil
+}
+
 type MockTrackRecordDAOLimitTracks struct{}
 
 func (dao MockTrackRecordDAOLimitTracks) GetTrackRecords(start, end time.Time) ([]model.TrackRecord, error) {
@@ -80,49 +102,49 @@ func (dao MockTrackRecordDAOLimitTracks) GetTrackRecordsByStation(stationId stri
 	end time.Time) ([]model.TrackRecord, error) {
 	if stationId == "withMoreThanTopThree" {
 		trackRecords := []model.TrackRecord{
-			{stationId, time.Now().Unix(), "track", model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"RHCP", "Dani California"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"RHCP", "Dani California"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"RHCP", "Dani California"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"Cardi B", "I Like It"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"Cardi B", "I Like It"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"MØ", "Final Song"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"Jonas Blue, Jack & Jack", "Rise"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "Dani California"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "Dani California"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "Dani California"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"Cardi B", "I Like It"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"Cardi B", "I Like It"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"MØ", "Final Song"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"Jonas Blue, Jack & Jack", "Rise"}},
 		}
 		return trackRecords, nil
 	}
 
 	if stationId == "withMoreThanTopThreeAndDuplicatedCounters" {
 		trackRecords := []model.TrackRecord{
-			{stationId, time.Now().Unix(), "track", model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"RHCP", "Dani California"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"RHCP", "Dani California"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"RHCP", "Dani California"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"RHCP", "Dani California"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"RHCP", "Dani California"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"Cardi B", "I Like It"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"Cardi B", "I Like It"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"MØ", "Final Song"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"Jonas Blue, Jack & Jack", "Rise"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "Dani California"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "Dani California"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "Dani California"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "Dani California"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "Dani California"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"Cardi B", "I Like It"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"Cardi B", "I Like It"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"MØ", "Final Song"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"Jonas Blue, Jack & Jack", "Rise"}},
 		}
 		return trackRecords, nil
 	}
 
 	if stationId == "withDuplicatedCountersOnly" {
 		trackRecords := []model.TrackRecord{
-			{stationId, time.Now().Unix(), "track", model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"RHCP", "Dani California"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"Cardi B", "I Like It"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"MØ", "Final Song"}},
-			{stationId, time.Now().Unix(), "track", model.Track{"Jonas Blue, Jack & Jack", "Rise"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "The Adventures Of Rain Dance Maggie"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"RHCP", "Dani California"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"Cardi B", "I Like It"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"MØ", "Final Song"}},
+			{StationId: stationId, Timestamp: time.Now().Unix(), Type: "track", Track: model.Track{"Jonas Blue, Jack & Jack", "Rise"}},
 		}
 		return trackRecords, nil
 	}
@@ -138,6 +160,28 @@ func (dao MockTrackRecordDAOLimitTracks) CreateTrackRecord(trackRecord model.Tra
 	return nil
 }
 
+func (dao MockTrackRecordDAOLimitTracks) UpdateTrackRecord(trackRecord model.TrackRecord) error {
+	return nil
+}
+
+func (dao MockTrackRecordDAOLimitTracks) DeleteTrackRecord(station string, airtime int64) error {
+	return nil
+}
+
+func (dao MockTrackRecordDAOLimitTracks) GetRecords(types []string, start,
+	end time.Time) ([]model.TrackRecord, error) {
+	return dao.GetTrackRecords(start, end)
+}
+
+func (dao MockTrackRecordDAOLimitTracks) GetRecordsByStation(stationId string, types []string,
+	start, end time.Time) ([]model.TrackRecord, error) {
+	return dao.GetTrackRecordsByStation(stationId, start, end)
+}
+
+func (dao MockTrackRecordDAOLimitTracks) PutTrackRecords(trackRecords []model.TrackRecord) error {
+	return nil
+}
+
 var countedTracks = model.CountedTracks{
 	"test",     // to be defined in the specific tests
 	time.Now(), // to be defined in the specific tests
@@ -147,6 +191,7 @@ var countedTracks = model.CountedTracks{
 		{2, model.Track{"Jonas Blue, Jack & Jack", "Rise"}},
 		{1, model.Track{"Cardi B", "I Like It"}},
 	},
+	time.Time{},
 }
 
 var tracks = model.Tracks{
@@ -158,6 +203,7 @@ var tracks = model.Tracks{
 		{"Jonas Blue, Jack & Jack", "Rise"},
 		{"Cardi B", "I Like It"},
 	},
+	time.Time{},
 }
 
 func TestNewTracksWorker(t *testing.T) {
@@ -178,7 +224,7 @@ func TestNewTracksWorker(t *testing.T) {
 				test.dao, test.station, err, test.expectedErr)
 			continue
 		}
-		expectedResult := TracksWorker{test.dao, test.station}
+		expectedResult := TracksWorker{test.dao, nil, test.station, DefaultRanking}
 		if err == nil && !reflect.DeepEqual(result, expectedResult) {
 			t.Errorf("TestNewTracksWorker(%q, %q): got result (%v), expected (%v)",
 				test.dao, test.station, result, expectedResult)
@@ -202,14 +248,14 @@ func TestTracksWorker_TopTracks(t *testing.T) {
 		expectedErr    bool
 	}{
 		{
-			TracksWorker{MockTrackRecordDAO{}, "station-A"},
+			TracksWorker{MockTrackRecordDAO{}, nil, "station-A", DefaultRanking},
 			startDate,
 			endDate,
 			countedTracks,
 			false,
 		},
 		{
-			TracksWorker{MockTrackRecordDAO{}, "notracksstation"},
+			TracksWorker{MockTrackRecordDAO{}, nil, "notracksstation", DefaultRanking},
 			startDate,
 			endDate,
 			model.CountedTracks{
@@ -217,11 +263,12 @@ func TestTracksWorker_TopTracks(t *testing.T) {
 				startDate,
 				endDate,
 				[]model.CountedTrack{},
+				time.Time{},
 			},
 			false,
 		},
 		{
-			TracksWorker{MockTrackRecordDAO{}, "errorstation"},
+			TracksWorker{MockTrackRecordDAO{}, nil, "errorstation", DefaultRanking},
 			endDate,
 			startDate,
 			model.CountedTracks{},
@@ -232,7 +279,7 @@ func TestTracksWorker_TopTracks(t *testing.T) {
 	for _, test := range tests {
 		result, err := test.worker.TopTracks(test.startDate, test.endDate)
 		if (err != nil) != test.expectedErr {
-			t.Errorf("(%q).TopTracks(%v, %v): got err (%v), expected err: %v",
+			t.Errorf("(%v).TopTracks(%v, %v): got err (%v), expected err: %v",
 				test.worker, test.startDate, test.endDate, err, test.expectedErr)
 			continue
 		}
@@ -245,12 +292,12 @@ func TestTracksWorker_TopTracks(t *testing.T) {
 
 		if result.StartDate != test.expectedResult.StartDate ||
 			result.EndDate != test.expectedResult.EndDate {
-			t.Errorf("(%q).TopTracks(%v, %v): got result startdate: %v / enddate: %v",
+			t.Errorf("(%v).TopTracks(%v, %v): got result startdate: %v / enddate: %v",
 				test.worker, test.startDate, test.endDate, result.StartDate, result.EndDate)
 		}
 
 		if len(result.CountedTracks) != len(test.expectedResult.CountedTracks) {
-			t.Errorf("(%q).TopTracks(%v, %v): got len of result (%q), expected (%q)",
+			t.Errorf("(%v).TopTracks(%v, %v): got len of result (%q), expected (%q)",
 				test.worker, test.startDate, test.endDate, len(result.CountedTracks),
 				len(test.expectedResult.CountedTracks))
 			continue
@@ -258,7 +305,7 @@ func TestTracksWorker_TopTracks(t *testing.T) {
 
 		for i, expectedCountedTrack := range test.expectedResult.CountedTracks {
 			if !reflect.DeepEqual(result.CountedTracks[i], expectedCountedTrack) {
-				t.Errorf("(%q).TopTracks(%v, %v): got result (%q), expected (%q)",
+				t.Errorf("(%v).TopTracks(%v, %v): got result (%q), expected (%q)",
 					test.worker, test.startDate, test.endDate, result, test.expectedResult)
 			}
 		}
@@ -274,6 +321,7 @@ var countedTracksWithMoreThanTopThree = model.CountedTracks{
 		{3, model.Track{"RHCP", "Dani California"}},
 		{2, model.Track{"Cardi B", "I Like It"}},
 	},
+	time.Time{},
 }
 
 var countedTracksWithMoreThanTopThreeAndDuplicatedCounters = model.CountedTracks{
@@ -287,6 +335,7 @@ var countedTracksWithMoreThanTopThreeAndDuplicatedCounters = model.CountedTracks
 		{1, model.Track{"MØ", "Final Song"}},
 		{1, model.Track{"Jonas Blue, Jack & Jack", "Rise"}},
 	},
+	time.Time{},
 }
 
 var countedTracksWithDuplicatedCountersOnly = model.CountedTracks{
@@ -300,6 +349,7 @@ var countedTracksWithDuplicatedCountersOnly = model.CountedTracks{
 		{1, model.Track{"MØ", "Final Song"}},
 		{1, model.Track{"Jonas Blue, Jack & Jack", "Rise"}},
 	},
+	time.Time{},
 }
 
 func TestTracksWorker_TopTracksLimited(t *testing.T) {
@@ -320,17 +370,17 @@ func TestTracksWorker_TopTracksLimited(t *testing.T) {
 		expectedErr    bool
 	}{
 		{
-			TracksWorker{MockTrackRecordDAOLimitTracks{}, "withMoreThanTopThree"},
+			TracksWorker{MockTrackRecordDAOLimitTracks{}, nil, "withMoreThanTopThree", DefaultRanking},
 			countedTracksWithMoreThanTopThree,
 			false,
 		},
 		{
-			TracksWorker{MockTrackRecordDAOLimitTracks{}, "withMoreThanTopThreeAndDuplicatedCounters"},
+			TracksWorker{MockTrackRecordDAOLimitTracks{}, nil, "withMoreThanTopThreeAndDuplicatedCounters", DefaultRanking},
 			countedTracksWithMoreThanTopThreeAndDuplicatedCounters,
 			false,
 		},
 		{
-			TracksWorker{MockTrackRecordDAOLimitTracks{}, "withDuplicatedCountersOnly"},
+			TracksWorker{MockTrackRecordDAOLimitTracks{}, nil, "withDuplicatedCountersOnly", DefaultRanking},
 			countedTracksWithDuplicatedCountersOnly,
 			false,
 		},
@@ -339,7 +389,7 @@ func TestTracksWorker_TopTracksLimited(t *testing.T) {
 	for _, test := range tests {
 		result, err := test.worker.TopTracks(startDate, endDate)
 		if (err != nil) != test.expectedErr {
-			t.Errorf("(%q).TopTracks(%v, %v): got err (%v), expected err: %v",
+			t.Errorf("(%v).TopTracks(%v, %v): got err (%v), expected err: %v",
 				test.worker, startDate, endDate, err, test.expectedErr)
 			continue
 		}
@@ -352,12 +402,12 @@ func TestTracksWorker_TopTracksLimited(t *testing.T) {
 
 		if result.StartDate != test.expectedResult.StartDate ||
 			result.EndDate != test.expectedResult.EndDate {
-			t.Errorf("(%q).TopTracks(%v, %v): got result startdate: %v / enddate: %v",
+			t.Errorf("(%v).TopTracks(%v, %v): got result startdate: %v / enddate: %v",
 				test.worker, startDate, endDate, result.StartDate, result.EndDate)
 		}
 
 		if len(result.CountedTracks) != len(test.expectedResult.CountedTracks) {
-			t.Errorf("(%q).TopTracks(%v, %v): got len of result (%q), expected (%q)",
+			t.Errorf("(%v).TopTracks(%v, %v): got len of result (%q), expected (%q)",
 				test.worker, startDate, endDate, len(result.CountedTracks),
 				len(test.expectedResult.CountedTracks))
 			continue
@@ -375,7 +425,7 @@ func TestTracksWorker_TopTracksLimited(t *testing.T) {
 
 		// just check if the number of tracks per counter value are equal
 		if !reflect.DeepEqual(expectedNumberOfTracksPerCounter, gotNumberOfTracksPerCounter) {
-			t.Errorf("(%q).TopTracks(%v, %v): got number of track per counter: (%q), expected (%q)",
+			t.Errorf("(%v).TopTracks(%v, %v): got number of track per counter: (%q), expected (%q)",
 				test.worker, startDate, endDate, gotNumberOfTracksPerCounter, expectedNumberOfTracksPerCounter)
 		}
 	}
@@ -396,14 +446,14 @@ func TestTracksWorker_AllTracks(t *testing.T) {
 		expectedErr    bool
 	}{
 		{
-			TracksWorker{MockTrackRecordDAO{}, "station-A"},
+			TracksWorker{MockTrackRecordDAO{}, nil, "station-A", DefaultRanking},
 			startDate,
 			endDate,
 			tracks,
 			false,
 		},
 		{
-			TracksWorker{MockTrackRecordDAO{}, "notracksstation"},
+			TracksWorker{MockTrackRecordDAO{}, nil, "notracksstation", DefaultRanking},
 			startDate,
 			endDate,
 			model.Tracks{
@@ -411,11 +461,12 @@ func TestTracksWorker_AllTracks(t *testing.T) {
 				startDate,
 				endDate,
 				[]model.Track{},
+				time.Time{},
 			},
 			false,
 		},
 		{
-			TracksWorker{MockTrackRecordDAO{}, "errorstation"},
+			TracksWorker{MockTrackRecordDAO{}, nil, "errorstation", DefaultRanking},
 			endDate,
 			startDate,
 			tracks,
@@ -426,7 +477,7 @@ func TestTracksWorker_AllTracks(t *testing.T) {
 	for _, test := range tests {
 		result, err := test.worker.AllTracks(test.startDate, test.endDate)
 		if (err != nil) != test.expectedErr {
-			t.Errorf("(%q).AllTracks(%v, %v): got err (%v), expected err: %v",
+			t.Errorf("(%v).AllTracks(%v, %v): got err (%v), expected err: %v",
 				test.worker, test.startDate, test.endDate, err, test.expectedErr)
 			continue
 		}
@@ -439,12 +490,12 @@ func TestTracksWorker_AllTracks(t *testing.T) {
 
 		if result.StartDate != test.expectedResult.StartDate ||
 			result.EndDate != test.expectedResult.EndDate {
-			t.Errorf("(%q).AllTracks(%v, %v): got result startdate: %v / enddate: %v",
+			t.Errorf("(%v).AllTracks(%v, %v): got result startdate: %v / enddate: %v",
 				test.worker, test.startDate, test.endDate, result.StartDate, result.EndDate)
 		}
 
 		if len(result.Tracks) != len(test.expectedResult.Tracks) {
-			t.Errorf("(%q).AllTracks(%v, %v): got result (%q), expected (%q)",
+			t.Errorf("(%v).AllTracks(%v, %v): got result (%q), expected (%q)",
 				test.worker, test.startDate, test.endDate, result, test.expectedResult)
 		}
 
@@ -457,7 +508,7 @@ func TestTracksWorker_AllTracks(t *testing.T) {
 				}
 			}
 			if !match {
-				t.Errorf("(%q).AllTracks(%v, %v): expected item (%q) is not element of result (%q)",
+				t.Errorf("(%v).AllTracks(%v, %v): expected item (%q) is not element of result (%q)",
 					test.worker, test.startDate, test.endDate, track, result)
 			}
 		}
@@ -471,17 +522,17 @@ func TestTracksWorker_MostRecentTrackRecord(t *testing.T) {
 		expectedErr    bool
 	}{
 		{
-			TracksWorker{MockTrackRecordDAO{}, "station-A"},
+			TracksWorker{MockTrackRecordDAO{}, nil, "station-A", DefaultRanking},
 			model.TrackRecord{
-				"station-A",
-				1234567890,
-				"track",
-				model.Track{"RHCP", "Californication"},
+				StationId: "station-A",
+				Timestamp: 1234567890,
+				Type:      "track",
+				Track:     model.Track{"RHCP", "Californication"},
 			},
 			false,
 		},
 		{
-			TracksWorker{MockTrackRecordDAO{}, "notracksstation"},
+			TracksWorker{MockTrackRecordDAO{}, nil, "notracksstation", DefaultRanking},
 			model.TrackRecord{},
 			true,
 		},
@@ -490,7 +541,7 @@ func TestTracksWorker_MostRecentTrackRecord(t *testing.T) {
 	for _, test := range tests {
 		result, err := test.worker.MostRecentTrackRecord()
 		if (err != nil) != test.expectedErr {
-			t.Errorf("(%q).MostRecentTrackRecord(): got err (%v), expected err: %v",
+			t.Errorf("(%v).MostRecentTrackRecord(): got err (%v), expected err: %v",
 				test.worker, err, test.expectedErr)
 			continue
 		}
@@ -502,8 +553,111 @@ func TestTracksWorker_MostRecentTrackRecord(t *testing.T) {
 		}
 
 		if !reflect.DeepEqual(result, test.expectedResult) {
-			t.Errorf("(%q).MostRecentTrackRecord(): result (%q) does not match expected result (%q)",
+			t.Errorf("(%v).MostRecentTrackRecord(): result (%q) does not match expected result (%q)",
 				test.worker, result, test.expectedResult)
 		}
 	}
 }
+
+func TestTracksWorker_DisplayCasing(t *testing.T) {
+	dao := datalayer.NewMemoryTrackRecordDAO(
+		model.TrackRecord{StationId: "fm4", Timestamp: 1537700000, Type: "track",
+			Track:        model.Track{"ty dolla $ign", "or nah"},
+			TrackDisplay: model.TrackDisplay{"Ty Dolla $ign", "Or Nah"}},
+		model.TrackRecord{StationId: "fm4", Timestamp: 1537701000, Type: "track",
+			Track:        model.Track{"ty dolla $ign", "or nah"},
+			TrackDisplay: model.TrackDisplay{"Ty Dolla $ign", "Or Nah"}},
+		model.TrackRecord{StationId: "fm4", Timestamp: 1537702000, Type: "track",
+			Track:        model.Track{"ty dolla $ign", "or nah"},
+			TrackDisplay: model.TrackDisplay{"TY DOLLA $IGN", ""}},
+		model.TrackRecord{StationId: "fm4", Timestamp: 1537703000, Type: "track",
+			Track: model.Track{"cardi b", "i like it"}},
+	)
+	worker, _ := NewTracksWorker(dao, "fm4")
+	startDate, endDate := time.Unix(1537600000, 0), time.Unix(1537800000, 0)
+
+	result, err := worker.TopTracks(startDate, endDate)
+	expected := []model.CountedTrack{
+		{3, model.Track{"Ty Dolla $ign", "Or Nah"}},
+		{1, model.Track{"cardi b", "i like it"}},
+	}
+	if err != nil || !reflect.DeepEqual(result.CountedTracks, expected) {
+		t.Errorf("TopTracks(): got (%v, %v), expected (%v, nil)", result.CountedTracks, err,
+			expected)
+	}
+
+	all, err := worker.AllTracks(startDate, endDate)
+	expectedAll := []model.Track{{"cardi b", "i like it"}, {"Ty Dolla $ign", "Or Nah"}}
+	if err != nil || !reflect.DeepEqual(all.Tracks, expectedAll) {
+		t.Errorf("AllTracks(): got (%v, %v), expected (%v, nil)", all.Tracks, err, expectedAll)
+	}
+}
+
+func TestTracksWorker_TopTracks_Folded(t *testing.T) {
+	record := func(timestamp int64, artist, title string,
+		display model.TrackDisplay) model.TrackRecord {
+		return model.TrackRecord{StationId: "fm4", Timestamp: timestamp, Type: "track",
+			Track: model.Track{artist, title}, TrackDisplay: display}
+	}
+	dao := datalayer.NewMemoryTrackRecordDAO(
+		record(1537700000, "beyoncé", "déjà vu", model.TrackDisplay{"Beyoncé", "Déjà Vu"}),
+		record(1537701000, "beyoncé", "déjà vu", model.TrackDisplay{"Beyoncé", "Déjà Vu"}),
+		record(1537702000, "beyonce", "deja vu", model.TrackDisplay{"Beyonce", "Deja Vu"}),
+		record(1537703000, "cardi b", "i like it", model.TrackDisplay{}),
+		record(1537704000, "mo", "final song", model.TrackDisplay{}),
+		record(1537705000, "mø", "final song", model.TrackDisplay{"MØ", "Final Song"}),
+	)
+	worker, _ := NewTracksWorker(dao, "fm4")
+	startDate, endDate := time.Unix(1537600000, 0), time.Unix(1537800000, 0)
+
+	// variants are served in the form played most often, ties in the form ordered first
+	result, err := worker.TopTracks(startDate, endDate)
+	expected := []model.CountedTrack{
+		{3, model.Track{"Beyoncé", "Déjà Vu"}},
+		{2, model.Track{"mo", "final song"}},
+		{1, model.Track{"cardi b", "i like it"}},
+	}
+	if err != nil || !reflect.DeepEqual(result.CountedTracks, expected) {
+		t.Errorf("TopTracks(): got (%v, %v), expected (%v, nil)", result.CountedTracks, err,
+			expected)
+	}
+}
+
+func TestTracksWorker_TopArtists(t *testing.T) {
+	record := func(timestamp int64, artist, title, displayArtist string) model.TrackRecord {
+		return model.TrackRecord{StationId: "fm4", Timestamp: timestamp, Type: "track",
+			Track:        model.Track{artist, title},
+			TrackDisplay: model.TrackDisplay{DisplayArtist: displayArtist}}
+	}
+	dao := datalayer.NewMemoryTrackRecordDAO(
+		record(1537700000, "nico & vinz feat. kid ink", "that's how you know",
+			"Nico & Vinz feat. Kid Ink"),
+		record(1537701000, "kid ink", "show me", "KID INK"),
+		record(1537702000, "kid ink", "show me", "Kid Ink"),
+		record(1537703000, "ed sheeran", "south of the border (feat. kid ink)", ""),
+		record(1537704000, "ed sheeran", "shape of you", "Ed Sheeran"),
+		// single acts are not split, duets of solo artists are
+		record(1537705000, "earth, wind & fire", "september", "Earth, Wind & Fire"),
+		record(1537706000, "simon & garfunkel", "mrs. robinson", "Simon & Garfunkel"),
+		record(1537707000, "ed sheeran & kid ink", "duet", "Ed Sheeran & Kid Ink"),
+	)
+	worker, _ := NewTracksWorker(dao, "fm4")
+	startDate, endDate := time.Unix(1537600000, 0), time.Unix(1537800000, 0)
+
+	result, err := worker.TopArtists(startDate, endDate)
+	expected := model.CountedArtists{"fm4", startDate, endDate, []model.CountedArtist{
+		{5, "KID INK"},
+		{3, "Ed Sheeran"},
+		{1, "Earth, Wind & Fire"},
+		{1, "Nico & Vinz"},
+		{1, "Simon & Garfunkel"},
+	}, time.Unix(1537707000, 0)}
+	if err != nil || !reflect.DeepEqual(result, expected) {
+		t.Errorf("TopArtists(): got (%v, %v), expected (%v, nil)", result, err, expected)
+	}
+
+	worker.station = "errorstation"
+	if _, err := worker.TopArtists(endDate, startDate); err == nil {
+		t.Error("TopArtists(): got no error, expected error for end before start")
+	}
+}
@@ -0,0 +1,33 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+)
+
+type UpdateStationGroupWorker struct {
+	dao   datalayer.StationGroupDAO
+	group model.StationGroup
+}
+
+func NewUpdateStationGroupWorker(dao datalayer.StationGroupDAO,
+	group model.StationGroup) (UpdateStationGroupWorker, error) {
+	if dao == nil {
+		return UpdateStationGroupWorker{}, errors.New("dao must not be nil")
+	}
+	return UpdateStationGroupWorker{dao, group}, nil
+}
+
+func (worker UpdateStationGroupWorker) HandleRequest() (interface{}, error) {
+	if err := worker.group.Sanitize(); err != nil {
+		return nil, NewValidationError(err)
+	}
+
+	if err := worker.dao.Update(worker.group); err != nil {
+		return nil, err
+	}
+
+	return fmt.Sprintf("group updated: /groups/%s", worker.group.ID), nil
+}
@@ -0,0 +1,89 @@
+package request
+
+import (
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"reflect"
+	"testing"
+)
+
+func TestNewUpdateStationGroupWorker(t *testing.T) {
+	group := model.StationGroup{"austria", "Austria", "", []string{"kronehit"}}
+
+	var tests = []struct {
+		dao         datalayer.StationGroupDAO
+		expectedErr bool
+	}{
+		{MockStationGroupDAOSuccess{}, false},
+		{nil, true},
+	}
+
+	for _, test := range tests {
+		result, err := NewUpdateStationGroupWorker(test.dao, group)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("NewUpdateStationGroupWorker(%v, %v): got err (%v), expected err: %v",
+				test.dao, group, err, test.expectedErr)
+			continue
+		}
+		expectedResult := UpdateStationGroupWorker{test.dao, group}
+		if err == nil && !reflect.DeepEqual(result, expectedResult) {
+			t.Errorf("NewUpdateStationGroupWorker(%v, %v): got result (%v), expected (%v)",
+				test.dao, group, result, expectedResult)
+		}
+	}
+}
+
+func TestUpdateStationGroupWorker_HandleRequest(t *testing.T) {
+	var tests = []struct {
+		worker         UpdateStationGroupWorker
+		expectedResult string
+		expectedErr    bool
+	}{
+		// success
+		{
+			UpdateStationGroupWorker{MockStationGroupDAOSuccess{},
+				model.StationGroup{"AUSTRIA", " Austria ", "", []string{"kronehit"}}},
+			"group updated: /groups/austria",
+			false,
+		},
+		// group does not exist
+		{
+			UpdateStationGroupWorker{MockStationGroupDAOSuccess{},
+				model.StationGroup{"orf", "ORF", "", []string{"orf-wien"}}},
+			"ignored",
+			true,
+		},
+		// no member stations
+		{
+			UpdateStationGroupWorker{MockStationGroupDAOSuccess{},
+				model.StationGroup{"austria", "Austria", "", []string{}}},
+			"ignored",
+			true,
+		},
+		// database error
+		{
+			UpdateStationGroupWorker{MockStationGroupDAOFail{},
+				model.StationGroup{"austria", "Austria", "", []string{"kronehit"}}},
+			"ignored",
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		result, err := test.worker.HandleRequest()
+		if (err != nil) != test.expectedErr {
+			t.Errorf("(%v).HandleRequest(): got err (%v), expected err: %v",
+				test.worker, err, test.expectedErr)
+			continue
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if result != test.expectedResult {
+			t.Errorf("(%v).HandleRequest(): got result (%v), expected (%v)",
+				test.worker, result, test.expectedResult)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+)
+
+// UpdateStationWorker replaces a station. If `keepActive` is set, i. e. the request omitted the
+// `active` flag, the station keeps its stored flag instead of being deactivated.
+type UpdateStationWorker struct {
+	dao        datalayer.StationDAO
+	station    model.Station
+	keepActive bool
+}
+
+func NewUpdateStationWorker(dao datalayer.StationDAO, station model.Station,
+	keepActive bool) (UpdateStationWorker, error) {
+	if dao == nil {
+		return UpdateStationWorker{}, errors.New("dao must not be nil")
+	}
+	return UpdateStationWorker{dao, station, keepActive}, nil
+}
+
+func (worker UpdateStationWorker) HandleRequest() (interface{}, error) {
+	if err := worker.station.Sanitize(); err != nil {
+		return nil, NewValidationError(err)
+	}
+
+	if worker.keepActive {
+		stored, err := worker.dao.Get(worker.station.ID)
+		if err != nil {
+			return nil, err
+		}
+		worker.station.Active = stored.Active
+	}
+
+	if err := worker.dao.Update(worker.station); err != nil {
+		return nil, err
+	}
+
+	return fmt.Sprintf("station updated: /stations/%s", worker.station.ID), nil
+}
@@ -0,0 +1,121 @@
+package request
+
+import (
+	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/model"
+	"reflect"
+	"testing"
+)
+
+func TestNewUpdateStationWorker(t *testing.T) {
+	var tests = []struct {
+		dao         datalayer.StationDAO
+		station     model.Station
+		expectedErr bool
+	}{
+		{MockStationDAOSuccess{}, model.Station{ID: "kronehit", Name: "Kronehit", Active: true}, false},
+		{nil, model.Station{ID: "kronehit", Name: "Kronehit", Active: true}, true},
+	}
+
+	for _, test := range tests {
+		result, err := NewUpdateStationWorker(test.dao, test.station, false)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("NewUpdateStationWorker(%v, %v): got err (%v), expected err: %v",
+				test.dao, test.station, err, test.expectedErr)
+			continue
+		}
+		expectedResult := UpdateStationWorker{test.dao, test.station, false}
+		if err == nil && !reflect.DeepEqual(result, expectedResult) {
+			t.Errorf("NewUpdateStationWorker(%v, %v): got result (%v), expected (%v)",
+				test.dao, test.station, result, expectedResult)
+		}
+	}
+}
+
+func TestUpdateStationWorker_HandleRequest(t *testing.T) {
+	var tests = []struct {
+		worker         UpdateStationWorker
+		expectedResult string
+		expectedErr    bool
+	}{
+		// success
+		{
+			UpdateStationWorker{MockStationDAOSuccess{}, model.Station{ID: "kronehit",
+				Name: "Kronehit", Description: "We are the most music", Active: true}, false},
+			"station updated: /stations/kronehit",
+			false,
+		},
+		// station does not exist
+		{
+			UpdateStationWorker{MockStationDAOSuccess{},
+				model.Station{ID: "fm4", Name: "FM4", Active: true}, false},
+			"ignored",
+			true,
+		},
+		// missing name
+		{
+			UpdateStationWorker{MockStationDAOSuccess{},
+				model.Station{ID: "kronehit", Name: " ", Active: true}, false},
+			"ignored",
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		result, err := test.worker.HandleRequest()
+		if (err != nil) != test.expectedErr {
+			t.Errorf("(%v).HandleRequest(): got err (%v), expected err: %v",
+				test.worker, err, test.expectedErr)
+			continue
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if result != test.expectedResult {
+			t.Errorf("(%v).HandleRequest(): got result (%v), expected (%v)",
+				test.worker, result, test.expectedResult)
+		}
+	}
+}
+
+type MockStationDAORecordingUpdate struct {
+	MockStationDAOSuccess
+	updated *model.Station
+}
+
+func (dao MockStationDAORecordingUpdate) Update(station model.Station) error {
+	*dao.updated = station
+	return dao.MockStationDAOSuccess.Update(station)
+}
+
+func TestUpdateStationWorker_HandleRequest_KeepActive(t *testing.T) {
+	var tests = []struct {
+		station        model.Station
+		keepActive     bool
+		expectedActive bool
+		expectedErr    bool
+	}{
+		{model.Station{ID: "kronehit", Name: "Kronehit"}, true, true, false},
+		{model.Station{ID: "kronehit", Name: "Kronehit"}, false, false, false},
+		{model.Station{ID: "hitradio-oe3", Name: "Hitradio Ö3", Active: true}, true, false, false},
+		{model.Station{ID: "fm4", Name: "FM4"}, true, false, true},
+	}
+
+	for _, test := range tests {
+		var updated model.Station
+		dao := MockStationDAORecordingUpdate{updated: &updated}
+		worker, _ := NewUpdateStationWorker(dao, test.station, test.keepActive)
+		_, err := worker.HandleRequest()
+		if (err != nil) != test.expectedErr {
+			t.Errorf("(%v).HandleRequest(): got err (%v), expected err: %v", worker, err,
+				test.expectedErr)
+			continue
+		}
+		if err == nil && updated.Active != test.expectedActive {
+			t.Errorf("(%v).HandleRequest(): got active %v, expected %v", worker, updated.Active,
+				test.expectedActive)
+		}
+	}
+}
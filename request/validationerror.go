@@ -0,0 +1,20 @@
+package request
+
+// ValidationError signals that the data sent by the client is invalid, as opposed to the worker
+// failing to serve the request.
+type ValidationError struct {
+	err error
+}
+
+func NewValidationError(err error) ValidationError {
+	return ValidationError{err}
+}
+
+func (err ValidationError) Error() string {
+	return err.err.Error()
+}
+
+func IsValidation(err error) bool {
+	_, ok := err.(ValidationError)
+	return ok
+}
@@ -7,19 +7,30 @@ import (
 
 type WeekSearchWorker struct {
 	SearchWorker
-	date time.Time
+	date      time.Time
+	weekStart time.Weekday
 }
 
-func NewWeekSearchWorker(dao datalayer.TrackRecordDAO, query string,
-	date time.Time) (WeekSearchWorker, error) {
-	searchWorker, err := NewSearchWorker(dao, query)
+func NewWeekSearchWorker(dao datalayer.TrackRecordDAO, rollups *Rollups, query, artist string,
+	date time.Time, weekStart time.Weekday) (WeekSearchWorker, error) {
+	searchWorker, err := NewSearchWorker(dao, query, artist)
 	if err != nil {
 		return WeekSearchWorker{}, err
 	}
-	return WeekSearchWorker{searchWorker, date}, nil
+	searchWorker.rollups = rollups
+	return WeekSearchWorker{searchWorker, date, weekStart}, nil
 }
 
 func (worker WeekSearchWorker) HandleRequest() (interface{}, error) {
-	startDate, endDate := calculateWeekBoundaries(worker.date)
+	startDate, endDate := calculateWeekBoundaries(worker.date, worker.weekStart)
 	return worker.Search(startDate, endDate)
 }
+
+func (worker WeekSearchWorker) Period() (time.Time, time.Time) {
+	return calculateWeekBoundaries(worker.date, worker.weekStart)
+}
+
+func (worker WeekSearchWorker) CacheKey() string {
+	startDate, _ := worker.Period()
+	return cacheKey("search", worker.cacheKeyQuery(), "week", formatCacheKeyDate(startDate))
+}
@@ -27,15 +27,16 @@ func TestNewWeekSearchWorker(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		result, err := NewWeekSearchWorker(test.dao, test.query, test.date)
+		result, err := NewWeekSearchWorker(test.dao, nil, test.query, "", test.date, time.Monday)
 		if (err != nil) != test.expectedErr {
 			t.Errorf("NewWeekSearchWorker(%q, %q, %q): got err (%v), expected err: %v",
 				test.dao, test.query, test.date, err, test.expectedErr)
 			continue
 		}
 		expectedResult := WeekSearchWorker{
-			SearchWorker{test.dao, strings.Split(strings.ToLower(test.query), queryStrKeywordsSeparator)},
+			SearchWorker{test.dao, nil, strings.Split(strings.ToLower(test.query), queryStrKeywordsSeparator), ""},
 			test.date,
+			time.Monday,
 		}
 		if err == nil && !reflect.DeepEqual(result, expectedResult) {
 			t.Errorf("NewWeekSearchWorker(%q, %q, %q): got result (%v), expected (%v)",
@@ -53,33 +54,37 @@ func TestWeekSearchWorker_HandleRequest(t *testing.T) {
 		expectedErr    bool
 	}{
 		{
-			WeekSearchWorker{SearchWorker{MockTrackRecordDAO{}, []string{"californication"}}, date},
+			WeekSearchWorker{SearchWorker{MockTrackRecordDAO{}, nil, []string{"californication"}, ""},
+				date, time.Monday},
 			matchedTracks0,
 			false,
 		},
 		{
-			WeekSearchWorker{SearchWorker{MockTrackRecordDAO{}, []string{"cali"}}, date},
+			WeekSearchWorker{SearchWorker{MockTrackRecordDAO{}, nil, []string{"cali"}, ""},
+				date, time.Monday},
 			matchedTracks1,
 			false,
 		},
 		{
-			WeekSearchWorker{SearchWorker{MockTrackRecordDAO{}, []string{"maggie", "rhcp"}}, date},
+			WeekSearchWorker{SearchWorker{MockTrackRecordDAO{}, nil, []string{"maggie", "rhcp"}, ""},
+				date, time.Monday},
 			matchedTracks2,
 			false,
 		},
 		{
-			WeekSearchWorker{SearchWorker{MockTrackRecordDAO{}, []string{"ø"}}, date},
+			WeekSearchWorker{SearchWorker{MockTrackRecordDAO{}, nil, []string{"mo"}, ""}, date, time.Monday},
 			matchedTracks3,
 			false,
 		},
 		{
-			WeekSearchWorker{SearchWorker{MockTrackRecordDAO{}, []string{"no", "tracks", "query"}}, date},
+			WeekSearchWorker{SearchWorker{MockTrackRecordDAO{}, nil, []string{"no", "tracks", "query"}, ""},
+				date, time.Monday},
 			model.MatchedTracks{},
 			false,
 		},
 		{
-			WeekSearchWorker{SearchWorker{MockTrackRecordDAOWeekVerifier{}, []string{"nevermind"}},
-				date},
+			WeekSearchWorker{SearchWorker{MockTrackRecordDAOWeekVerifier{}, nil, []string{"nevermind"}, ""},
+				date, time.Monday},
 			model.MatchedTracks{},
 			false,
 		},
@@ -2,54 +2,68 @@ package request
 
 import (
 	"github.com/RadioCheckerApp/api/datalayer"
-	"log"
 	"time"
 )
 
 type WeekTracksWorker struct {
 	TracksWorker
-	date   time.Time
-	filter Filter
+	date      time.Time
+	filter    Filter
+	weekStart time.Weekday
 }
 
-func NewWeekTracksWorker(dao datalayer.TrackRecordDAO, station string, date time.Time,
-	filter Filter) (WeekTracksWorker, error) {
+func NewWeekTracksWorker(dao datalayer.TrackRecordDAO, rollups *Rollups, station string,
+	date time.Time, filter Filter, weekStart time.Weekday, ranking Ranking) (WeekTracksWorker,
+	error) {
 	tracksWorker, err := NewTracksWorker(dao, station)
 	if err != nil {
 		return WeekTracksWorker{}, err
 	}
-	return WeekTracksWorker{tracksWorker, date, filter}, nil
+	tracksWorker.rollups = rollups
+	tracksWorker.ranking = ranking
+	return WeekTracksWorker{tracksWorker, date, filter, weekStart}, nil
 }
 
 func (worker WeekTracksWorker) HandleRequest() (interface{}, error) {
-	startDate, endDate := calculateWeekBoundaries(worker.date)
-	if worker.filter == Top {
+	startDate, endDate := calculateWeekBoundaries(worker.date, worker.weekStart)
+	switch worker.filter {
+	case Artists:
+		return worker.TopArtists(startDate, endDate)
+	case Top:
 		return worker.TopTracks(startDate, endDate)
+	default:
+		return worker.AllTracks(startDate, endDate)
 	}
-	return worker.AllTracks(startDate, endDate)
 }
 
-func calculateWeekBoundaries(date time.Time) (time.Time, time.Time) {
-	startDate := calculateFirstDateOfWeek(date)
-	endDate := startDate.AddDate(0, 0, 7).Add(-1 * time.Second)
-	return startDate, endDate
+func (worker WeekTracksWorker) Period() (time.Time, time.Time) {
+	return calculateWeekBoundaries(worker.date, worker.weekStart)
 }
 
-func calculateFirstDateOfWeek(date time.Time) time.Time {
-	dateWithoutTime := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, getLocation())
-	return dateWithoutTime.AddDate(0, 0, -normalizeWeekdayNumber(dateWithoutTime))
+// CacheKey identifies the week by its first date, hence all dates of a week share the result.
+func (worker WeekTracksWorker) CacheKey() string {
+	startDate, _ := worker.Period()
+	return cacheKey("tracks", worker.station, "week", formatCacheKeyDate(startDate),
+		worker.filter.String(), worker.ranking.String())
 }
 
-func getLocation() *time.Location {
-	location, err := time.LoadLocation("Europe/Berlin")
-	if err != nil {
-		log.Fatal("unable to load timezone location `Europe/Berlin`")
-	}
-	return location
+func calculateWeekBoundaries(date time.Time, weekStart time.Weekday) (time.Time, time.Time) {
+	startDate := calculateFirstDateOfWeek(date, weekStart)
+	endDate := startDate.AddDate(0, 0, 7).Add(-1 * time.Second)
+	return startDate, endDate
+}
+
+// calculateFirstDateOfWeek returns the first date of the week in the date's location.
+func calculateFirstDateOfWeek(date time.Time, weekStart time.Weekday) time.Time {
+	dateWithoutTime := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0,
+		date.Location())
+	return dateWithoutTime.AddDate(0, 0, -normalizeWeekdayNumber(dateWithoutTime, weekStart))
 }
 
-func normalizeWeekdayNumber(date time.Time) int {
+// normalizeWeekdayNumber returns the position of the date within a week beginning on `weekStart`,
+// e. g. 0 for a Monday and 6 for a Sunday if the week starts on Monday.
+func normalizeWeekdayNumber(date time.Time, weekStart time.Weekday) int {
 	// Sunday = 0, ..., Saturday = 6
 	usWeekdayNumber := date.Weekday()
-	return int((usWeekdayNumber + 6) % 7)
+	return int((usWeekdayNumber - weekStart + 7) % 7)
 }
@@ -50,6 +50,28 @@ func (dao MockTrackRecordDAOWeekVerifier) CreateTrackRecord(trackRecord model.Tr
 	return nil
 }
 
+func (dao MockTrackRecordDAOWeekVerifier) UpdateTrackRecord(trackRecord model.TrackRecord) error {
+	return nil
+}
+
+func (dao MockTrackRecordDAOWeekVerifier) DeleteTrackRecord(station string, airtime int64) error {
+	return nil
+}
+
+func (dao MockTrackRecordDAOWeekVerifier) GetRecords(types []string, start,
+	end time.Time) ([]model.TrackRecord, error) {
+	return dao.GetTrackRecords(start, end)
+}
+
+func (dao MockTrackRecordDAOWeekVerifier) GetRecordsByStation(stationId string, types []string,
+	start, end time.Time) ([]model.TrackRecord, error) {
+	return dao.GetTrackRecordsByStation(stationId, start, end)
+}
+
+func (dao MockTrackRecordDAOWeekVerifier) PutTrackRecords(trackRecords []model.TrackRecord) error {
+	return nil
+}
+
 func TestNewWeekTracksWorker(t *testing.T) {
 	var tests = []struct {
 		dao         datalayer.TrackRecordDAO
@@ -70,14 +92,15 @@ func TestNewWeekTracksWorker(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		result, err := NewWeekTracksWorker(test.dao, test.station, test.date, test.filter)
+		result, err := NewWeekTracksWorker(test.dao, nil, test.station, test.date, test.filter,
+			time.Monday, DefaultRanking)
 		if (err != nil) != test.expectedErr {
 			t.Errorf("TestWeekDayTracksWorker(%q, %q, %q, %q): got err (%v), expected err: %v",
 				test.dao, test.station, test.date, test.filter, err, test.expectedErr)
 			continue
 		}
-		expectedResult := WeekTracksWorker{TracksWorker{test.dao, test.station}, test.date,
-			test.filter}
+		expectedResult := WeekTracksWorker{TracksWorker{test.dao, nil, test.station, DefaultRanking}, test.date,
+			test.filter, time.Monday}
 		if err == nil && !reflect.DeepEqual(result, expectedResult) {
 			t.Errorf("TestWeekDayTracksWorker(%q, %q, %q, %q): got result (%v), expected (%v)",
 				test.dao, test.station, test.date, test.filter, result, expectedResult)
@@ -94,19 +117,20 @@ func TestWeekTracksWorker_HandleRequest_TopTracks(t *testing.T) {
 		expectedErr    bool
 	}{
 		{
-			WeekTracksWorker{TracksWorker{MockTrackRecordDAO{}, "station-A"}, date, Top},
+			WeekTracksWorker{TracksWorker{MockTrackRecordDAO{}, nil, "station-A", DefaultRanking},
+				date, Top, time.Monday},
 			countedTracks,
 			false,
 		},
 		{
-			WeekTracksWorker{TracksWorker{MockTrackRecordDAO{}, "notracksstation"}, date,
-				Top},
+			WeekTracksWorker{TracksWorker{MockTrackRecordDAO{}, nil, "notracksstation", DefaultRanking}, date,
+				Top, time.Monday},
 			model.CountedTracks{},
 			false,
 		},
 		{
-			WeekTracksWorker{TracksWorker{MockTrackRecordDAOWeekVerifier{}, "nevermind"}, date,
-				Top},
+			WeekTracksWorker{TracksWorker{MockTrackRecordDAOWeekVerifier{}, nil, "nevermind", DefaultRanking}, date,
+				Top, time.Monday},
 			model.CountedTracks{},
 			false,
 		},
@@ -153,18 +177,20 @@ func TestWeekTracksWorker_HandleRequest_AllTracks(t *testing.T) {
 		expectedErr    bool
 	}{
 		{
-			WeekTracksWorker{TracksWorker{MockTrackRecordDAO{}, "station-A"}, date, All},
+			WeekTracksWorker{TracksWorker{MockTrackRecordDAO{}, nil, "station-A", DefaultRanking},
+				date, All, time.Monday},
 			tracks,
 			false,
 		},
 		{
-			WeekTracksWorker{TracksWorker{MockTrackRecordDAO{}, "notracksstation"}, date, All},
+			WeekTracksWorker{TracksWorker{MockTrackRecordDAO{}, nil, "notracksstation", DefaultRanking},
+				date, All, time.Monday},
 			model.Tracks{},
 			false,
 		},
 		{
-			WeekTracksWorker{TracksWorker{MockTrackRecordDAOWeekVerifier{}, "nevermind"}, date,
-				All},
+			WeekTracksWorker{TracksWorker{MockTrackRecordDAOWeekVerifier{}, nil, "nevermind", DefaultRanking}, date,
+				All, time.Monday},
 			model.Tracks{},
 			false,
 		},
@@ -212,3 +238,44 @@ func TestWeekTracksWorker_HandleRequest_AllTracks(t *testing.T) {
 		}
 	}
 }
+
+func TestCalculateWeekBoundaries(t *testing.T) {
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	// Wednesday, 2018-02-14
+	date := time.Date(2018, 2, 14, 13, 37, 0, 0, loc)
+
+	var tests = []struct {
+		weekStart     time.Weekday
+		expectedStart time.Time
+		expectedEnd   time.Time
+	}{
+		{
+			time.Monday,
+			time.Date(2018, 2, 12, 0, 0, 0, 0, loc),
+			time.Date(2018, 2, 18, 23, 59, 59, 0, loc),
+		},
+		{
+			time.Sunday,
+			time.Date(2018, 2, 11, 0, 0, 0, 0, loc),
+			time.Date(2018, 2, 17, 23, 59, 59, 0, loc),
+		},
+		{
+			time.Saturday,
+			time.Date(2018, 2, 10, 0, 0, 0, 0, loc),
+			time.Date(2018, 2, 16, 23, 59, 59, 0, loc),
+		},
+		{
+			time.Wednesday,
+			time.Date(2018, 2, 14, 0, 0, 0, 0, loc),
+			time.Date(2018, 2, 20, 23, 59, 59, 0, loc),
+		},
+	}
+
+	for _, test := range tests {
+		start, end := calculateWeekBoundaries(date, test.weekStart)
+		if !start.Equal(test.expectedStart) || !end.Equal(test.expectedEnd) {
+			t.Errorf("calculateWeekBoundaries(%v, %v): got (%v, %v), expected (%v, %v)",
+				date, test.weekStart, start, end, test.expectedStart, test.expectedEnd)
+		}
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"errors"
 	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/export"
 	"github.com/RadioCheckerApp/api/model"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -18,27 +20,139 @@ const (
 	All
 	Top
 	Latest
+	Artists
 )
 
+func (filter Filter) String() string {
+	switch filter {
+	case All:
+		return "all"
+	case Top:
+		return "top"
+	case Latest:
+		return "latest"
+	case Artists:
+		return "artists"
+	default:
+		return "err"
+	}
+}
+
 const (
 	queryStrDateParam      = "date"
 	queryStrWeekParam      = "week"
 	queryStrFilterParam    = "filter"
 	queryStrStationParam   = "station"
 	queryStrQueryParam     = "q"
+	queryStrArtistParam    = "artist"
 	queryStrTimestampParam = "timestamp"
+	queryStrWeekStartParam = "weekStart"
+	queryStrActiveParam    = "active"
+	queryStrCountryParam   = "country"
+	queryStrGenreParam     = "genre"
+	queryStrLanguageParam  = "language"
+	queryStrGroupParam     = "group"
+	queryStrFromParam      = "from"
+	queryStrToParam        = "to"
+	queryStrFormatParam    = "format"
+	queryStrTypesParam     = "types"
 )
 
+var isoWeekRegexp = regexp.MustCompile(`^(\d{4})-W(\d{2})$`)
+
 func CreateMetaWorker() Worker {
 	return MetaWorker{}
 }
 
-func CreateStationsWorker(dao datalayer.StationDAO) (Worker, error) {
-	return NewStationsWorker(dao)
+func CreateStationsWorker(dao datalayer.StationDAO, queryStringParams map[string]string) (Worker,
+	error) {
+	filter, err := getStationsFilter(queryStringParams)
+	if err != nil {
+		return nil, err
+	}
+	return NewStationsWorker(dao, filter)
+}
+
+func getStationsFilter(queryStringParams map[string]string) (StationsFilter, error) {
+	var filter StationsFilter
+	if activeStr, ok := queryStringParams[queryStrActiveParam]; ok && activeStr != "" {
+		active, err := strconv.ParseBool(activeStr)
+		if err != nil {
+			return StationsFilter{}, errors.New("invalid active filter provided")
+		}
+		filter.Active = &active
+	}
+	filter.Country = strings.TrimSpace(queryStringParams[queryStrCountryParam])
+	filter.Genre = strings.TrimSpace(queryStringParams[queryStrGenreParam])
+	filter.Language = strings.TrimSpace(queryStringParams[queryStrLanguageParam])
+	return filter, nil
+}
+
+func CreateStationStatusWorker(sDAO datalayer.StationDAO, trDAO datalayer.TrackRecordDAO,
+	pathParams map[string]string, settings Settings) (Worker, error) {
+	stationId, err := getStation(pathParams)
+	if err != nil {
+		return nil, err
+	}
+	return NewStationStatusWorker(sDAO, trDAO, stationId, settings.Location)
+}
+
+func CreateCreateStationWorker(dao datalayer.StationDAO, pathParams map[string]string,
+	body []byte) (Worker, error) {
+	station, err := getStationFromBody(pathParams, body)
+	if err != nil {
+		return nil, err
+	}
+	return NewCreateStationWorker(dao, station)
 }
 
-func CreateTracksWorker(dao datalayer.TrackRecordDAO, pathParams,
-	queryStringParams map[string]string) (Worker, error) {
+func CreateUpdateStationWorker(dao datalayer.StationDAO, pathParams map[string]string,
+	body []byte) (Worker, error) {
+	station, err := getStationFromBody(pathParams, body)
+	if err != nil {
+		return nil, err
+	}
+	var fields struct {
+		Active *bool `json:"active"`
+	}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, errors.New("request body contains invalid JSON")
+	}
+	return NewUpdateStationWorker(dao, station, fields.Active == nil)
+}
+
+func CreateDeactivateStationWorker(dao datalayer.StationDAO, pathParams map[string]string) (Worker,
+	error) {
+	stationId, err := getStation(pathParams)
+	if err != nil {
+		return nil, err
+	}
+	return NewDeactivateStationWorker(dao, stationId)
+}
+
+// getStationFromBody unmarshals the station contained in the request's body. The station ID is
+// taken from the path; a diverging ID in the body is rejected.
+func getStationFromBody(pathParams map[string]string, body []byte) (model.Station, error) {
+	stationId, err := getStation(pathParams)
+	if err != nil {
+		return model.Station{}, err
+	}
+
+	var station model.Station
+	if err := json.Unmarshal(body, &station); err != nil {
+		return model.Station{}, errors.New("request body contains invalid JSON")
+	}
+	if station.ID != "" && strings.ToLower(station.ID) != stationId {
+		return model.Station{}, errors.New("stationId of request body does not match path")
+	}
+	station.ID = stationId
+	return station, nil
+}
+
+// CreateTracksWorker creates the worker serving a station's tracks. Rollups may be nil, in which
+// case all plays are counted from the track records.
+func CreateTracksWorker(dao datalayer.TrackRecordDAO, rollups *Rollups, pathParams,
+	queryStringParams map[string]string, settings Settings) (Worker, error) {
 	station, err := getStation(pathParams)
 	if err != nil {
 		return nil, err
@@ -54,24 +168,131 @@ func CreateTracksWorker(dao datalayer.TrackRecordDAO, pathParams,
 	}
 
 	if formattedDateStr, ok := queryStringParams[queryStrDateParam]; ok {
-		date, err := createDate(formattedDateStr)
+		date, err := createDate(formattedDateStr, settings.Location)
 		if err != nil {
 			return nil, err
 		}
-		return NewDayTracksWorker(dao, station, date, filter)
+		return NewDayTracksWorker(dao, rollups, station, date, filter, settings.Ranking)
 	}
 
 	if formattedDateStr, ok := queryStringParams[queryStrWeekParam]; ok {
-		date, err := createDate(formattedDateStr)
+		date, err := createWeekDate(formattedDateStr, settings.Location)
+		if err != nil {
+			return nil, err
+		}
+		weekStart, err := getWeekStart(queryStringParams)
 		if err != nil {
 			return nil, err
 		}
-		return NewWeekTracksWorker(dao, station, date, filter)
+		return NewWeekTracksWorker(dao, rollups, station, date, filter, weekStart, settings.Ranking)
 	}
 
 	return nil, errors.New("invalid/insufficient parameter(s) provided")
 }
 
+// CreateGroupTracksWorker creates the worker serving the tracks of a station group. Rollups may be
+// nil, in which case all plays are counted from the track records.
+func CreateGroupTracksWorker(groupDAO datalayer.StationGroupDAO,
+	trackRecordDAO datalayer.TrackRecordDAO, rollups *Rollups, pathParams,
+	queryStringParams map[string]string, settings Settings) (Worker, error) {
+	groupId, err := getGroup(pathParams)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := getFilter(queryStringParams)
+	if err != nil {
+		return nil, err
+	}
+	if filter == Latest || filter == Artists {
+		return nil, errors.New("filter `" + filter.String() + "` is not supported for groups")
+	}
+
+	if formattedDateStr, ok := queryStringParams[queryStrDateParam]; ok {
+		date, err := createDate(formattedDateStr, settings.Location)
+		if err != nil {
+			return nil, err
+		}
+		startDate, endDate := calculateDayBoundaries(date)
+		return NewGroupTracksWorker(groupDAO, trackRecordDAO, rollups, groupId, startDate, endDate,
+			filter, settings.Ranking)
+	}
+
+	if formattedDateStr, ok := queryStringParams[queryStrWeekParam]; ok {
+		date, err := createWeekDate(formattedDateStr, settings.Location)
+		if err != nil {
+			return nil, err
+		}
+		weekStart, err := getWeekStart(queryStringParams)
+		if err != nil {
+			return nil, err
+		}
+		startDate, endDate := calculateWeekBoundaries(date, weekStart)
+		return NewGroupTracksWorker(groupDAO, trackRecordDAO, rollups, groupId, startDate, endDate,
+			filter, settings.Ranking)
+	}
+
+	return nil, errors.New("invalid/insufficient parameter(s) provided")
+}
+
+func CreateStationGroupsWorker(dao datalayer.StationGroupDAO) (Worker, error) {
+	return NewStationGroupsWorker(dao)
+}
+
+func CreateCreateStationGroupWorker(dao datalayer.StationGroupDAO, pathParams map[string]string,
+	body []byte) (Worker, error) {
+	group, err := getGroupFromBody(pathParams, body)
+	if err != nil {
+		return nil, err
+	}
+	return NewCreateStationGroupWorker(dao, group)
+}
+
+func CreateUpdateStationGroupWorker(dao datalayer.StationGroupDAO, pathParams map[string]string,
+	body []byte) (Worker, error) {
+	group, err := getGroupFromBody(pathParams, body)
+	if err != nil {
+		return nil, err
+	}
+	return NewUpdateStationGroupWorker(dao, group)
+}
+
+func CreateDeleteStationGroupWorker(dao datalayer.StationGroupDAO,
+	pathParams map[string]string) (Worker, error) {
+	groupId, err := getGroup(pathParams)
+	if err != nil {
+		return nil, err
+	}
+	return NewDeleteStationGroupWorker(dao, groupId)
+}
+
+// getGroupFromBody unmarshals the station group contained in the request's body. The group ID is
+// taken from the path; a diverging ID in the body is rejected.
+func getGroupFromBody(pathParams map[string]string, body []byte) (model.StationGroup, error) {
+	groupId, err := getGroup(pathParams)
+	if err != nil {
+		return model.StationGroup{}, err
+	}
+
+	var group model.StationGroup
+	if err := json.Unmarshal(body, &group); err != nil {
+		return model.StationGroup{}, errors.New("request body contains invalid JSON")
+	}
+	if group.ID != "" && strings.ToLower(group.ID) != groupId {
+		return model.StationGroup{}, errors.New("groupId of request body does not match path")
+	}
+	group.ID = groupId
+	return group, nil
+}
+
+func getGroup(pathParams map[string]string) (string, error) {
+	group, ok := pathParams[queryStrGroupParam]
+	if !ok || group == "" {
+		return "", errors.New("path parameter `group` missing/invalid")
+	}
+	return strings.ToLower(group), nil
+}
+
 func getStation(pathParams map[string]string) (string, error) {
 	station, ok := pathParams[queryStrStationParam]
 	if !ok || station == "" {
@@ -89,39 +310,138 @@ func getFilter(queryStringParams map[string]string) (Filter, error) {
 		return All, nil
 	case "latest":
 		return Latest, nil
+	case "artists":
+		return Artists, nil
 	default:
 		return Err, errors.New("invalid filter provided")
 	}
 }
 
-func createDate(formattedDateStr string) (time.Time, error) {
-	date, err := time.ParseInLocation("2006-01-02", formattedDateStr, getLocation())
+// CreateExportWorker exports the station's track records aired from the start of the `from` day to
+// the end of the `to` day. The format defaults to CSV, the types to tracks.
+func CreateExportWorker(dao datalayer.TrackRecordDAO, pathParams,
+	queryStringParams map[string]string, settings Settings) (Worker, error) {
+	station, err := getStation(pathParams)
+	if err != nil {
+		return nil, err
+	}
+	startDate, err := createDate(queryStringParams[queryStrFromParam], settings.Location)
+	if err != nil {
+		return nil, err
+	}
+	toDate, err := createDate(queryStringParams[queryStrToParam], settings.Location)
+	if err != nil {
+		return nil, err
+	}
+	_, endDate := calculateDayBoundaries(toDate)
+
+	format := export.CSV
+	if formatStr := queryStringParams[queryStrFormatParam]; formatStr != "" {
+		if format, err = export.ParseFormat(strings.ToLower(formatStr)); err != nil {
+			return nil, err
+		}
+	}
+	types, err := getRecordTypes(queryStringParams)
+	if err != nil {
+		return nil, err
+	}
+	return NewExportWorker(dao, station, types, startDate, endDate, format)
+}
+
+func getRecordTypes(queryStringParams map[string]string) ([]string, error) {
+	typesStr, ok := queryStringParams[queryStrTypesParam]
+	if !ok || typesStr == "" {
+		return []string{model.TypeTrack}, nil
+	}
+	return model.ParseRecordTypes(typesStr)
+}
+
+// CreateAirtimeWorker estimates the airtime shares of the station's `date` per hour.
+func CreateAirtimeWorker(dao datalayer.TrackRecordDAO, pathParams,
+	queryStringParams map[string]string, settings Settings) (Worker, error) {
+	station, err := getStation(pathParams)
+	if err != nil {
+		return nil, err
+	}
+	date, err := createDate(queryStringParams[queryStrDateParam], settings.Location)
+	if err != nil {
+		return nil, err
+	}
+	return NewAirtimeWorker(dao, station, date)
+}
+
+func createDate(formattedDateStr string, location *time.Location) (time.Time, error) {
+	date, err := time.ParseInLocation("2006-01-02", formattedDateStr, location)
 	if err != nil {
 		return time.Time{}, errors.New("invalid date format provided")
 	}
 	return date, err
 }
 
-func CreateSearchWorker(dao datalayer.TrackRecordDAO, queryStringParams map[string]string) (Worker, error) {
+// createWeekDate accepts either a date (`2018-02-12`) or an ISO week (`2018-W07`). The latter
+// resolves to the Monday of the given ISO week.
+func createWeekDate(formattedWeekStr string, location *time.Location) (time.Time, error) {
+	matches := isoWeekRegexp.FindStringSubmatch(formattedWeekStr)
+	if matches == nil {
+		return createDate(formattedWeekStr, location)
+	}
+
+	year, _ := strconv.Atoi(matches[1])
+	week, _ := strconv.Atoi(matches[2])
+
+	// January 4th is always part of the first ISO week of a year
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, location)
+	date := jan4.AddDate(0, 0, -normalizeWeekdayNumber(jan4, time.Monday)+(week-1)*7)
+
+	if isoYear, isoWeek := date.ISOWeek(); week < 1 || isoYear != year || isoWeek != week {
+		return time.Time{}, errors.New("invalid week format provided")
+	}
+	return date, nil
+}
+
+func getWeekStart(queryStringParams map[string]string) (time.Weekday, error) {
+	weekStartStr, _ := queryStringParams[queryStrWeekStartParam]
+	switch strings.ToLower(weekStartStr) {
+	case "monday", "":
+		return time.Monday, nil
+	case "sunday":
+		return time.Sunday, nil
+	case "saturday":
+		return time.Saturday, nil
+	default:
+		return time.Monday, errors.New("invalid week start provided")
+	}
+}
+
+// CreateSearchWorker creates the worker searching the tracks of all stations. Rollups may be nil,
+// in which case all plays are counted from the track records.
+func CreateSearchWorker(dao datalayer.TrackRecordDAO, rollups *Rollups,
+	queryStringParams map[string]string, settings Settings) (Worker, error) {
+	// searching for an artist requires no further keywords
+	artist := strings.TrimSpace(queryStringParams[queryStrArtistParam])
 	query, err := getQuery(queryStringParams)
-	if err != nil {
+	if err != nil && artist == "" {
 		return nil, err
 	}
 
 	if formattedDateStr, ok := queryStringParams[queryStrDateParam]; ok {
-		date, err := createDate(formattedDateStr)
+		date, err := createDate(formattedDateStr, settings.Location)
 		if err != nil {
 			return nil, err
 		}
-		return NewDaySearchWorker(dao, query, date)
+		return NewDaySearchWorker(dao, rollups, query, artist, date)
 	}
 
 	if formattedDateStr, ok := queryStringParams[queryStrWeekParam]; ok {
-		date, err := createDate(formattedDateStr)
+		date, err := createWeekDate(formattedDateStr, settings.Location)
 		if err != nil {
 			return nil, err
 		}
-		return NewWeekSearchWorker(dao, query, date)
+		weekStart, err := getWeekStart(queryStringParams)
+		if err != nil {
+			return nil, err
+		}
+		return NewWeekSearchWorker(dao, rollups, query, artist, date, weekStart)
 	}
 
 	return nil, errors.New("invalid/insufficient parameter(s) provided")
@@ -135,8 +455,11 @@ func getQuery(queryStringParams map[string]string) (string, error) {
 	return query, nil
 }
 
-func CreateCreateTrackWorker(trDAO datalayer.TrackRecordDAO, sDAO datalayer.StationDAO,
-	pathParams map[string]string, body []byte) (Worker, error) {
+// CreateCreateTrackWorker creates the worker persisting a track record reported by the crawler
+// identified by `principalID`.
+func CreateCreateTrackWorker(trDAO datalayer.TrackRecordDAO, stations *StationCache,
+	rollups *Rollups, pathParams map[string]string, body []byte, principalID string,
+	settings Settings) (Worker, error) {
 	station, err := getStation(pathParams)
 	if err != nil {
 		return nil, err
@@ -147,13 +470,15 @@ func CreateCreateTrackWorker(trDAO datalayer.TrackRecordDAO, sDAO datalayer.Stat
 		return nil, err
 	}
 
-	track, err := getTrack(body)
+	trackRecord, err := getTrackRecord(body)
 	if err != nil {
 		return nil, err
 	}
-
-	trackRecord := model.TrackRecord{station, timestamp, "track", track}
-	return NewCreateTrackWorker(trDAO, sDAO, trackRecord)
+	trackRecord.StationId = station
+	trackRecord.Timestamp = timestamp
+	trackRecord.PrincipalID = principalID
+	return NewCreateTrackWorker(trDAO, stations, rollups, trackRecord, settings.TrackRecordRules,
+		settings.Duplicates)
 }
 
 func getTimestamp(pathParams map[string]string) (int64, error) {
@@ -164,14 +489,24 @@ func getTimestamp(pathParams map[string]string) (int64, error) {
 	return strconv.ParseInt(timestamp, 10, 64)
 }
 
-func getTrack(body []byte) (model.Track, error) {
+// getTrackRecord returns the type, track and metadata of the body; crawlers reporting music only
+// omit the type.
+func getTrackRecord(body []byte) (model.TrackRecord, error) {
 	// TODO: Implement Unmarshaller interface in model.Track
-	var track model.Track
-	if err := json.Unmarshal(body, &track); err != nil {
-		return model.Track{}, errors.New("request body contains invalid JSON")
+	var content struct {
+		model.Track
+		model.TrackMetadata
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(body, &content); err != nil {
+		return model.TrackRecord{}, errors.New("request body contains invalid JSON")
+	}
+	if reflect.DeepEqual(content.Track, model.Track{}) && content.Type == "" {
+		return model.TrackRecord{}, errors.New("request body contains invalid data")
 	}
-	if reflect.DeepEqual(track, model.Track{}) {
-		return model.Track{}, errors.New("request body contains invalid data")
+	if content.Type == "" {
+		content.Type = model.TypeTrack
 	}
-	return track, nil
+	return model.TrackRecord{Type: content.Type, Track: content.Track,
+		TrackMetadata: content.TrackMetadata}, nil
 }
@@ -2,6 +2,7 @@ package request
 
 import (
 	"github.com/RadioCheckerApp/api/datalayer"
+	"github.com/RadioCheckerApp/api/export"
 	"github.com/RadioCheckerApp/api/model"
 	"reflect"
 	"testing"
@@ -25,12 +26,23 @@ func TestCreateTracksWorker(t *testing.T) {
 			map[string]string{"station": "station-a"},
 			map[string]string{"date": dateStr, "filter": "top"},
 			DayTracksWorker{
-				TracksWorker{MockTrackRecordDAO{}, "station-a"},
+				TracksWorker{MockTrackRecordDAO{}, nil, "station-a", DefaultRanking},
 				time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc),
 				Top,
 			},
 			false,
 		},
+		{
+			MockTrackRecordDAO{},
+			map[string]string{"station": "station-a"},
+			map[string]string{"date": dateStr, "filter": "Artists"},
+			DayTracksWorker{
+				TracksWorker{MockTrackRecordDAO{}, nil, "station-a", DefaultRanking},
+				time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc),
+				Artists,
+			},
+			false,
+		},
 		{
 			MockTrackRecordDAO{},
 			map[string]string{"station": ""},
@@ -43,18 +55,57 @@ func TestCreateTracksWorker(t *testing.T) {
 			map[string]string{"station": "station-a"},
 			map[string]string{"week": dateStr, "filter": "top"},
 			WeekTracksWorker{
-				TracksWorker{MockTrackRecordDAO{}, "station-a"},
+				TracksWorker{MockTrackRecordDAO{}, nil, "station-a", DefaultRanking},
+				time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc),
+				Top,
+				time.Monday,
+			},
+			false,
+		},
+		{
+			MockTrackRecordDAO{},
+			map[string]string{"station": "station-a"},
+			map[string]string{"week": "2018-W07", "filter": "top"},
+			WeekTracksWorker{
+				TracksWorker{MockTrackRecordDAO{}, nil, "station-a", DefaultRanking},
+				time.Date(2018, 2, 12, 0, 0, 0, 0, loc),
+				Top,
+				time.Monday,
+			},
+			false,
+		},
+		{
+			MockTrackRecordDAO{},
+			map[string]string{"station": "station-a"},
+			map[string]string{"week": dateStr, "filter": "top", "weekStart": "Sunday"},
+			WeekTracksWorker{
+				TracksWorker{MockTrackRecordDAO{}, nil, "station-a", DefaultRanking},
 				time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc),
 				Top,
+				time.Sunday,
 			},
 			false,
 		},
+		{
+			MockTrackRecordDAO{},
+			map[string]string{"station": "station-a"},
+			map[string]string{"week": dateStr, "filter": "top", "weekStart": "friday"},
+			nil,
+			true,
+		},
+		{
+			MockTrackRecordDAO{},
+			map[string]string{"station": "station-a"},
+			map[string]string{"week": "2018-W54", "filter": "top"},
+			nil,
+			true,
+		},
 		{
 			MockTrackRecordDAO{},
 			map[string]string{"station": "station-a"},
 			map[string]string{"date": dateStr, "filter": "all"},
 			DayTracksWorker{
-				TracksWorker{MockTrackRecordDAO{}, "station-a"},
+				TracksWorker{MockTrackRecordDAO{}, nil, "station-a", DefaultRanking},
 				time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc),
 				All,
 			},
@@ -65,9 +116,10 @@ func TestCreateTracksWorker(t *testing.T) {
 			map[string]string{"station": "station-a"},
 			map[string]string{"week": dateStr, "filter": "all"},
 			WeekTracksWorker{
-				TracksWorker{MockTrackRecordDAO{}, "station-a"},
+				TracksWorker{MockTrackRecordDAO{}, nil, "station-a", DefaultRanking},
 				time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc),
 				All,
+				time.Monday,
 			},
 			false,
 		},
@@ -76,9 +128,10 @@ func TestCreateTracksWorker(t *testing.T) {
 			map[string]string{"station": "CamelCaseStation"},
 			map[string]string{"week": dateStr, "filter": "all"},
 			WeekTracksWorker{
-				TracksWorker{MockTrackRecordDAO{}, "camelcasestation"},
+				TracksWorker{MockTrackRecordDAO{}, nil, "camelcasestation", DefaultRanking},
 				time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc),
 				All,
+				time.Monday,
 			},
 			false,
 		},
@@ -108,9 +161,10 @@ func TestCreateTracksWorker(t *testing.T) {
 			map[string]string{"station": "noTracksStation"},
 			map[string]string{"week": dateStr, "filter": ""},
 			WeekTracksWorker{
-				TracksWorker{MockTrackRecordDAO{}, "notracksstation"},
+				TracksWorker{MockTrackRecordDAO{}, nil, "notracksstation", DefaultRanking},
 				time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc),
 				Top,
+				time.Monday,
 			},
 			false,
 		},
@@ -119,7 +173,7 @@ func TestCreateTracksWorker(t *testing.T) {
 			map[string]string{"station": "missingFilter"},
 			map[string]string{"date": dateStr},
 			DayTracksWorker{
-				TracksWorker{MockTrackRecordDAO{}, "missingfilter"},
+				TracksWorker{MockTrackRecordDAO{}, nil, "missingfilter", DefaultRanking},
 				time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc),
 				Top,
 			},
@@ -136,14 +190,14 @@ func TestCreateTracksWorker(t *testing.T) {
 			MockTrackRecordDAO{},
 			map[string]string{"station": "station-a"},
 			map[string]string{"filter": "latest"},
-			TracksWorker{MockTrackRecordDAO{}, "station-a"},
+			TracksWorker{MockTrackRecordDAO{}, nil, "station-a", DefaultRanking},
 			false,
 		},
 		{
 			MockTrackRecordDAO{},
 			map[string]string{"station": "station-a"},
 			map[string]string{"date": "2018-08-26", "filter": "latest"},
-			TracksWorker{MockTrackRecordDAO{}, "station-a"},
+			TracksWorker{MockTrackRecordDAO{}, nil, "station-a", DefaultRanking},
 			false,
 		},
 		{
@@ -156,7 +210,8 @@ func TestCreateTracksWorker(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		result, err := CreateTracksWorker(test.dao, test.pathParams, test.queryStringParams)
+		result, err := CreateTracksWorker(test.dao, nil, test.pathParams, test.queryStringParams,
+			DefaultSettings())
 		if (err != nil) != test.expectedErr {
 			t.Errorf("CreateTracksWorker(%q, %q, %q): got (%q, %v), expected error: %v",
 				test.dao, test.pathParams, test.queryStringParams, result, err,
@@ -193,7 +248,7 @@ func TestCreateSearchWorker(t *testing.T) {
 			MockTrackRecordDAO{},
 			map[string]string{"date": dateStr, "q": "dani+california"},
 			DaySearchWorker{
-				SearchWorker{MockTrackRecordDAO{}, []string{"dani", "california"}},
+				SearchWorker{MockTrackRecordDAO{}, nil, []string{"dani", "california"}, ""},
 				time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc),
 			},
 			false,
@@ -202,11 +257,47 @@ func TestCreateSearchWorker(t *testing.T) {
 			MockTrackRecordDAO{},
 			map[string]string{"week": dateStr, "q": "dani+california"},
 			WeekSearchWorker{
-				SearchWorker{MockTrackRecordDAO{}, []string{"dani", "california"}},
+				SearchWorker{MockTrackRecordDAO{}, nil, []string{"dani", "california"}, ""},
+				time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc),
+				time.Monday,
+			},
+			false,
+		},
+		{
+			MockTrackRecordDAO{},
+			map[string]string{"week": "2020-W53", "q": "dani+california", "weekStart": "saturday"},
+			WeekSearchWorker{
+				SearchWorker{MockTrackRecordDAO{}, nil, []string{"dani", "california"}, ""},
+				time.Date(2020, 12, 28, 0, 0, 0, 0, loc),
+				time.Saturday,
+			},
+			false,
+		},
+		// the artist is searched with or without keywords
+		{
+			MockTrackRecordDAO{},
+			map[string]string{"date": dateStr, "artist": "Kid Ink "},
+			DaySearchWorker{
+				SearchWorker{MockTrackRecordDAO{}, nil, nil, "kid ink"},
+				time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc),
+			},
+			false,
+		},
+		{
+			MockTrackRecordDAO{},
+			map[string]string{"date": dateStr, "q": "know", "artist": "Kid Ink"},
+			DaySearchWorker{
+				SearchWorker{MockTrackRecordDAO{}, nil, []string{"know"}, "kid ink"},
 				time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc),
 			},
 			false,
 		},
+		{
+			MockTrackRecordDAO{},
+			map[string]string{"date": dateStr, "artist": " "},
+			nil,
+			true,
+		},
 		{
 			MockTrackRecordDAO{},
 			map[string]string{"date": "2018-07-32", "q": "dani+california"},
@@ -246,7 +337,7 @@ func TestCreateSearchWorker(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		result, err := CreateSearchWorker(test.dao, test.queryStringParams)
+		result, err := CreateSearchWorker(test.dao, nil, test.queryStringParams, DefaultSettings())
 		if (err != nil) != test.expectedErr {
 			t.Errorf("CreateSearchWorker(%q, %q): got (%q, %v), expected error: %v",
 				test.dao, test.queryStringParams, result, err,
@@ -268,10 +359,46 @@ func TestCreateSearchWorker(t *testing.T) {
 	}
 }
 
+func TestCreateWeekDate(t *testing.T) {
+	loc, _ := time.LoadLocation("Europe/Berlin")
+
+	var tests = []struct {
+		input          string
+		expectedResult time.Time
+		expectedErr    bool
+	}{
+		{"2018-02-14", time.Date(2018, 2, 14, 0, 0, 0, 0, loc), false},
+		{"2018-W07", time.Date(2018, 2, 12, 0, 0, 0, 0, loc), false},
+		{"2018-W01", time.Date(2018, 1, 1, 0, 0, 0, 0, loc), false},
+		{"2019-W01", time.Date(2018, 12, 31, 0, 0, 0, 0, loc), false},
+		{"2021-W01", time.Date(2021, 1, 4, 0, 0, 0, 0, loc), false},
+		{"2020-W53", time.Date(2020, 12, 28, 0, 0, 0, 0, loc), false},
+		{"2018-W53", time.Time{}, true},
+		{"2018-W00", time.Time{}, true},
+		{"2018-W7", time.Time{}, true},
+		{"2018W07", time.Time{}, true},
+	}
+
+	for _, test := range tests {
+		result, err := createWeekDate(test.input, DefaultSettings().Location)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("createWeekDate(%q): got err (%v), expected err: %v",
+				test.input, err, test.expectedErr)
+			continue
+		}
+		if !result.Equal(test.expectedResult) {
+			t.Errorf("createWeekDate(%q): got (%v), expected (%v)",
+				test.input, result, test.expectedResult)
+		}
+	}
+}
+
 func TestCreateCreateTrackWorker(t *testing.T) {
+	stations, _ := NewStationCache(MockStationDAOSuccess{}, time.Minute)
+
 	var tests = []struct {
 		trDAO          datalayer.TrackRecordDAO
-		sDAO           datalayer.StationDAO
+		stations       *StationCache
 		pathParams     map[string]string
 		body           []byte
 		expectedResult Worker
@@ -280,25 +407,75 @@ func TestCreateCreateTrackWorker(t *testing.T) {
 		// success
 		{
 			MockTrackRecordDAO{},
-			MockStationDAOSuccess{},
+			stations,
 			map[string]string{"station": "hitradio-oe3", "timestamp": "1234567890"},
 			[]byte("{\"artist\":\"RHCP\",\"title\":\"Californication\"}"),
 			CreateTrackWorker{
 				MockTrackRecordDAO{},
-				MockStationDAOSuccess{},
+				stations,
+				nil,
+				model.TrackRecord{
+					StationId:   "hitradio-oe3",
+					Timestamp:   1234567890,
+					Type:        "track",
+					Track:       model.Track{"RHCP", "Californication"},
+					PrincipalID: "oe3-crawler",
+				},
+				model.DefaultTrackRecordRules,
+				DefaultDuplicatePolicy,
+			},
+			false,
+		},
+		// metadata
+		{
+			MockTrackRecordDAO{},
+			stations,
+			map[string]string{"station": "hitradio-oe3", "timestamp": "1234567890"},
+			[]byte("{\"artist\":\"RHCP\",\"title\":\"Californication\",\"album\":\"Californication\"," +
+				"\"duration\":321,\"isrc\":\"USWB19900690\",\"year\":1999}"),
+			CreateTrackWorker{
+				MockTrackRecordDAO{},
+				stations,
+				nil,
+				model.TrackRecord{
+					StationId: "hitradio-oe3",
+					Timestamp: 1234567890,
+					Type:      "track",
+					Track:     model.Track{"RHCP", "Californication"},
+					TrackMetadata: model.TrackMetadata{Album: "Californication", Duration: 321,
+						ISRC: "USWB19900690", Year: 1999},
+					PrincipalID: "oe3-crawler",
+				},
+				model.DefaultTrackRecordRules,
+				DefaultDuplicatePolicy,
+			},
+			false,
+		},
+		// non-music item without details
+		{
+			MockTrackRecordDAO{},
+			stations,
+			map[string]string{"station": "hitradio-oe3", "timestamp": "1234567890"},
+			[]byte("{\"type\":\"news\"}"),
+			CreateTrackWorker{
+				MockTrackRecordDAO{},
+				stations,
+				nil,
 				model.TrackRecord{
-					"hitradio-oe3",
-					1234567890,
-					"track",
-					model.Track{"RHCP", "Californication"},
+					StationId:   "hitradio-oe3",
+					Timestamp:   1234567890,
+					Type:        "news",
+					PrincipalID: "oe3-crawler",
 				},
+				model.DefaultTrackRecordRules,
+				DefaultDuplicatePolicy,
 			},
 			false,
 		},
 		// empty station
 		{
 			MockTrackRecordDAO{},
-			MockStationDAOSuccess{},
+			stations,
 			map[string]string{"station": "", "timestamp": "1234567890"},
 			[]byte("{\"artist\":\"RHCP\",\"title\":\"Californication\"}"),
 			nil,
@@ -307,7 +484,7 @@ func TestCreateCreateTrackWorker(t *testing.T) {
 		// missing station
 		{
 			MockTrackRecordDAO{},
-			MockStationDAOSuccess{},
+			stations,
 			map[string]string{"timestamp": "1234567890"},
 			[]byte("{\"artist\":\"RHCP\",\"title\":\"Californication\"}"),
 			nil,
@@ -316,7 +493,7 @@ func TestCreateCreateTrackWorker(t *testing.T) {
 		// empty timestamp
 		{
 			MockTrackRecordDAO{},
-			MockStationDAOSuccess{},
+			stations,
 			map[string]string{"station": "hitradio-oe3", "timestamp": ""},
 			[]byte("{\"artist\":\"RHCP\",\"title\":\"Californication\"}"),
 			nil,
@@ -325,7 +502,7 @@ func TestCreateCreateTrackWorker(t *testing.T) {
 		// missing timestamp
 		{
 			MockTrackRecordDAO{},
-			MockStationDAOSuccess{},
+			stations,
 			map[string]string{"station": "hitradio-oe3"},
 			[]byte("{\"artist\":\"RHCP\",\"title\":\"Californication\"}"),
 			nil,
@@ -334,7 +511,7 @@ func TestCreateCreateTrackWorker(t *testing.T) {
 		// empty body
 		{
 			MockTrackRecordDAO{},
-			MockStationDAOSuccess{},
+			stations,
 			map[string]string{"station": "hitradio-oe3", "timestamp": "1234567890"},
 			[]byte(""),
 			nil,
@@ -343,7 +520,7 @@ func TestCreateCreateTrackWorker(t *testing.T) {
 		// invalid body
 		{
 			MockTrackRecordDAO{},
-			MockStationDAOSuccess{},
+			stations,
 			map[string]string{"station": "hitradio-oe3", "timestamp": "1234567890"},
 			[]byte("I am a sentence and cannot be unmarshalled into a track object."),
 			nil,
@@ -352,7 +529,7 @@ func TestCreateCreateTrackWorker(t *testing.T) {
 		// invalid JSON
 		{
 			MockTrackRecordDAO{},
-			MockStationDAOSuccess{},
+			stations,
 			map[string]string{"station": "hitradio-oe3", "timestamp": "1234567890"},
 			[]byte("\"arist\":\"RHCP\",\"invalid\"-\"field\"}"),
 			nil,
@@ -361,25 +538,484 @@ func TestCreateCreateTrackWorker(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		result, err := CreateCreateTrackWorker(test.trDAO, test.sDAO, test.pathParams, test.body)
+		result, err := CreateCreateTrackWorker(test.trDAO, test.stations, nil, test.pathParams, test.body,
+			"oe3-crawler", DefaultSettings())
 		if (err != nil) != test.expectedErr {
-			t.Errorf("CreateCreateTracksWorker(%q, %q, %q, %q): got (%q, %v), expected error: %v",
-				test.trDAO, test.sDAO, test.pathParams, test.body, result,
+			t.Errorf("CreateCreateTracksWorker(%q, %v, %q, %q): got (%q, %v), expected error: %v",
+				test.trDAO, test.stations, test.pathParams, test.body, result,
 				err, test.expectedErr)
 			continue
 		}
 
 		if reflect.TypeOf(result) != reflect.TypeOf(test.expectedResult) {
-			t.Errorf("CreateCreateTracksWorker(%q, %q, %q, %q): got return type (%v), expected (%q)",
-				test.trDAO, test.sDAO, test.pathParams, test.body,
+			t.Errorf("CreateCreateTracksWorker(%q, %v, %q, %q): got return type (%v), expected (%q)",
+				test.trDAO, test.stations, test.pathParams, test.body,
 				reflect.TypeOf(result), reflect.TypeOf(test.expectedResult))
 			continue
 		}
 
 		if !reflect.DeepEqual(result, test.expectedResult) {
-			t.Errorf("CreateCreateTracksWorker(%q, %q, %q, %q): got \n(%q), expected \n(%q)",
-				test.trDAO, test.sDAO, test.pathParams, test.body,
+			t.Errorf("CreateCreateTracksWorker(%q, %v, %q, %q): got \n(%q), expected \n(%q)",
+				test.trDAO, test.stations, test.pathParams, test.body,
 				result, test.expectedResult)
 		}
 	}
 }
+
+func TestCreateCreateStationWorker(t *testing.T) {
+	var tests = []struct {
+		dao            datalayer.StationDAO
+		pathParams     map[string]string
+		body           []byte
+		expectedResult Worker
+		expectedErr    bool
+	}{
+		// success
+		{
+			MockStationDAOSuccess{},
+			map[string]string{"station": "FM4"},
+			[]byte("{\"name\":\"FM4\",\"description\":\"Alternative\"}"),
+			CreateStationWorker{
+				MockStationDAOSuccess{},
+				model.Station{ID: "fm4", Name: "FM4", Description: "Alternative"},
+			},
+			false,
+		},
+		// matching station ID in body
+		{
+			MockStationDAOSuccess{},
+			map[string]string{"station": "fm4"},
+			[]byte("{\"stationId\":\"fm4\",\"name\":\"FM4\"}"),
+			CreateStationWorker{
+				MockStationDAOSuccess{},
+				model.Station{ID: "fm4", Name: "FM4"},
+			},
+			false,
+		},
+		// diverging station ID in body
+		{
+			MockStationDAOSuccess{},
+			map[string]string{"station": "fm4"},
+			[]byte("{\"stationId\":\"kronehit\",\"name\":\"FM4\"}"),
+			nil,
+			true,
+		},
+		// missing station
+		{
+			MockStationDAOSuccess{},
+			map[string]string{},
+			[]byte("{\"name\":\"FM4\"}"),
+			nil,
+			true,
+		},
+		// invalid JSON
+		{
+			MockStationDAOSuccess{},
+			map[string]string{"station": "fm4"},
+			[]byte("name: FM4"),
+			nil,
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		result, err := CreateCreateStationWorker(test.dao, test.pathParams, test.body)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("CreateCreateStationWorker(%v, %v, %s): got (%v, %v), expected error: %v",
+				test.dao, test.pathParams, test.body, result, err, test.expectedErr)
+			continue
+		}
+
+		if !reflect.DeepEqual(result, test.expectedResult) {
+			t.Errorf("CreateCreateStationWorker(%v, %v, %s): got \n(%v), expected \n(%v)",
+				test.dao, test.pathParams, test.body, result, test.expectedResult)
+		}
+	}
+}
+
+func TestCreateUpdateStationWorker(t *testing.T) {
+	var tests = []struct {
+		dao            datalayer.StationDAO
+		pathParams     map[string]string
+		body           []byte
+		expectedResult Worker
+		expectedErr    bool
+	}{
+		{
+			MockStationDAOSuccess{},
+			map[string]string{"station": "kronehit"},
+			[]byte("{\"name\":\"Kronehit\",\"active\":true}"),
+			UpdateStationWorker{
+				MockStationDAOSuccess{},
+				model.Station{ID: "kronehit", Name: "Kronehit", Active: true},
+				false,
+			},
+			false,
+		},
+		// omitting `active` keeps the stored flag
+		{
+			MockStationDAOSuccess{},
+			map[string]string{"station": "kronehit"},
+			[]byte("{\"name\":\"Kronehit\"}"),
+			UpdateStationWorker{
+				MockStationDAOSuccess{},
+				model.Station{ID: "kronehit", Name: "Kronehit"},
+				true,
+			},
+			false,
+		},
+		{
+			MockStationDAOSuccess{},
+			map[string]string{"station": ""},
+			[]byte("{\"name\":\"Kronehit\"}"),
+			nil,
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		result, err := CreateUpdateStationWorker(test.dao, test.pathParams, test.body)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("CreateUpdateStationWorker(%v, %v, %s): got (%v, %v), expected error: %v",
+				test.dao, test.pathParams, test.body, result, err, test.expectedErr)
+			continue
+		}
+
+		if !reflect.DeepEqual(result, test.expectedResult) {
+			t.Errorf("CreateUpdateStationWorker(%v, %v, %s): got \n(%v), expected \n(%v)",
+				test.dao, test.pathParams, test.body, result, test.expectedResult)
+		}
+	}
+}
+
+func TestCreateDeactivateStationWorker(t *testing.T) {
+	var tests = []struct {
+		dao            datalayer.StationDAO
+		pathParams     map[string]string
+		expectedResult Worker
+		expectedErr    bool
+	}{
+		{
+			MockStationDAOSuccess{},
+			map[string]string{"station": "Kronehit"},
+			DeactivateStationWorker{MockStationDAOSuccess{}, "kronehit"},
+			false,
+		},
+		{MockStationDAOSuccess{}, map[string]string{}, nil, true},
+		{nil, map[string]string{"station": "kronehit"}, nil, true},
+	}
+
+	for _, test := range tests {
+		result, err := CreateDeactivateStationWorker(test.dao, test.pathParams)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("CreateDeactivateStationWorker(%v, %v): got (%v, %v), expected error: %v",
+				test.dao, test.pathParams, result, err, test.expectedErr)
+			continue
+		}
+
+		if err == nil && !reflect.DeepEqual(result, test.expectedResult) {
+			t.Errorf("CreateDeactivateStationWorker(%v, %v): got \n(%v), expected \n(%v)",
+				test.dao, test.pathParams, result, test.expectedResult)
+		}
+	}
+}
+
+func TestCreateStationsWorker(t *testing.T) {
+	var tests = []struct {
+		queryStringParams map[string]string
+		expectedResult    Worker
+		expectedErr       bool
+	}{
+		{
+			map[string]string{},
+			StationsWorker{MockStationDAOSuccess{}, StationsFilter{}},
+			false,
+		},
+		{
+			map[string]string{"active": ""},
+			StationsWorker{MockStationDAOSuccess{}, StationsFilter{}},
+			false,
+		},
+		{
+			map[string]string{"active": "true"},
+			StationsWorker{MockStationDAOSuccess{}, StationsFilter{Active: &activeTrue}},
+			false,
+		},
+		{
+			map[string]string{"active": "false"},
+			StationsWorker{MockStationDAOSuccess{}, StationsFilter{Active: &activeFalse}},
+			false,
+		},
+		{
+			map[string]string{"active": "true", "country": " AT ", "genre": "pop", "language": "de"},
+			StationsWorker{MockStationDAOSuccess{}, StationsFilter{&activeTrue, "AT", "pop", "de"}},
+			false,
+		},
+		{map[string]string{"active": "maybe"}, nil, true},
+	}
+
+	for _, test := range tests {
+		result, err := CreateStationsWorker(MockStationDAOSuccess{}, test.queryStringParams)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("CreateStationsWorker(%v): got (%v, %v), expected error: %v",
+				test.queryStringParams, result, err, test.expectedErr)
+			continue
+		}
+
+		if !reflect.DeepEqual(result, test.expectedResult) {
+			t.Errorf("CreateStationsWorker(%v): got \n(%v), expected \n(%v)",
+				test.queryStringParams, result, test.expectedResult)
+		}
+	}
+}
+
+func TestCreateStationStatusWorker(t *testing.T) {
+	var tests = []struct {
+		pathParams     map[string]string
+		expectedResult Worker
+		expectedErr    bool
+	}{
+		{
+			map[string]string{"station": "Kronehit"},
+			StationStatusWorker{MockStationDAOSuccess{}, MockTrackRecordDAO{}, "kronehit",
+				DefaultSettings().Location},
+			false,
+		},
+		{map[string]string{"station": ""}, nil, true},
+		{map[string]string{}, nil, true},
+	}
+
+	for _, test := range tests {
+		result, err := CreateStationStatusWorker(MockStationDAOSuccess{}, MockTrackRecordDAO{},
+			test.pathParams, DefaultSettings())
+		if (err != nil) != test.expectedErr {
+			t.Errorf("CreateStationStatusWorker(%v): got (%v, %v), expected error: %v",
+				test.pathParams, result, err, test.expectedErr)
+			continue
+		}
+
+		if !reflect.DeepEqual(result, test.expectedResult) {
+			t.Errorf("CreateStationStatusWorker(%v): got \n(%v), expected \n(%v)",
+				test.pathParams, result, test.expectedResult)
+		}
+	}
+}
+
+func TestCreateGroupTracksWorker(t *testing.T) {
+	day, _ := time.ParseInLocation("2006-01-02", "2018-09-19", DefaultSettings().Location)
+	dayStart, dayEnd := calculateDayBoundaries(day)
+	weekStart, weekEnd := calculateWeekBoundaries(day, time.Monday)
+	sundayWeekStart, sundayWeekEnd := calculateWeekBoundaries(day, time.Sunday)
+	rollups := newTestRollups(day)
+
+	var tests = []struct {
+		pathParams        map[string]string
+		queryStringParams map[string]string
+		expectedResult    Worker
+		expectedErr       bool
+	}{
+		{
+			map[string]string{"group": "Austria"},
+			map[string]string{"date": "2018-09-19"},
+			GroupTracksWorker{MockStationGroupDAOSuccess{}, MockTrackRecordDAO{}, rollups,
+				"austria", dayStart, dayEnd, Top, DefaultRanking},
+			false,
+		},
+		{
+			map[string]string{"group": "austria"},
+			map[string]string{"week": "2018-09-19", "filter": "all"},
+			GroupTracksWorker{MockStationGroupDAOSuccess{}, MockTrackRecordDAO{}, rollups,
+				"austria", weekStart, weekEnd, All, DefaultRanking},
+			false,
+		},
+		{
+			map[string]string{"group": "austria"},
+			map[string]string{"week": "2018-W38", "weekStart": "sunday", "filter": "top"},
+			GroupTracksWorker{MockStationGroupDAOSuccess{}, MockTrackRecordDAO{}, rollups,
+				"austria", sundayWeekStart, sundayWeekEnd, Top, DefaultRanking},
+			false,
+		},
+		{map[string]string{"group": "austria"}, map[string]string{"filter": "latest"}, nil, true},
+		{map[string]string{"group": "austria"}, map[string]string{"date": "2018-09-19",
+			"filter": "artists"}, nil, true},
+		{map[string]string{"group": "austria"}, map[string]string{"filter": "top"}, nil, true},
+		{map[string]string{"group": "austria"}, map[string]string{"date": "19.09.2018"}, nil, true},
+		{map[string]string{}, map[string]string{"date": "2018-09-19"}, nil, true},
+	}
+
+	for _, test := range tests {
+		result, err := CreateGroupTracksWorker(MockStationGroupDAOSuccess{}, MockTrackRecordDAO{},
+			rollups, test.pathParams, test.queryStringParams, DefaultSettings())
+		if (err != nil) != test.expectedErr {
+			t.Errorf("CreateGroupTracksWorker(%v, %v): got (%v, %v), expected error: %v",
+				test.pathParams, test.queryStringParams, result, err, test.expectedErr)
+			continue
+		}
+
+		if err == nil && !reflect.DeepEqual(result, test.expectedResult) {
+			t.Errorf("CreateGroupTracksWorker(%v, %v): got \n(%v), expected \n(%v)",
+				test.pathParams, test.queryStringParams, result, test.expectedResult)
+		}
+	}
+}
+
+func TestCreateCreateStationGroupWorker(t *testing.T) {
+	var tests = []struct {
+		pathParams     map[string]string
+		body           []byte
+		expectedResult Worker
+		expectedErr    bool
+	}{
+		{
+			map[string]string{"group": "ORF"},
+			[]byte("{\"name\":\"ORF\",\"stations\":[\"orf-wien\",\"orf-tirol\"]}"),
+			CreateStationGroupWorker{
+				MockStationGroupDAOSuccess{},
+				model.StationGroup{"orf", "ORF", "", []string{"orf-wien", "orf-tirol"}},
+			},
+			false,
+		},
+		{
+			map[string]string{"group": "orf"},
+			[]byte("{\"groupId\":\"austria\",\"name\":\"ORF\"}"),
+			nil,
+			true,
+		},
+		{map[string]string{"group": "orf"}, []byte("name: ORF"), nil, true},
+		{map[string]string{}, []byte("{\"name\":\"ORF\"}"), nil, true},
+	}
+
+	for _, test := range tests {
+		result, err := CreateCreateStationGroupWorker(MockStationGroupDAOSuccess{},
+			test.pathParams, test.body)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("CreateCreateStationGroupWorker(%v, %s): got (%v, %v), expected error: %v",
+				test.pathParams, test.body, result, err, test.expectedErr)
+			continue
+		}
+
+		if err == nil && !reflect.DeepEqual(result, test.expectedResult) {
+			t.Errorf("CreateCreateStationGroupWorker(%v, %s): got \n(%v), expected \n(%v)",
+				test.pathParams, test.body, result, test.expectedResult)
+		}
+	}
+}
+
+func TestCreateDeleteStationGroupWorker(t *testing.T) {
+	var tests = []struct {
+		pathParams     map[string]string
+		expectedResult Worker
+		expectedErr    bool
+	}{
+		{
+			map[string]string{"group": "Austria"},
+			DeleteStationGroupWorker{MockStationGroupDAOSuccess{}, "austria"},
+			false,
+		},
+		{map[string]string{}, nil, true},
+	}
+
+	for _, test := range tests {
+		result, err := CreateDeleteStationGroupWorker(MockStationGroupDAOSuccess{},
+			test.pathParams)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("CreateDeleteStationGroupWorker(%v): got (%v, %v), expected error: %v",
+				test.pathParams, result, err, test.expectedErr)
+			continue
+		}
+
+		if err == nil && !reflect.DeepEqual(result, test.expectedResult) {
+			t.Errorf("CreateDeleteStationGroupWorker(%v): got \n(%v), expected \n(%v)",
+				test.pathParams, result, test.expectedResult)
+		}
+	}
+}
+
+func TestCreateExportWorker(t *testing.T) {
+	location := DefaultSettings().Location
+	startDate := time.Date(2018, 9, 1, 0, 0, 0, 0, location)
+	endDate := time.Date(2018, 9, 16, 0, 0, 0, 0, location).Add(-time.Second)
+
+	var tests = []struct {
+		pathParams        map[string]string
+		queryStringParams map[string]string
+		expectedResult    Worker
+		expectedErr       bool
+	}{
+		{
+			map[string]string{"station": "FM4"},
+			map[string]string{"from": "2018-09-01", "to": "2018-09-15"},
+			ExportWorker{MockTrackRecordDAO{}, "fm4", []string{"track"}, startDate, endDate,
+				export.CSV},
+			false,
+		},
+		{
+			map[string]string{"station": "fm4"},
+			map[string]string{"from": "2018-09-01", "to": "2018-09-15", "format": "JSONL",
+				"types": "news,Ad"},
+			ExportWorker{MockTrackRecordDAO{}, "fm4", []string{"news", "ad"}, startDate, endDate,
+				export.JSONL},
+			false,
+		},
+		{
+			map[string]string{"station": "fm4"},
+			map[string]string{"from": "2018-09-01", "to": "2018-09-15", "types": "track,song"},
+			nil,
+			true,
+		},
+		{
+			map[string]string{"station": "fm4"},
+			map[string]string{"from": "2018-09-01", "to": "2018-09-15", "format": "xml"},
+			nil,
+			true,
+		},
+		{map[string]string{"station": "fm4"}, map[string]string{"from": "2018-09-01"}, nil, true},
+		{map[string]string{}, map[string]string{"from": "2018-09-01", "to": "2018-09-15"}, nil,
+			true},
+	}
+
+	for _, test := range tests {
+		result, err := CreateExportWorker(MockTrackRecordDAO{}, test.pathParams,
+			test.queryStringParams, DefaultSettings())
+		if (err != nil) != test.expectedErr {
+			t.Errorf("CreateExportWorker(%v, %v): got err (%v), expected err: %v", test.pathParams,
+				test.queryStringParams, err, test.expectedErr)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(result, test.expectedResult) {
+			t.Errorf("CreateExportWorker(%v, %v): got (%v), expected (%v)", test.pathParams,
+				test.queryStringParams, result, test.expectedResult)
+		}
+	}
+}
+
+func TestCreateAirtimeWorker(t *testing.T) {
+	date := time.Date(2018, 9, 23, 0, 0, 0, 0, DefaultSettings().Location)
+
+	var tests = []struct {
+		pathParams        map[string]string
+		queryStringParams map[string]string
+		expectedResult    Worker
+		expectedErr       bool
+	}{
+		{map[string]string{"station": "FM4"}, map[string]string{"date": "2018-09-23"},
+			AirtimeWorker{MockTrackRecordDAO{}, "fm4", date}, false},
+		{map[string]string{"station": "fm4"}, map[string]string{"date": "23.09.2018"}, nil, true},
+		{map[string]string{"station": "fm4"}, map[string]string{}, nil, true},
+		{map[string]string{}, map[string]string{"date": "2018-09-23"}, nil, true},
+	}
+
+	for _, test := range tests {
+		result, err := CreateAirtimeWorker(MockTrackRecordDAO{}, test.pathParams,
+			test.queryStringParams, DefaultSettings())
+		if (err != nil) != test.expectedErr {
+			t.Errorf("CreateAirtimeWorker(%v, %v): got err (%v), expected err: %v",
+				test.pathParams, test.queryStringParams, err, test.expectedErr)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(result, test.expectedResult) {
+			t.Errorf("CreateAirtimeWorker(%v, %v): got (%v), expected (%v)", test.pathParams,
+				test.queryStringParams, result, test.expectedResult)
+		}
+	}
+}